@@ -0,0 +1,104 @@
+// Command dql works with DQL queries from the command line.
+//
+// Usage:
+//
+//	dql run [-endpoint url] [-var key=value...] query.dql
+//
+// The run command parses and validates the query, sends it to the HTTP endpoint of a Dgraph
+// Alpha along with its variables, and pretty-prints the JSON data of the response.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"dql/dql"
+	"dql/dqlhttp"
+)
+
+// vars collects the -var flags into query variables.
+type vars map[string]string
+
+func (v vars) String() string {
+	return fmt.Sprint(map[string]string(v))
+}
+
+func (v vars) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	if !strings.HasPrefix(key, "$") {
+		key = "$" + key
+	}
+	v[key] = value
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "run" {
+		fmt.Fprintln(os.Stderr, "usage: dql run [-endpoint url] [-var key=value...] query.dql")
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	endpoint := flags.String("endpoint", "http://localhost:8080", "URL of the Dgraph Alpha HTTP endpoint")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of the request")
+	variables := vars{}
+	flags.Var(variables, "var", "query variable as key=value, can be repeated")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dql run [-endpoint url] [-var key=value...] query.dql")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*endpoint, *timeout, flags.Arg(0), variables); err != nil {
+		fmt.Fprintln(os.Stderr, "dql:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes a query file and prints the response.
+func run(endpoint string, timeout time.Duration, file string, variables vars) error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	q, err := dql.Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if err := q.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	for _, p := range q.Params {
+		if _, ok := variables[p.Ref().String()]; !ok && p.Default == "" {
+			return fmt.Errorf("%s: missing value for %s", file, p.Ref())
+		}
+	}
+
+	client := dqlhttp.NewClient(endpoint)
+	client.HTTPClient = &http.Client{Timeout: timeout}
+	resp, err := client.Execute(context.Background(), q, variables)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, resp.Json, "", "  "); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	fmt.Println(out.String())
+	return nil
+}
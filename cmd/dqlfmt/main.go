@@ -0,0 +1,100 @@
+// Command dqlfmt formats, checks and lints DQL queries.
+//
+// Usage:
+//
+//	dqlfmt [-l] [-w] [-lint] [file.dql...]
+//
+// Without files, the query is read from standard input and the formatted query is written to
+// standard output. The flags are:
+//
+//	-l     list the files whose formatting differs from dqlfmt's
+//	-w     write the formatted query back to its file
+//	-lint  report lint issues instead of formatting
+//
+// The exit code is 0 on success, 1 if lint issues were found or, with -l, if a file is not
+// formatted, and 2 on syntax errors and other failures.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"dql/dql"
+)
+
+func main() {
+	list := flag.Bool("l", false, "list files whose formatting differs from dqlfmt's")
+	write := flag.Bool("w", false, "write result to the source file instead of standard output")
+	lint := flag.Bool("lint", false, "report lint issues instead of formatting")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dqlfmt [-l] [-w] [-lint] [file.dql...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *list || *write {
+			fmt.Fprintln(os.Stderr, "dqlfmt: -l and -w require file arguments")
+			os.Exit(2)
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "dqlfmt:", err)
+			os.Exit(2)
+		}
+		os.Exit(process("<stdin>", src, *list, false, *lint))
+	}
+
+	code := 0
+	for _, name := range flag.Args() {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "dqlfmt:", err)
+			code = 2
+			continue
+		}
+		code = max(code, process(name, src, *list, *write, *lint))
+	}
+	os.Exit(code)
+}
+
+// process formats, checks or lints a query and returns the exit code for it.
+func process(name string, src []byte, list bool, write bool, lint bool) int {
+	q, err := dql.Parse(string(src))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 2
+	}
+
+	if lint {
+		issues := dql.Lint(q)
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", name, issue)
+		}
+		if len(issues) != 0 {
+			return 1
+		}
+		return 0
+	}
+
+	formatted := dql.Format(q)
+	switch {
+	case list:
+		if formatted != string(src) {
+			fmt.Println(name)
+			return 1
+		}
+	case write:
+		if formatted != string(src) {
+			if err := os.WriteFile(name, []byte(formatted), 0o644); err != nil {
+				fmt.Fprintln(os.Stderr, "dqlfmt:", err)
+				return 2
+			}
+		}
+	default:
+		fmt.Print(formatted)
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+// Command dqlgen generates Go code building the DQL queries of .dql files.
+//
+// Usage:
+//
+//	dqlgen [-pkg name] [-o file] query.dql...
+//	dqlgen [-pkg name] [-o file] -schema schema.dql
+//
+// Each query file becomes a function named after it, e.g. GetUserQuery for get_user.dql,
+// returning the equivalent *dql.Query built with the dql package. With -schema, structs,
+// predicate constants and typed query builders are generated from a Dgraph schema instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"dql/dql"
+	"dql/dqlgen"
+)
+
+func main() {
+	pkg := flag.String("pkg", "queries", "name of the package of the generated file")
+	out := flag.String("o", "", "output file, standard output if empty")
+	schema := flag.String("schema", "", "Dgraph schema file to generate typed code from")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dqlgen [-pkg name] [-o file] query.dql...\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       dqlgen [-pkg name] [-o file] -schema schema.dql\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if (flag.NArg() == 0) == (*schema == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var src []byte
+	var err error
+	if *schema != "" {
+		src, err = generateSchema(*pkg, *schema)
+	} else {
+		src, err = generateQueries(*pkg, flag.Args())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "dqlgen:", err)
+		os.Exit(1)
+	}
+}
+
+// generateQueries generates the builder functions of query files.
+func generateQueries(pkg string, files []string) ([]byte, error) {
+	sources := []dqlgen.Source{}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("dqlgen: %w", err)
+		}
+		sources = append(sources, dqlgen.Source{Func: dqlgen.FuncName(name), Name: name, DQL: string(data)})
+	}
+	return dqlgen.Generate(pkg, sources...)
+}
+
+// generateSchema generates the typed code of a schema file.
+func generateSchema(pkg string, file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("dqlgen: %w", err)
+	}
+	schema, err := dql.ParseSchema(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("dqlgen: %s: %w", file, err)
+	}
+	return dqlgen.GenerateSchema(pkg, schema)
+}
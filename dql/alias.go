@@ -0,0 +1,202 @@
+package dql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// aliasSeparatorPattern matches the characters of an attribute name that cannot appear in an
+// alias, such as the parentheses of sum(val(score)).
+var aliasSeparatorPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// GenerateAliases gives stable aliases to the attributes of the query blocks whose response
+// keys are awkward or missing, and returns the generated mapping.
+//
+// Aggregations and other expressions, such as sum(val(score)) or count(friend), are returned
+// by Dgraph under their expression as key: they are aliased after it, e.g. sum_val_score.
+// Under @normalize, Dgraph only returns aliased attributes: the unaliased leaf attributes are
+// aliased after their path, e.g. friend_name for the name of a friend edge. Existing aliases
+// are kept, and generated aliases are made unique within their object with a numeric suffix.
+// Variable blocks, fragments and raw attributes are left unchanged.
+//
+// The mapping gives, for the dotted path of each generated alias in the response, the key the
+// value would have had without it: the expression, or the dotted path of the attribute under
+// @normalize. DecodeAliased uses it to decode responses into structs tagged with these keys.
+//
+// Returns:
+//   - The mapping from the paths of the generated aliases to the original keys.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("stats", Has("score")).WithAttributes(
+//	    NewAttribute("count(uid)"),
+//	    NewAttribute("friend").WithAttributes(NewAttribute("sum(val(score))")),
+//	))
+//	aliases := query.GenerateAliases()
+//	fmt.Println(query.String())
+//	// Output: { stats (func: has(score)) { count_uid : count(uid) friend { sum_val_score : sum(val(score)) } } }
+//	fmt.Println(aliases)
+//	// Output: map[stats.count_uid:count(uid) stats.friend.sum_val_score:sum(val(score))]
+func (q *Query) GenerateAliases() map[string]string {
+	mustBeMutable(q.frozen, "query", q.Name)
+	res := map[string]string{}
+	for _, qb := range q.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		mustBeMutable(qb.frozen, "query block", qb.Name)
+		normalize := hasDirective(qb.Directives, "normalize")
+		generateAliases(qb.Name, "", qb.Attributes, normalize, takenKeys(qb.Attributes, normalize), res)
+	}
+	return res
+}
+
+// DecodeAliased decodes the response of a query whose aliases were generated by
+// GenerateAliases, restoring the original keys of the aliased values first, so structs can be
+// tagged with the expressions of the query, e.g. `json:"count(uid)"`.
+//
+// A value keeps its alias if its object already holds a value under the original key.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//   - aliases: The mapping returned by GenerateAliases.
+//   - v: A pointer to the value to decode into.
+//
+// Returns:
+//   - An error if the data cannot be decoded.
+//
+// Example:
+//
+//	aliases := query.GenerateAliases()
+//	resp, err := client.Execute(ctx, query, nil)
+//	var res struct {
+//	    Stats []struct {
+//	        Count int `json:"count(uid)"`
+//	    } `json:"stats"`
+//	}
+//	err = DecodeAliased(resp.Json, aliases, &res)
+func DecodeAliased(data []byte, aliases map[string]string, v any) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var root any
+	if err := d.Decode(&root); err != nil {
+		return fmt.Errorf("dql: decode: %w", err)
+	}
+	restoreAliases(root, "", aliases)
+	restored, err := json.Marshal(root)
+	if err != nil {
+		return fmt.Errorf("dql: decode: %w", err)
+	}
+	if err := json.Unmarshal(restored, v); err != nil {
+		return fmt.Errorf("dql: decode: %w", err)
+	}
+	return nil
+}
+
+// generateAliases aliases the attributes of a selection set, see GenerateAliases.
+//
+// path is the path of the response object holding the values of attrs, and prefix the path
+// of attrs below the @normalize attribute they are flattened into, if any. taken holds the
+// keys already used in the object.
+func generateAliases(path string, prefix string, attrs []*Attribute, normalize bool, taken map[string]bool, res map[string]string) {
+	for _, a := range attrs {
+		if a.Raw || strings.HasPrefix(a.Name, "...") {
+			continue
+		}
+		mustBeMutable(a.frozen, "attribute", a.Name)
+		name := a.Name
+		if prefix != "" {
+			name = prefix + "." + a.Name
+		}
+		if a.Alias == "" && (isExpression(a.Name) || normalize && len(a.Attributes) == 0) {
+			base := strings.Trim(aliasSeparatorPattern.ReplaceAllString(name, "_"), "_")
+			alias := base
+			for i := 2; taken[alias]; i++ {
+				alias = fmt.Sprintf("%s_%d", base, i)
+			}
+			a.Alias = alias
+			taken[alias] = true
+			if alias != name {
+				res[path+"."+alias] = name
+			}
+		}
+		switch {
+		case normalize:
+			generateAliases(path, name, a.Attributes, true, taken, res)
+		case hasDirective(a.Directives, "normalize"):
+			key := responseKey(a)
+			generateAliases(path+"."+key, "", a.Attributes, true, takenKeys(a.Attributes, true), res)
+		default:
+			key := responseKey(a)
+			generateAliases(path+"."+key, "", a.Attributes, false, takenKeys(a.Attributes, false), res)
+		}
+	}
+}
+
+// takenKeys returns the keys the attributes of a selection set already use in the response:
+// their aliases, and their names unless they are normalized.
+func takenKeys(attrs []*Attribute, normalize bool) map[string]bool {
+	res := map[string]bool{}
+	for _, a := range attrs {
+		switch {
+		case a.Alias != "":
+			res[a.Alias] = true
+		case !normalize:
+			res[a.Name] = true
+		}
+		if normalize {
+			for key := range takenKeys(a.Attributes, true) {
+				res[key] = true
+			}
+		}
+	}
+	return res
+}
+
+// responseKey returns the key of the value of an attribute in the response.
+func responseKey(a *Attribute) string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	return a.Name
+}
+
+// hasDirective reports whether directives hold a directive with the given name.
+func hasDirective(directives []Criteria, name string) bool {
+	for _, d := range directives {
+		if directiveName(d) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreAliases renames the aliased keys of a decoded response back to their original keys.
+func restoreAliases(v any, path string, aliases map[string]string) {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			restoreAliases(e, path, aliases)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			p := key
+			if path != "" {
+				p = path + "." + key
+			}
+			restoreAliases(v[key], p, aliases)
+			original, ok := aliases[p]
+			if _, exists := v[original]; ok && !exists {
+				v[original] = v[key]
+				delete(v, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newAliasQuery returns a query with nested aggregations, an alias clash and a @normalize block.
+func newAliasQuery() *Query {
+	return NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("name"), NewAttribute("count(friend)"),
+		NewAttribute("friend").WithAttributes(NewAttribute("count(friend)"), NewAttribute("s").WithAlias("count_friend")))).
+		WithQueryBlocks(NewQueryBlock("flat", Uid("0x1")).WithDirectives("@normalize").
+			WithAttributes(NewAttribute("name"), NewAttribute("friend").WithAttributes(NewAttribute("name"))))
+}
+
+func TestGenerateAliases(t *testing.T) {
+	q := newAliasQuery()
+	aliases := q.GenerateAliases()
+	want := "{ me (func: uid(0x1)) { name count_friend : count(friend) friend { count_friend_2 : count(friend) count_friend : s } } " +
+		"flat (func: uid(0x1)) @normalize { name : name friend { friend_name : name } } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	wantAliases := map[string]string{
+		"me.count_friend":          "count(friend)",
+		"me.friend.count_friend_2": "count(friend)",
+		"flat.friend_name":         "friend.name",
+	}
+	if !reflect.DeepEqual(aliases, wantAliases) {
+		t.Errorf("GenerateAliases() = %v, want %v", aliases, wantAliases)
+	}
+}
+
+func TestDecodeAliased(t *testing.T) {
+	q := newAliasQuery()
+	aliases := q.GenerateAliases()
+	data := []byte(`{"me": [{"name": "Alice", "count_friend": 2, "friend": [{"count_friend_2": 1, "count_friend": "x"}]}],
+		"flat": [{"name": "Alice", "friend_name": "Bob"}]}`)
+	var res struct {
+		Me []struct {
+			Name   string `json:"name"`
+			Count  int    `json:"count(friend)"`
+			Friend []struct {
+				Count int    `json:"count(friend)"`
+				S     string `json:"count_friend"`
+			} `json:"friend"`
+		} `json:"me"`
+		Flat []struct {
+			Name       string `json:"name"`
+			FriendName string `json:"friend.name"`
+		} `json:"flat"`
+	}
+	if err := DecodeAliased(data, aliases, &res); err != nil {
+		t.Fatalf("DecodeAliased() error = %v", err)
+	}
+	me := res.Me[0]
+	if me.Name != "Alice" || me.Count != 2 || me.Friend[0].Count != 1 || me.Friend[0].S != "x" || res.Flat[0].FriendName != "Bob" {
+		t.Errorf("DecodeAliased() = %+v", res)
+	}
+	if err := DecodeAliased([]byte("{"), aliases, &res); err == nil {
+		t.Error("DecodeAliased() of invalid JSON error = nil")
+	}
+}
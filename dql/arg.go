@@ -0,0 +1,53 @@
+package dql
+
+// Arg is a named argument of a block, such as first: 10 or orderasc: name.
+//
+// Args follow the root function in the criteria of a block. Keeping them structured, rather
+// than as raw strings, allows pagination and ordering to be inspected and validated.
+type Arg struct {
+	// Name is the name of the argument.
+	Name string
+
+	// Value is the value of the argument.
+	Value Criteria
+}
+
+// NewArg creates a new Arg with the specified name and value.
+//
+// Parameters:
+//   - name: The name of the argument.
+//   - value: The value of the argument, either a Criteria or a value rendered verbatim. The
+//     predicates of orderasc and orderdesc given as strings are escaped with Predicate.
+//
+// Returns:
+//   - A pointer to an Arg object.
+//
+// Example:
+//
+//	arg := NewArg("first", 10)
+//	fmt.Println(arg.String()) // Output: first: 10
+func NewArg(name string, value any) *Arg {
+	if s, ok := value.(string); ok && (name == "orderasc" || name == "orderdesc") {
+		value = Raw(Predicate(s))
+	}
+	return &Arg{
+		Name:  name,
+		Value: toCriteria(value),
+	}
+}
+
+// String generates a string representation of the argument.
+//
+// Returns:
+//   - A string representation of the argument.
+func (a *Arg) String() string {
+	return a.Name + ": " + a.Value.String()
+}
+
+func (a *Arg) args() []Criteria {
+	return []Criteria{a.Value}
+}
+
+func (a *Arg) withArgs(args []Criteria) Criteria {
+	return &Arg{Name: a.Name, Value: args[0]}
+}
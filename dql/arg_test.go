@@ -0,0 +1,74 @@
+package dql
+
+import "testing"
+
+func TestBlockArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Criteria
+		want string
+	}{
+		{"pagination", NewQueryBlock("me", Has("user")).WithFirst(10).WithOffset(20),
+			"me (func: has(user), first: 10, offset: 20) { }"},
+		{"after", NewQueryBlock("me", Has("user")).WithFirst(10).WithAfter("0x2a"),
+			"me (func: has(user), first: 10, after: 0x2a) { }"},
+		{"ordering", NewQueryBlock("me", Has("user")).WithOrderAsc("name").WithOrderDesc("age"),
+			"me (func: has(user), orderasc: name, orderdesc: age) { }"},
+		{"var block", NewVarBlock(Has("user")).WithName("u").WithFirst(5).WithOrderDesc("score"),
+			"u AS var (func: has(user), first: 5, orderdesc: score) { }"},
+		{"arg", NewArg("first", ParamRef("n")), "first: $n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"valid", NewQuery("", NewQueryBlock("me", Has("user")).WithFirst(-10).WithOffset(5).WithAfter("0x1f")), ""},
+		{"param", NewQuery("Q", NewQueryBlock("me", Has("user")).WithCriteria(NewArg("first", ParamRef("n")))).
+			WithParam(NewParam("n", ParamInt)), ""},
+		{"first zero", NewQuery("", NewQueryBlock("me", Has("user")).WithFirst(0)), `dql: query block "me": first: must not be zero`},
+		{"negative offset", NewQuery("", NewQueryBlock("me", Has("user")).WithOffset(-1)), `dql: query block "me": offset: -1 is negative`},
+		{"raw first", NewQuery("", NewQueryBlock("me", Has("user")).WithCriteria("first: ten")), `dql: query block "me": first: "ten" is not an integer`},
+		{"after", NewQuery("", NewQueryBlock("me", Has("user")).WithAfter("42")), `dql: query block "me": after: "42" is not a uid`},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("u"))).WithVarBlocks(NewVarBlock(Has("user")).WithName("u").WithFirst(0)),
+			`dql: var block "u": first: must not be zero`},
+		{"edge", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("friend").WithOffset(-2))),
+			`dql: query block "me": friend: offset: -2 is negative`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaginationParamRef(t *testing.T) {
+	q := NewQuery("GetUsers", NewQueryBlock("users", Has("user")).
+		WithFirst(ParamRef("first")).
+		WithOffset(ParamRef("$offset")).
+		WithAttributes(NewAttribute("friend").WithAfter(ParamRef("after")))).
+		WithParam(NewParam("first", ParamInt), NewParam("offset", ParamInt), NewParam("after", ParamString))
+	want := "query GetUsers ( $first: int, $offset: int, $after: string ) { users (func: has(user), first: $first, offset: $offset) { friend (after: $after) } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	undeclared := NewQuery("", NewQueryBlockOpt("users", Func(Has("user")), First(ParamRef("first"))))
+	if err := undeclared.Validate(); err == nil {
+		t.Error("Validate() of an undeclared pagination param error = nil")
+	}
+}
@@ -1,22 +1,45 @@
 package dql
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // Attribute represents an attribute in a DQL query.
 //
-// An Attribute can have an alias, directives, and nested attributes.
+// An Attribute can have an alias, arguments such as pagination, directives, and nested
+// attributes.
 type Attribute struct {
 	// Alias is an optional alias for the attribute.
 	Alias string
 
+	// Var is an optional name of the variable the attribute's values are assigned to.
+	Var string
+
 	// Name is the name of the attribute.
 	Name string
 
+	// Args is a list of arguments of the attribute, such as pagination and ordering.
+	Args []Criteria
+
 	// Directives is a list of directives applied to the attribute.
-	Directives []string
+	Directives []Criteria
+
+	// ArgsAfterDirectives renders the arguments after the directives, see
+	// WithArgsAfterDirectives.
+	ArgsAfterDirectives bool
 
 	// Attributes is a list of nested attributes under this attribute.
 	Attributes []*Attribute
+
+	// Raw marks the attribute as verbatim DQL held in Name, see NewRawAttribute.
+	Raw bool
+
+	// fragment is the fragment spread by the attribute, see Spread.
+	fragment *Fragment
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewAttribute creates a new Attribute with the specified name.
@@ -37,10 +60,138 @@ func NewAttribute(name string) *Attribute {
 	}
 }
 
+// WithAlias sets the alias of the attribute.
+//
+// The alias becomes the key of the attribute in the response. It is mandatory for
+// attributes of @normalize blocks and is commonly used to name aggregations.
+// Aliases must be unique within a selection set, which is checked by Validate.
+//
+// Parameters:
+//   - alias: The alias of the attribute.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("count(uid)").WithAlias("total")
+//	fmt.Println(attr.String()) // Output: total : count(uid)
+func (a *Attribute) WithAlias(alias string) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Alias = alias
+	return a
+}
+
+// WithArgs adds one or more arguments to the attribute.
+//
+// Parameters:
+//   - args: One or more arguments, either Arg objects or strings.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("director.film").WithArgs(NewArg("first", 3))
+//	fmt.Println(attr.String()) // Output: director.film (first: 3)
+func (a *Attribute) WithArgs(args ...any) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	for _, arg := range args {
+		a.Args = append(a.Args, toCriteria(arg))
+	}
+	return a
+}
+
+// WithFirst limits the edge to the first n results.
+//
+// Parameters:
+//   - n: The number of results, an int or a ParamRef bound when the query is executed.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("director.film").
+//	    WithFirst(3).
+//	    WithOrderAsc("name@en").
+//	    WithAttributes(NewAttribute("name@en"))
+//	fmt.Println(attr.String()) // Output: director.film (first: 3, orderasc: name@en) { name@en }
+func (a *Attribute) WithFirst(n any) *Attribute {
+	return a.WithArgs(NewArg("first", n))
+}
+
+// WithOffset skips the first n results of the edge.
+//
+// Parameters:
+//   - n: The number of results to skip, an int or a ParamRef.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithOffset(n any) *Attribute {
+	return a.WithArgs(NewArg("offset", n))
+}
+
+// WithAfter starts the results of the edge after the given uid.
+//
+// Parameters:
+//   - uid: The uid after which results start, a string, a UID or a ParamRef.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithAfter(uid any) *Attribute {
+	return a.WithArgs(NewArg("after", uid))
+}
+
+// WithOrderAsc orders the results of the edge by a predicate in ascending order.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithOrderAsc(predicate string) *Attribute {
+	return a.WithArgs(NewArg("orderasc", predicate))
+}
+
+// WithOrderDesc orders the results of the edge by a predicate in descending order.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithOrderDesc(predicate string) *Attribute {
+	return a.WithArgs(NewArg("orderdesc", predicate))
+}
+
+// WithVar assigns the values of the attribute to a variable.
+//
+// Depending on the attribute, the variable holds uids (for edges) or values (for scalars and
+// aggregations) which later blocks can use with uid() and val().
+//
+// Parameters:
+//   - name: The name of the variable.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("count(uid)").WithVar("a")
+//	fmt.Println(attr.String()) // Output: a as count(uid)
+//
+// See: https://dgraph.io/docs/query-language/value-variables/
+func (a *Attribute) WithVar(name string) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Var = name
+	return a
+}
+
 // WithDirectives adds one or more directives to the attribute.
 //
 // Parameters:
-//   - directives: One or more directives to add to the attribute.
+//   - directives: One or more directives, either Directive objects or strings, to add to the attribute.
 //
 // Returns:
 //   - The updated Attribute object.
@@ -49,13 +200,45 @@ func NewAttribute(name string) *Attribute {
 //
 //	attr := NewAttribute("name").WithDirectives("@filter(eq(name, \"John\"))")
 //	fmt.Println(attr.String()) // Output: name @filter(eq(name, "John"))
-func (a *Attribute) WithDirectives(directives ...string) *Attribute {
+func (a *Attribute) WithDirectives(directives ...any) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
 	for _, d := range directives {
-		a.Directives = append(a.Directives, d)
+		a.Directives = append(a.Directives, toCriteria(d))
 	}
 	return a
 }
 
+// WithArgsAfterDirectives renders the arguments of the attribute, such as first: 3 or
+// orderasc: name, after its directives rather than before them.
+//
+// Arguments can only follow a directive with arguments: after a directive without arguments
+// such as @cascade, they would be read as the arguments of the directive. In that case they
+// are rendered before the directives, and Validate reports the attribute.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("friend").WithFirst(3).
+//	    WithDirectives(NewDirective("filter", Has("name"))).
+//	    WithArgsAfterDirectives()
+//	fmt.Println(attr.String()) // Output: friend @filter(has(name)) (first: 3)
+func (a *Attribute) WithArgsAfterDirectives() *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.ArgsAfterDirectives = true
+	return a
+}
+
+// argsAfterDirectives reports whether the arguments of the attribute render after its
+// directives: ArgsAfterDirectives is set and the last directive has arguments.
+func (a *Attribute) argsAfterDirectives() bool {
+	if !a.ArgsAfterDirectives || len(a.Args) == 0 || len(a.Directives) == 0 {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSpace(a.Directives[len(a.Directives)-1].String()), ")")
+}
+
 // WithAttributes adds one or more nested attributes to the attribute.
 //
 // Parameters:
@@ -70,6 +253,7 @@ func (a *Attribute) WithDirectives(directives ...string) *Attribute {
 //	    WithAttributes(NewAttribute("name"), NewAttribute("age"))
 //	fmt.Println(attr.String()) // Output: person { name age }
 func (a *Attribute) WithAttributes(attributes ...*Attribute) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
 	for _, attr := range attributes {
 		a.Attributes = append(a.Attributes, attr)
 	}
@@ -78,18 +262,32 @@ func (a *Attribute) WithAttributes(attributes ...*Attribute) *Attribute {
 
 // String generates a string representation of the attribute.
 //
-// The string includes the alias (if set), name, directives, and any nested attributes.
+// The string includes the alias and variable (if set), name, arguments, directives, and any
+// nested attributes.
 //
 // Returns:
 //   - A string representation of the attribute.
 func (a *Attribute) String() string {
+	if a.Raw {
+		return a.Name
+	}
 	components := []string{}
 	if a.Alias != "" {
 		components = append(components, a.Alias, ":")
 	}
+	if a.Var != "" {
+		components = append(components, a.Var, "as")
+	}
 	components = append(components, a.Name)
+	after := a.argsAfterDirectives()
+	if len(a.Args) != 0 && !after {
+		components = append(components, "("+joinCriteria(a.Args)+")")
+	}
 	for _, f := range a.Directives {
-		components = append(components, f)
+		components = append(components, f.String())
+	}
+	if after {
+		components = append(components, "("+joinCriteria(a.Args)+")")
 	}
 	if len(a.Attributes) != 0 {
 		components = append(components, "{")
@@ -100,3 +298,18 @@ func (a *Attribute) String() string {
 	}
 	return strings.Join(components, " ")
 }
+
+// Validate checks the nested attributes of the attribute.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the attribute is valid.
+func (a *Attribute) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if a.Raw {
+		return nil
+	}
+	if err := validateAttributes(a.Attributes); err != nil {
+		return fmt.Errorf("dql: attribute %q: %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package dql
+
+import "testing"
+
+func TestAttributeString(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *Attribute
+		want string
+	}{
+		{"plain", NewAttribute("name"), "name"},
+		{"alias", NewAttribute("count(uid)").WithAlias("total"), "total : count(uid)"},
+		{"pagination", NewAttribute("friend").WithFirst(5).WithOffset(10).WithAttributes(NewAttribute("name")),
+			"friend (first: 5, offset: 10) { name }"},
+		{"ordering and after", NewAttribute("friend").WithOrderAsc("name").WithAfter("0x1"),
+			"friend (orderasc: name, after: 0x1)"},
+		{"order desc", NewAttribute("post").WithOrderDesc("date"), "post (orderdesc: date)"},
+		{"arguments and directives", NewAttribute("friend").WithAlias("friends").WithFirst(3).
+			WithDirectives(NewDirective("filter", Has("name"))).WithAttributes(NewAttribute("name")),
+			"friends : friend (first: 3) @filter(has(name)) { name }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeArgsClone(t *testing.T) {
+	a := NewAttribute("friend").WithFirst(5)
+	c := a.Clone()
+	c.Args[0] = NewArg("first", 1)
+	if got := a.String(); got != "friend (first: 5)" {
+		t.Errorf("original = %s, want friend (first: 5)", got)
+	}
+}
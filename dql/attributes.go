@@ -0,0 +1,199 @@
+package dql
+
+import "slices"
+
+// AttributeIndex returns the position of the attribute of the query block whose response key,
+// its alias or else its name, is name, or -1 if there is none.
+//
+// Parameters:
+//   - name: The response key of the attribute.
+//
+// Returns:
+//   - The index of the attribute in Attributes, or -1.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("name"), NewAttribute("age"))
+//	fmt.Println(queryBlock.AttributeIndex("age")) // Output: 1
+func (qb *QueryBlock) AttributeIndex(name string) int {
+	return attributeIndex(qb.Attributes, name)
+}
+
+// InsertAttributes inserts attributes into the query block at position i, shifting the
+// following attributes, so later stages can place attributes next to existing ones, e.g. at
+// AttributeIndex(name) + 1. It panics if i is out of range.
+//
+// Parameters:
+//   - i: The position of the first inserted attribute, between 0 and len(Attributes).
+//   - attrs: The attributes to insert.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("name"), NewAttribute("age"))
+//	queryBlock.InsertAttributes(1, NewAttribute("email"))
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) { name email age }
+func (qb *QueryBlock) InsertAttributes(i int, attrs ...*Attribute) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Attributes = slices.Insert(qb.Attributes, i, attrs...)
+	return qb
+}
+
+// ReplaceAttribute replaces the attribute of the query block whose response key, its alias or
+// else its name, is name, keeping its position. The attribute is appended if there is none.
+//
+// Parameters:
+//   - name: The response key of the attribute to replace.
+//   - attr: The new attribute.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("friend"), NewAttribute("age"))
+//	queryBlock.ReplaceAttribute("friend", NewAttribute("friend").WithFirst(10).WithAttributes(NewAttribute("name")))
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) { friend (first: 10) { name } age }
+func (qb *QueryBlock) ReplaceAttribute(name string, attr *Attribute) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Attributes = replaceAttribute(qb.Attributes, name, attr)
+	return qb
+}
+
+// RemoveAttribute removes the attribute of the query block whose response key, its alias or
+// else its name, is name, keeping the order of the others. It does nothing if there is none.
+//
+// Parameters:
+//   - name: The response key of the attribute to remove.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("name"), NewAttribute("password"), NewAttribute("age"))
+//	queryBlock.RemoveAttribute("password")
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) { name age }
+func (qb *QueryBlock) RemoveAttribute(name string) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Attributes = removeAttribute(qb.Attributes, name)
+	return qb
+}
+
+// AttributeIndex returns the position of the attribute of the variable block whose response
+// key is name, or -1 if there is none, see QueryBlock.AttributeIndex.
+func (vb *VarBlock) AttributeIndex(name string) int {
+	return attributeIndex(vb.Attributes, name)
+}
+
+// InsertAttributes inserts attributes into the variable block at position i, see
+// QueryBlock.InsertAttributes.
+func (vb *VarBlock) InsertAttributes(i int, attrs ...*Attribute) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
+	vb.Attributes = slices.Insert(vb.Attributes, i, attrs...)
+	return vb
+}
+
+// ReplaceAttribute replaces the attribute of the variable block whose response key is name,
+// see QueryBlock.ReplaceAttribute.
+func (vb *VarBlock) ReplaceAttribute(name string, attr *Attribute) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
+	vb.Attributes = replaceAttribute(vb.Attributes, name, attr)
+	return vb
+}
+
+// RemoveAttribute removes the attribute of the variable block whose response key is name, see
+// QueryBlock.RemoveAttribute.
+func (vb *VarBlock) RemoveAttribute(name string) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
+	vb.Attributes = removeAttribute(vb.Attributes, name)
+	return vb
+}
+
+// AttributeIndex returns the position of the attribute of the fragment whose response key is
+// name, or -1 if there is none, see QueryBlock.AttributeIndex.
+func (f *Fragment) AttributeIndex(name string) int {
+	return attributeIndex(f.Attributes, name)
+}
+
+// InsertAttributes inserts attributes into the fragment at position i, see
+// QueryBlock.InsertAttributes.
+func (f *Fragment) InsertAttributes(i int, attrs ...*Attribute) *Fragment {
+	mustBeMutable(f.frozen, "fragment", f.Name)
+	f.Attributes = slices.Insert(f.Attributes, i, attrs...)
+	return f
+}
+
+// ReplaceAttribute replaces the attribute of the fragment whose response key is name, see
+// QueryBlock.ReplaceAttribute.
+func (f *Fragment) ReplaceAttribute(name string, attr *Attribute) *Fragment {
+	mustBeMutable(f.frozen, "fragment", f.Name)
+	f.Attributes = replaceAttribute(f.Attributes, name, attr)
+	return f
+}
+
+// RemoveAttribute removes the attribute of the fragment whose response key is name, see
+// QueryBlock.RemoveAttribute.
+func (f *Fragment) RemoveAttribute(name string) *Fragment {
+	mustBeMutable(f.frozen, "fragment", f.Name)
+	f.Attributes = removeAttribute(f.Attributes, name)
+	return f
+}
+
+// AttributeIndex returns the position of the nested attribute whose response key is name, or
+// -1 if there is none, see QueryBlock.AttributeIndex.
+func (a *Attribute) AttributeIndex(name string) int {
+	return attributeIndex(a.Attributes, name)
+}
+
+// InsertAttributes inserts nested attributes at position i, see QueryBlock.InsertAttributes.
+func (a *Attribute) InsertAttributes(i int, attrs ...*Attribute) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Attributes = slices.Insert(a.Attributes, i, attrs...)
+	return a
+}
+
+// ReplaceAttribute replaces the nested attribute whose response key is name, see
+// QueryBlock.ReplaceAttribute.
+func (a *Attribute) ReplaceAttribute(name string, attr *Attribute) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Attributes = replaceAttribute(a.Attributes, name, attr)
+	return a
+}
+
+// RemoveAttribute removes the nested attribute whose response key is name, see
+// QueryBlock.RemoveAttribute.
+func (a *Attribute) RemoveAttribute(name string) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Attributes = removeAttribute(a.Attributes, name)
+	return a
+}
+
+// attributeIndex returns the position of the attribute whose response key is name, or -1.
+func attributeIndex(attrs []*Attribute, name string) int {
+	return slices.IndexFunc(attrs, func(a *Attribute) bool {
+		return responseKey(a) == name
+	})
+}
+
+// replaceAttribute replaces the attribute whose response key is name, or appends attr.
+func replaceAttribute(attrs []*Attribute, name string, attr *Attribute) []*Attribute {
+	if i := attributeIndex(attrs, name); i >= 0 {
+		attrs[i] = attr
+		return attrs
+	}
+	return append(attrs, attr)
+}
+
+// removeAttribute removes the attributes whose response key is name.
+func removeAttribute(attrs []*Attribute, name string) []*Attribute {
+	return slices.DeleteFunc(attrs, func(a *Attribute) bool {
+		return responseKey(a) == name
+	})
+}
@@ -0,0 +1,50 @@
+package dql
+
+import "testing"
+
+func TestAttributeOperations(t *testing.T) {
+	qb := NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute("name"), NewAttribute("email").WithAlias("mail"), NewAttribute("age"))
+	if i, j, k := qb.AttributeIndex("mail"), qb.AttributeIndex("email"), qb.AttributeIndex("age"); i != 1 || j != -1 || k != 2 {
+		t.Errorf("AttributeIndex() = %d, %d, %d, want 1, -1, 2", i, j, k)
+	}
+	tests := []struct {
+		name string
+		op   func(qb *QueryBlock)
+		want string
+	}{
+		{"insert", func(qb *QueryBlock) { qb.InsertAttributes(1, NewAttribute("uid"), NewAttribute("nick")) }, "mail : email uid nick age"},
+		{"insert at end", func(qb *QueryBlock) { qb.InsertAttributes(len(qb.Attributes), NewAttribute("uid")) }, "mail : email age uid"},
+		{"replace", func(qb *QueryBlock) { qb.ReplaceAttribute("mail", NewAttribute("email")) }, "email age"},
+		{"replace missing", func(qb *QueryBlock) { qb.ReplaceAttribute("nick", NewAttribute("nick")) }, "mail : email age nick"},
+		{"remove", func(qb *QueryBlock) { qb.RemoveAttribute("age") }, "mail : email"},
+		{"remove missing", func(qb *QueryBlock) { qb.RemoveAttribute("email") }, "mail : email age"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute("name"), NewAttribute("email").WithAlias("mail"), NewAttribute("age"))
+			qb.RemoveAttribute("name")
+			tt.op(qb)
+			if want := "me (func: has(name)) { " + tt.want + " }"; qb.String() != want {
+				t.Errorf("String() = %s, want %s", qb.String(), want)
+			}
+		})
+	}
+
+	edge := NewAttribute("friend").WithAttributes(NewAttribute("name"))
+	edge.InsertAttributes(0, NewAttribute("uid")).ReplaceAttribute("name", NewAttribute("nick"))
+	vb := NewVarBlock(Has("name")).WithAttributes(NewAttribute("a"), NewAttribute("b")).RemoveAttribute("a")
+	f := NewFragment("f").WithAttributes(NewAttribute("x")).InsertAttributes(1, NewAttribute("y"))
+	if edge.String() != "friend { uid nick }" || vb.AttributeIndex("b") != 0 || f.AttributeIndex("y") != 1 {
+		t.Errorf("operations = %s, %d, %d", edge.String(), vb.AttributeIndex("b"), f.AttributeIndex("y"))
+	}
+}
+
+func TestAttributeOperationsFrozen(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute("name"))).Freeze()
+	defer func() {
+		if recover() == nil {
+			t.Error("RemoveAttribute() on a frozen block did not panic")
+		}
+	}()
+	q.QueryBlocks[0].RemoveAttribute("name")
+}
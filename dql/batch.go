@@ -0,0 +1,218 @@
+package dql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// pathKey is the key under which Dgraph returns the paths of shortest path blocks.
+const pathKey = "_path_"
+
+// Batch holds independent queries sent to Dgraph in a single request, saving the round trips
+// of running them one after the other.
+//
+// The queries are merged into one: their blocks must have distinct names, and so must the
+// variables they define. Parameters declared by several queries must agree, and take the same
+// value. The response is split back into one response per query.
+type Batch struct {
+	keys    []string
+	queries map[string]*Query
+}
+
+// NewBatch creates a new, empty Batch.
+//
+// Returns:
+//   - A pointer to a Batch object.
+//
+// Example:
+//
+//	batch := NewBatch().
+//	    Add("users", NewQuery("", NewQueryBlock("users", Has("user")).WithAttributes(NewAttribute("name")))).
+//	    Add("posts", NewQuery("", NewQueryBlock("posts", Has("post")).WithAttributes(NewAttribute("title"))))
+//	responses, err := batch.Execute(ctx, client, nil)
+//	fmt.Println(string(responses["posts"].Json)) // Output: {"posts":[...]}
+func NewBatch() *Batch {
+	return &Batch{queries: map[string]*Query{}}
+}
+
+// Add adds a query to the batch under a key, replacing the query previously added under it.
+//
+// Parameters:
+//   - key: The key of the query, under which Split returns its response.
+//   - q: The query.
+//
+// Returns:
+//   - The updated Batch object.
+func (b *Batch) Add(key string, q *Query) *Batch {
+	if _, ok := b.queries[key]; !ok {
+		b.keys = append(b.keys, key)
+	}
+	b.queries[key] = q
+	return b
+}
+
+// Keys returns the keys of the queries of the batch.
+//
+// Returns:
+//   - The keys, in the order the queries were added.
+func (b *Batch) Keys() []string {
+	return append([]string{}, b.keys...)
+}
+
+// Validate checks every query of the batch, and that they can be sent together.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the batch is valid.
+func (b *Batch) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if len(b.keys) == 0 {
+		return fmt.Errorf("dql: batch: no queries")
+	}
+	blocks := map[string]string{}
+	variables := map[string]string{}
+	paths := ""
+	for _, key := range b.keys {
+		q := b.queries[key]
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("dql: batch: query %q: %w", key, err)
+		}
+		for _, qb := range q.QueryBlocks {
+			if qb.Raw {
+				return fmt.Errorf("dql: batch: query %q: raw query blocks cannot be split from the response", key)
+			}
+			if other, ok := blocks[qb.Name]; ok {
+				return fmt.Errorf("dql: batch: queries %q and %q both define block %q", other, key, qb.Name)
+			}
+			blocks[qb.Name] = key
+		}
+		if len(q.ShortestPaths) != 0 {
+			if paths != "" {
+				return fmt.Errorf("dql: batch: queries %q and %q both find shortest paths, returned under the same key", paths, key)
+			}
+			paths = key
+		}
+		for _, name := range definedVariables(q) {
+			if other, ok := variables[name]; ok && other != key {
+				return fmt.Errorf("dql: batch: queries %q and %q both define variable %q", other, key, name)
+			}
+			variables[name] = key
+		}
+	}
+	_, err = b.merge()
+	return err
+}
+
+// Query renders the queries of the batch into a single query.
+//
+// The merged query is named after the first query, or "batch" if it declares parameters but
+// has no name, and requests debug information if any query does.
+//
+// Returns:
+//   - A pointer to the merged Query object.
+//   - An error if the batch is invalid, see Validate.
+func (b *Batch) Query() (*Query, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b.merge()
+}
+
+// Split splits the response of the merged query into the responses of each query.
+//
+// The response of each query holds the results of its blocks. The extensions of the response,
+// such as the latency, are shared by every query.
+//
+// Parameters:
+//   - resp: The response of the query returned by Query.
+//
+// Returns:
+//   - The responses, by key.
+//   - An error if the response is not a JSON object.
+func (b *Batch) Split(resp *Response) (map[string]*Response, error) {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Json, &data); err != nil {
+		return nil, fmt.Errorf("dql: batch: split response: %w", err)
+	}
+	res := map[string]*Response{}
+	for _, key := range b.keys {
+		q := b.queries[key]
+		results := map[string]json.RawMessage{}
+		for _, qb := range q.QueryBlocks {
+			if raw, ok := data[qb.Name]; ok {
+				results[qb.Name] = raw
+			}
+		}
+		if raw, ok := data[pathKey]; ok && len(q.ShortestPaths) != 0 {
+			results[pathKey] = raw
+		}
+		out, err := json.Marshal(results)
+		if err != nil {
+			return nil, fmt.Errorf("dql: batch: split response: %w", err)
+		}
+		res[key] = &Response{Json: out, Extensions: resp.Extensions}
+	}
+	return res, nil
+}
+
+// Execute runs the queries of the batch in a single request.
+//
+// Parameters:
+//   - ctx: The context of the execution.
+//   - exec: The Executor running the merged query.
+//   - vars: The variables of the queries, given by name with their leading $.
+//
+// Returns:
+//   - The responses, by key.
+//   - An error if the batch is invalid or the execution failed.
+func (b *Batch) Execute(ctx context.Context, exec Executor, vars map[string]string) (map[string]*Response, error) {
+	q, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := exec.Execute(ctx, q, vars)
+	if err != nil {
+		return nil, err
+	}
+	return b.Split(resp)
+}
+
+// merge merges copies of the queries of the batch.
+func (b *Batch) merge() (*Query, error) {
+	res := &Query{Name: b.queries[b.keys[0]].Name}
+	for _, key := range b.keys {
+		q := b.queries[key].Clone()
+		if err := res.Merge(q); err != nil {
+			return nil, fmt.Errorf("dql: batch: query %q: %w", key, err)
+		}
+		res.Debug = res.Debug || q.Debug
+	}
+	if res.Name == "" && len(res.Params) != 0 {
+		res.Name = "batch"
+	}
+	return res, nil
+}
+
+// definedVariables returns the variables a query defines in its blocks and attributes.
+func definedVariables(q *Query) []string {
+	res := []string{}
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Name != "" && !n.Raw {
+				res = append(res, n.Name)
+			}
+		case *ShortestPath:
+			if n.Name != "" {
+				res = append(res, n.Name)
+			}
+		case *Attribute:
+			if n.Var != "" {
+				res = append(res, n.Var)
+			}
+			res = append(res, facetVars(n.Directives)...)
+		}
+		return true
+	})
+	return res
+}
@@ -0,0 +1,64 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	b := NewBatch().
+		Add("users", NewQuery("", NewQueryBlock("users", Has("email")).WithAttributes(NewAttribute("name")))).
+		Add("posts", NewQuery("", NewQueryBlock("posts", Has("title")).WithAttributes(NewAttribute("title"))))
+	var got *Query
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		got = q
+		return &Response{Json: []byte(`{"users": [{"name": "Alice"}], "posts": [{"title": "Hi"}], "other": []}`)}, nil
+	})
+	res, err := b.Execute(context.Background(), exec, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "{ users (func: has(email)) { name } posts (func: has(title)) { title } }"; got.String() != want {
+		t.Errorf("query = %s, want %s", got.String(), want)
+	}
+	if got := string(res["users"].Json); got != `{"users":[{"name":"Alice"}]}` {
+		t.Errorf("users = %s", got)
+	}
+	if got := string(res["posts"].Json); got != `{"posts":[{"title":"Hi"}]}` {
+		t.Errorf("posts = %s", got)
+	}
+	if keys := b.Keys(); len(keys) != 2 || keys[0] != "users" || keys[1] != "posts" {
+		t.Errorf("Keys() = %v, want [users posts]", keys)
+	}
+}
+
+func TestBatchValidate(t *testing.T) {
+	named := func(block string) *Query {
+		return NewQuery("", NewQueryBlock(block, Has("name")))
+	}
+	withVar := func(block string) *Query {
+		return NewQuery("", NewQueryBlock(block, Has("name")).WithAttributes(NewAttribute("uid").WithVar("u")))
+	}
+	withPath := func(block string) *Query {
+		return named(block).WithShortestPaths(NewShortestPath("0x1", "0x2").WithAttributes(NewAttribute("friend")))
+	}
+	tests := []struct {
+		name    string
+		b       *Batch
+		wantErr string
+	}{
+		{"valid", NewBatch().Add("a", named("a")).Add("b", named("b")), ""},
+		{"empty", NewBatch(), "dql: batch: no queries"},
+		{"same block", NewBatch().Add("a", named("me")).Add("b", named("me")), `dql: batch: queries "a" and "b" both define block "me"`},
+		{"same variable", NewBatch().Add("a", withVar("a")).Add("b", withVar("b")), `dql: batch: queries "a" and "b" both define variable "u"`},
+		{"two shortest paths", NewBatch().Add("a", withPath("a")).Add("b", withPath("b")), `dql: batch: queries "a" and "b" both find shortest paths, returned under the same key`},
+		{"invalid query", NewBatch().Add("a", NewQuery("", NewQueryBlock("a", Uid("u")))), `dql: batch: query "a": dql: undefined variable "u"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.b.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package dql
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// decimalPattern matches decimal numbers such as -12.50 or 1e-3.
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// decimal is a number given by its decimal representation, rendered verbatim.
+type decimal string
+
+// BigInt creates an integer literal of arbitrary size, rendered with all its digits.
+//
+// The value is copied, so modifying it afterwards does not change the literal.
+//
+// Parameters:
+//   - v: The integer.
+//
+// Returns:
+//   - A Literal of the integer.
+//
+// Example:
+//
+//	balance, _ := new(big.Int).SetString("9007199254740993", 10)
+//	fmt.Println(Ge("balance", BigInt(balance)).String()) // Output: ge(balance, 9007199254740993)
+func BigInt(v *big.Int) Literal {
+	return Literal{new(big.Int).Set(v)}
+}
+
+// BigFloat creates a floating-point literal of arbitrary precision, rendered with as many
+// digits as its precision requires rather than rounded to a float64.
+//
+// The value is copied, so modifying it afterwards does not change the literal.
+//
+// Parameters:
+//   - v: The number.
+//
+// Returns:
+//   - A Literal of the number.
+//
+// See: https://dgraph.io/docs/dql/predicate-types/#bigfloat
+func BigFloat(v *big.Float) Literal {
+	return Literal{new(big.Float).Copy(v)}
+}
+
+// Decimal creates a number literal from its decimal representation, rendered exactly as
+// given, e.g. for amounts read from a database or an API as strings.
+//
+// Parameters:
+//   - s: The decimal representation, such as 12.50 or -1e-3.
+//
+// Returns:
+//   - A Literal of the number.
+//   - An error if s is not a decimal number.
+//
+// Example:
+//
+//	price, err := Decimal("19.99")
+//	fmt.Println(Le("price", price).String()) // Output: le(price, 19.99)
+func Decimal(s string) (Literal, error) {
+	if !decimalPattern.MatchString(s) {
+		return Literal{}, fmt.Errorf("dql: invalid decimal %q", s)
+	}
+	return Literal{decimal(s)}, nil
+}
+
+// ValidateValue checks that a value can be compared with a predicate of the schema, so
+// numbers too large or not integral for an int predicate are reported before Dgraph rounds
+// or rejects them.
+//
+// Integers, including BigInt values and integral decimals, must fit in 64 bits for int
+// predicates, which do not accept floating-point values. Float and bigfloat predicates
+// accept any number. Values of other predicate types are checked as by Query.Coerce, e.g.
+// strings must be dates for datetime predicates.
+//
+// Parameters:
+//   - predicate: The name of the predicate.
+//   - v: The value, or a Literal holding it.
+//
+// Returns:
+//   - An error if the predicate is not defined or cannot hold the value.
+//
+// Example:
+//
+//	schema, _ := ParseSchema(`age: int .`)
+//	price, _ := Decimal("19.99")
+//	fmt.Println(schema.ValidateValue("age", price)) // Output: dql: predicate "age": 19.99 is not an int
+func (s *Schema) ValidateValue(predicate string, v any) error {
+	p := s.Predicate(predicate)
+	if p == nil {
+		return wrapError(ErrSchemaMismatch, fmt.Errorf("dql: undefined predicate %q", predicate))
+	}
+	if l, ok := v.(Literal); ok {
+		v = l.Value
+	}
+	if _, err := coerceValue(p, v); err != nil {
+		return wrapError(ErrSchemaMismatch, fmt.Errorf("dql: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dql
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBigNumbers(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	precise, _ := new(big.Float).SetPrec(200).SetString("0.1000000000000000000000001")
+	dec, err := Decimal("-12.50e3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"big int", Eq("balance", BigInt(huge)).String(), "eq(balance, 123456789012345678901234567890)"},
+		{"big float", Eq("rate", BigFloat(precise)).String(), "eq(rate, 0.1000000000000000000000001)"},
+		{"decimal", Eq("amount", dec).String(), "eq(amount, -12.50e3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("String() = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+
+	lit := BigInt(huge)
+	huge.SetInt64(0)
+	if got := lit.String(); got != "123456789012345678901234567890" {
+		t.Errorf("BigInt() shares its value: %s", got)
+	}
+	if _, err := Decimal("1.2.3"); err == nil {
+		t.Error("Decimal() of an invalid number error = nil")
+	}
+}
+
+func TestBigNumberParameterize(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	dec, _ := Decimal("1.25")
+	q, vars := NewQuery("Q", NewQueryBlock("me", Eq("balance", BigInt(huge))).WithDirectives(NewDirective("filter", Gt("rate", dec)))).Parameterize()
+	want := "query Q ( $v0: int, $v1: float ) { me (func: eq(balance, $v0)) @filter(gt(rate, $v1)) { } }"
+	if got := q.String(); got != want {
+		t.Errorf("Parameterize() = %s, want %s", got, want)
+	}
+	if vars["$v0"] != "123456789012345678901234567890" || vars["$v1"] != "1.25" {
+		t.Errorf("Parameterize() vars = %v", vars)
+	}
+}
+
+func TestSchemaValidateValue(t *testing.T) {
+	schema, err := ParseSchema("count: int .\nrate: float .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	whole, _ := Decimal("42")
+	fraction, _ := Decimal("4.2")
+	tests := []struct {
+		name      string
+		predicate string
+		value     any
+		wantErr   string
+	}{
+		{"int", "count", 42, ""},
+		{"whole decimal", "count", whole, ""},
+		{"float predicate", "rate", 1.5, ""},
+		{"overflow", "count", BigInt(huge), `dql: predicate "count": 123456789012345678901234567890 overflows an int`},
+		{"uint overflow", "count", uint64(1 << 63), `dql: predicate "count": 9223372036854775808 overflows an int`},
+		{"fraction", "count", fraction, `dql: predicate "count": 4.2 is not an int`},
+		{"float", "count", 1.5, `dql: predicate "count": 1.5 is not an int`},
+		{"big float", "count", BigFloat(big.NewFloat(2.5)), `dql: predicate "count": 2.5 is not an int`},
+		{"undefined", "age", 1, `dql: undefined predicate "age"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(schema.ValidateValue(tt.predicate, tt.value)); got != tt.wantErr {
+				t.Errorf("ValidateValue() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package dql
+
+// Built-in predicates and selections of Dgraph.
+const (
+	// PredicateUID is the uid of a node.
+	PredicateUID = "uid"
+
+	// PredicateType is the predicate holding the types of a node.
+	PredicateType = "dgraph.type"
+
+	// ExpandAll selects every predicate of the types of a node.
+	ExpandAll = "expand(_all_)"
+)
+
+// UIDAttribute creates an attribute selecting the uid of a node.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+func UIDAttribute() *Attribute {
+	return NewAttribute(PredicateUID)
+}
+
+// TypeAttribute creates an attribute selecting the dgraph.type predicate of a node.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+func TypeAttribute() *Attribute {
+	return NewAttribute(PredicateType)
+}
+
+// ExpandAllAttribute creates an expand(_all_) attribute selecting every predicate of the types
+// of a node.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+//
+// See: https://dgraph.io/docs/query-language/expand-predicates/
+func ExpandAllAttribute() *Attribute {
+	return NewAttribute(ExpandAll)
+}
+
+// WithTypeSelection adds the uid and dgraph.type attributes to the attribute, unless they are
+// already selected.
+//
+// The type information lets clients decode polymorphic results, e.g. into interfaces.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("friend").WithAttributes(NewAttribute("name")).WithTypeSelection()
+//	fmt.Println(attr.String()) // Output: friend { name uid dgraph.type }
+func (a *Attribute) WithTypeSelection() *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Attributes = withTypeSelection(a.Attributes)
+	return a
+}
+
+// WithTypeSelection adds the uid and dgraph.type attributes to the query block, unless they
+// are already selected.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", Has("user")).WithTypeSelection()
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) { uid dgraph.type }
+func (qb *QueryBlock) WithTypeSelection() *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Attributes = withTypeSelection(qb.Attributes)
+	return qb
+}
+
+// withTypeSelection appends the uid and dgraph.type attributes missing from a selection set.
+func withTypeSelection(attrs []*Attribute) []*Attribute {
+	for _, name := range []string{PredicateUID, PredicateType} {
+		found := false
+		for _, a := range attrs {
+			if a.Name == name && a.Alias == "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			attrs = append(attrs, NewAttribute(name))
+		}
+	}
+	return attrs
+}
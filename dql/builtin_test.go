@@ -0,0 +1,27 @@
+package dql
+
+import "testing"
+
+func TestWithTypeSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Criteria
+		want string
+	}{
+		{"query block", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("name")).WithTypeSelection(),
+			"me (func: has(user)) { name uid dgraph.type }"},
+		{"already selected", NewQueryBlock("me", Has("user")).WithAttributes(UIDAttribute(), TypeAttribute()).WithTypeSelection(),
+			"me (func: has(user)) { uid dgraph.type }"},
+		{"aliased uid", NewQueryBlock("me", Has("user")).WithAttributes(UIDAttribute().WithAlias("id")).WithTypeSelection(),
+			"me (func: has(user)) { id : uid uid dgraph.type }"},
+		{"attribute", NewAttribute("owns").WithAttributes(ExpandAllAttribute()).WithTypeSelection(),
+			"owns { expand(_all_) uid dgraph.type }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
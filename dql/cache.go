@@ -0,0 +1,365 @@
+package dql
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores the responses cached by a Cache, e.g. in memory or in a shared store
+// such as Redis.
+//
+// Implementations must be safe for concurrent use. Responses are shared with the callers of
+// the Executor, so stores must not modify them.
+type CacheStore interface {
+	// Get returns the response stored under key, and whether it was found and has not
+	// expired.
+	Get(ctx context.Context, key string) (*Response, bool)
+
+	// Set stores a response under key for the duration of ttl, or without expiry if ttl is
+	// zero.
+	Set(ctx context.Context, key string, resp *Response, ttl time.Duration)
+
+	// Delete removes the response stored under key, if any.
+	Delete(ctx context.Context, key string)
+}
+
+// DefaultCacheEntries is the number of responses a Cache created with NewCache holds at most.
+const DefaultCacheEntries = 10000
+
+// Cache caches the responses of queries, keyed by the fingerprint of the query and its
+// variables. Cache is safe for concurrent use.
+//
+// Cached responses are invalidated when their TTL expires, or explicitly by the predicates
+// their queries read, e.g. after a mutation changed them. The oldest responses are evicted
+// when the cache holds MaxEntries of them.
+//
+// The cache key only depends on the query it receives, so the middleware must run after the
+// rewriters depending on the context, such as a FilterRewriter isolating tenants, so that it
+// sees the rewritten query; otherwise Scope must add the context to the key. Rewriting
+// executors run behind a cache without Scope fail rather than share responses across
+// contexts.
+type Cache struct {
+	// Store holds the cached responses.
+	Store CacheStore
+
+	// TTL is the duration responses are cached for, zero for no expiry.
+	TTL time.Duration
+
+	// MaxEntries is the number of responses the cache holds at most, zero for no limit.
+	MaxEntries int
+
+	// Scope returns the part of the cache key depending on the context of the execution,
+	// e.g. the tenant or the user, so that responses are only shared within a scope. It is
+	// needed when the cache runs before the rewriters; nil for none.
+	Scope func(ctx context.Context) string
+
+	mu sync.Mutex
+
+	// keys holds the cache keys of the responses of the queries reading each predicate. The
+	// empty predicate holds the keys of queries whose predicates are unknown.
+	keys map[string]map[string]bool
+
+	// entries holds the element of order of each cache key.
+	entries map[string]*list.Element
+
+	// order holds the cached entries from the oldest to the newest.
+	order *list.List
+
+	// generation is incremented by Invalidate, so responses of queries executed during an
+	// invalidation, which may predate it, are not cached.
+	generation uint64
+}
+
+// cacheEntry is a response tracked by a Cache.
+type cacheEntry struct {
+	key        string
+	expires    time.Time
+	predicates []string
+}
+
+// unscopedCacheKey is the context key marking executions served by a Cache without Scope.
+type unscopedCacheKey struct{}
+
+// errUnscopedCache is returned by rewriting executors run behind a Cache without Scope.
+var errUnscopedCache = fmt.Errorf("dql: rewriters run behind a cache without Scope: place the cache after the rewriters or set Cache.Scope")
+
+// NewCache creates a new Cache holding at most DefaultCacheEntries responses.
+//
+// Parameters:
+//   - store: The store holding the cached responses, e.g. NewMemoryCacheStore().
+//   - ttl: The duration responses are cached for, zero for no expiry.
+//
+// Returns:
+//   - A pointer to a Cache object.
+//
+// Example:
+//
+//	cache := NewCache(NewMemoryCacheStore(), time.Minute)
+//	exec := Chain(client, RewriteMiddleware(FilterRewriter(tenantFilter)), cache.Middleware())
+//	// After a mutation of the name predicate:
+//	cache.Invalidate(ctx, "name")
+func NewCache(store CacheStore, ttl time.Duration) *Cache {
+	return &Cache{Store: store, TTL: ttl, MaxEntries: DefaultCacheEntries}
+}
+
+// Middleware returns a Middleware serving queries from the cache, and caching the responses
+// of the queries it executes.
+//
+// Queries requesting debug information are not cached, nor are failed queries. Queries with
+// raw blocks or attributes, or selecting expand(), read predicates that are not known in
+// advance: their responses are invalidated by any call to Invalidate. Responses of queries
+// running while Invalidate is called are not cached, since they may predate the change.
+//
+// Returns:
+//   - A Middleware.
+func (c *Cache) Middleware() Middleware {
+	return func(next Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+			if q.Debug {
+				return next.Execute(ctx, q, vars)
+			}
+			scope := ""
+			if c.Scope != nil {
+				scope = c.Scope(ctx)
+			} else {
+				ctx = context.WithValue(ctx, unscopedCacheKey{}, true)
+			}
+			key := cacheKey(scope, q, vars)
+			if resp, ok := c.Store.Get(ctx, key); ok {
+				return resp, nil
+			}
+			generation := c.currentGeneration()
+			resp, err := next.Execute(ctx, q, vars)
+			if err != nil {
+				return nil, err
+			}
+			evicted, ok := c.track(key, cachePredicates(q), generation)
+			if !ok {
+				return resp, nil
+			}
+			c.Store.Set(ctx, key, resp, c.TTL)
+			// An invalidation between tracking and storing may have deleted the key before it
+			// was stored.
+			if c.currentGeneration() != generation {
+				evicted = append(evicted, key)
+			}
+			for _, key := range evicted {
+				c.Store.Delete(ctx, key)
+			}
+			return resp, nil
+		})
+	}
+}
+
+// Invalidate removes the cached responses of the queries reading some predicates.
+//
+// Parameters:
+//   - ctx: The context of the removal, passed to the store.
+//   - predicates: The predicates whose values changed.
+func (c *Cache) Invalidate(ctx context.Context, predicates ...string) {
+	c.mu.Lock()
+	c.generation++
+	keys := map[string]bool{}
+	for _, p := range append(predicates, "") {
+		for key := range c.keys[predicateOf(p)] {
+			keys[key] = true
+		}
+	}
+	for key := range keys {
+		c.untrack(key)
+	}
+	c.mu.Unlock()
+	for key := range keys {
+		c.Store.Delete(ctx, key)
+	}
+}
+
+// Len returns the number of responses tracked by the cache, expired ones included until they
+// are pruned.
+//
+// Returns:
+//   - The number of responses.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// currentGeneration returns the number of calls to Invalidate so far.
+func (c *Cache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// track records the predicates read by the query cached under key, and prunes the expired
+// and the oldest entries beyond MaxEntries. It returns the keys of the evicted responses,
+// which the caller removes from the store, and false without tracking the query if Invalidate
+// was called since generation, when the query started.
+func (c *Cache) track(key string, predicates []string, generation uint64) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return nil, false
+	}
+	if c.keys == nil {
+		c.keys = map[string]map[string]bool{}
+		c.entries = map[string]*list.Element{}
+		c.order = list.New()
+	}
+	c.untrack(key)
+	e := &cacheEntry{key: key, predicates: predicates}
+	if c.TTL > 0 {
+		e.expires = time.Now().Add(c.TTL)
+	}
+	c.entries[key] = c.order.PushBack(e)
+	for _, p := range predicates {
+		if c.keys[p] == nil {
+			c.keys[p] = map[string]bool{}
+		}
+		c.keys[p][key] = true
+	}
+	// Entries are ordered by insertion, hence by expiry as long as TTL does not change.
+	var evicted []string
+	now := time.Now()
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		e := front.Value.(*cacheEntry)
+		expired := !e.expires.IsZero() && now.After(e.expires)
+		if !expired && (c.MaxEntries <= 0 || len(c.entries) <= c.MaxEntries) {
+			break
+		}
+		c.untrack(e.key)
+		evicted = append(evicted, e.key)
+	}
+	return evicted, true
+}
+
+// untrack removes the entry of key, if any. The caller must hold c.mu.
+func (c *Cache) untrack(key string) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	e := elem.Value.(*cacheEntry)
+	for _, p := range e.predicates {
+		delete(c.keys[p], key)
+		if len(c.keys[p]) == 0 {
+			delete(c.keys, p)
+		}
+	}
+	c.order.Remove(elem)
+	delete(c.entries, key)
+}
+
+// cacheKey returns the cache key of a query and its variables within a scope.
+func cacheKey(scope string, q *Query, vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	components := []string{Quote(scope), q.Fingerprint()}
+	for _, name := range names {
+		components = append(components, Quote(name)+"="+Quote(vars[name]))
+	}
+	return hash(strings.Join(components, "\n"))
+}
+
+// cachePredicates returns the predicates read by a query, including the empty predicate if
+// some of them are unknown.
+func cachePredicates(q *Query) []string {
+	res := Predicates(q)
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Raw {
+				res = append(res, "")
+			}
+		case *QueryBlock:
+			if n.Raw {
+				res = append(res, "")
+			}
+		case *Attribute:
+			if n.Raw || strings.HasPrefix(n.Name, "expand(") {
+				res = append(res, "")
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// memoryCacheSweep is the least number of entries from which a memoryCacheStore sweeps the
+// expired ones.
+const memoryCacheSweep = 1024
+
+// memoryCacheStore is a CacheStore holding responses in memory.
+type memoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+
+	// sweepAt is the number of entries from which Set removes the expired ones.
+	sweepAt int
+}
+
+// memoryCacheEntry is a response held by a memoryCacheStore.
+type memoryCacheEntry struct {
+	resp    *Response
+	expires time.Time
+}
+
+// NewMemoryCacheStore creates a CacheStore holding responses in the memory of the process.
+//
+// Expired responses are removed when they are looked up, and swept when the number of
+// responses doubled since the last sweep. The Cache using the store bounds the number of
+// responses it holds, see Cache.MaxEntries.
+//
+// Returns:
+//   - A CacheStore.
+func NewMemoryCacheStore() CacheStore {
+	return &memoryCacheStore{entries: map[string]memoryCacheEntry{}, sweepAt: memoryCacheSweep}
+}
+
+func (s *memoryCacheStore) Get(ctx context.Context, key string) (*Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return e.resp, true
+}
+
+func (s *memoryCacheStore) Set(ctx context.Context, key string, resp *Response, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if len(s.entries) >= s.sweepAt {
+		for k, e := range s.entries {
+			if !e.expires.IsZero() && now.After(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.sweepAt = max(2*len(s.entries), memoryCacheSweep)
+	}
+	e := memoryCacheEntry{resp: resp}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	s.entries[key] = e
+}
+
+func (s *memoryCacheStore) Delete(ctx context.Context, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
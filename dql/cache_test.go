@@ -0,0 +1,214 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingExecutor counts the queries it executes, responding with their text.
+type countingExecutor struct {
+	calls int
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+	e.calls++
+	return &Response{Json: []byte(q.String())}, nil
+}
+
+// userQuery returns a query looking up a user by name.
+func userQuery(name string) *Query {
+	return NewQuery("", NewQueryBlock("me", Eq("name", name)).WithAttributes(NewAttribute("email")))
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		run       func(c *Cache, exec Executor)
+		wantCalls int
+	}{
+		{"hit", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			exec.Execute(ctx, userQuery("Alice"), nil)
+		}, 1},
+		{"different values", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			exec.Execute(ctx, userQuery("Bob"), nil)
+		}, 2},
+		{"different vars", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice"), map[string]string{"$a": "1"})
+			exec.Execute(ctx, userQuery("Alice"), map[string]string{"$a": "2"})
+		}, 2},
+		{"debug", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice").WithDebug(), nil)
+			exec.Execute(ctx, userQuery("Alice").WithDebug(), nil)
+		}, 2},
+		{"invalidate read predicate", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			c.Invalidate(ctx, "email")
+			exec.Execute(ctx, userQuery("Alice"), nil)
+		}, 2},
+		{"invalidate other predicate", func(c *Cache, exec Executor) {
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			c.Invalidate(ctx, "age")
+			exec.Execute(ctx, userQuery("Alice"), nil)
+		}, 1},
+		{"eviction", func(c *Cache, exec Executor) {
+			c.MaxEntries = 2
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			exec.Execute(ctx, userQuery("Bob"), nil)
+			exec.Execute(ctx, userQuery("Carol"), nil)
+			exec.Execute(ctx, userQuery("Alice"), nil)
+		}, 4},
+		{"expiry", func(c *Cache, exec Executor) {
+			c.TTL = time.Millisecond
+			exec.Execute(ctx, userQuery("Alice"), nil)
+			time.Sleep(2 * time.Millisecond)
+			exec.Execute(ctx, userQuery("Alice"), nil)
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingExecutor{}
+			c := NewCache(NewMemoryCacheStore(), 0)
+			tt.run(c, Chain(next, c.Middleware()))
+			if next.calls != tt.wantCalls {
+				t.Errorf("executed %d queries, want %d", next.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestCacheBounded checks that the cache does not track more responses than MaxEntries, and
+// prunes expired ones.
+func TestCacheBounded(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(NewMemoryCacheStore(), 0)
+	c.MaxEntries = 10
+	exec := Chain(&countingExecutor{}, c.Middleware())
+	for i := 0; i < 100; i++ {
+		exec.Execute(ctx, userQuery(fmt.Sprint(i)), nil)
+	}
+	if got := c.Len(); got != 10 {
+		t.Errorf("Len() = %d, want 10", got)
+	}
+	c.TTL = time.Millisecond
+	exec.Execute(ctx, userQuery("Alice"), nil)
+	time.Sleep(2 * time.Millisecond)
+	c.Invalidate(ctx, "email")
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() after invalidation = %d, want 0", got)
+	}
+	exec.Execute(ctx, userQuery("Alice"), nil)
+	time.Sleep(2 * time.Millisecond)
+	exec.Execute(ctx, userQuery("Bob"), nil)
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() after expiry = %d, want 1", got)
+	}
+}
+
+// TestCacheScope checks that responses are not shared across tenants when the cache runs
+// before a rewriter depending on the context.
+func TestCacheScope(t *testing.T) {
+	type tenantKey struct{}
+	filter := FilterRewriter(func(ctx context.Context) (any, error) {
+		return Eq("tenant", ctx.Value(tenantKey{}).(string)), nil
+	})
+	acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+	globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+	c := NewCache(NewMemoryCacheStore(), 0)
+	exec := Chain(&countingExecutor{}, c.Middleware(), RewriteMiddleware(filter))
+	if _, err := exec.Execute(acme, userQuery("Alice"), nil); !errors.Is(err, errUnscopedCache) {
+		t.Errorf("Execute() without Scope error = %v, want %v", err, errUnscopedCache)
+	}
+
+	c.Scope = func(ctx context.Context) string { return ctx.Value(tenantKey{}).(string) }
+	a, err := exec.Execute(acme, userQuery("Alice"), nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	g, err := exec.Execute(globex, userQuery("Alice"), nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(a.Json) == string(g.Json) {
+		t.Errorf("tenants share the response %s", a.Json)
+	}
+
+	// After the rewriters, the cache sees the rewritten queries and needs no Scope.
+	inner := NewCache(NewMemoryCacheStore(), 0)
+	exec = Chain(&countingExecutor{}, RewriteMiddleware(filter), inner.Middleware())
+	a, _ = exec.Execute(acme, userQuery("Alice"), nil)
+	g, _ = exec.Execute(globex, userQuery("Alice"), nil)
+	if string(a.Json) == string(g.Json) {
+		t.Errorf("tenants share the response %s", a.Json)
+	}
+}
+
+// TestCacheInvalidateInFlight checks that the response of a query running while the cache is
+// invalidated is not cached, since it may predate the change.
+func TestCacheInvalidateInFlight(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan struct{}), make(chan struct{})
+	calls := 0
+	next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-release
+		}
+		return &Response{Json: []byte(fmt.Sprint(calls))}, nil
+	})
+	c := NewCache(NewMemoryCacheStore(), 0)
+	exec := Chain(next, c.Middleware())
+	done := make(chan *Response)
+	go func() {
+		resp, _ := exec.Execute(ctx, userQuery("Alice"), nil)
+		done <- resp
+	}()
+	<-started
+	c.Invalidate(ctx, "email")
+	close(release)
+	<-done
+	resp, err := exec.Execute(ctx, userQuery("Alice"), nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(resp.Json) != "2" || c.Len() != 1 {
+		t.Errorf("response = %s, Len() = %d, want a new response cached once", resp.Json, c.Len())
+	}
+}
+
+// TestCacheConcurrent runs queries and invalidations concurrently, for the race detector.
+func TestCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(NewMemoryCacheStore(), time.Minute)
+	c.MaxEntries = 8
+	exec := Chain(ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		return &Response{Json: []byte(q.String())}, nil
+	}), c.Middleware())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := exec.Execute(ctx, userQuery(fmt.Sprint(j%16)), nil); err != nil {
+					t.Errorf("Execute() error = %v", err)
+				}
+				if j%10 == i {
+					c.Invalidate(ctx, "name")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Len(); got > 8 {
+		t.Errorf("Len() = %d, want at most 8", got)
+	}
+}
@@ -0,0 +1,108 @@
+package dql
+
+import "sort"
+
+// WithCanonical enables the canonical rendering of the query.
+//
+// In canonical mode, String and PrettyPrint sort the parts of the query whose order does not
+// affect its semantics: parameters by name, the attributes of every selection set, the
+// directives of every block and attribute, and the arguments of blocks and attributes, the
+// root function staying first and orderasc and orderdesc keeping their relative order, since
+// it sets the primary and secondary sort keys. Queries built in different orders then render
+// byte for byte the same, e.g. for cache keys and golden tests. The query itself is left
+// untouched; raw blocks and attributes are rendered as they are.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Has("user")).
+//	    WithAttributes(NewAttribute("name"), NewAttribute("age"))).WithCanonical()
+//	fmt.Println(query.String()) // Output: { me (func: has(user)) { age name } }
+func (q *Query) WithCanonical() *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	q.Canonical = true
+	return q
+}
+
+// canonical returns a copy of the query with the parts whose order does not matter sorted,
+// see WithCanonical.
+func (q *Query) canonical() *Query {
+	res := q.Clone()
+	res.Canonical = false
+	sort.SliceStable(res.Params, func(i, j int) bool {
+		return res.Params[i].Ref() < res.Params[j].Ref()
+	})
+	// Arguments and directives are sorted first, since the order of attributes depends on
+	// them.
+	Walk(res, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Raw {
+				return false
+			}
+			n.Criteria = canonicalArgs(n.Criteria)
+			sortDirectives(n.Directives)
+		case *QueryBlock:
+			if n.Raw {
+				return false
+			}
+			n.Criteria = canonicalArgs(n.Criteria)
+			sortDirectives(n.Directives)
+		case *Attribute:
+			if n.Raw {
+				return false
+			}
+			n.Args = sortedCriteria(n.Args)
+			sortDirectives(n.Directives)
+		}
+		return true
+	})
+	Walk(res, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			sortAttributes(n.Attributes)
+			return !n.Raw
+		case *QueryBlock:
+			sortAttributes(n.Attributes)
+			return !n.Raw
+		case *ShortestPath:
+			sortAttributes(n.Attributes)
+		case *Fragment:
+			sortAttributes(n.Attributes)
+		case *Attribute:
+			sortAttributes(n.Attributes)
+			return !n.Raw
+		}
+		return true
+	})
+	return res
+}
+
+// canonicalArgs sorts the arguments of a block, keeping the root function first.
+func canonicalArgs(criteria []Criteria) []Criteria {
+	if len(criteria) == 0 || !isRootFunction(criteria[0]) {
+		return sortedCriteria(criteria)
+	}
+	return append([]Criteria{criteria[0]}, sortedCriteria(criteria[1:])...)
+}
+
+// sortDirectives sorts directives by their rendering.
+func sortDirectives(directives []Criteria) {
+	sort.SliceStable(directives, func(i, j int) bool {
+		return directives[i].String() < directives[j].String()
+	})
+}
+
+// sortAttributes sorts attributes by their canonical rendering, which does not depend on the
+// order of their nested attributes.
+func sortAttributes(attrs []*Attribute) {
+	keys := map[*Attribute]string{}
+	for _, a := range attrs {
+		keys[a] = canonicalAttribute(a)
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		return keys[attrs[i]] < keys[attrs[j]]
+	})
+}
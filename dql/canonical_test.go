@@ -0,0 +1,44 @@
+package dql
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	a := NewQuery("Q", NewQueryBlock("me", Has("user")).
+		WithFirst(5).WithOrderAsc("name").WithOrderDesc("age").
+		WithDirectives("@cascade", NewDirective("filter", Has("email"))).
+		WithAttributes(NewAttribute("name"), NewAttribute("friend").WithAttributes(NewAttribute("uid"), NewAttribute("age")))).
+		WithParam(NewParam("b", ParamInt).WithDefault("1"), NewParam("a", ParamString).WithDefault("x"))
+	b := NewQuery("Q", NewQueryBlock("me", Has("user")).
+		WithOrderAsc("name").WithOrderDesc("age").WithFirst(5).
+		WithDirectives(NewDirective("filter", Has("email")), "@cascade").
+		WithAttributes(NewAttribute("friend").WithAttributes(NewAttribute("age"), NewAttribute("uid")), NewAttribute("name"))).
+		WithParam(NewParam("a", ParamString).WithDefault("x"), NewParam("b", ParamInt).WithDefault("1"))
+	if a.String() == b.String() {
+		t.Fatalf("String() of differently built queries is already equal")
+	}
+	before := a.String()
+	a.WithCanonical()
+	b.WithCanonical()
+	if a.String() != b.String() {
+		t.Errorf("canonical String() = %s and %s, want the same text", a.String(), b.String())
+	}
+	want := `query Q ( $a: string = "x", $b: int = 1 ) { me (func: has(user), first: 5, orderasc: name, orderdesc: age) @cascade @filter(has(email)) { friend { age uid } name } }`
+	if got := a.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if a.QueryBlocks[0].Attributes[0].Name != "name" {
+		t.Errorf("WithCanonical() reordered the query itself")
+	}
+	if c := a.Clone(); !c.Canonical {
+		t.Errorf("Clone() lost canonical mode")
+	}
+	a.Canonical = false
+	if a.String() != before {
+		t.Errorf("String() without canonical mode = %s, want %s", a.String(), before)
+	}
+
+	order := NewQuery("", NewQueryBlock("me", Has("user")).WithOrderDesc("age").WithOrderAsc("name")).WithCanonical()
+	if got, want := order.String(), "{ me (func: has(user), orderdesc: age, orderasc: name) { } }"; got != want {
+		t.Errorf("String() = %s, want the sort keys in their order %s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package dql
+
+// Cascade creates a @cascade directive, removing the nodes of a block or an edge that lack
+// some of the selected predicates.
+//
+// Without fields, nodes must have every predicate selected below the directive. With fields,
+// only the listed predicates are required, e.g. @cascade(name, email).
+//
+// Parameters:
+//   - fields: The predicates the nodes must have, or none to require every selected predicate.
+//
+// Returns:
+//   - A pointer to a Directive object.
+//
+// Example:
+//
+//	attr := NewAttribute("friend").WithDirectives(Cascade("email")).
+//	    WithAttributes(NewAttribute("name"), NewAttribute("email"))
+//	fmt.Println(attr.String()) // Output: friend @cascade(email) { name email }
+//
+// See: https://dgraph.io/docs/query-language/cascade-directive/
+func Cascade(fields ...string) *Directive {
+	d := NewDirective("cascade")
+	for _, f := range fields {
+		d.Args = append(d.Args, Raw(Predicate(f)))
+	}
+	return d
+}
+
+// WithCascade sets the @cascade directive of the query block, replacing an existing one.
+//
+// Pagination arguments are rendered before the directive, within the parentheses of the
+// block, and the directive keeps its place among the other directives when replaced.
+//
+// Parameters:
+//   - fields: The predicates the nodes must have, or none to require every selected predicate.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", Has("user")).WithFirst(10).WithCascade("email")
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user), first: 10) @cascade(email) { }
+func (qb *QueryBlock) WithCascade(fields ...string) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Directives = withCascade(qb.Directives, fields)
+	return qb
+}
+
+// WithCascade sets the @cascade directive of the variable block, replacing an existing one.
+//
+// Parameters:
+//   - fields: The predicates the nodes must have, or none to require every selected predicate.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithCascade(fields ...string) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
+	vb.Directives = withCascade(vb.Directives, fields)
+	return vb
+}
+
+// WithCascade sets the @cascade directive of the attribute, replacing an existing one, so
+// the nodes the edge leads to are filtered without affecting the nodes of the parent.
+//
+// Parameters:
+//   - fields: The predicates the nodes must have, or none to require every selected predicate.
+//
+// Returns:
+//   - The updated Attribute object.
+//
+// Example:
+//
+//	attr := NewAttribute("friend").WithFirst(5).WithCascade().
+//	    WithAttributes(NewAttribute("name"))
+//	fmt.Println(attr.String()) // Output: friend (first: 5) @cascade { name }
+func (a *Attribute) WithCascade(fields ...string) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Directives = withCascade(a.Directives, fields)
+	return a
+}
+
+// withCascade replaces the @cascade directive of directives, or appends one.
+func withCascade(directives []Criteria, fields []string) []Criteria {
+	d := Cascade(fields...)
+	for i, existing := range directives {
+		if directiveName(existing) == "cascade" {
+			directives[i] = d
+			return directives
+		}
+	}
+	return append(directives, d)
+}
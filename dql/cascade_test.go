@@ -0,0 +1,29 @@
+package dql
+
+import "testing"
+
+func TestCascade(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"all predicates", Cascade().String(), "@cascade"},
+		{"some predicates", Cascade("name", "first name").String(), "@cascade(name, <first%20name>)"},
+		{"query block", NewQueryBlock("me", Has("user")).WithFirst(10).WithCascade("email").WithAttributes(NewAttribute("email")).String(),
+			"me (func: has(user), first: 10) @cascade(email) { email }"},
+		{"replaces an existing cascade", NewQueryBlock("me", Has("user")).WithDirectives("@normalize").WithCascade().WithCascade("name").String(),
+			"me (func: has(user)) @normalize @cascade(name) { }"},
+		{"var block", NewVarBlock(Has("user")).WithName("u").WithCascade().String(),
+			"u AS var (func: has(user)) @cascade { }"},
+		{"attribute", NewAttribute("friend").WithFirst(5).WithCascade("name").WithAttributes(NewAttribute("name")).String(),
+			"friend (first: 5) @cascade(name) { name }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("String() = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package dql
+
+// Clone creates a deep copy of the query.
+//
+// The copy can be modified without affecting the original query, even if the original is
+// frozen. The fragments spread with Spread are copied as well, once each, and the spreads of
+// the copy point to these copies. Criteria values are shared between both queries, since they
+// are not modified by the builders, and so is the Schema.
+//
+// Returns:
+//   - A pointer to the copied Query object.
+func (q *Query) Clone() *Query {
+	res := &Query{Name: q.Name, Strict: q.Strict, Debug: q.Debug, Canonical: q.Canonical, Schema: q.Schema}
+	if q.Limits != nil {
+		limits := *q.Limits
+		res.Limits = &limits
+	}
+	for _, p := range q.Params {
+		res.Params = append(res.Params, p.Clone())
+	}
+	for _, vb := range q.VarBlocks {
+		res.VarBlocks = append(res.VarBlocks, vb.Clone())
+	}
+	for _, sp := range q.ShortestPaths {
+		res.ShortestPaths = append(res.ShortestPaths, sp.Clone())
+	}
+	for _, qb := range q.QueryBlocks {
+		res.QueryBlocks = append(res.QueryBlocks, qb.Clone())
+	}
+	copies := map[*Fragment]*Fragment{}
+	for _, f := range q.Fragments {
+		c := f.Clone()
+		copies[f] = c
+		res.Fragments = append(res.Fragments, c)
+	}
+	for _, vb := range res.VarBlocks {
+		relinkSpreads(vb.Attributes, copies)
+	}
+	for _, sp := range res.ShortestPaths {
+		relinkSpreads(sp.Attributes, copies)
+	}
+	for _, qb := range res.QueryBlocks {
+		relinkSpreads(qb.Attributes, copies)
+	}
+	for _, f := range res.Fragments {
+		relinkSpreads(f.Attributes, copies)
+	}
+	return res
+}
+
+// relinkSpreads points the spreads among attrs to copies of their fragments, copying each
+// fragment the first time it is met. copies maps the original fragments to their copies.
+func relinkSpreads(attrs []*Attribute, copies map[*Fragment]*Fragment) {
+	for _, a := range attrs {
+		if a.fragment != nil {
+			c, ok := copies[a.fragment]
+			if !ok {
+				c = a.fragment.Clone()
+				copies[a.fragment] = c
+				relinkSpreads(c.Attributes, copies)
+			}
+			a.fragment = c
+		}
+		relinkSpreads(a.Attributes, copies)
+	}
+}
+
+// Clone creates a copy of the parameter.
+//
+// Returns:
+//   - A pointer to the copied Param object.
+func (p *Param) Clone() *Param {
+	res := *p
+	res.frozen = false
+	return &res
+}
+
+// Clone creates a deep copy of the variable block.
+//
+// Returns:
+//   - A pointer to the copied VarBlock object.
+func (vb *VarBlock) Clone() *VarBlock {
+	res := *vb
+	res.frozen = false
+	res.Criteria = append([]Criteria(nil), vb.Criteria...)
+	res.Directives = append([]Criteria(nil), vb.Directives...)
+	res.Attributes = cloneAttributes(vb.Attributes)
+	return &res
+}
+
+// Clone creates a deep copy of the shortest path block.
+//
+// Returns:
+//   - A pointer to the copied ShortestPath object.
+func (sp *ShortestPath) Clone() *ShortestPath {
+	res := *sp
+	res.frozen = false
+	res.Attributes = cloneAttributes(sp.Attributes)
+	return &res
+}
+
+// Clone creates a deep copy of the query block.
+//
+// Returns:
+//   - A pointer to the copied QueryBlock object.
+func (qb *QueryBlock) Clone() *QueryBlock {
+	res := *qb
+	res.frozen = false
+	res.Criteria = append([]Criteria(nil), qb.Criteria...)
+	res.Directives = append([]Criteria(nil), qb.Directives...)
+	res.Attributes = cloneAttributes(qb.Attributes)
+	return &res
+}
+
+// Clone creates a deep copy of the fragment.
+//
+// Returns:
+//   - A pointer to the copied Fragment object.
+func (f *Fragment) Clone() *Fragment {
+	res := *f
+	res.frozen = false
+	res.Attributes = cloneAttributes(f.Attributes)
+	return &res
+}
+
+// Clone creates a deep copy of the attribute and its nested attributes.
+//
+// Returns:
+//   - A pointer to the copied Attribute object.
+func (a *Attribute) Clone() *Attribute {
+	res := *a
+	res.frozen = false
+	res.Args = append([]Criteria(nil), a.Args...)
+	res.Directives = append([]Criteria(nil), a.Directives...)
+	res.Attributes = cloneAttributes(a.Attributes)
+	return &res
+}
+
+func cloneAttributes(attrs []*Attribute) []*Attribute {
+	if attrs == nil {
+		return nil
+	}
+	res := make([]*Attribute, len(attrs))
+	for i, a := range attrs {
+		res[i] = a.Clone()
+	}
+	return res
+}
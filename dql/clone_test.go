@@ -0,0 +1,125 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+// newSharedQuery builds a query with every kind of declaration, and a fragment spread without
+// being declared.
+func newSharedQuery() *Query {
+	userFields := NewFragment("userFields").WithAttributes(NewAttribute("name"))
+	return NewQuery("Q", NewQueryBlock("me", Has("user")).
+		WithCriteria("first: 10").
+		WithDirectives("@filter(has(email))").
+		WithAttributes(NewAttribute("friend").WithAttributes(Spread(userFields)))).
+		WithParam(NewParam("$name", "string")).
+		WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends")).
+		WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("friend"))).
+		WithFragments(NewFragment("postFields").WithAttributes(NewAttribute("title")))
+}
+
+// TestCloneIsolation checks that modifying a copy leaves the original query unchanged.
+func TestCloneIsolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(q *Query)
+	}{
+		{"query block", func(q *Query) { q.QueryBlocks[0].WithAttributes(NewAttribute("secret")) }},
+		{"criteria", func(q *Query) { q.QueryBlocks[0].Criteria[0] = Has("secret") }},
+		{"directives", func(q *Query) { q.QueryBlocks[0].Directives[0] = Raw("@cascade") }},
+		{"nested attribute", func(q *Query) { q.QueryBlocks[0].Attributes[0].WithAttributes(NewAttribute("secret")) }},
+		{"spread fragment", func(q *Query) {
+			q.QueryBlocks[0].Attributes[0].Attributes[0].fragment.WithAttributes(NewAttribute("secret"))
+		}},
+		{"var block", func(q *Query) { q.VarBlocks[0].WithAttributes(NewAttribute("secret")) }},
+		{"shortest path", func(q *Query) { q.ShortestPaths[0].WithAttributes(NewAttribute("secret")) }},
+		{"fragment", func(q *Query) { q.Fragments[0].WithAttributes(NewAttribute("secret")) }},
+		{"param", func(q *Query) { q.Params[0].WithDefault("secret") }},
+		{"filter rewriter", func(q *Query) {
+			rewrite := FilterRewriter(func(ctx context.Context) (any, error) { return Eq("tenant", "acme"), nil })
+			if _, err := rewrite(context.Background(), q); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newSharedQuery()
+			want := q.String()
+			c := q.Clone()
+			tt.modify(c)
+			if got := q.String(); got != want {
+				t.Errorf("original = %s, want %s", got, want)
+			}
+			if c.String() == want {
+				t.Errorf("copy = %s, want a modified query", c)
+			}
+		})
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(q *Query)
+	}{
+		{"query", func(q *Query) { q.WithDebug() }},
+		{"query block", func(q *Query) { q.QueryBlocks[0].WithFirst(1) }},
+		{"attribute", func(q *Query) { q.QueryBlocks[0].Attributes[0].WithAlias("f") }},
+		{"spread fragment", func(q *Query) {
+			q.QueryBlocks[0].Attributes[0].Attributes[0].fragment.WithAttributes(NewAttribute("secret"))
+		}},
+		{"var block", func(q *Query) { q.VarBlocks[0].WithName("other") }},
+		{"shortest path", func(q *Query) { q.ShortestPaths[0].WithDepth(3) }},
+		{"fragment", func(q *Query) { q.Fragments[0].WithAttributes(NewAttribute("secret")) }},
+		{"param", func(q *Query) { q.Params[0].WithDefault("x") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newSharedQuery().Freeze()
+			if !q.Frozen() {
+				t.Fatal("Frozen() = false after Freeze()")
+			}
+			defer func() {
+				if recover() == nil {
+					t.Errorf("modification of a frozen query did not panic")
+				}
+			}()
+			tt.modify(q)
+		})
+	}
+}
+
+// TestFreezeSharedFragment checks that freezing a query leaves the fragments it spreads
+// without declaring them, which other queries may share, modifiable.
+func TestFreezeSharedFragment(t *testing.T) {
+	shared := NewFragment("userFields").WithAttributes(NewAttribute("name"))
+	q := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(Spread(shared))).Freeze()
+	other := NewQuery("", NewQueryBlock("users", Has("user")).WithAttributes(Spread(shared)))
+	want := q.String()
+	shared.WithAttributes(NewAttribute("email"))
+	if got := q.String(); got != want {
+		t.Errorf("frozen query = %s, want %s", got, want)
+	}
+	if got := other.String(); got != "{ users (func: has(user)) { ...userFields } } fragment userFields { name email }" {
+		t.Errorf("other query = %s", got)
+	}
+	if q.QueryBlocks[0].Attributes[0].fragment == shared || !q.QueryBlocks[0].Attributes[0].fragment.frozen {
+		t.Errorf("frozen query does not hold a frozen copy of the fragment")
+	}
+}
+
+func TestFreezeClone(t *testing.T) {
+	q := newSharedQuery().Freeze()
+	c := q.Clone()
+	if c.Frozen() {
+		t.Fatal("Frozen() = true for a copy")
+	}
+	c.QueryBlocks[0].WithFirst(1)
+	c.Fragments[0].WithAttributes(NewAttribute("email"))
+	c.QueryBlocks[0].Attributes[0].Attributes[0].fragment.WithAttributes(NewAttribute("email"))
+	if q.String() == c.String() {
+		t.Errorf("copy of a frozen query was not modified: %s", c)
+	}
+}
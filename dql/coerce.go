@@ -0,0 +1,242 @@
+package dql
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// comparisonFunctions lists the functions comparing a predicate with literal values.
+var comparisonFunctions = map[string]bool{"eq": true, "le": true, "lt": true, "ge": true, "gt": true, "between": true}
+
+// dateLayouts lists the layouts of the datetime strings Dgraph accepts, from the most precise.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02", "2006-01", "2006"}
+
+// WithSchema types the literal values of the query with a schema.
+//
+// The values compared with a predicate by eq, le, lt, ge, gt and between must then suit the
+// type the schema declares for it: Validate reports the values Dgraph would reject or
+// misinterpret, such as a string compared with an int predicate, and Coerce converts the
+// others, e.g. formatting date strings for datetime predicates. Predicates the schema does not
+// define and expressions such as count(friend) are not checked. Parameters are checked by
+// their declared type instead: Validate reports an int, float or bool parameter compared with
+// a predicate of another type, while string parameters are converted by Dgraph and accepted.
+// Validate also reports language tags, such as the @en of age@en, on predicates that are not
+// strings.
+//
+// Parameters:
+//   - schema: The schema of the database.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	schema, _ := ParseSchema(`age: int .`)
+//	query := NewQuery("", NewQueryBlock("adults", Ge("age", "18"))).WithSchema(schema)
+//	fmt.Println(query.Validate()) // Output: dql: query block "adults": predicate "age": "18" is not an int
+func (q *Query) WithSchema(schema *Schema) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	q.Schema = schema
+	return q
+}
+
+// Coerce converts the literal values of the query to the types its schema declares for their
+// predicates, see WithSchema.
+//
+// Values are converted where the conversion is lossless: numbers and booleans compared with a
+// string predicate become strings, strings compared with a bool predicate become booleans, and
+// times and date strings compared with a datetime predicate become RFC 3339 strings in UTC.
+// The query is left untouched; a coerced copy is returned.
+//
+// Returns:
+//   - A pointer to the coerced copy of the query.
+//   - An error if a value cannot be converted to the type of its predicate.
+//
+// Example:
+//
+//	schema, _ := ParseSchema(`created_at: datetime .`)
+//	query := NewQuery("", NewQueryBlock("recent", Ge("created_at", "2024-01-02"))).WithSchema(schema)
+//	coerced, err := query.Coerce()
+//	fmt.Println(coerced.String()) // Output: { recent (func: ge(created_at, "2024-01-02T00:00:00Z")) { } }
+func (q *Query) Coerce() (*Query, error) {
+	res := q.Clone()
+	if res.Schema == nil {
+		return res, nil
+	}
+	var err error
+	for _, list := range criteriaLists(res) {
+		for i, c := range list {
+			if directiveName(c) == "facets" {
+				continue
+			}
+			list[i] = mapCriteria(c, func(c Criteria) Criteria {
+				f, ok := c.(*Function)
+				if !ok || err != nil {
+					return c
+				}
+				var coerced *Function
+				coerced, err = res.Schema.coerceFunction(f)
+				return coerced
+			})
+		}
+	}
+	if err != nil {
+		return nil, wrapError(ErrSchemaMismatch, fmt.Errorf("dql: coerce: %w", err))
+	}
+	return res, nil
+}
+
+// validateValues reports the first literal value of the criteria lists of a node that does
+// not suit the type of its predicate, and language tags on predicates that are not strings.
+func (s *Schema) validateValues(n Node) error {
+	if err := s.validateLangs(n); err != nil {
+		return err
+	}
+	var err error
+	for _, list := range criteriaLists(n) {
+		for _, c := range list {
+			if directiveName(c) == "facets" {
+				continue
+			}
+			walkCriteria(c, func(c Criteria) {
+				if f, ok := c.(*Function); ok && err == nil {
+					_, err = s.coerceFunction(f)
+				}
+			})
+		}
+	}
+	return err
+}
+
+// coerceFunction returns a copy of a comparison function with its literal values coerced to
+// the type of its predicate. Other functions are returned as is.
+func (s *Schema) coerceFunction(f *Function) (*Function, error) {
+	if !comparisonFunctions[f.Name] || len(f.Args) < 2 || isExpression(f.Args[0].String()) {
+		return f, nil
+	}
+	p := s.Predicate(predicateOf(f.Args[0].String()))
+	if p == nil {
+		return f, nil
+	}
+	args := append([]Criteria{}, f.Args...)
+	for i, arg := range args[1:] {
+		coerced, err := coerceArg(p, arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i+1] = coerced
+	}
+	return &Function{Name: f.Name, Args: args}, nil
+}
+
+// coerceArg coerces a literal value, or the literal values of a list, to the type of p.
+func coerceArg(p *SchemaPredicate, arg Criteria) (Criteria, error) {
+	switch arg := arg.(type) {
+	case Literal:
+		v, err := coerceValue(p, arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		return Literal{v}, nil
+	case List:
+		res := make(List, len(arg))
+		for i, e := range arg {
+			var err error
+			if res[i], err = coerceArg(p, e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	return arg, nil
+}
+
+// coerceValue converts a Go value to the type of p, see Query.Coerce.
+func coerceValue(p *SchemaPredicate, v any) (any, error) {
+	switch p.Type {
+	case "int":
+		return v, checkInt(p, v)
+	case "float", "bigfloat":
+		switch v.(type) {
+		case string, bool, time.Time:
+			return nil, typeError(p, v)
+		}
+	case "string", "password":
+		switch v := v.(type) {
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, *big.Int, *big.Float, decimal:
+			return strings.Trim(SafeValue(v), `"`), nil
+		case time.Time:
+			return v.UTC().Format(time.RFC3339Nano), nil
+		}
+	case "bool":
+		switch v := v.(type) {
+		case bool:
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, typeError(p, v)
+			}
+			return b, nil
+		default:
+			return nil, typeError(p, v)
+		}
+	case "datetime":
+		switch v := v.(type) {
+		case time.Time:
+			return v.UTC().Round(0), nil
+		case string:
+			for _, layout := range dateLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t.UTC(), nil
+				}
+			}
+			return nil, typeError(p, v)
+		default:
+			return nil, typeError(p, v)
+		}
+	}
+	return v, nil
+}
+
+// checkInt reports a value that does not fit an int predicate.
+func checkInt(p *SchemaPredicate, v any) error {
+	var n *big.Int
+	switch v := v.(type) {
+	case *big.Int:
+		n = v
+	case decimal:
+		if strings.ContainsAny(string(v), ".eE") {
+			return typeError(p, v)
+		}
+		n, _ = new(big.Int).SetString(string(v), 10)
+	case *big.Float:
+		if !v.IsInt() {
+			return typeError(p, v)
+		}
+		n, _ = v.Int(nil)
+	case uint:
+		n = new(big.Int).SetUint64(uint64(v))
+	case uint64:
+		n = new(big.Int).SetUint64(v)
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return nil
+	default:
+		return typeError(p, v)
+	}
+	if !n.IsInt64() {
+		return fmt.Errorf("predicate %q: %s overflows an int", p.Name, n)
+	}
+	return nil
+}
+
+// typeError reports a value that does not suit the type of p.
+func typeError(p *SchemaPredicate, v any) error {
+	article := "a"
+	if strings.ContainsAny(p.Type[:1], "aeiou") {
+		article = "an"
+	}
+	return fmt.Errorf("predicate %q: %s is not %s %s", p.Name, SafeValue(v), article, p.Type)
+}
@@ -0,0 +1,64 @@
+package dql
+
+import "testing"
+
+func TestCoerce(t *testing.T) {
+	schema, err := ParseSchema("age: int .\nname: string .\nactive: bool .\ncreated: datetime .\nscore: float .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		filter  *Function
+		want    string
+		wantErr string
+	}{
+		{"int", Eq("age", 30), `eq(age, 30)`, ""},
+		{"number as string", Eq("name", 42), `eq(name, "42")`, ""},
+		{"bool from string", Eq("active", "true"), `eq(active, true)`, ""},
+		{"date", Ge("created", "2024-01-02"), `ge(created, "2024-01-02T00:00:00Z")`, ""},
+		{"between", Between("created", "2024", "2025-06"), `between(created, "2024-01-01T00:00:00Z", "2025-06-01T00:00:00Z")`, ""},
+		{"list", Eq("name", 1, 2), `eq(name, ["1", "2"])`, ""},
+		{"undeclared predicate", Eq("nick", 1), `eq(nick, 1)`, ""},
+		{"string for int", Eq("age", "thirty"), "", `dql: coerce: predicate "age": "thirty" is not an int`},
+		{"bad bool", Eq("active", "maybe"), "", `dql: coerce: predicate "active": "maybe" is not a bool`},
+		{"bad date", Ge("created", "yesterday"), "", `dql: coerce: predicate "created": "yesterday" is not a datetime`},
+		{"string for float", Gt("score", "high"), "", `dql: coerce: predicate "score": "high" is not a float`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery("", NewQueryBlockOpt("me", Func(Has("name")), Filter(tt.filter))).WithSchema(schema)
+			before := q.String()
+			got, err := q.Coerce()
+			if errString(err) != tt.wantErr {
+				t.Fatalf("Coerce() error = %q, want %q", errString(err), tt.wantErr)
+			}
+			if q.String() != before {
+				t.Errorf("Coerce() modified the query: %s", q.String())
+			}
+			if err != nil {
+				return
+			}
+			if want := "{ me (func: has(name)) @filter(" + tt.want + ") { } }"; got.String() != want {
+				t.Errorf("Coerce() = %s, want %s", got.String(), want)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaValues(t *testing.T) {
+	schema, err := ParseSchema("age: int .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewQuery("", NewQueryBlock("me", Eq("age", "old"))).WithSchema(schema)
+	if got, want := errString(q.Validate()), `dql: query block "me": predicate "age": "old" is not an int`; got != want {
+		t.Errorf("Validate() error = %q, want %q", got, want)
+	}
+	if err := NewQuery("", NewQueryBlock("me", Eq("age", "old"))).Validate(); err != nil {
+		t.Errorf("Validate() without a schema error = %v", err)
+	}
+}
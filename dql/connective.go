@@ -0,0 +1,87 @@
+package dql
+
+import "strings"
+
+// Connective combines filters with a logical operator, such as
+// has(email) AND eq(tenant, "acme").
+//
+// Operands that are connectives or raw DQL are wrapped in parentheses, so the operators they
+// contain keep their precedence. The operands stay nodes, so literals and parameters in them
+// are still found by strict mode, Parameterize and Redacted.
+type Connective struct {
+	// Op is the logical operator, AND or OR.
+	Op string
+
+	// Args is the list of combined filters.
+	Args []Criteria
+}
+
+// And combines filters with AND.
+//
+// Parameters:
+//   - filters: The filters, either Criteria such as Has("email") or strings.
+//
+// Returns:
+//   - A pointer to a Connective object.
+//
+// Example:
+//
+//	filter := And(Has("email"), Eq("tenant", "acme"))
+//	fmt.Println(filter.String()) // Output: has(email) AND eq(tenant, "acme")
+//
+// See: https://dgraph.io/docs/query-language/connecting-filters/
+func And(filters ...any) *Connective {
+	return newConnective("AND", filters)
+}
+
+// Or combines filters with OR.
+//
+// Parameters:
+//   - filters: The filters, either Criteria such as Has("email") or strings.
+//
+// Returns:
+//   - A pointer to a Connective object.
+//
+// Example:
+//
+//	filter := Or(Has("email"), Has("phone"))
+//	fmt.Println(filter.String()) // Output: has(email) OR has(phone)
+//
+// See: https://dgraph.io/docs/query-language/connecting-filters/
+func Or(filters ...any) *Connective {
+	return newConnective("OR", filters)
+}
+
+// newConnective creates a Connective combining filters with op.
+func newConnective(op string, filters []any) *Connective {
+	c := &Connective{Op: op}
+	for _, f := range filters {
+		c.Args = append(c.Args, toCriteria(f))
+	}
+	return c
+}
+
+// String generates the DQL representation of the combined filters.
+//
+// Returns:
+//   - The operands separated by the operator.
+func (c *Connective) String() string {
+	parts := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		switch arg.(type) {
+		case *Connective, Raw:
+			parts[i] = "(" + strings.TrimSpace(arg.String()) + ")"
+		default:
+			parts[i] = arg.String()
+		}
+	}
+	return strings.Join(parts, " "+c.Op+" ")
+}
+
+func (c *Connective) args() []Criteria {
+	return c.Args
+}
+
+func (c *Connective) withArgs(args []Criteria) Criteria {
+	return &Connective{Op: c.Op, Args: args}
+}
@@ -0,0 +1,24 @@
+package dql
+
+import "testing"
+
+func TestConnective(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *Connective
+		want string
+	}{
+		{"and", And(Has("email"), Eq("tenant", "acme")), `has(email) AND eq(tenant, "acme")`},
+		{"or", Or(Has("email"), Has("phone")), "has(email) OR has(phone)"},
+		{"nested", And(Has("user"), Or(Has("email"), Has("phone"))), "has(user) AND (has(email) OR has(phone))"},
+		{"raw", And("has(email) OR has(phone)", Has("user")), "(has(email) OR has(phone)) AND has(user)"},
+		{"param", And(Eq("name", ParamRef("$name")), Has("user")), "eq(name, $name) AND has(user)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package dql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// countPattern matches the count(predicate) and len(variable) expressions of a function.
+	countPattern = regexp.MustCompile(`\b(count|len)\(\s*([^()]*?)\s*\)`)
+
+	// comparisonPrefixPattern matches the text preceding the first argument of a comparison
+	// function, the only place count() and len() are accepted in functions.
+	comparisonPrefixPattern = regexp.MustCompile(`\b(eq|le|lt|ge|gt|between)\(\s*$`)
+)
+
+// Count creates a count(predicate) expression, the number of edges of a predicate, to compare
+// in functions such as ge(count(friend), 3).
+//
+// Dgraph only accepts count() as the first argument of eq, le, lt, ge, gt and between. Used in
+// the root function of a block, it requires the @count index on the predicate.
+//
+// Parameters:
+//   - predicate: The predicate whose edges are counted, optionally reversed with ~.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	block := NewQueryBlock("popular", Has("name")).
+//	    WithDirectives(NewDirective("filter", Ge(Count("friend"), 3))).
+//	    WithAttributes(NewAttribute("name"))
+//	fmt.Println(block.String()) // Output: popular (func: has(name)) @filter(ge(count(friend), 3)) { name }
+//
+// See: https://dgraph.io/docs/query-language/count/
+func Count(predicate string) *Function {
+	return NewFunction("count", Raw(Predicate(predicate)))
+}
+
+// Len creates a len(variable) expression, the number of uids of a uid variable, to compare in
+// filters such as gt(len(friends), 0).
+//
+// Dgraph only accepts len() as the first argument of eq, le, lt, ge, gt and between, within
+// @filter directives. Names that are not valid variable names are rendered as quoted strings,
+// so they cannot inject DQL.
+//
+// Parameters:
+//   - variable: The name of the uid variable.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Gt(Len("friends"), 0).String()) // Output: gt(len(friends), 0)
+//
+// See: https://dgraph.io/docs/query-language/functions/#equal-to
+func Len(variable string) *Function {
+	if !namePattern.MatchString(variable) {
+		return NewFunction("len", Literal{variable})
+	}
+	return NewFunction("len", Raw(variable))
+}
+
+// subject converts the first argument of a comparison function into Criteria: predicate
+// names are escaped with Predicate, and expressions such as Count and Len are kept as-is.
+func subject(predicate any) Criteria {
+	if s, ok := predicate.(string); ok {
+		return Raw(Predicate(s))
+	}
+	return toCriteria(predicate)
+}
+
+// validateCounts checks the count() and len() expressions of the root function and
+// directives of a block or an attribute.
+//
+// Both are only accepted as the first argument of a comparison function. len() is further
+// restricted to @filter directives, count(uid) is only an aggregation of a selection set and
+// neither can be used to filter facets. Named arguments such as orderasc are not checked.
+func validateCounts(criteria []Criteria) error {
+	for _, c := range criteria {
+		name := directiveName(c)
+		switch {
+		case name == "filter", name == "facets":
+		case name != "", !isRootFunction(c) && argName(c) != "func":
+			continue
+		}
+		s := c.String()
+		for _, m := range countPattern.FindAllStringSubmatchIndex(s, -1) {
+			fn, arg := s[m[2]:m[3]], s[m[4]:m[5]]
+			expr := fn + "(" + arg + ")"
+			switch {
+			case name == "facets":
+				return fmt.Errorf("%s is not allowed in @facets", expr)
+			case !comparisonPrefixPattern.MatchString(s[:m[0]]):
+				return fmt.Errorf("%s is only allowed as the first argument of eq, le, lt, ge, gt and between", expr)
+			case fn == "len" && name != "filter":
+				return fmt.Errorf("%s is only allowed in @filter", expr)
+			case fn == "count" && strings.EqualFold(arg, "uid"):
+				return fmt.Errorf("%s is only allowed in selection sets", expr)
+			}
+		}
+	}
+	return nil
+}
+
+// CountOnly turns the block into a count of the nodes it matches, by replacing its selection
+// set with count(uid), since counting matches is among the most common queries.
+//
+// Dgraph returns the count as the single object of the block, e.g. {"me": [{"count": 42}]}.
+// DecodeCount reads it, DecodeMap returns it as an int64 rather than a list, and Decode
+// decodes it into integer fields directly. Blocks are recognized by their selection set, see
+// IsCountOnly, so parsed and cloned blocks are decoded as counts as well.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("total", Has("user")).
+//	    WithAttributes(NewAttribute("name")).
+//	    CountOnly()
+//	fmt.Println(queryBlock.String()) // Output: total (func: has(user)) { count(uid) }
+//
+// See: https://dgraph.io/docs/query-language/count/
+func (qb *QueryBlock) CountOnly() *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Attributes = []*Attribute{NewAttribute("count(uid)")}
+	return qb
+}
+
+// IsCountOnly reports whether the block only counts the nodes it matches, i.e. whether its
+// selection set is a single unaliased count(uid), see CountOnly.
+//
+// Returns:
+//   - True if the block is a count.
+func (qb *QueryBlock) IsCountOnly() bool {
+	if qb.Raw || len(qb.Attributes) != 1 {
+		return false
+	}
+	a := qb.Attributes[0]
+	return !a.Raw && a.Alias == "" && a.Var == "" && len(a.Attributes) == 0 && strings.ReplaceAll(a.Name, " ", "") == "count(uid)"
+}
+
+// DecodeCount reads the result of a count-only block, see QueryBlock.CountOnly, from the JSON
+// data of a response.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//   - block: The name of the block.
+//
+// Returns:
+//   - The number of nodes the block matched.
+//   - An error if the data holds no count for the block.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("total", Has("user")).CountOnly())
+//	resp, err := exec.Execute(ctx, query, nil)
+//	n, err := DecodeCount(resp.Json, "total")
+func DecodeCount(data []byte, block string) (int64, error) {
+	var root map[string]any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&root); err != nil {
+		return 0, fmt.Errorf("dql: decode: %w", err)
+	}
+	raw, ok := root[block]
+	if !ok {
+		return 0, fmt.Errorf("dql: decode: no result for block %q", block)
+	}
+	n, err := countResult(raw)
+	if err != nil {
+		return 0, fmt.Errorf("dql: decode: %s: %w", block, err)
+	}
+	return n, nil
+}
+
+// countResult reads the count of the result of a count-only block, a list holding a single
+// {"count": n} object.
+func countResult(raw any) (int64, error) {
+	list, ok := raw.([]any)
+	if !ok || len(list) > 1 {
+		return 0, fmt.Errorf("expected a single count")
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	obj, _ := list[0].(map[string]any)
+	count, ok := obj["count"].(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("expected a count, got %v", list[0])
+	}
+	return count.Int64()
+}
@@ -0,0 +1,107 @@
+package dql
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"count", Ge(Count("friend"), 3).String(), "ge(count(friend), 3)"},
+		{"count of a reverse edge", Between(Count("~owner"), 1, 5).String(), "between(count(~owner), 1, 5)"},
+		{"len", Gt(Len("friends"), 0).String(), "gt(len(friends), 0)"},
+		{"plain predicate", Eq("name", "Alice").String(), `eq(name, "Alice")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("String() = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"count at root", NewQuery("", NewQueryBlock("me", Ge(Count("friend"), 3))), ""},
+		{"len in filter", NewQuery("", NewQueryBlock("me", Uid("f")).WithDirectives(NewDirective("filter", Gt(Len("f"), 0)))).
+			WithVarBlocks(NewVarBlock(Has("friend")).WithName("f")), ""},
+		{"count in a selection set", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("count(uid)"))), ""},
+		{"count outside a comparison", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Has(Count("friend").String())))),
+			`dql: query block "me": count(friend) is only allowed as the first argument of eq, le, lt, ge, gt and between`},
+		{"len at root", NewQuery("", NewQueryBlock("me", Gt(Len("f"), 0))).WithVarBlocks(NewVarBlock(Has("friend")).WithName("f")),
+			`dql: query block "me": len(f) is only allowed in @filter`},
+		{"count of uid", NewQuery("", NewQueryBlock("me", Ge(Count("uid"), 1))),
+			`dql: query block "me": count(uid) is only allowed in selection sets`},
+		{"edge filter", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+			NewAttribute("friend").WithDirectives(NewDirective("filter", Ge(Count("uid"), 1))).WithAttributes(NewAttribute("name")))),
+			`dql: query block "me": friend: count(uid) is only allowed in selection sets`},
+		{"facets", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+			NewAttribute("friend").WithDirectives(Facets(Raw("eq(count(x), 1)"))).WithAttributes(NewAttribute("name")))),
+			`dql: query block "me": friend: count() is not allowed in facet filters`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCountOnly(t *testing.T) {
+	qb := NewQueryBlock("total", Has("email")).WithAttributes(NewAttribute("name")).CountOnly()
+	if got, want := qb.String(), "total (func: has(email)) { count(uid) }"; got != want {
+		t.Errorf("CountOnly() = %s, want %s", got, want)
+	}
+	if !qb.IsCountOnly() || !NewQueryBlock("n", Has("x")).WithAttributes(NewAttribute("count( uid )")).IsCountOnly() {
+		t.Errorf("IsCountOnly() = false for a count block")
+	}
+	if NewQueryBlock("n", Has("x")).WithAttributes(NewAttribute("count(uid)").WithAlias("n")).IsCountOnly() {
+		t.Errorf("IsCountOnly() = true for an aliased count")
+	}
+
+	q := NewQuery("", qb).WithQueryBlocks(NewQueryBlock("me", Has("email")).WithAttributes(NewAttribute("name")))
+	got, err := q.DecodeMap([]byte(`{"total": [{"count": 42}], "me": [{"name": "Alice"}]}`))
+	if err != nil {
+		t.Fatalf("DecodeMap() error = %v", err)
+	}
+	if got["total"] != int64(42) {
+		t.Errorf("DecodeMap() total = %#v, want 42", got["total"])
+	}
+
+	var res struct {
+		Total int `dql:"total"`
+	}
+	if err := Decode([]byte(`{"total": [{"count": 7}]}`), &res); err != nil || res.Total != 7 {
+		t.Errorf("Decode() = %d, %v, want 7", res.Total, err)
+	}
+}
+
+func TestDecodeCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int64
+		wantErr string
+	}{
+		{"count", `{"total": [{"count": 3}]}`, 3, ""},
+		{"no match", `{"total": []}`, 0, ""},
+		{"missing block", `{}`, 0, `dql: decode: no result for block "total"`},
+		{"not a count", `{"total": [{"name": "a"}]}`, 0, "dql: decode: total: expected a count, got map[name:a]"},
+		{"several", `{"total": [{"count": 1}, {"count": 2}]}`, 0, "dql: decode: total: expected a single count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCount([]byte(tt.data), "total")
+			if got != tt.want || errString(err) != tt.wantErr {
+				t.Errorf("DecodeCount() = %d, %q, want %d, %q", got, errString(err), tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Criteria is an argument of a query or variable block, such as its root function or an
+// ordering or pagination argument.
+//
+// Typed function builders such as Has, Raw strings and any other fmt.Stringer can be used as
+// criteria. Plain strings are still accepted by the builders and are treated as Raw.
+type Criteria interface {
+	fmt.Stringer
+}
+
+// toCriteria converts a builder argument into Criteria.
+//
+// Parameters are replaced with a reference to them, strings are wrapped into Raw, Criteria are
+// kept as-is and any other value is formatted with fmt.Sprint.
+func toCriteria(v any) Criteria {
+	switch v := v.(type) {
+	case *Param:
+		return v.Ref()
+	case Criteria:
+		return v
+	case string:
+		return Raw(v)
+	default:
+		return Raw(fmt.Sprint(v))
+	}
+}
+
+// compositeCriteria is implemented by criteria made of other criteria, such as Function and
+// Directive.
+type compositeCriteria interface {
+	Criteria
+
+	// args returns the nested criteria.
+	args() []Criteria
+
+	// withArgs returns a copy of the criteria with the nested criteria replaced.
+	withArgs(args []Criteria) Criteria
+}
+
+// walkCriteria calls visit for c and, in depth-first order, for all criteria nested in it.
+func walkCriteria(c Criteria, visit func(c Criteria)) {
+	visit(c)
+	if cc, ok := c.(compositeCriteria); ok {
+		for _, arg := range cc.args() {
+			walkCriteria(arg, visit)
+		}
+	}
+}
+
+// mapCriteria rebuilds c bottom-up, replacing each criteria by the result of fn.
+// Composite criteria are copied rather than modified, so criteria shared with other
+// queries are left untouched.
+func mapCriteria(c Criteria, fn func(c Criteria) Criteria) Criteria {
+	if cc, ok := c.(compositeCriteria); ok {
+		args := cc.args()
+		mapped := make([]Criteria, len(args))
+		for i, arg := range args {
+			mapped[i] = mapCriteria(arg, fn)
+		}
+		c = cc.withArgs(mapped)
+	}
+	return fn(c)
+}
+
+// mapCriteriaList applies mapCriteria to every criteria of a list in place.
+func mapCriteriaList(list []Criteria, fn func(c Criteria) Criteria) {
+	for i, c := range list {
+		list[i] = mapCriteria(c, fn)
+	}
+}
+
+// joinCriteria renders a list of criteria separated by commas.
+func joinCriteria(criteria []Criteria) string {
+	res := ""
+	for i, c := range criteria {
+		if i > 0 {
+			res += ", "
+		}
+		res += c.String()
+	}
+	return res
+}
+
+// namedArgPattern matches criteria written as a named argument, such as "first: 10".
+var namedArgPattern = regexp.MustCompile(`^\s*\w+\s*:`)
+
+// isRootFunction reports whether criteria is a root function rather than a named argument.
+func isRootFunction(c Criteria) bool {
+	if _, ok := c.(*Arg); ok {
+		return false
+	}
+	return !namedArgPattern.MatchString(c.String())
+}
+
+// blockArguments renders the argument list of a block.
+//
+// The first criteria is prefixed with "func: " when it is a root function. Blocks made only
+// of named arguments, such as pagination, are rendered without the prefix.
+func blockArguments(criteria []Criteria) string {
+	if len(criteria) != 0 && isRootFunction(criteria[0]) {
+		return "(func: " + joinCriteria(criteria) + ")"
+	}
+	return "(" + joinCriteria(criteria) + ")"
+}
@@ -0,0 +1,23 @@
+package dql
+
+import "testing"
+
+func TestBlockArgumentsPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		qb   *QueryBlock
+		want string
+	}{
+		{"root function", NewQueryBlock("me", Has("user")).WithFirst(1), "me (func: has(user), first: 1) { }"},
+		{"raw root function", NewQueryBlock("me", "uid(0x1)"), "me (func: uid(0x1)) { }"},
+		{"arguments only", NewQueryBlockOpt("me", First(1), Offset(2)), "me (first: 1, offset: 2) { }"},
+		{"raw argument", NewQueryBlockOpt("me", Args("first: 1")), "me (first: 1) { }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.qb.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package dql
+
+import "time"
+
+// Time creates a datetime literal, rendered as a quoted RFC 3339 string such as
+// "2024-01-02T15:04:05Z".
+//
+// The time is converted to UTC, so equal instants render the same regardless of the location
+// they were created in, which keeps fingerprints and cached queries stable.
+//
+// Parameters:
+//   - t: The time.
+//
+// Returns:
+//   - A Literal of the time.
+//
+// Example:
+//
+//	filter := Ge("created_at", Time(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)))
+//	fmt.Println(filter.String()) // Output: ge(created_at, "2024-01-02T15:04:05Z")
+//
+// See: https://dgraph.io/docs/query-language/schema/#datetime
+func Time(t time.Time) Literal {
+	return Literal{t.UTC().Round(0)}
+}
+
+// Duration creates a datetime literal of the instant a duration before now, for filters on
+// recent nodes such as "created in the last 24 hours", since DQL has no relative dates.
+//
+// The instant is computed when Duration is called and truncated to the second, so queries
+// built within the same second render identically.
+//
+// Parameters:
+//   - d: The duration before now.
+//
+// Returns:
+//   - A Literal of the instant, rendered as a quoted RFC 3339 string.
+//
+// Example:
+//
+//	// Nodes created in the last 24 hours.
+//	filter := Ge("created_at", Duration(24*time.Hour))
+//	fmt.Println(filter.String()) // Output: ge(created_at, "2024-01-01T15:04:05Z")
+func Duration(d time.Duration) Literal {
+	return Time(time.Now().Add(-d).Truncate(time.Second))
+}
@@ -0,0 +1,34 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	at := time.Date(2024, 5, 1, 14, 30, 0, 500, time.FixedZone("CEST", 2*3600))
+	if got, want := Ge("created_at", Time(at)).String(), `ge(created_at, "2024-05-01T12:30:00.0000005Z")`; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	q, vars := NewQuery("Q", NewQueryBlock("me", Ge("created_at", Time(at)))).Parameterize()
+	if got, want := q.String(), "query Q ( $v0: string ) { me (func: ge(created_at, $v0)) { } }"; got != want {
+		t.Errorf("Parameterize() = %s, want %s", got, want)
+	}
+	if got, want := vars["$v0"], "2024-05-01T12:30:00.0000005Z"; got != want {
+		t.Errorf("Parameterize() vars = %v, want $v0: %s", vars, want)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	before := time.Now().Add(-time.Hour).Truncate(time.Second)
+	lit := Duration(time.Hour)
+	after := time.Now().Add(-time.Hour)
+	at, ok := lit.Value.(time.Time)
+	if !ok || at.Before(before) || at.After(after) || at.Location() != time.UTC || at.Nanosecond() != 0 {
+		t.Errorf("Duration() = %v, want a UTC second between %v and %v", lit.Value, before, after)
+	}
+	if s := lit.String(); !strings.HasSuffix(s, `Z"`) {
+		t.Errorf("String() = %s, want a quoted UTC datetime", s)
+	}
+}
@@ -0,0 +1,442 @@
+package dql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode decodes the JSON data of a response into v, following the struct tags used by
+// AttributesFromStruct.
+//
+// The key of each struct field is taken from its `dql` tag, falling back to its `dgraph` and
+// `json` tags and then to the field name, and is matched case-insensitively when no key
+// matches exactly. Embedded structs are flattened. Values of other types are decoded with
+// encoding/json.
+//
+// Facets are decoded from the predicate|facet keys Dgraph returns them under, into fields
+// tagged with these keys, e.g. `dql:"friend|since"`. Facets of scalar predicates are found
+// next to the predicate, and facets of uid edges in the objects the edge leads to, so the
+// field belongs to the struct of these objects. Facets of scalar lists, returned by Dgraph as
+// an object keyed by the index of each value, are decoded into slices in the order of the
+// list.
+//
+// Dgraph returns lists or single values depending on the schema: a list holding a single
+// value is decoded into a non-list field, and a single value or node into a slice field. Uid
+// edges selecting only uid, e.g. friend { uid }, are decoded into string, UID, []string and
+// []UID fields as the uids of their nodes, and the results of count-only blocks, see
+// QueryBlock.CountOnly, into integer fields as their count.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//   - v: A non-nil pointer to the value to decode into.
+//
+// Returns:
+//   - An error if the data cannot be decoded into v.
+//
+// Example:
+//
+//	type Friend struct {
+//	    Name  string    `dql:"name"`
+//	    Since time.Time `dql:"friend|since"`
+//	}
+//	var res struct {
+//	    Me []struct {
+//	        Name     string   `dql:"name"`
+//	        Nicks    []string `dql:"nickname"`
+//	        NickKind []string `dql:"nickname|kind"`
+//	        Friends  []Friend `dql:"friend"`
+//	    } `dql:"me"`
+//	}
+//	err := Decode(resp.Json, &res)
+//
+// See: https://dgraph.io/docs/query-language/facets/
+func Decode(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("dql: decode: expected a non-nil pointer, got %T", v)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var root any
+	if err := d.Decode(&root); err != nil {
+		return fmt.Errorf("dql: decode: %w", err)
+	}
+	if err := decodeValue(root, rv.Elem(), ""); err != nil {
+		return fmt.Errorf("dql: decode: %w", err)
+	}
+	return nil
+}
+
+// decodeValue decodes a value of a response into rv. path locates the value in errors.
+func decodeValue(raw any, rv reflect.Value, path string) error {
+	t := rv.Type()
+	if list, ok := raw.([]any); ok && !acceptsList(t) {
+		if len(list) == 1 && isScalarType(elemPointerType(t)) && decodeJSON(raw, rv, path) == nil {
+			return nil
+		}
+		switch len(list) {
+		case 0:
+			rv.SetZero()
+			return nil
+		case 1:
+			return decodeValue(list[0], rv, path)
+		default:
+			return fmt.Errorf("%s: expected a single value, got a list of %d", describePath(path), len(list))
+		}
+	}
+	if obj, ok := raw.(map[string]any); ok && len(obj) == 1 && (rv.Kind() == reflect.String || elemPointerType(t) == uidType) {
+		if uid, ok := obj["uid"]; ok {
+			raw = uid
+		}
+	}
+	if obj, ok := raw.(map[string]any); ok && len(obj) == 1 && isIntegerKind(elemPointerType(t).Kind()) {
+		if count, ok := obj["count"]; ok {
+			raw = count
+		}
+	}
+	if raw == nil || isScalarType(t) {
+		return decodeJSON(raw, rv, path)
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return decodeValue(raw, rv.Elem(), path)
+	case reflect.Struct:
+		if obj, ok := raw.(map[string]any); ok {
+			return decodeStruct(obj, rv, path)
+		}
+	case reflect.Slice:
+		if list, ok := raw.([]any); ok {
+			res := reflect.MakeSlice(t, len(list), len(list))
+			for i, e := range list {
+				if err := decodeValue(e, res.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+			rv.Set(res)
+			return nil
+		}
+		if facets, ok := indexedFacets(raw); ok {
+			return decodeValue(facets, rv, path)
+		}
+		if _, ok := raw.(string); !ok || t.Elem().Kind() != reflect.Uint8 {
+			return decodeValue([]any{raw}, rv, path)
+		}
+	}
+	return decodeJSON(raw, rv, path)
+}
+
+// isIntegerKind reports whether a kind is an integer kind, into which the {"count": n} object
+// of a count-only block decodes.
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// uidType is the type of UID.
+var uidType = reflect.TypeOf(UID(0))
+
+// acceptsList reports whether a list of a response decodes into a value of type t as is. Lists
+// are unwrapped for the other types, since Dgraph returns the single node of an edge or the
+// single value of a predicate as a list unless the schema says otherwise. Types decoding
+// themselves are first given the list as is.
+func acceptsList(t reflect.Type) bool {
+	switch elemPointerType(t).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// elemPointerType unwraps pointer types.
+func elemPointerType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// decodeStruct decodes an object of a response into the fields of a struct.
+func decodeStruct(obj map[string]any, rv reflect.Value, path string) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" && elemType(field.Type).Kind() == reflect.Struct {
+			if field.Type.Kind() == reflect.Pointer {
+				if !field.IsExported() {
+					continue
+				}
+				if fv.IsNil() {
+					fv.Set(reflect.New(field.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := decodeStruct(obj, fv, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		raw, ok := obj[name]
+		if !ok {
+			for key, v := range obj {
+				if strings.EqualFold(key, name) {
+					raw, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := decodeValue(raw, fv, joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeJSON decodes a value of a response into rv with encoding/json.
+func decodeJSON(raw any, rv reflect.Value, path string) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, rv.Addr().Interface()); err != nil {
+		if path == "" {
+			return err
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// describePath returns the path of a value of a response for errors.
+func describePath(path string) string {
+	if path == "" {
+		return "response"
+	}
+	return path
+}
+
+// indexedFacets converts the facets of a scalar list, an object keyed by the index of each
+// value such as {"0": "a", "2": "b"}, into a list in the order of the indexes.
+func indexedFacets(raw any) ([]any, bool) {
+	obj, ok := raw.(map[string]any)
+	if !ok || len(obj) == 0 {
+		return nil, false
+	}
+	indexes := make([]int, 0, len(obj))
+	for key := range obj {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return nil, false
+		}
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	res := make([]any, indexes[len(indexes)-1]+1)
+	for _, i := range indexes {
+		res[i] = obj[strconv.Itoa(i)]
+	}
+	return res, true
+}
+
+// joinPath appends a key to the path of a value of a response.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// DecodeMap decodes the JSON data of a response of the query into nested maps, for consumers
+// that do not know the shape of the results in advance, such as admin interfaces.
+//
+// The values are converted to Go types following the query and its Schema, see WithSchema:
+// uids become UIDs, values of datetime predicates time.Time, values of int predicates int64
+// and values of float predicates float64. Other numbers, such as the values of aggregations or
+// of predicates the schema does not define, become int64 if they are integers and float64
+// otherwise. Response keys are mapped back to their predicates through the aliases of the
+// query, and through fragments. The results of count-only blocks, see QueryBlock.CountOnly,
+// become the int64 count. Other values are kept as encoding/json decodes them.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//
+// Returns:
+//   - The results of each block, by block name.
+//   - An error if the data is not a JSON object.
+//
+// Example:
+//
+//	schema, _ := ParseSchema(`name: string . born: datetime .`)
+//	query := NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(
+//	    UIDAttribute(), NewAttribute("name"), NewAttribute("born"), NewAttribute("count(friend)").WithAlias("friends"),
+//	)).WithSchema(schema)
+//	res, err := query.DecodeMap(resp.Json)
+//	me := res["me"].([]any)[0].(map[string]any)
+//	fmt.Printf("%T %T %T", me["uid"], me["born"], me["friends"]) // Output: dql.UID time.Time int64
+func (q *Query) DecodeMap(data []byte) (map[string]any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var root map[string]any
+	if err := d.Decode(&root); err != nil {
+		return nil, fmt.Errorf("dql: decode: %w", err)
+	}
+	m := &mapDecoder{schema: q.Schema, fragments: map[string]*Fragment{}}
+	for _, f := range q.fragments() {
+		m.fragments[f.Name] = f
+	}
+	blocks := map[string]*QueryBlock{}
+	for _, qb := range q.QueryBlocks {
+		if !qb.Raw {
+			blocks[qb.Name] = qb
+		}
+	}
+	for key, v := range root {
+		qb := blocks[key]
+		if qb == nil {
+			root[key] = m.value(v, "", nil)
+			continue
+		}
+		if qb.IsCountOnly() {
+			n, err := countResult(v)
+			if err != nil {
+				return nil, fmt.Errorf("dql: decode: %s: %w", key, err)
+			}
+			root[key] = n
+			continue
+		}
+		root[key] = m.value(v, "", m.selection(qb.Attributes, hasDirective(qb.Directives, "normalize")))
+	}
+	return root, nil
+}
+
+// mapDecoder converts the values of a response decoded into maps, see Query.DecodeMap.
+type mapDecoder struct {
+	schema    *Schema
+	fragments map[string]*Fragment
+}
+
+// selection returns the attributes of a selection set by response key, spreading fragments.
+// Under @normalize, the aliased attributes of nested selection sets are included as well,
+// since Dgraph flattens them into the same object.
+func (m *mapDecoder) selection(attrs []*Attribute, normalize bool) map[string]*Attribute {
+	res := map[string]*Attribute{}
+	var collect func(attrs []*Attribute, nested bool)
+	collect = func(attrs []*Attribute, nested bool) {
+		for _, a := range attrs {
+			if a.Raw {
+				continue
+			}
+			if name, ok := strings.CutPrefix(a.Name, "..."); ok {
+				f := a.fragment
+				if f == nil {
+					f = m.fragments[name]
+				}
+				if f != nil {
+					collect(f.Attributes, nested)
+				}
+				continue
+			}
+			if !nested || a.Alias != "" {
+				res[responseKey(a)] = a
+			}
+			if normalize {
+				collect(a.Attributes, true)
+			}
+		}
+	}
+	collect(attrs, false)
+	return res
+}
+
+// value converts a value of the response read from a predicate, given the selection set of
+// the value if it is an edge.
+func (m *mapDecoder) value(v any, predicate string, sel map[string]*Attribute) any {
+	switch v := v.(type) {
+	case []any:
+		for i, e := range v {
+			v[i] = m.value(e, predicate, sel)
+		}
+		return v
+	case map[string]any:
+		for key, e := range v {
+			if key == PredicateUID {
+				if s, ok := e.(string); ok {
+					if uid, err := ParseUID(s); err == nil {
+						v[key] = uid
+					}
+				}
+				continue
+			}
+			a := sel[key]
+			switch {
+			case a == nil && strings.Contains(key, "|"):
+				v[key] = m.value(e, "", nil)
+			case a == nil:
+				v[key] = m.value(e, predicateOf(key), nil)
+			case isExpression(a.Name):
+				v[key] = m.value(e, "", nil)
+			default:
+				v[key] = m.value(e, predicateOf(a.Name), m.selection(a.Attributes, hasDirective(a.Directives, "normalize")))
+			}
+		}
+		return v
+	case json.Number:
+		typ := ""
+		if p := m.predicate(predicate); p != nil {
+			typ = p.Type
+		}
+		if typ != "float" && typ != "bigfloat" {
+			if n, err := v.Int64(); err == nil {
+				return n
+			}
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case string:
+		if p := m.predicate(predicate); p != nil && p.Type == "datetime" {
+			for _, layout := range dateLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t
+				}
+			}
+		}
+		return v
+	}
+	return v
+}
+
+// predicate looks up the definition of a predicate in the schema, if any.
+func (m *mapDecoder) predicate(name string) *SchemaPredicate {
+	if m.schema == nil || name == "" {
+		return nil
+	}
+	return m.schema.Predicate(name)
+}
@@ -0,0 +1,151 @@
+package dql
+
+import (
+	"testing"
+	"time"
+)
+
+type decodeFriend struct {
+	Name  string    `dql:"name"`
+	Since time.Time `dql:"friend|since"`
+}
+
+type decodeUser struct {
+	structBase
+	Name      string          `dql:"name"`
+	Nick      string          `dgraph:"nick@en"`
+	Age       int             `json:"age"`
+	NameLang  string          `dql:"name|lang"`
+	Friends   []*decodeFriend `dql:"friend"`
+	Tags      []string        `dql:"tag"`
+	TagWeight []float64       `dql:"tag|weight"`
+	Score     float64
+	hidden    string
+}
+
+func TestDecode(t *testing.T) {
+	data := []byte(`{"me": [{
+		"uid": "0x1", "name": "Alice", "name|lang": "en", "nick@en": "Ali", "age": 30, "score": 1.5,
+		"friend": [{"name": "Bob", "friend|since": "2020-01-02T00:00:00Z"}],
+		"tag": ["go", "dql"], "tag|weight": {"1": 0.5, "0": 1}
+	}]}`)
+	var res struct {
+		Me []decodeUser `json:"me"`
+	}
+	if err := Decode(data, &res); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	me := res.Me[0]
+	since := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	switch {
+	case me.UID != "0x1", me.Name != "Alice", me.Nick != "Ali", me.Age != 30, me.Score != 1.5, me.NameLang != "en":
+		t.Errorf("Decode() = %+v", me)
+	case len(me.Friends) != 1 || me.Friends[0].Name != "Bob" || !me.Friends[0].Since.Equal(since):
+		t.Errorf("Friends = %+v", me.Friends)
+	case len(me.TagWeight) != 2 || me.TagWeight[0] != 1 || me.TagWeight[1] != 0.5:
+		t.Errorf("TagWeight = %v, want [1 0.5]", me.TagWeight)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var user decodeUser
+	tests := []struct {
+		name    string
+		data    string
+		v       any
+		wantErr string
+	}{
+		{"not a pointer", `{}`, user, "dql: decode: expected a non-nil pointer, got dql.decodeUser"},
+		{"invalid json", `{`, &user, "dql: decode: unexpected EOF"},
+		{"wrong type", `{"friend": [{"name": 1}]}`, &user, "dql: decode: friend[0].name: json: cannot unmarshal number into Go value of type string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(Decode([]byte(tt.data), tt.v)); got != tt.wantErr {
+				t.Errorf("Decode() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeShapes(t *testing.T) {
+	type node struct {
+		Owner   string   `dql:"owner"`
+		Best    *string  `dql:"best"`
+		Tags    []string `dql:"tag"`
+		Friends []string `dql:"friend"`
+		Raw     []byte   `dql:"raw"`
+		Single  int      `dql:"single"`
+		Empty   string   `dql:"empty"`
+	}
+	data := []byte(`{"owner": {"uid": "0x1"}, "best": [{"uid": "0x2"}], "tag": "go", "friend": [{"uid": "0x3"}, {"uid": "0x4"}],
+		"raw": "aGk=", "single": [7], "empty": []}`)
+	var res node
+	if err := Decode(data, &res); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	switch {
+	case res.Owner != "0x1", res.Best == nil || *res.Best != "0x2", res.Single != 7, res.Empty != "", string(res.Raw) != "hi":
+		t.Errorf("Decode() = %+v", res)
+	case len(res.Tags) != 1 || res.Tags[0] != "go":
+		t.Errorf("Tags = %v, want [go]", res.Tags)
+	case len(res.Friends) != 2 || res.Friends[0] != "0x3" || res.Friends[1] != "0x4":
+		t.Errorf("Friends = %v, want [0x3 0x4]", res.Friends)
+	}
+
+	var uids struct {
+		ID      UID   `dql:"uid"`
+		Owner   UID   `dql:"owner"`
+		Friends []UID `dql:"friend"`
+	}
+	if err := Decode([]byte(`{"uid": "0x9", "owner": {"uid": "0x1"}, "friend": [{"uid": "0x3"}, {"uid": "0x4"}]}`), &uids); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if uids.ID != 0x9 || uids.Owner != 0x1 || len(uids.Friends) != 2 || uids.Friends[1] != 0x4 {
+		t.Errorf("Decode() = %+v", uids)
+	}
+
+	var single struct {
+		Name string `dql:"name"`
+	}
+	err := Decode([]byte(`{"name": ["a", "b"]}`), &single)
+	if got, want := errString(err), "dql: decode: name: expected a single value, got a list of 2"; got != want {
+		t.Errorf("Decode() error = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeMap(t *testing.T) {
+	schema, err := ParseSchema("age: int .\nscore: float .\ncreated: datetime .\nname: string .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewQuery("", NewQueryBlock("me", Has("name")).WithAttributes(
+		NewAttribute("uid"), NewAttribute("age"), NewAttribute("score"), NewAttribute("created"),
+		NewAttribute("created").WithAlias("since"), NewAttribute("friend").WithAttributes(NewAttribute("age")))).WithSchema(schema)
+	data := []byte(`{"me": [{"uid": "0x1", "age": 30, "score": 2, "created": "2024-01-02T03:04:05Z", "since": "2024",
+		"friend": [{"age": 7}]}], "extra": {"n": 1.5}}`)
+	got, err := q.DecodeMap(data)
+	if err != nil {
+		t.Fatalf("DecodeMap() error = %v", err)
+	}
+	me := got["me"].([]any)[0].(map[string]any)
+	if me["uid"] != UID(1) || me["age"] != int64(30) || me["score"] != float64(2) {
+		t.Errorf("DecodeMap() = %#v", me)
+	}
+	if created, ok := me["created"].(time.Time); !ok || !created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("created = %#v, want a time", me["created"])
+	}
+	if since, ok := me["since"].(time.Time); !ok || since.Year() != 2024 {
+		t.Errorf("since = %#v, want a time", me["since"])
+	}
+	if friend := me["friend"].([]any)[0].(map[string]any); friend["age"] != int64(7) {
+		t.Errorf("friend = %#v", friend)
+	}
+	if extra := got["extra"].(map[string]any); extra["n"] != 1.5 {
+		t.Errorf("extra = %#v", extra)
+	}
+
+	if _, err := q.DecodeMap([]byte(`[`)); err == nil {
+		t.Error("DecodeMap() of invalid JSON succeeded")
+	}
+}
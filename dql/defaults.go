@@ -0,0 +1,165 @@
+package dql
+
+import "context"
+
+// BlockHook modifies a query block, to apply defaults to the blocks of queries, see
+// Query.ApplyDefaults.
+type BlockHook func(qb *QueryBlock)
+
+// defaultsKey is the context key of the defaults of a call site, see WithDefaults.
+type defaultsKey struct{}
+
+// callDefaults are the defaults of a call site.
+type callDefaults struct {
+	hooks []BlockHook
+	skip  bool
+}
+
+// DefaultDirectives creates a BlockHook adding directives to query blocks, unless the blocks
+// already hold a directive with the same name.
+//
+// Parameters:
+//   - directives: The directives, either Criteria such as Cascade() or strings.
+//
+// Returns:
+//   - A BlockHook.
+func DefaultDirectives(directives ...any) BlockHook {
+	return func(qb *QueryBlock) {
+		for _, d := range directives {
+			c := toCriteria(d)
+			if !hasDirective(qb.Directives, directiveName(c)) {
+				qb.Directives = append(qb.Directives, c)
+			}
+		}
+	}
+}
+
+// DefaultTypeSelection creates a BlockHook selecting uid and dgraph.type in query blocks and
+// in the nested selection sets of their edges, unless already selected, see
+// QueryBlock.WithTypeSelection.
+//
+// Returns:
+//   - A BlockHook.
+func DefaultTypeSelection() BlockHook {
+	var edges func(attrs []*Attribute)
+	edges = func(attrs []*Attribute) {
+		for _, a := range attrs {
+			if !a.Raw && len(a.Attributes) != 0 {
+				a.WithTypeSelection()
+				edges(a.Attributes)
+			}
+		}
+	}
+	return func(qb *QueryBlock) {
+		qb.WithTypeSelection()
+		edges(qb.Attributes)
+	}
+}
+
+// ForBlocks creates a BlockHook applying hooks only to the query blocks matching a condition,
+// e.g. to the blocks following a naming convention.
+//
+// Parameters:
+//   - match: The condition.
+//   - hooks: The hooks applied to the matching blocks.
+//
+// Returns:
+//   - A BlockHook.
+func ForBlocks(match func(qb *QueryBlock) bool, hooks ...BlockHook) BlockHook {
+	return func(qb *QueryBlock) {
+		if match(qb) {
+			for _, hook := range hooks {
+				hook(qb)
+			}
+		}
+	}
+}
+
+// ApplyDefaults applies hooks to every query block of the query, in order, and returns the
+// query. Raw blocks are left unchanged.
+//
+// Parameters:
+//   - hooks: The hooks.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	strict := func(qb *QueryBlock) bool { return strings.HasPrefix(qb.Name, "strict") }
+//	query := NewQuery("", NewQueryBlock("strictUsers", Has("user")).WithAttributes(NewAttribute("name"))).
+//	    ApplyDefaults(ForBlocks(strict, DefaultDirectives(Cascade())), DefaultTypeSelection())
+//	fmt.Println(query.String()) // Output: { strictUsers (func: has(user)) @cascade { name uid dgraph.type } }
+func (q *Query) ApplyDefaults(hooks ...BlockHook) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	for _, qb := range q.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		mustBeMutable(qb.frozen, "query block", qb.Name)
+		for _, hook := range hooks {
+			hook(qb)
+		}
+	}
+	return q
+}
+
+// DefaultsRewriter creates a Rewriter applying defaults to every executed query, see
+// Query.ApplyDefaults.
+//
+// Call sites adjust the defaults through the context of the execution: WithDefaults adds
+// hooks applied after the defaults of the rewriter, and WithoutDefaults disables them.
+//
+// Parameters:
+//   - hooks: The hooks applied to the query blocks of every query.
+//
+// Returns:
+//   - A Rewriter.
+//
+// Example:
+//
+//	exec := NewRewritingExecutor(client, DefaultsRewriter(DefaultTypeSelection()))
+//	resp, err := exec.Execute(WithDefaults(ctx, DefaultDirectives(Cascade())), query, nil)
+func DefaultsRewriter(hooks ...BlockHook) Rewriter {
+	return func(ctx context.Context, q *Query) (*Query, error) {
+		d, _ := ctx.Value(defaultsKey{}).(*callDefaults)
+		if d != nil && d.skip {
+			return q, nil
+		}
+		q.ApplyDefaults(hooks...)
+		if d != nil {
+			q.ApplyDefaults(d.hooks...)
+		}
+		return q, nil
+	}
+}
+
+// WithDefaults returns a copy of ctx adding hooks to the defaults applied by DefaultsRewriter
+// to the queries executed with it.
+//
+// Parameters:
+//   - ctx: The context.
+//   - hooks: The hooks applied after the defaults of the rewriter.
+//
+// Returns:
+//   - The derived context.
+func WithDefaults(ctx context.Context, hooks ...BlockHook) context.Context {
+	d := &callDefaults{}
+	if parent, ok := ctx.Value(defaultsKey{}).(*callDefaults); ok {
+		d.hooks, d.skip = append(d.hooks, parent.hooks...), parent.skip
+	}
+	d.hooks = append(d.hooks, hooks...)
+	return context.WithValue(ctx, defaultsKey{}, d)
+}
+
+// WithoutDefaults returns a copy of ctx disabling the defaults applied by DefaultsRewriter to
+// the queries executed with it, e.g. for a query needing exactly the selection it builds.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - The derived context.
+func WithoutDefaults(ctx context.Context) context.Context {
+	return context.WithValue(ctx, defaultsKey{}, &callDefaults{skip: true})
+}
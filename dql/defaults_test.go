@@ -0,0 +1,65 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	isUsers := func(qb *QueryBlock) bool { return qb.Name == "users" }
+	tests := []struct {
+		name  string
+		hooks []BlockHook
+		want  string
+	}{
+		{"directive", []BlockHook{DefaultDirectives("@cascade")},
+			"{ users (func: has(email)) @cascade { friend { name } } posts (func: has(title)) @cascade { } }"},
+		{"existing directive kept", []BlockHook{ForBlocks(isUsers, DefaultDirectives(NewDirective("filter", Has("active"))))},
+			"{ users (func: has(email)) @filter(has(verified)) { friend { name } } posts (func: has(title)) { } }"},
+		{"type selection", []BlockHook{ForBlocks(isUsers, DefaultTypeSelection())},
+			"{ users (func: has(email)) @filter(has(verified)) { friend { name uid dgraph.type } uid dgraph.type } posts (func: has(title)) { } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery("", NewQueryBlock("users", Has("email")).
+				WithAttributes(NewAttribute("friend").WithAttributes(NewAttribute("name")))).
+				WithQueryBlocks(NewQueryBlock("posts", Has("title")))
+			if tt.name != "directive" {
+				q.QueryBlocks[0].WithDirectives(NewDirective("filter", Has("verified")))
+			}
+			if got := q.ApplyDefaults(tt.hooks...).String(); got != tt.want {
+				t.Errorf("ApplyDefaults() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultsRewriter(t *testing.T) {
+	var executed string
+	next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		executed = q.String()
+		return &Response{}, nil
+	})
+	exec := NewRewritingExecutor(next, DefaultsRewriter(DefaultDirectives("@normalize")))
+	q := NewQuery("", NewQueryBlock("me", Has("user"))).Freeze()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"global", context.Background(), "{ me (func: has(user)) @normalize { } }"},
+		{"per call", WithDefaults(context.Background(), DefaultDirectives("@cascade")), "{ me (func: has(user)) @normalize @cascade { } }"},
+		{"without", WithoutDefaults(context.Background()), "{ me (func: has(user)) { } }"},
+		{"without then per call", WithDefaults(WithoutDefaults(context.Background()), DefaultDirectives("@cascade")), "{ me (func: has(user)) { } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := exec.Execute(tt.ctx, q, nil); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if executed != tt.want {
+				t.Errorf("executed %s, want %s", executed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package dql
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Directive represents a directive applied to a block or an attribute, such as @filter,
+// @cascade or @normalize.
+//
+// Directives are accepted wherever WithDirectives is available, alongside plain strings
+// which are treated as Raw.
+type Directive struct {
+	// Name is the name of the directive, without the leading @.
+	Name string
+
+	// Args is the list of arguments of the directive.
+	Args []Criteria
+}
+
+// NewDirective creates a new Directive with the specified name and arguments.
+//
+// Parameters:
+//   - name: The name of the directive, without the leading @.
+//   - args: The arguments of the directive, either Criteria or strings.
+//
+// Returns:
+//   - A pointer to a Directive object.
+//
+// Example:
+//
+//	directive := NewDirective("filter", Has("email"))
+//	fmt.Println(directive.String()) // Output: @filter(has(email))
+//
+// See: https://dgraph.io/docs/query-language/directive/
+func NewDirective(name string, args ...any) *Directive {
+	d := &Directive{
+		Name: name,
+	}
+	for _, a := range args {
+		d.Args = append(d.Args, toCriteria(a))
+	}
+	return d
+}
+
+// String generates a string representation of the directive.
+//
+// Returns:
+//   - A string representation of the directive.
+func (d *Directive) String() string {
+	if len(d.Args) == 0 {
+		return "@" + d.Name
+	}
+	return "@" + d.Name + "(" + joinCriteria(d.Args) + ")"
+}
+
+func (d *Directive) args() []Criteria {
+	return d.Args
+}
+
+func (d *Directive) withArgs(args []Criteria) Criteria {
+	return &Directive{Name: d.Name, Args: args}
+}
+
+// directiveName returns the name of a directive without the leading @, also for directives
+// given as raw strings. It returns an empty string for values that are not directives.
+func directiveName(c Criteria) string {
+	if d, ok := c.(*Directive); ok {
+		return d.Name
+	}
+	s := strings.TrimSpace(c.String())
+	if !strings.HasPrefix(s, "@") {
+		return ""
+	}
+	end := strings.IndexFunc(s[1:], func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		return s[1:]
+	}
+	return s[1 : end+1]
+}
+
+// OrderDirectives reorders the directives of every block and attribute of the query, so
+// directives render in a fixed order rather than in the order they were added.
+//
+// Directives named in order come first, in that order, followed by the other directives in
+// their current order. Raw blocks and attributes are left unchanged. Directives added
+// afterwards are appended as usual, so OrderDirectives is called once the query is built, or
+// applied to every query with DirectiveOrderRewriter.
+//
+// OrderDirectives does not move arguments such as first: 3, which render before the
+// directives unless the attribute is built with Attribute.WithArgsAfterDirectives.
+//
+// Parameters:
+//   - order: The names of the directives, without the leading @.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", Has("user")).
+//	    WithDirectives(Cascade(), NewDirective("filter", Has("email")))
+//	query := NewQuery("", queryBlock).OrderDirectives("filter", "cascade")
+//	fmt.Println(query.String()) // Output: { me (func: has(user)) @filter(has(email)) @cascade { } }
+func (q *Query) OrderDirectives(order ...string) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	rank := map[string]int{}
+	for i, name := range order {
+		rank[name] = i
+	}
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			mustBeMutable(n.frozen, "var block", n.Name)
+			orderDirectives(n.Directives, rank)
+		case *QueryBlock:
+			mustBeMutable(n.frozen, "query block", n.Name)
+			orderDirectives(n.Directives, rank)
+		case *Attribute:
+			mustBeMutable(n.frozen, "attribute", n.Name)
+			orderDirectives(n.Directives, rank)
+		}
+		return true
+	})
+	return q
+}
+
+// DirectiveOrderRewriter creates a Rewriter reordering the directives of every query, see
+// Query.OrderDirectives.
+//
+// Parameters:
+//   - order: The names of the directives, without the leading @.
+//
+// Returns:
+//   - A Rewriter.
+func DirectiveOrderRewriter(order ...string) Rewriter {
+	return func(ctx context.Context, q *Query) (*Query, error) {
+		return q.OrderDirectives(order...), nil
+	}
+}
+
+// orderDirectives sorts directives in place by their rank, keeping the unranked directives
+// after the ranked ones in their current order.
+func orderDirectives(directives []Criteria, rank map[string]int) {
+	sort.SliceStable(directives, func(i, j int) bool {
+		ri, iok := rank[directiveName(directives[i])]
+		rj, jok := rank[directiveName(directives[j])]
+		if iok && jok {
+			return ri < rj
+		}
+		return iok && !jok
+	})
+}
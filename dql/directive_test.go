@@ -0,0 +1,88 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOrderDirectives(t *testing.T) {
+	newQuery := func() *Query {
+		return NewQuery("", NewQueryBlock("me", Has("user")).
+			WithDirectives("@normalize", Cascade(), NewDirective("filter", Has("email"))).
+			WithAttributes(NewAttribute("friend").WithDirectives(Facets("since"), Cascade(), NewDirective("filter", Has("name"))).WithAttributes(NewAttribute("name")))).
+			WithVarBlocks(NewVarBlock(Has("user")).WithDirectives(Cascade(), NewDirective("filter", Has("age"))))
+	}
+	want := "{ var (func: has(user)) @filter(has(age)) @cascade { } " +
+		"me (func: has(user)) @filter(has(email)) @cascade @normalize { friend @filter(has(name)) @cascade @facets(since) { name } } }"
+	if got := newQuery().OrderDirectives("filter", "cascade").String(); got != want {
+		t.Errorf("OrderDirectives() = %s, want %s", got, want)
+	}
+
+	q := newQuery()
+	rewritten, err := DirectiveOrderRewriter("filter", "cascade")(context.Background(), q)
+	if err != nil {
+		t.Fatalf("DirectiveOrderRewriter() error = %v", err)
+	}
+	if got := rewritten.String(); got != want {
+		t.Errorf("DirectiveOrderRewriter() = %s, want %s", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("OrderDirectives() of a frozen query did not panic")
+		}
+	}()
+	newQuery().Freeze().OrderDirectives("filter")
+}
+
+func TestArgsAfterDirectives(t *testing.T) {
+	tests := []struct {
+		name    string
+		attr    *Attribute
+		want    string
+		wantErr string
+	}{
+		{"before", NewAttribute("friend").WithFirst(3).WithDirectives(NewDirective("filter", Has("name"))),
+			"friend (first: 3) @filter(has(name))", ""},
+		{"after", NewAttribute("friend").WithFirst(3).WithOrderAsc("name").WithDirectives(NewDirective("filter", Has("name"))).WithArgsAfterDirectives(),
+			"friend @filter(has(name)) (first: 3, orderasc: name)", ""},
+		{"after ordered directives", NewAttribute("friend").WithFirst(3).WithDirectives(Cascade(), NewDirective("filter", Has("name"))).WithArgsAfterDirectives(),
+			"friend @cascade @filter(has(name)) (first: 3)", ""},
+		{"after directive without arguments", NewAttribute("friend").WithFirst(3).WithDirectives(NewDirective("filter", Has("name")), Cascade()).WithArgsAfterDirectives(),
+			"friend (first: 3) @filter(has(name)) @cascade", `dql: query block "me": friend: arguments cannot follow @cascade, which has no arguments`},
+		{"no directives", NewAttribute("friend").WithFirst(3).WithArgsAfterDirectives(), "friend (first: 3)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.attr.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			q := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(tt.attr.WithAttributes(NewAttribute("name"))))
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseArgsAfterDirectives(t *testing.T) {
+	src := "{ me (func: has(user)) { friend @filter(has(name)) (first: 3) { name } } }"
+	q, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := q.String(); got != src {
+		t.Errorf("String() = %s, want %s", got, src)
+	}
+	data, err := q.MarshalProto()
+	if err != nil {
+		t.Fatalf("MarshalProto() error = %v", err)
+	}
+	decoded, err := UnmarshalQueryProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalQueryProto() error = %v", err)
+	}
+	if got := decoded.String(); got != src {
+		t.Errorf("proto round trip = %s, want %s", got, src)
+	}
+}
@@ -0,0 +1,257 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Document holds the named queries of a service along with the fragments and parameters
+// they share, so that all of them are defined and reviewed in one place.
+//
+// The queries of a document only declare what is specific to them: Query adds the shared
+// fragments they spread and the shared parameters they reference. Validate checks that the
+// definitions the queries declare themselves are consistent with the shared ones.
+type Document struct {
+	// Queries is the list of the queries of the document, identified by their names.
+	Queries []*Query
+
+	// Fragments is the list of the fragments shared by the queries.
+	Fragments []*Fragment
+
+	// Params is the list of the parameters shared by the queries.
+	Params []*Param
+}
+
+// NewDocument creates a new Document holding queries.
+//
+// Parameters:
+//   - queries: The queries of the document, each with a unique name.
+//
+// Returns:
+//   - A pointer to a Document object.
+//
+// Example:
+//
+//	userFields := NewFragment("userFields").WithAttributes(NewAttribute("name"), NewAttribute("email"))
+//	doc := NewDocument(
+//	    NewQuery("UserByEmail", NewQueryBlock("user", Eq("email", ParamRef("email"))).
+//	        WithAttributes(NewAttribute("...userFields"))),
+//	    NewQuery("Users", NewQueryBlock("users", Has("email")).WithFirst(ParamRef("first")).
+//	        WithAttributes(NewAttribute("...userFields"))),
+//	).WithFragments(userFields).WithParams(NewParam("email", ParamString), NewParam("first", ParamInt).WithDefault("20"))
+//	fmt.Println(doc.Query("UserByEmail").String()) // Output: query UserByEmail ( $email: string ) { user (func: eq(email, $email)) { ...userFields } } fragment userFields { name email }
+func NewDocument(queries ...*Query) *Document {
+	return &Document{Queries: queries}
+}
+
+// WithQueries adds one or more queries to the document.
+//
+// Parameters:
+//   - queries: One or more Query objects, each with a unique name.
+//
+// Returns:
+//   - The updated Document object.
+func (d *Document) WithQueries(queries ...*Query) *Document {
+	d.Queries = append(d.Queries, queries...)
+	return d
+}
+
+// WithFragments adds one or more fragments shared by the queries of the document.
+//
+// Parameters:
+//   - fragments: One or more Fragment objects.
+//
+// Returns:
+//   - The updated Document object.
+func (d *Document) WithFragments(fragments ...*Fragment) *Document {
+	d.Fragments = append(d.Fragments, fragments...)
+	return d
+}
+
+// WithParams adds one or more parameters shared by the queries of the document.
+//
+// Parameters:
+//   - params: One or more Param objects.
+//
+// Returns:
+//   - The updated Document object.
+func (d *Document) WithParams(params ...*Param) *Document {
+	d.Params = append(d.Params, params...)
+	return d
+}
+
+// Names returns the names of the queries of the document.
+//
+// Returns:
+//   - The names, in the order of the queries.
+func (d *Document) Names() []string {
+	res := make([]string, len(d.Queries))
+	for i, q := range d.Queries {
+		res[i] = q.Name
+	}
+	return res
+}
+
+// Query returns a copy of a query of the document, completed with the shared fragments it
+// spreads and the shared parameters it references without declaring them.
+//
+// Parameters:
+//   - name: The name of the query.
+//
+// Returns:
+//   - A copy of the query ready to be executed, or nil if the document has no such query.
+func (d *Document) Query(name string) *Query {
+	for _, q := range d.Queries {
+		if q.Name == name {
+			return d.assemble(q)
+		}
+	}
+	return nil
+}
+
+// assemble returns a copy of q declaring the shared fragments and parameters it uses.
+func (d *Document) assemble(q *Query) *Query {
+	res := q.Clone()
+	// Adding a fragment may spread further shared fragments, so look again until every spread
+	// fragment is declared.
+	for added := true; added; {
+		added = false
+		declared := map[string]bool{}
+		for _, f := range res.fragments() {
+			declared[f.Name] = true
+		}
+		Walk(res, func(n Node) bool {
+			if a, ok := n.(*Attribute); ok && !a.Raw {
+				if name, ok := strings.CutPrefix(a.Name, "..."); ok && !declared[name] {
+					if f := d.fragment(name); f != nil {
+						declared[name] = true
+						res.Fragments = append(res.Fragments, f.Clone())
+						added = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	declared := map[string]bool{}
+	for _, p := range res.Params {
+		declared[p.Ref().String()] = true
+	}
+	for _, list := range criteriaLists(res) {
+		for _, c := range list {
+			walkCriteria(c, func(c Criteria) {
+				if r, ok := c.(ParamRef); ok && !declared[r.String()] {
+					if p := d.param(r.String()); p != nil {
+						declared[r.String()] = true
+						res.Params = append(res.Params, p.Clone())
+					}
+				}
+			})
+		}
+	}
+	return res
+}
+
+// fragment returns the shared fragment with the given name, or nil.
+func (d *Document) fragment(name string) *Fragment {
+	for _, f := range d.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// param returns the shared parameter with the given reference, e.g. $name, or nil.
+func (d *Document) param(ref string) *Param {
+	for _, p := range d.Params {
+		if p.Ref().String() == ref {
+			return p
+		}
+	}
+	return nil
+}
+
+// Validate checks the document and its queries.
+//
+// Queries must be named and their names unique, and so must the shared fragments and
+// parameters. Fragments and parameters declared by a query under a shared name must match
+// the shared definition, and every query, completed as returned by Query, must be valid.
+//
+// Returns:
+//   - An error describing the problem found, or nil if the document is valid.
+//
+// Example:
+//
+//	doc := NewDocument(NewQuery("Users", NewQueryBlock("users", Has("email")).WithFirst(ParamRef("first"))).
+//	    WithParam(NewParam("first", ParamString))).
+//	    WithParams(NewParam("first", ParamInt))
+//	fmt.Println(doc.Validate()) // Output: dql: query "Users": param $first: string does not match the shared param $first: int
+func (d *Document) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	fragments := map[string]*Fragment{}
+	for _, f := range d.Fragments {
+		if fragments[f.Name] != nil {
+			return fmt.Errorf("dql: duplicate shared fragment %q", f.Name)
+		}
+		fragments[f.Name] = f
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	params := map[string]*Param{}
+	for _, p := range d.Params {
+		ref := p.Ref().String()
+		if params[ref] != nil {
+			return fmt.Errorf("dql: duplicate shared param %s", ref)
+		}
+		params[ref] = p
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	names := map[string]bool{}
+	for _, q := range d.Queries {
+		if q.Name == "" {
+			return fmt.Errorf("dql: unnamed query in document")
+		}
+		if names[q.Name] {
+			return fmt.Errorf("dql: duplicate query %q", q.Name)
+		}
+		names[q.Name] = true
+		for _, f := range q.fragments() {
+			if shared := fragments[f.Name]; shared != nil && canonicalFragment(f) != canonicalFragment(shared) {
+				return fmt.Errorf("dql: query %q: fragment %q does not match the shared definition", q.Name, f.Name)
+			}
+		}
+		for _, p := range q.Params {
+			if shared := params[p.Ref().String()]; shared != nil && (p.Type != shared.Type || p.Default != shared.Default) {
+				return fmt.Errorf("dql: query %q: param %s does not match the shared param %s", q.Name, p, shared)
+			}
+		}
+		if err := d.assemble(q).Validate(); err != nil {
+			return fmt.Errorf("dql: query %q: %w", q.Name, err)
+		}
+	}
+	return nil
+}
+
+// Register validates the document and registers its queries, completed as returned by Query,
+// under their names.
+//
+// Parameters:
+//   - r: The Registry the queries are registered with.
+//
+// Returns:
+//   - An error if the document is invalid or a name is already registered.
+func (d *Document) Register(r *Registry) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	for _, q := range d.Queries {
+		if err := r.Register(q.Name, d.assemble(q)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newUserDocument returns a document whose queries share a fragment and a param.
+func newUserDocument() *Document {
+	return NewDocument(
+		NewQuery("GetUser", NewQueryBlock("me", Eq("email", ParamRef("$email"))).WithAttributes(NewAttribute("...userFields"))),
+		NewQuery("ListUsers", NewQueryBlock("users", Has("email")).WithFirst(ParamRef("$first")).WithAttributes(NewAttribute("...userFields"))),
+	).WithFragments(
+		NewFragment("userFields").WithAttributes(NewAttribute("name"), NewAttribute("...addressFields")),
+		NewFragment("addressFields").WithAttributes(NewAttribute("city")),
+	).WithParams(NewParam("email", ParamString), NewParam("first", ParamInt).WithDefault("10"))
+}
+
+func TestDocument(t *testing.T) {
+	d := newUserDocument()
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := d.Names(); !reflect.DeepEqual(got, []string{"GetUser", "ListUsers"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	want := `query GetUser ( $email: string ) { me (func: eq(email, $email)) { ...userFields } } ` +
+		`fragment userFields { name ...addressFields } fragment addressFields { city }`
+	if got := d.Query("GetUser").String(); got != want {
+		t.Errorf("Query() = %s, want %s", got, want)
+	}
+	if q := d.Query("ListUsers"); len(q.Params) != 1 || q.Params[0].Default != "10" {
+		t.Errorf("Query() params = %v, want only $first", q.Params)
+	}
+	if d.Query("missing") != nil {
+		t.Error("Query() of an unknown name is not nil")
+	}
+	if len(d.Queries[0].Params) != 0 || len(d.Queries[0].Fragments) != 0 {
+		t.Error("Query() modified the queries of the document")
+	}
+
+	r := NewRegistry()
+	if err := d.Register(r); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"GetUser", "ListUsers"}) {
+		t.Errorf("registered %v", got)
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(d *Document)
+		wantErr string
+	}{
+		{"unnamed query", func(d *Document) { d.WithQueries(NewQuery("", NewQueryBlock("x", Has("a")))) }, "dql: unnamed query in document"},
+		{"duplicate query", func(d *Document) { d.WithQueries(NewQuery("GetUser", NewQueryBlock("x", Has("a")))) }, `dql: duplicate query "GetUser"`},
+		{"duplicate fragment", func(d *Document) { d.WithFragments(NewFragment("userFields").WithAttributes(NewAttribute("x"))) },
+			`dql: duplicate shared fragment "userFields"`},
+		{"duplicate param", func(d *Document) { d.WithParams(NewParam("email", ParamString)) }, "dql: duplicate shared param $email"},
+		{"mismatched param", func(d *Document) { d.Queries[1].WithParam(NewParam("first", ParamInt).WithDefault("5")) },
+			`dql: query "ListUsers": param $first: int = 5 does not match the shared param $first: int = 10`},
+		{"mismatched fragment", func(d *Document) {
+			d.Queries[0].WithFragments(NewFragment("addressFields").WithAttributes(NewAttribute("zip")))
+		},
+			`dql: query "GetUser": fragment "addressFields" does not match the shared definition`},
+		{"invalid query", func(d *Document) { d.WithQueries(NewQuery("Bad", NewQueryBlock("x", Eq("a", ParamRef("$missing"))))) },
+			`dql: query "Bad": dql: undeclared param $missing`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUserDocument()
+			tt.edit(d)
+			if got := errString(d.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+			if err := d.Register(NewRegistry()); errString(err) != tt.wantErr {
+				t.Errorf("Register() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT renders the structure of a query as a Graphviz DOT graph, to visualize complex
+// queries while debugging or reviewing them.
+//
+// Blocks and fragments are drawn as boxes holding a tree of their attributes. Dashed edges
+// lead from the block or attribute defining a variable to the blocks and attributes using
+// it, labeled with the name of the variable, and dotted edges lead from fragment spreads to
+// the fragments. Raw blocks and attributes are drawn with their DQL as label.
+//
+// Parameters:
+//   - q: The query to render.
+//
+// Returns:
+//   - The DOT source of the graph, e.g. for dot -Tsvg.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Uid("friends")).WithAttributes(NewAttribute("name"))).
+//	    WithVarBlocks(NewVarBlock(Has("user")).WithName("friends"))
+//	os.WriteFile("query.dot", []byte(ToDOT(query)), 0o644)
+func ToDOT(q *Query) string {
+	g := &dotGraph{defs: map[string]string{}}
+	for _, vb := range q.VarBlocks {
+		label := "var"
+		if vb.Name != "" {
+			label = vb.Name + " as var"
+		}
+		if vb.Raw {
+			label = vb.String()
+		}
+		id := g.node(label, "box", vb.Criteria, vb.Directives)
+		if vb.Name != "" {
+			g.defs[vb.Name] = id
+		}
+		g.attributes(id, vb.Attributes)
+	}
+	for _, sp := range q.ShortestPaths {
+		id := g.node(sp.Name+" as shortest\n(from: "+sp.From+", to: "+sp.To+")", "box", nil, nil)
+		g.refs = append(g.refs, dotRef{id: id, text: sp.From + " " + sp.To, bare: true})
+		if sp.Name != "" {
+			g.defs[sp.Name] = id
+		}
+		g.attributes(id, sp.Attributes)
+	}
+	for _, qb := range q.QueryBlocks {
+		label := qb.Name
+		if qb.Raw {
+			label = qb.String()
+		}
+		id := g.node(label, "box", qb.Criteria, qb.Directives)
+		g.attributes(id, qb.Attributes)
+	}
+	fragments := map[string]string{}
+	for _, f := range q.fragments() {
+		id := g.node("fragment "+f.Name, "folder", nil, nil)
+		fragments[f.Name] = id
+		g.attributes(id, f.Attributes)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph query {\n\trankdir=LR;\n")
+	b.WriteString(g.body.String())
+	for _, s := range g.spreads {
+		if id, ok := fragments[s.text]; ok {
+			fmt.Fprintf(&b, "\t%s -> %s [style=dotted];\n", s.id, id)
+		}
+	}
+	for _, r := range g.refs {
+		for _, name := range dotVariables(r.text, r.bare) {
+			if def, ok := g.defs[name]; ok {
+				fmt.Fprintf(&b, "\t%s -> %s [style=dashed, label=%q];\n", def, r.id, name)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotGraph accumulates the nodes and edges of the graph rendered by ToDOT.
+type dotGraph struct {
+	body    strings.Builder
+	count   int
+	defs    map[string]string
+	refs    []dotRef
+	spreads []dotRef
+}
+
+// dotRef records the text of a node referring to variables or fragments, resolved once all
+// definitions are known.
+type dotRef struct {
+	id   string
+	text string
+	bare bool
+}
+
+// node adds a node for a block, recording the variables used by its criteria and directives.
+func (g *dotGraph) node(label string, shape string, criteria []Criteria, directives []Criteria) string {
+	id := fmt.Sprintf("n%d", g.count)
+	g.count++
+	parts := []string{}
+	for _, c := range criteria {
+		parts = append(parts, c.String())
+	}
+	if len(parts) != 0 && shape == "box" {
+		label += "\n(" + strings.Join(parts, ", ") + ")"
+	}
+	for _, d := range directives {
+		parts = append(parts, d.String())
+		label += "\n" + d.String()
+	}
+	fmt.Fprintf(&g.body, "\t%s [shape=%s, label=\"%s\"];\n", id, shape, dotEscape(label))
+	g.refs = append(g.refs, dotRef{id: id, text: strings.Join(parts, " ")})
+	return id
+}
+
+// attributes adds the nodes of a tree of attributes below the node parent.
+func (g *dotGraph) attributes(parent string, attrs []*Attribute) {
+	for _, a := range attrs {
+		id := fmt.Sprintf("n%d", g.count)
+		g.count++
+		label := a.String()
+		if !a.Raw {
+			label = a.Name
+			if a.Alias != "" {
+				label = a.Alias + " : " + label
+			}
+			if a.Var != "" {
+				label = a.Var + " as " + label
+			}
+			for _, d := range a.Directives {
+				label += "\n" + d.String()
+			}
+		}
+		fmt.Fprintf(&g.body, "\t%s [shape=plaintext, label=\"%s\"];\n\t%s -> %s;\n", id, dotEscape(label), parent, id)
+		if a.Raw {
+			continue
+		}
+		if a.Var != "" {
+			g.defs[a.Var] = id
+		}
+		if name, ok := strings.CutPrefix(a.Name, "..."); ok {
+			g.spreads = append(g.spreads, dotRef{id: id, text: name})
+			continue
+		}
+		text := a.Name
+		for _, c := range append(append([]Criteria{}, a.Args...), a.Directives...) {
+			text += " " + c.String()
+		}
+		g.refs = append(g.refs, dotRef{id: id, text: text})
+		g.attributes(id, a.Attributes)
+	}
+}
+
+// dotVariables returns the variables referenced by text with uid(), val() or math(), and its
+// bare words too if bare is set, as in the endpoints of shortest path blocks.
+func dotVariables(text string, bare bool) []string {
+	if bare {
+		return wordPattern.FindAllString(text, -1)
+	}
+	names := []string{}
+	for _, m := range variableRefPattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, wordPattern.FindAllString(m[1], -1)...)
+	}
+	return names
+}
+
+// dotEscapeReplacer escapes the labels of the graph, turning line breaks into DOT \n escapes.
+var dotEscapeReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+// dotEscape escapes a label for a quoted DOT string.
+func dotEscape(label string) string {
+	return dotEscapeReplacer.Replace(label)
+}
@@ -0,0 +1,49 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("me", Uid("friends")).WithAttributes(NewAttribute("name").WithAlias("n"), NewAttribute("...userFields"))).
+		WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends")).
+		WithFragments(NewFragment("userFields").WithAttributes(NewAttribute("email")))
+	want := `digraph query {
+	rankdir=LR;
+	n0 [shape=box, label="friends as var\n(has(friend))"];
+	n1 [shape=box, label="me\n(uid(friends))"];
+	n2 [shape=plaintext, label="n : name"];
+	n1 -> n2;
+	n3 [shape=plaintext, label="...userFields"];
+	n1 -> n3;
+	n4 [shape=folder, label="fragment userFields"];
+	n5 [shape=plaintext, label="email"];
+	n4 -> n5;
+	n3 -> n4 [style=dotted];
+	n0 -> n1 [style=dashed, label="friends"];
+}
+`
+	if got := ToDOT(q); got != want {
+		t.Errorf("ToDOT() = %s, want %s", got, want)
+	}
+}
+
+func TestToDOTVariables(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("path", Uid("p")).WithAttributes(NewAttribute("val(a)"))).
+		WithVarBlocks(NewVarBlock(Has("age")).WithAttributes(NewAttribute("age").WithVar("a"))).
+		WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("friend"))).
+		WithQueryBlocks(NewQueryBlock("q", Eq("name", `say "hi"`)))
+	got := ToDOT(q)
+	for _, want := range []string{
+		`n0 [shape=box, label="var\n(has(age))"];`,
+		`n2 [shape=box, label="p as shortest\n(from: 0x1, to: 0x2)"];`,
+		`n1 -> n5 [style=dashed, label="a"];`,
+		`n2 -> n4 [style=dashed, label="p"];`,
+		`label="q\n(eq(name, \"say \\\"hi\\\"\"))"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToDOT() has no %s:\n%s", want, got)
+		}
+	}
+}
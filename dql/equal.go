@@ -0,0 +1,255 @@
+package dql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether two queries are semantically equal.
+//
+// The comparison is performed on the AST rather than on the rendered text, so formatting is
+// irrelevant. Orderings that do not affect the meaning of a query are ignored as well: the
+// order of attributes within a selection, of parameters, of blocks and fragments, and of the
+// arguments following the root function of a block.
+//
+// Parameters:
+//   - a: The first query.
+//   - b: The second query.
+//
+// Returns:
+//   - true if both queries are semantically equal, false otherwise.
+//
+// Example:
+//
+//	a := NewQuery("", NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("name"), NewAttribute("age")))
+//	b := NewQuery("", NewQueryBlock("me", "has(user)").
+//	    WithAttributes(NewAttribute("age"), NewAttribute("name")))
+//	fmt.Println(Equal(a, b)) // Output: true
+func Equal(a, b *Query) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff lists the semantic differences between two queries.
+//
+// Blocks, parameters and fragments are matched by name and compared in their canonical form,
+// using the same rules as Equal. Declarations sharing a name are matched in order, the second
+// one being reported as e.g. query block "me" #2. Each difference is reported as a single
+// human-readable line.
+//
+// Parameters:
+//   - a: The first query.
+//   - b: The second query.
+//
+// Returns:
+//   - The list of differences, empty if the queries are semantically equal.
+//
+// Example:
+//
+//	a := NewQuery("", NewQueryBlock("me", "has(user)"))
+//	b := NewQuery("", NewQueryBlock("me", "has(post)"))
+//	fmt.Println(Diff(a, b))
+//	// Output: [query block "me": "me (func: has(user)) { }" != "me (func: has(post)) { }"]
+func Diff(a, b *Query) []string {
+	diffs := []string{}
+	if a.Name != b.Name {
+		diffs = append(diffs, fmt.Sprintf("query name: %q != %q", a.Name, b.Name))
+	}
+
+	paramsA, paramsB := map[string][]string{}, map[string][]string{}
+	for _, p := range a.Params {
+		paramsA[p.Ref().String()] = append(paramsA[p.Ref().String()], p.String())
+	}
+	for _, p := range b.Params {
+		paramsB[p.Ref().String()] = append(paramsB[p.Ref().String()], p.String())
+	}
+	diffs = append(diffs, diffKeyed("param", paramsA, paramsB)...)
+
+	varsA, varsB := map[string][]string{}, map[string][]string{}
+	unnamedA, unnamedB := []string{}, []string{}
+	for _, vb := range a.VarBlocks {
+		if vb.Name == "" || vb.Raw {
+			unnamedA = append(unnamedA, canonicalVarBlock(vb))
+			continue
+		}
+		varsA[vb.Name] = append(varsA[vb.Name], canonicalVarBlock(vb))
+	}
+	for _, vb := range b.VarBlocks {
+		if vb.Name == "" || vb.Raw {
+			unnamedB = append(unnamedB, canonicalVarBlock(vb))
+			continue
+		}
+		varsB[vb.Name] = append(varsB[vb.Name], canonicalVarBlock(vb))
+	}
+	diffs = append(diffs, diffKeyed("var block", varsA, varsB)...)
+	sort.Strings(unnamedA)
+	sort.Strings(unnamedB)
+	if strings.Join(unnamedA, "\n") != strings.Join(unnamedB, "\n") {
+		diffs = append(diffs, fmt.Sprintf("unnamed var blocks: %q != %q", unnamedA, unnamedB))
+	}
+
+	pathsA, pathsB := map[string][]string{}, map[string][]string{}
+	for _, sp := range a.ShortestPaths {
+		pathsA[sp.Name] = append(pathsA[sp.Name], canonicalShortestPath(sp))
+	}
+	for _, sp := range b.ShortestPaths {
+		pathsB[sp.Name] = append(pathsB[sp.Name], canonicalShortestPath(sp))
+	}
+	diffs = append(diffs, diffKeyed("shortest path", pathsA, pathsB)...)
+
+	blocksA, blocksB := map[string][]string{}, map[string][]string{}
+	for _, qb := range a.QueryBlocks {
+		blocksA[qb.Name] = append(blocksA[qb.Name], canonicalQueryBlock(qb))
+	}
+	for _, qb := range b.QueryBlocks {
+		blocksB[qb.Name] = append(blocksB[qb.Name], canonicalQueryBlock(qb))
+	}
+	diffs = append(diffs, diffKeyed("query block", blocksA, blocksB)...)
+
+	fragmentsA, fragmentsB := map[string][]string{}, map[string][]string{}
+	for _, f := range a.fragments() {
+		fragmentsA[f.Name] = append(fragmentsA[f.Name], canonicalFragment(f))
+	}
+	for _, f := range b.fragments() {
+		fragmentsB[f.Name] = append(fragmentsB[f.Name], canonicalFragment(f))
+	}
+	diffs = append(diffs, diffKeyed("fragment", fragmentsA, fragmentsB)...)
+
+	return diffs
+}
+
+// diffKeyed compares two sets of canonical strings indexed by name. The declarations sharing a
+// name are compared in order.
+func diffKeyed(kind string, a, b map[string][]string) []string {
+	names := []string{}
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diffs := []string{}
+	for _, name := range names {
+		for i := 0; i < max(len(a[name]), len(b[name])); i++ {
+			label := fmt.Sprintf("%s %q", kind, name)
+			if i > 0 {
+				label += fmt.Sprintf(" #%d", i+1)
+			}
+			switch {
+			case i >= len(b[name]):
+				diffs = append(diffs, label+": only in a")
+			case i >= len(a[name]):
+				diffs = append(diffs, label+": only in b")
+			case a[name][i] != b[name][i]:
+				diffs = append(diffs, fmt.Sprintf("%s: %q != %q", label, a[name][i], b[name][i]))
+			}
+		}
+	}
+	return diffs
+}
+
+// canonicalCriteria renders the argument list of a block, keeping the root function first and
+// sorting the remaining arguments.
+func canonicalCriteria(criteria []Criteria) string {
+	if len(criteria) == 0 || !isRootFunction(criteria[0]) {
+		return blockArguments(sortedCriteria(criteria))
+	}
+	return blockArguments(append([]Criteria{criteria[0]}, sortedCriteria(criteria[1:])...))
+}
+
+// sortedCriteria returns a copy of criteria sorted by their rendering. Ordering arguments are
+// moved last but keep their relative order, which defines the primary and secondary sort keys.
+func sortedCriteria(criteria []Criteria) []Criteria {
+	res := append([]Criteria(nil), criteria...)
+	key := func(c Criteria) string {
+		if a, ok := c.(*Arg); ok && (a.Name == "orderasc" || a.Name == "orderdesc") {
+			return "\xff"
+		}
+		return c.String()
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return key(res[i]) < key(res[j])
+	})
+	return res
+}
+
+// canonicalAttributes renders a selection set with its attributes sorted.
+func canonicalAttributes(attrs []*Attribute) string {
+	if len(attrs) == 0 {
+		return "{ }"
+	}
+	components := make([]string, len(attrs))
+	for i, a := range attrs {
+		components[i] = canonicalAttribute(a)
+	}
+	sort.Strings(components)
+	return "{ " + strings.Join(components, " ") + " }"
+}
+
+func canonicalAttribute(a *Attribute) string {
+	components := []string{}
+	if a.Alias != "" {
+		components = append(components, a.Alias, ":")
+	}
+	if a.Var != "" {
+		components = append(components, a.Var, "as")
+	}
+	components = append(components, a.Name)
+	if len(a.Args) != 0 {
+		components = append(components, "("+joinCriteria(sortedCriteria(a.Args))+")")
+	}
+	for _, d := range a.Directives {
+		components = append(components, d.String())
+	}
+	if len(a.Attributes) != 0 {
+		components = append(components, canonicalAttributes(a.Attributes))
+	}
+	return strings.Join(components, " ")
+}
+
+func canonicalQueryBlock(qb *QueryBlock) string {
+	if qb.Raw {
+		return qb.Name
+	}
+	components := []string{qb.Name, canonicalCriteria(qb.Criteria)}
+	for _, d := range qb.Directives {
+		components = append(components, d.String())
+	}
+	components = append(components, canonicalAttributes(qb.Attributes))
+	return strings.Join(components, " ")
+}
+
+func canonicalVarBlock(vb *VarBlock) string {
+	if vb.Raw {
+		return vb.Name
+	}
+	components := []string{}
+	if vb.Name != "" {
+		components = append(components, vb.Name, "AS")
+	}
+	components = append(components, "var", canonicalCriteria(vb.Criteria))
+	for _, d := range vb.Directives {
+		components = append(components, d.String())
+	}
+	components = append(components, canonicalAttributes(vb.Attributes))
+	return strings.Join(components, " ")
+}
+
+func canonicalShortestPath(sp *ShortestPath) string {
+	components := []string{}
+	if sp.Name != "" {
+		components = append(components, sp.Name, "AS")
+	}
+	components = append(components, fmt.Sprintf("shortest(%s)", sp.arguments()))
+	components = append(components, canonicalAttributes(sp.Attributes))
+	return strings.Join(components, " ")
+}
+
+func canonicalFragment(f *Fragment) string {
+	return "fragment " + f.Name + " " + canonicalAttributes(f.Attributes)
+}
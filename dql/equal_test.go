@@ -0,0 +1,64 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Query
+		want bool
+	}{
+		{"attribute order", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(NewAttribute("name"), NewAttribute("age"))),
+			NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(NewAttribute("age"), NewAttribute("name"))), true},
+		{"argument order", NewQuery("", NewQueryBlock("me", "has(user)").WithCriteria("first: 10", "offset: 5")),
+			NewQuery("", NewQueryBlock("me", "has(user)").WithCriteria("offset: 5", "first: 10")), true},
+		{"block order", NewQuery("", NewQueryBlock("a", "has(a)")).WithQueryBlocks(NewQueryBlock("b", "has(b)")),
+			NewQuery("", NewQueryBlock("b", "has(b)")).WithQueryBlocks(NewQueryBlock("a", "has(a)")), true},
+		{"root function", NewQuery("", NewQueryBlock("me", "has(user)")),
+			NewQuery("", NewQueryBlock("me", "has(post)")), false},
+		{"nested attribute", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(NewAttribute("friend").WithAttributes(NewAttribute("name")))),
+			NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(NewAttribute("friend").WithAttributes(NewAttribute("age")))), false},
+		{"unnamed var blocks", NewQuery("", NewQueryBlock("me", "uid(a)")).WithVarBlocks(NewVarBlock("has(a)"), NewVarBlock("has(b)")),
+			NewQuery("", NewQueryBlock("me", "uid(a)")).WithVarBlocks(NewVarBlock("has(b)"), NewVarBlock("has(a)")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v, diff %q", got, tt.want, Diff(tt.a, tt.b))
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Query
+		want []string
+	}{
+		{"equal", NewQuery("Q", NewQueryBlock("me", "has(user)")), NewQuery("Q", NewQueryBlock("me", "has(user)")), []string{}},
+		{"name", NewQuery("Q", NewQueryBlock("me", "has(user)")), NewQuery("R", NewQueryBlock("me", "has(user)")),
+			[]string{`query name: "Q" != "R"`}},
+		{"query block", NewQuery("", NewQueryBlock("me", "has(user)")), NewQuery("", NewQueryBlock("me", "has(post)")),
+			[]string{`query block "me": "me (func: has(user)) { }" != "me (func: has(post)) { }"`}},
+		{"only in one", NewQuery("", NewQueryBlock("me", "has(user)")).WithParam(NewParam("$a", "int")),
+			NewQuery("", NewQueryBlock("me", "has(user)")).WithFragments(NewFragment("f")),
+			[]string{`param "$a": only in a`, `fragment "f": only in b`}},
+		{"duplicate name", NewQuery("", NewQueryBlock("me", "has(user)")).WithQueryBlocks(NewQueryBlock("me", "has(post)")),
+			NewQuery("", NewQueryBlock("me", "has(user)")).WithQueryBlocks(NewQueryBlock("me", "has(comment)")),
+			[]string{`query block "me" #2: "me (func: has(post)) { }" != "me (func: has(comment)) { }"`}},
+		{"duplicate only in one", NewQuery("", NewQueryBlock("me", "has(user)")).WithQueryBlocks(NewQueryBlock("me", "has(post)")),
+			NewQuery("", NewQueryBlock("me", "has(user)")),
+			[]string{`query block "me" #2: only in a`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package dql
+
+import "errors"
+
+// ErrorKind is a kind of error of the package, which callers test with errors.Is rather than
+// by matching error messages.
+//
+// Kinds form a hierarchy: an error of a kind is also an error of its parent kind, e.g. an
+// ErrUnknownVariable error is an ErrValidation error.
+type ErrorKind struct {
+	// name describes the kind.
+	name string
+
+	// parent is the kind this kind refines, or nil.
+	parent *ErrorKind
+}
+
+var (
+	// ErrValidation is the kind of the errors of invalid queries, reported by Validate.
+	ErrValidation = &ErrorKind{name: "dql: invalid query"}
+
+	// ErrUnknownVariable is the kind of the errors of references to variables, parameters or
+	// query variables that are not defined or declared. It refines ErrValidation.
+	ErrUnknownVariable = &ErrorKind{name: "dql: unknown variable", parent: ErrValidation}
+
+	// ErrSchemaMismatch is the kind of the errors of queries not suiting the schema of the
+	// database, such as values of the wrong type, see Query.WithSchema. It refines
+	// ErrValidation.
+	ErrSchemaMismatch = &ErrorKind{name: "dql: schema mismatch", parent: ErrValidation}
+
+	// ErrSyntax is the kind of the errors of malformed DQL, schemas and uids, reported by
+	// Parse, ParseSchema and ParseUID.
+	ErrSyntax = &ErrorKind{name: "dql: syntax error"}
+
+	// ErrExec is the kind of the errors of executions, reported by executors when a request
+	// fails or Dgraph rejects a query. The underlying error, such as a context or network
+	// error, remains available through errors.Is and errors.As.
+	ErrExec = &ErrorKind{name: "dql: execution failed"}
+)
+
+// Error describes the kind.
+//
+// Returns:
+//   - The description of the kind.
+func (k *ErrorKind) Error() string {
+	return k.name
+}
+
+// Is reports whether the kind refines target, so errors.Is matches the parent kinds of a kind.
+//
+// Parameters:
+//   - target: The error to compare the kind with.
+//
+// Returns:
+//   - True if target is a parent kind of the kind.
+func (k *ErrorKind) Is(target error) bool {
+	for p := k.parent; p != nil; p = p.parent {
+		if p == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Error is an error of the package classified by kind.
+//
+// Its message is the message of the underlying error. Callers branch on the kind with
+// errors.Is, or read it with errors.As.
+//
+// Example:
+//
+//	_, err := Parse("{ me (func: has(name) { name } }")
+//	if errors.Is(err, ErrSyntax) {
+//	    // report the malformed query to its author
+//	}
+//	var dqlErr *Error
+//	if errors.As(err, &dqlErr) {
+//	    fmt.Println(dqlErr.Kind) // Output: dql: syntax error
+//	}
+type Error struct {
+	// Kind is the kind of the error.
+	Kind *ErrorKind
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error.
+//
+// Returns:
+//   - The message of the error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the underlying error, so errors.Is and errors.As match both.
+//
+// Returns:
+//   - The kind and the underlying error.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// wrapError classifies err with a kind, unless err is nil or already classified, in which case
+// it is returned as is, keeping the more specific kind of nested calls.
+func wrapError(kind *ErrorKind, err error) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
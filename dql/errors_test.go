@@ -0,0 +1,52 @@
+package dql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	schema, err := ParseSchema("age: int .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, parseErr := Parse("{ me (")
+	_, uidErr := ParseUID("alice")
+	tests := []struct {
+		name string
+		err  error
+		is   []*ErrorKind
+		not  []*ErrorKind
+	}{
+		{"unknown variable", NewQuery("", NewQueryBlock("me", Uid("f"))).Validate(),
+			[]*ErrorKind{ErrUnknownVariable, ErrValidation}, []*ErrorKind{ErrSchemaMismatch, ErrSyntax}},
+		{"schema mismatch", NewQuery("", NewQueryBlock("me", Eq("age", "old"))).WithSchema(schema).Validate(),
+			[]*ErrorKind{ErrSchemaMismatch, ErrValidation}, []*ErrorKind{ErrUnknownVariable}},
+		{"invalid block", NewQuery("", NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute("a").WithAlias("x"), NewAttribute("b").WithAlias("x"))).Validate(),
+			[]*ErrorKind{ErrValidation}, []*ErrorKind{ErrUnknownVariable, ErrSchemaMismatch}},
+		{"missing variable", NewQuery("Q", NewQueryBlock("me", Has("name"))).WithParam(NewParam("a", ParamInt)).ValidateVars(nil),
+			[]*ErrorKind{ErrValidation}, []*ErrorKind{ErrUnknownVariable}},
+		{"undeclared variable", NewQuery("Q", NewQueryBlock("me", Has("name"))).ValidateVars(map[string]string{"$a": "1"}),
+			[]*ErrorKind{ErrUnknownVariable, ErrValidation}, nil},
+		{"syntax", parseErr, []*ErrorKind{ErrSyntax}, []*ErrorKind{ErrValidation}},
+		{"uid", uidErr, []*ErrorKind{ErrSyntax}, []*ErrorKind{ErrValidation}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, kind := range tt.is {
+				if !errors.Is(tt.err, kind) {
+					t.Errorf("errors.Is(%v, %v) = false", tt.err, kind)
+				}
+			}
+			for _, kind := range tt.not {
+				if errors.Is(tt.err, kind) {
+					t.Errorf("errors.Is(%v, %v) = true", tt.err, kind)
+				}
+			}
+			var e *Error
+			if !errors.As(tt.err, &e) || e.Kind != tt.is[0] {
+				t.Errorf("errors.As() = %v, want kind %v", e, tt.is[0])
+			}
+		})
+	}
+}
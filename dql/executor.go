@@ -0,0 +1,57 @@
+package dql
+
+import (
+	"context"
+	"time"
+)
+
+// Response is the response of Dgraph to a query.
+type Response struct {
+	// Json is the JSON data of the response, holding the results of each block.
+	Json []byte
+
+	// Extensions is the JSON of the extensions of the response, holding the latency and the
+	// metrics of the query, nil if the Executor does not report them.
+	Extensions []byte
+}
+
+// Executor runs queries against Dgraph.
+//
+// The package does not depend on a Dgraph client: applications implement Executor on top of
+// the client they use, and tests can use the mock of the dqltest package.
+type Executor interface {
+	// Execute runs a query with its variables, given by name with their leading $.
+	Execute(ctx context.Context, q *Query, vars map[string]string) (*Response, error)
+}
+
+// ExecutorFunc adapts a function to the Executor interface.
+type ExecutorFunc func(ctx context.Context, q *Query, vars map[string]string) (*Response, error)
+
+// Execute calls f(ctx, q, vars).
+func (f ExecutorFunc) Execute(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+	return f(ctx, q, vars)
+}
+
+// QueryTimeout returns the time left before the deadline of ctx, for executors to pass it to
+// Dgraph as the timeout of the query, so the Alpha stops working on a query its caller has
+// given up on.
+//
+// Parameters:
+//   - ctx: The context of the execution.
+//
+// Returns:
+//   - The time left, at least 1ms so an expiring deadline never means no timeout.
+//   - False if ctx has no deadline.
+//
+// Example:
+//
+//	if timeout, ok := QueryTimeout(ctx); ok {
+//	    req.Timeout = timeout // e.g. the timeout field of a gRPC request
+//	}
+func QueryTimeout(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return max(time.Until(deadline), time.Millisecond), true
+}
@@ -0,0 +1,98 @@
+package dql
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// existsBlockName is the name of the block of the queries created by Exists.
+	existsBlockName = "exists"
+
+	// firstMatchBlockName is the name of the block of the queries created by FirstMatch.
+	firstMatchBlockName = "match"
+)
+
+// Exists creates a query checking whether any node matches a root function. The block returns
+// the uid of at most one node, so Dgraph stops at the first match; DecodeExists reads the
+// answer.
+//
+// Parameters:
+//   - criteria: The root function, either a Criteria or a string.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := Exists(Eq("username", "alice"))
+//	fmt.Println(query.String()) // Output: { exists (func: eq(username, "alice"), first: 1) { uid } }
+//	resp, err := exec.Execute(ctx, query, nil)
+//	taken, err := DecodeExists(resp.Json)
+func Exists(criteria any) *Query {
+	qb := NewQueryBlock(existsBlockName, criteria).WithFirst(1).WithAttributes(UIDAttribute())
+	return NewQuery("", qb)
+}
+
+// DecodeExists reads the answer of a query created by Exists from the JSON data of its
+// response.
+//
+// Parameters:
+//   - data: The JSON data of the response.
+//
+// Returns:
+//   - True if a node matched.
+//   - An error if the data cannot be decoded.
+func DecodeExists(data []byte) (bool, error) {
+	var blocks map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return false, fmt.Errorf("dql: decode: %w", err)
+	}
+	return len(blocks[existsBlockName]) != 0, nil
+}
+
+// FirstMatch creates a query fetching the first node matching a root function, with the
+// attributes of a selection; DecodeFirstMatch decodes it.
+//
+// Parameters:
+//   - criteria: The root function, either a Criteria or a string.
+//   - selection: The attributes to fetch.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := FirstMatch(Eq("email", "alice@example.com"), NewSelection(UIDAttribute(), NewAttribute("name")))
+//	fmt.Println(query.String()) // Output: { match (func: eq(email, "alice@example.com"), first: 1) { uid name } }
+//	resp, err := exec.Execute(ctx, query, nil)
+//	user, err := DecodeFirstMatch[User](resp.Json)
+func FirstMatch(criteria any, selection *Selection) *Query {
+	qb := NewQueryBlock(firstMatchBlockName, criteria).WithFirst(1).WithSelection(selection)
+	return NewQuery("", qb)
+}
+
+// DecodeFirstMatch decodes the node returned by a query created by FirstMatch from the JSON
+// data of its response, with Decode.
+//
+// Parameters:
+//   - data: The JSON data of the response.
+//
+// Returns:
+//   - A pointer to the decoded node, or nil if no node matched.
+//   - An error if the data cannot be decoded.
+func DecodeFirstMatch[T any](data []byte) (*T, error) {
+	var blocks map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("dql: decode: %w", err)
+	}
+	nodes := blocks[firstMatchBlockName]
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	var node T
+	if err := Decode(nodes[0], &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
@@ -0,0 +1,50 @@
+package dql
+
+import "testing"
+
+func TestExists(t *testing.T) {
+	q := Exists(Eq("email", "a@x.io"))
+	if got, want := q.String(), `{ exists (func: eq(email, "a@x.io"), first: 1) { uid } }`; got != want {
+		t.Errorf("Exists() = %s, want %s", got, want)
+	}
+	tests := []struct {
+		data    string
+		want    bool
+		wantErr string
+	}{
+		{`{"exists": [{"uid": "0x1"}]}`, true, ""},
+		{`{"exists": []}`, false, ""},
+		{`{}`, false, ""},
+		{`[`, false, "dql: decode: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			got, err := DecodeExists([]byte(tt.data))
+			if got != tt.want || errString(err) != tt.wantErr {
+				t.Errorf("DecodeExists() = %v, %q, want %v, %q", got, errString(err), tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFirstMatch(t *testing.T) {
+	q := FirstMatch(Eq("email", "a@x.io"), NewSelection(UIDAttribute(), NewAttribute("name")))
+	if got, want := q.String(), `{ match (func: eq(email, "a@x.io"), first: 1) { uid name } }`; got != want {
+		t.Errorf("FirstMatch() = %s, want %s", got, want)
+	}
+	type user struct {
+		UID  UID    `dql:"uid"`
+		Name string `dql:"name"`
+	}
+	u, err := DecodeFirstMatch[user]([]byte(`{"match": [{"uid": "0x2", "name": "Alice"}]}`))
+	if err != nil || u == nil || u.UID != 0x2 || u.Name != "Alice" {
+		t.Errorf("DecodeFirstMatch() = %+v, %v, want Alice", u, err)
+	}
+	u, err = DecodeFirstMatch[user]([]byte(`{"match": []}`))
+	if err != nil || u != nil {
+		t.Errorf("DecodeFirstMatch() of no match = %+v, %v, want nil", u, err)
+	}
+	if _, err := DecodeFirstMatch[user]([]byte(`{"match": [{"name": 1}]}`)); err == nil {
+		t.Error("DecodeFirstMatch() of a mistyped node succeeded")
+	}
+}
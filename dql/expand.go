@@ -0,0 +1,67 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expandArgs lists the arguments Dgraph accepts on expand(): pagination only, since the
+// expanded edges have no common predicate to order by.
+var expandArgs = map[string]bool{"first": true, "offset": true, "after": true}
+
+// ExpandAttribute creates an expand() attribute selecting every predicate of the given types,
+// or of the types of each node if no type is given, as ExpandAllAttribute.
+//
+// The attribute can be bounded like an edge: pagination arguments, such as WithFirst, and a
+// @filter directive apply to the nodes each expanded uid edge leads to, and nested attributes
+// select their predicates. Validate rejects what Dgraph does not allow on expand(): aliases,
+// variables, ordering and directives other than @filter.
+//
+// Parameters:
+//   - types: The names of the types whose predicates are expanded, or none for _all_.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+//
+// Example:
+//
+//	attr := ExpandAttribute().WithFirst(10).WithAttributes(NewAttribute("uid"), NewAttribute("name"))
+//	fmt.Println(attr.String()) // Output: expand(_all_) (first: 10) { uid name }
+//
+//	attr = ExpandAttribute("Person", "Employee")
+//	fmt.Println(attr.String()) // Output: expand(Person, Employee)
+//
+// See: https://dgraph.io/docs/query-language/expand-predicates/
+func ExpandAttribute(types ...string) *Attribute {
+	if len(types) == 0 {
+		return ExpandAllAttribute()
+	}
+	return NewAttribute("expand(" + strings.Join(types, ", ") + ")")
+}
+
+// isExpand reports whether an attribute name is an expand() call.
+func isExpand(name string) bool {
+	return strings.HasPrefix(strings.TrimSpace(name), "expand(")
+}
+
+// validateExpand checks that an expand() attribute only carries what Dgraph allows on it:
+// pagination arguments, a @filter directive and nested attributes.
+func validateExpand(a *Attribute) error {
+	if a.Alias != "" {
+		return fmt.Errorf("aliases are not allowed on expand()")
+	}
+	if a.Var != "" {
+		return fmt.Errorf("variables are not allowed on expand()")
+	}
+	for _, arg := range a.Args {
+		if name := argName(arg); !expandArgs[name] {
+			return fmt.Errorf("argument %q is not allowed on expand()", name)
+		}
+	}
+	for _, d := range a.Directives {
+		if name := directiveName(d); name != "filter" {
+			return fmt.Errorf("@%s is not allowed on expand()", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package dql
+
+import "testing"
+
+func TestExpandAttribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		attr    *Attribute
+		want    string
+		wantErr string
+	}{
+		{"all", ExpandAttribute(), "expand(_all_)", ""},
+		{"types", ExpandAttribute("Person", "Animal"), "expand(Person, Animal)", ""},
+		{"pagination and filter", ExpandAttribute("Person").WithFirst(2).WithDirectives(NewDirective("filter", Has("name"))),
+			"expand(Person) (first: 2) @filter(has(name))", ""},
+		{"ordering", ExpandAttribute().WithOrderAsc("name"), "", `dql: query block "me": expand(_all_): argument "orderasc" is not allowed on expand()`},
+		{"alias", ExpandAttribute().WithAlias("all"), "", `dql: query block "me": expand(_all_): aliases are not allowed on expand()`},
+		{"variable", ExpandAttribute().WithVar("x"), "", `dql: query block "me": expand(_all_): variables are not allowed on expand()`},
+		{"cascade", ExpandAttribute().WithDirectives("@cascade"), "", `dql: query block "me": expand(_all_): @cascade is not allowed on expand()`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want != "" && tt.attr.String() != tt.want {
+				t.Errorf("String() = %s, want %s", tt.attr.String(), tt.want)
+			}
+			q := NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(tt.attr))
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package dql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Explanation is the report of Explain on the execution of a query.
+type Explanation struct {
+	// Parsing is the time Dgraph spent parsing the query.
+	Parsing time.Duration
+
+	// Processing is the time Dgraph spent processing the query.
+	Processing time.Duration
+
+	// Encoding is the time Dgraph spent encoding the response.
+	Encoding time.Duration
+
+	// Total is the total time Dgraph spent on the query.
+	Total time.Duration
+
+	// StartTs is the timestamp of the transaction the query read.
+	StartTs uint64
+
+	// UIDs is the number of uids Dgraph read for each predicate, with the total under _total.
+	UIDs map[string]int
+
+	// Blocks is the report of each query block, in order of the query.
+	Blocks []*BlockExplanation
+}
+
+// BlockExplanation is the report of Explain on a query block.
+type BlockExplanation struct {
+	// Name is the name of the block.
+	Name string
+
+	// Nodes is the number of nodes the block returned at its root.
+	Nodes int
+
+	// UIDs lists the uids of every node the block returned, at any depth, without duplicates.
+	// Nested nodes follow their parent, with the edges of a node in alphabetical order.
+	UIDs []string
+}
+
+// Explain runs a query in debug mode and reports the latency and the metrics of its
+// execution.
+//
+// The query is cloned and run with WithDebug, so Dgraph returns the latency of each phase of
+// the execution, the number of uids read for each predicate and the uid of every node. The
+// uids are reported for each query block, tying the nodes touched by the query back to the
+// blocks that returned them. The latency and metrics are left empty with executors that do
+// not report the extensions of the response.
+//
+// Parameters:
+//   - ctx: The context of the execution.
+//   - exec: The Executor running the query.
+//   - q: The query to explain.
+//   - vars: The values of the variables of the query, by name with their leading $.
+//
+// Returns:
+//   - The report of the execution.
+//   - An error if the query failed or its response could not be decoded.
+//
+// Example:
+//
+//	report, err := Explain(ctx, client, query, nil)
+//	fmt.Println(report) // Output: total 1.2ms (parsing 45µs, processing 1.1ms, encoding 30µs), 42 uids read ...
+//
+// See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#debug
+func Explain(ctx context.Context, exec Executor, q *Query, vars map[string]string) (*Explanation, error) {
+	resp, err := exec.Execute(ctx, q.Clone().WithDebug(), vars)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Json, &data); err != nil {
+		return nil, fmt.Errorf("dql: explain query %q: %w", q.Name, err)
+	}
+	res := &Explanation{UIDs: map[string]int{}}
+	if len(resp.Extensions) != 0 {
+		var ext struct {
+			ServerLatency serverLatency `json:"server_latency"`
+			Txn           struct {
+				StartTs uint64 `json:"start_ts"`
+			} `json:"txn"`
+			Metrics struct {
+				NumUids map[string]int `json:"num_uids"`
+			} `json:"metrics"`
+		}
+		if err := json.Unmarshal(resp.Extensions, &ext); err != nil {
+			return nil, fmt.Errorf("dql: explain query %q: %w", q.Name, err)
+		}
+		res.Parsing = time.Duration(ext.ServerLatency.ParsingNs)
+		res.Processing = time.Duration(ext.ServerLatency.ProcessingNs)
+		res.Encoding = time.Duration(ext.ServerLatency.EncodingNs)
+		res.Total = time.Duration(ext.ServerLatency.TotalNs)
+		res.StartTs = ext.Txn.StartTs
+		for pred, n := range ext.Metrics.NumUids {
+			res.UIDs[pred] = n
+		}
+	}
+
+	for _, qb := range q.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		block := &BlockExplanation{Name: qb.Name}
+		var nodes []any
+		if raw, ok := data[qb.Name]; ok {
+			if err := json.Unmarshal(raw, &nodes); err != nil {
+				return nil, fmt.Errorf("dql: explain block %q: %w", qb.Name, err)
+			}
+		}
+		block.Nodes = len(nodes)
+		seen := map[string]bool{}
+		collectUIDs(nodes, func(uid string) {
+			if !seen[uid] {
+				seen[uid] = true
+				block.UIDs = append(block.UIDs, uid)
+			}
+		})
+		res.Blocks = append(res.Blocks, block)
+	}
+	return res, nil
+}
+
+// collectUIDs reports the uid of every object of a decoded JSON value to add.
+func collectUIDs(v any, add func(uid string)) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			collectUIDs(item, add)
+		}
+	case map[string]any:
+		if uid, ok := v[PredicateUID].(string); ok {
+			add(uid)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectUIDs(v[k], add)
+		}
+	}
+}
+
+// String generates a human-readable summary of the explanation.
+//
+// Returns:
+//   - A string representation of the explanation, with one line per block.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "total %s (parsing %s, processing %s, encoding %s), %d uids read",
+		e.Total, e.Parsing, e.Processing, e.Encoding, e.UIDs["_total"])
+	for _, block := range e.Blocks {
+		fmt.Fprintf(&b, "\n%s: %d nodes, %d uids", block.Name, block.Nodes, len(block.UIDs))
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package dql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	var executed *Query
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		executed = q
+		return &Response{
+			Json: []byte(`{"me": [{"uid": "0x1", "friend": [{"uid": "0x2"}, {"uid": "0x1"}]}, {"uid": "0x3"}], "you": []}`),
+			Extensions: []byte(`{"server_latency": {"parsing_ns": 1000, "processing_ns": 2000, "encoding_ns": 3000, "total_ns": 6000},
+				"txn": {"start_ts": 42}, "metrics": {"num_uids": {"_total": 5, "friend": 2}}}`),
+		}, nil
+	})
+	q := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("uid"), NewAttribute("friend").WithAttributes(NewAttribute("uid")))).
+		WithQueryBlocks(NewQueryBlock("you", Has("user")), NewRawQueryBlock("raw(func: has(user)) { uid }"))
+	e, err := Explain(context.Background(), exec, q, nil)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if !executed.Debug || q.Debug {
+		t.Errorf("Explain() ran a query with Debug %v and changed the caller's query", executed.Debug)
+	}
+	if e.Total != 6*time.Microsecond || e.Parsing != time.Microsecond || e.Processing != 2*time.Microsecond || e.Encoding != 3*time.Microsecond || e.StartTs != 42 {
+		t.Errorf("Explain() = %+v", e)
+	}
+	if !reflect.DeepEqual(e.UIDs, map[string]int{"_total": 5, "friend": 2}) {
+		t.Errorf("UIDs = %v", e.UIDs)
+	}
+	want := "total 6µs (parsing 1µs, processing 2µs, encoding 3µs), 5 uids read\nme: 2 nodes, 3 uids\nyou: 0 nodes, 0 uids"
+	if got := e.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := e.Blocks[0].UIDs; !reflect.DeepEqual(got, []string{"0x1", "0x2", "0x3"}) {
+		t.Errorf("UIDs of me = %v", got)
+	}
+}
+
+func TestExplainInvalidResponse(t *testing.T) {
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		return &Response{Json: []byte(`{"me": {"uid": "0x1"}}`)}, nil
+	})
+	_, err := Explain(context.Background(), exec, NewQuery("", NewQueryBlock("me", Has("user"))), nil)
+	if err == nil {
+		t.Error("Explain() of a block that is not a list error = nil")
+	}
+}
@@ -0,0 +1,139 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// facetVarPattern matches the variables assigned in a @facets directive, e.g. the score of
+// @facets(score as rating).
+var facetVarPattern = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s+as\s+`)
+
+// functionCallPattern matches the names of the functions called in a filter, e.g. the eq of
+// eq(close, true).
+var functionCallPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+// stringLiteralPattern matches the string literals of a rendered query.
+var stringLiteralPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// facetFunctions lists the functions Dgraph accepts in facet filters.
+var facetFunctions = map[string]bool{
+	"eq":         true,
+	"le":         true,
+	"lt":         true,
+	"ge":         true,
+	"gt":         true,
+	"allofterms": true,
+	"anyofterms": true,
+}
+
+// FacetVar assigns the values of a facet to a variable in a @facets directive, e.g. the
+// score as rating of @facets(score as rating).
+//
+// The variable maps each node the edge leads to to the facet of its edge. Later blocks use it
+// with val(), e.g. to aggregate or order by the facet.
+type FacetVar struct {
+	// Name is the name of the variable.
+	Name string
+
+	// Facet is the name of the facet.
+	Facet string
+}
+
+// String generates the "name as facet" assignment.
+//
+// Returns:
+//   - A string representation of the assignment.
+func (v FacetVar) String() string {
+	return v.Name + " as " + v.Facet
+}
+
+// Facets creates a @facets directive returning facets of an edge, or assigning them to
+// variables.
+//
+// Parameters:
+//   - facets: The facets, either names or FacetVar assignments. Without facets, every facet
+//     of the edge is returned.
+//
+// Returns:
+//   - A pointer to a Directive object.
+//
+// Example:
+//
+//	ratings := NewVarBlock(Uid("0x1")).WithAttributes(
+//	    NewAttribute("rated").WithDirectives(Facets(FacetVar{Name: "score", Facet: "rating"})),
+//	)
+//	best := NewQueryBlock("best", Uid("score")).
+//	    WithOrderDesc("val(score)").
+//	    WithAttributes(NewAttribute("name"), NewAttribute("val(score)"))
+//	query := NewQuery("", best).WithVarBlocks(ratings)
+//	fmt.Println(query.String()) // Output: { var (func: uid(0x1)) { rated @facets(score as rating) } best (func: uid(score), orderdesc: val(score)) { name val(score) } }
+//
+// See: https://dgraph.io/docs/query-language/facets/
+func Facets(facets ...any) *Directive {
+	return NewDirective("facets", facets...)
+}
+
+// facetVars returns the variables assigned by the @facets directives of directives.
+func facetVars(directives []Criteria) []string {
+	res := []string{}
+	for _, d := range directives {
+		if directiveName(d) != "facets" {
+			continue
+		}
+		for _, m := range facetVarPattern.FindAllStringSubmatch(d.String(), -1) {
+			res = append(res, m[1])
+		}
+	}
+	return res
+}
+
+// FacetFilter creates a @facets directive keeping the edges of a uid edge whose facets match a
+// filter, e.g. the close friends of friend @facets(eq(close, true)).
+//
+// Unlike @filter, which tests the nodes an edge leads to, a facet filter tests the facets of
+// the edge itself. Facet filters only accept the eq, le, lt, ge, gt, allofterms and anyofterms
+// functions, combined with AND, OR and NOT, which Validate checks. To also return facets,
+// add a separate Facets directive.
+//
+// Parameters:
+//   - criteria: The filter, either a Criteria such as Eq("close", true) or a string.
+//
+// Returns:
+//   - A pointer to a Directive object.
+//
+// Example:
+//
+//	friends := NewAttribute("friend").
+//	    WithDirectives(FacetFilter(Eq("close", true)), Facets("since")).
+//	    WithAttributes(NewAttribute("name"))
+//	fmt.Println(friends.String()) // Output: friend @facets(eq(close, true)) @facets(since) { name }
+//
+// See: https://dgraph.io/docs/query-language/facets/#filtering-on-facets
+func FacetFilter(criteria any) *Directive {
+	return NewDirective("facets", criteria)
+}
+
+// validateFacetFilters reports facet filters calling functions Dgraph does not accept on
+// facets, and facet filters of attributes without nested attributes, which are not uid edges.
+func validateFacetFilters(directives []Criteria, leaf bool) error {
+	for _, d := range directives {
+		if directiveName(d) != "facets" {
+			continue
+		}
+		text := strings.TrimPrefix(strings.TrimSpace(d.String()), "@facets")
+		text = stringLiteralPattern.ReplaceAllString(text, `""`)
+		for _, m := range functionCallPattern.FindAllStringSubmatch(text, -1) {
+			name := strings.ToLower(m[1])
+			switch {
+			case name == "and" || name == "or" || name == "not":
+			case !facetFunctions[name]:
+				return fmt.Errorf("%s() is not allowed in facet filters", m[1])
+			case leaf:
+				return fmt.Errorf("facet filters are only allowed on uid edges")
+			}
+		}
+	}
+	return nil
+}
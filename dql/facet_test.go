@@ -0,0 +1,76 @@
+package dql
+
+import "testing"
+
+func TestFacets(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("top", Uid("f")).WithOrderDesc("val(score)").WithAttributes(NewAttribute("name"), NewAttribute("val(score)"))).
+		WithVarBlocks(NewVarBlock(Uid("0x1")).WithAttributes(
+			NewAttribute("friend").WithVar("f").WithDirectives(Facets(FacetVar{Name: "score", Facet: "rating"}, "since"))))
+	want := "{ var (func: uid(0x1)) { f as friend @facets(score as rating, since) } top (func: uid(f), orderdesc: val(score)) { name val(score) } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if issues := Lint(q); len(issues) != 0 {
+		t.Errorf("Lint() = %v, want no issue", issues)
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("friends"))).WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends")), ""},
+		{"value variable", NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("age").WithVar("a"), NewAttribute("val(a)"))), ""},
+		{"undefined in root", NewQuery("", NewQueryBlock("me", Uid("friends"))), `dql: undefined variable "friends"`},
+		{"undefined in ordering", NewQuery("", NewQueryBlock("me", Has("user")).WithOrderAsc("val(score)")), `dql: undefined variable "score"`},
+		{"undefined in attribute", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("math(a + 1)"))), `dql: undefined variable "a"`},
+		{"undefined shortest path endpoint", NewQuery("", NewQueryBlock("path", Uid("p"))).WithShortestPaths(NewShortestPath("start", "0x2").WithName("p")),
+			`dql: undefined variable "start"`},
+		{"raw blocks may define variables", NewQuery("", NewQueryBlock("me", Uid("friends"))).WithVarBlocks(NewRawVarBlock("friends as var(func: has(friend))")), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFacetFilter(t *testing.T) {
+	edge := func(filter any) *Query {
+		return NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(
+			NewAttribute("friend").WithDirectives(FacetFilter(filter)).WithAttributes(NewAttribute("name"))))
+	}
+	tests := []struct {
+		name    string
+		q       *Query
+		want    string
+		wantErr string
+	}{
+		{"eq", edge(Eq("close", true)), "{ me (func: uid(0x1)) { friend @facets(eq(close, true)) { name } } }", ""},
+		{"connectives", edge("ge(since, 2020) AND NOT eq(close, false)"),
+			"{ me (func: uid(0x1)) { friend @facets(ge(since, 2020) AND NOT eq(close, false)) { name } } }", ""},
+		{"string holding a call", edge(Eq("note", "has(x)")), `{ me (func: uid(0x1)) { friend @facets(eq(note, "has(x)")) { name } } }`, ""},
+		{"unsupported function", edge(Has("since")), "", `dql: query block "me": friend: has() is not allowed in facet filters`},
+		{"scalar", NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("name").WithDirectives(FacetFilter(Eq("lang", "en"))))),
+			"", `dql: query block "me": name: facet filters are only allowed on uid edges`},
+		{"facet selection on scalar", NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("name").WithDirectives(Facets("lang")))),
+			"{ me (func: uid(0x1)) { name @facets(lang) } }", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+			if tt.want != "" && tt.q.String() != tt.want {
+				t.Errorf("String() = %s, want %s", tt.q.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package dql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// literalPattern matches the literal values of a rendered query: strings, uids and numbers.
+var literalPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|\b0x[0-9a-fA-F]+\b|-?\b\d+(?:\.\d+)?\b`)
+
+// Fingerprint computes a deterministic hash of the query.
+//
+// The hash is computed on the canonical form of the query used by Equal, so semantically
+// equal queries share the same fingerprint regardless of formatting and of the order of
+// their attributes, blocks, parameters and fragments. It is suitable for keying caches,
+// deduplicating queries in logs and identifying queries in traces.
+//
+// Returns:
+//   - The hex-encoded SHA-256 hash of the canonical query.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", `eq(name, "Alice")`))
+//	fmt.Println(query.Fingerprint())
+func (q *Query) Fingerprint() string {
+	return hash(canonicalQuery(q))
+}
+
+// ShapeFingerprint computes a deterministic hash of the query ignoring its literal values.
+//
+// It works like Fingerprint, but string, uid and number literals are replaced before
+// hashing, so queries that only differ by the values they look up share the same
+// fingerprint.
+//
+// Returns:
+//   - The hex-encoded SHA-256 hash of the canonical query without literals.
+//
+// Example:
+//
+//	a := NewQuery("", NewQueryBlock("me", `eq(name, "Alice")`))
+//	b := NewQuery("", NewQueryBlock("me", `eq(name, "Bob")`))
+//	fmt.Println(a.ShapeFingerprint() == b.ShapeFingerprint()) // Output: true
+func (q *Query) ShapeFingerprint() string {
+	return hash(literalPattern.ReplaceAllString(canonicalQuery(q), "?"))
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalQuery renders the query in the canonical form used by Equal.
+func canonicalQuery(q *Query) string {
+	params := make([]string, len(q.Params))
+	for i, p := range q.Params {
+		params[i] = p.String()
+	}
+	sort.Strings(params)
+
+	blocks := []string{}
+	for _, vb := range q.VarBlocks {
+		blocks = append(blocks, canonicalVarBlock(vb))
+	}
+	for _, sp := range q.ShortestPaths {
+		blocks = append(blocks, canonicalShortestPath(sp))
+	}
+	queryBlocks := []string{}
+	for _, qb := range q.QueryBlocks {
+		queryBlocks = append(queryBlocks, canonicalQueryBlock(qb))
+	}
+	sort.Strings(blocks)
+	sort.Strings(queryBlocks)
+
+	fragments := []string{}
+	for _, f := range q.fragments() {
+		fragments = append(fragments, canonicalFragment(f))
+	}
+	sort.Strings(fragments)
+
+	components := []string{"query", q.Name, "(", strings.Join(params, ", "), ")", "{"}
+	components = append(components, blocks...)
+	components = append(components, queryBlocks...)
+	components = append(components, "}")
+	components = append(components, fragments...)
+	return strings.Join(components, " ")
+}
@@ -0,0 +1,36 @@
+package dql
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	user := func(name string, attrs ...*Attribute) *Query {
+		return NewQuery("", NewQueryBlock("me", Raw(`eq(name, "`+name+`")`)).WithCriteria("first: 10").WithAttributes(attrs...))
+	}
+	tests := []struct {
+		name      string
+		a, b      *Query
+		wantSame  bool
+		wantShape bool
+	}{
+		{"same query", user("Alice", NewAttribute("name")), user("Alice", NewAttribute("name")), true, true},
+		{"attribute order", user("Alice", NewAttribute("name"), NewAttribute("age")),
+			user("Alice", NewAttribute("age"), NewAttribute("name")), true, true},
+		{"different value", user("Alice", NewAttribute("name")), user("Bob", NewAttribute("name")), false, true},
+		{"different number", NewQuery("", NewQueryBlock("me", Uid("0x1")).WithCriteria("first: 10")),
+			NewQuery("", NewQueryBlock("me", Uid("0x2")).WithCriteria("first: 20")), false, true},
+		{"different shape", user("Alice", NewAttribute("name")), user("Alice", NewAttribute("email")), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Fingerprint() == tt.b.Fingerprint(); got != tt.wantSame {
+				t.Errorf("same Fingerprint() = %v, want %v", got, tt.wantSame)
+			}
+			if got := tt.a.ShapeFingerprint() == tt.b.ShapeFingerprint(); got != tt.wantShape {
+				t.Errorf("same ShapeFingerprint() = %v, want %v", got, tt.wantShape)
+			}
+		})
+	}
+	if got := len(user("Alice").Fingerprint()); got != 64 {
+		t.Errorf("len(Fingerprint()) = %d, want 64", got)
+	}
+}
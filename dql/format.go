@@ -0,0 +1,21 @@
+package dql
+
+import "strings"
+
+// Format renders a query in the layout used by the dqlfmt command and golden files.
+//
+// The query is pretty-printed with PrettyPrint, without trailing whitespace and with a final
+// newline.
+//
+// Parameters:
+//   - q: The query to format.
+//
+// Returns:
+//   - The formatted query.
+func Format(q *Query) string {
+	lines := strings.Split(q.PrettyPrint(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
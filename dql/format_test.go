@@ -0,0 +1,14 @@
+package dql
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+		NewAttribute("name"),
+		NewAttribute("friend").WithAttributes(NewAttribute("name")),
+	))
+	want := "{\n  me (func: has(user)) {\n    name friend {\n      name\n    }\n  }\n}\n"
+	if got := Format(q); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
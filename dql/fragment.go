@@ -1,6 +1,9 @@
 package dql
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // Fragment represents a reusable fragment in a DQL query.
 //
@@ -11,6 +14,9 @@ type Fragment struct {
 
 	// Attributes is a list of attributes included in the fragment.
 	Attributes []*Attribute
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewFragment creates a new Fragment with the specified name.
@@ -45,6 +51,7 @@ func NewFragment(name string) *Fragment {
 //       WithAttributes(NewAttribute("name"), NewAttribute("age"))
 //   fmt.Println(fragment.String()) // Output: fragment userFragment { name age }
 func (f *Fragment) WithAttributes(attrs ...*Attribute) *Fragment {
+	mustBeMutable(f.frozen, "fragment", f.Name)
 	for _, a := range attrs {
 		f.Attributes = append(f.Attributes, a)
 	}
@@ -66,3 +73,91 @@ func (f *Fragment) String() string {
 	components = append(components, "}")
 	return strings.Join(components, " ")
 }
+
+// Validate checks the attributes of the fragment.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the fragment is valid.
+func (f *Fragment) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if err := validateName("fragment name", f.Name); err != nil {
+		return fmt.Errorf("dql: fragment %q: %w", f.Name, err)
+	}
+	if err := validateAttributes(f.Attributes); err != nil {
+		return fmt.Errorf("dql: fragment %q: %w", f.Name, err)
+	}
+	return nil
+}
+
+// Spread creates an attribute spreading a fragment, e.g. ...userFragment.
+//
+// The query the attribute ends up in includes the fragment automatically: it is rendered,
+// validated and traversed along with the fragments declared with WithFragments, unless the
+// query already declares a fragment with the same name.
+//
+// Parameters:
+//   - f: The fragment to spread.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+//
+// Example:
+//
+//	fragment := NewFragment("userFragment").WithAttributes(NewAttribute("name"))
+//	query := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(Spread(fragment)))
+//	fmt.Println(query.String()) // Output: { me (func: has(user)) { ...userFragment } } fragment userFragment { name }
+func Spread(f *Fragment) *Attribute {
+	return &Attribute{Name: "..." + f.Name, fragment: f}
+}
+
+// fragments returns the fragments of the query: the declared fragments followed by the
+// fragments spread with Spread which are not declared, in order of their first spread.
+func (q *Query) fragments() []*Fragment {
+	res := append([]*Fragment{}, q.Fragments...)
+	known := map[string]bool{}
+	for _, f := range q.Fragments {
+		known[f.Name] = true
+	}
+	var collect func(attrs []*Attribute)
+	collect = func(attrs []*Attribute) {
+		for _, a := range attrs {
+			if f := a.fragment; f != nil && !known[f.Name] {
+				known[f.Name] = true
+				res = append(res, f)
+				collect(f.Attributes)
+			}
+			collect(a.Attributes)
+		}
+	}
+	for _, vb := range q.VarBlocks {
+		collect(vb.Attributes)
+	}
+	for _, sp := range q.ShortestPaths {
+		collect(sp.Attributes)
+	}
+	for _, qb := range q.QueryBlocks {
+		collect(qb.Attributes)
+	}
+	for _, f := range q.Fragments {
+		collect(f.Attributes)
+	}
+	return res
+}
+
+// validateSpreads reports spreads of fragments the query does not include.
+func (q *Query) validateSpreads() error {
+	fragments := map[string]bool{}
+	for _, f := range q.fragments() {
+		fragments[f.Name] = true
+	}
+	var err error
+	Walk(q, func(n Node) bool {
+		if a, ok := n.(*Attribute); ok && !a.Raw && err == nil {
+			if name, ok := strings.CutPrefix(a.Name, "..."); ok && !fragments[name] {
+				err = fmt.Errorf("dql: undefined fragment %q", name)
+			}
+		}
+		return err == nil
+	})
+	return err
+}
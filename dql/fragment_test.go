@@ -0,0 +1,41 @@
+package dql
+
+import "testing"
+
+func TestSpread(t *testing.T) {
+	address := NewFragment("addressFields").WithAttributes(NewAttribute("city"))
+	user := NewFragment("userFields").WithAttributes(NewAttribute("name"), NewAttribute("address").WithAttributes(Spread(address)))
+	tests := []struct {
+		name    string
+		query   *Query
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "spread fragments are included",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(Spread(user))),
+			want:  "{ me (func: has(user)) { ...userFields } } fragment userFields { name address { ...addressFields } } fragment addressFields { city }",
+		},
+		{
+			name:  "declared fragments are not repeated",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(Spread(address))).WithFragments(address),
+			want:  "{ me (func: has(user)) { ...addressFields } } fragment addressFields { city }",
+		},
+		{
+			name:    "undefined fragment",
+			query:   NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("...userFields"))),
+			want:    "{ me (func: has(user)) { ...userFields } }",
+			wantErr: `dql: undefined fragment "userFields"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if got := errString(tt.query.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package dql
+
+import "fmt"
+
+// Freeze makes the query and all of its blocks, fragments, attributes and parameters
+// immutable, and returns the query.
+//
+// Builders are not safe for concurrent use: a query shared between goroutines, e.g. a
+// package-level query reused by every request, must not be modified. Once frozen, the builder
+// methods of the query and of its nodes, such as WithAttributes or WithFirst, panic instead
+// of racing. Reading, rendering, validating and executing a frozen query are safe from any
+// number of goroutines. Use Clone to get a modifiable copy; Rewrite rewrites a copy as well.
+//
+// Fragments spread with Spread but not declared with WithFragments may be shared with other
+// queries, e.g. the shared fragments of a Document: the query is given its own copies of
+// them, once each, which are frozen, so the originals stay modifiable and later changes to
+// them do not affect the frozen query. Declared fragments are owned by the query and frozen.
+//
+// Freeze must be called before the query is shared. The exported fields are still writable,
+// and direct assignments to them are not detected.
+//
+// Returns:
+//   - The frozen Query object.
+//
+// Example:
+//
+//	var usersQuery = NewQuery("Users", NewQueryBlock("users", Has("user")).
+//	    WithAttributes(NewAttribute("name"))).Freeze()
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    q := usersQuery.Clone().WithDebug() // usersQuery.WithDebug() would panic
+//	    ...
+//	}
+func (q *Query) Freeze() *Query {
+	if !q.frozen {
+		q.ownSpreadFragments()
+	}
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *Query:
+			n.frozen = true
+		case *Param:
+			n.frozen = true
+		case *VarBlock:
+			n.frozen = true
+		case *ShortestPath:
+			n.frozen = true
+		case *QueryBlock:
+			n.frozen = true
+		case *Fragment:
+			n.frozen = true
+		case *Attribute:
+			n.frozen = true
+		}
+		return true
+	})
+	return q
+}
+
+// ownSpreadFragments points the spreads of the query to copies of the fragments it does not
+// declare, see relinkSpreads.
+func (q *Query) ownSpreadFragments() {
+	copies := map[*Fragment]*Fragment{}
+	for _, f := range q.Fragments {
+		copies[f] = f
+	}
+	for _, vb := range q.VarBlocks {
+		relinkSpreads(vb.Attributes, copies)
+	}
+	for _, sp := range q.ShortestPaths {
+		relinkSpreads(sp.Attributes, copies)
+	}
+	for _, qb := range q.QueryBlocks {
+		relinkSpreads(qb.Attributes, copies)
+	}
+	for _, f := range q.Fragments {
+		relinkSpreads(f.Attributes, copies)
+	}
+}
+
+// Frozen reports whether the query was frozen with Freeze.
+//
+// Returns:
+//   - true if the query is frozen, false otherwise.
+func (q *Query) Frozen() bool {
+	return q.frozen
+}
+
+// mustBeMutable panics if a node of the given kind and name is frozen.
+func mustBeMutable(frozen bool, kind string, name string) {
+	if frozen {
+		panic(fmt.Sprintf("dql: modification of frozen %s %q, use Clone to get a modifiable copy", kind, name))
+	}
+}
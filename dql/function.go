@@ -0,0 +1,351 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Function represents a DQL function such as has(name) or uid(0x1).
+//
+// A Function can be used as the root criteria of a block or inside a filter. Unlike a raw
+// string, its name and arguments remain accessible after construction: predicates are held
+// as Raw arguments and values as Literal arguments.
+type Function struct {
+	// Name is the name of the function.
+	Name string
+
+	// Args is the list of arguments of the function.
+	Args []Criteria
+}
+
+// NewFunction creates a new Function with the specified name and arguments.
+//
+// Parameters:
+//   - name: The name of the function.
+//   - args: The arguments of the function, either Criteria or strings rendered verbatim.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fn := NewFunction("regexp", "name", "/^Steven.*$/")
+//	fmt.Println(fn.String()) // Output: regexp(name, /^Steven.*$/)
+//
+// See: https://dgraph.io/docs/query-language/functions/
+func NewFunction(name string, args ...any) *Function {
+	f := &Function{
+		Name: name,
+	}
+	for _, a := range args {
+		f.Args = append(f.Args, toCriteria(a))
+	}
+	return f
+}
+
+// Has creates a has(predicate) function.
+//
+// Like all typed function builders, Has escapes predicate names with Predicate.
+//
+// Parameters:
+//   - predicate: The predicate the nodes must have.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Has("user").String()) // Output: has(user)
+func Has(predicate string) *Function {
+	return NewFunction("has", Raw(Predicate(predicate)))
+}
+
+// Type creates a type(name) function.
+//
+// Names that are not valid type names are rendered as quoted strings, so they cannot inject
+// DQL, and are reported by Validate.
+//
+// Parameters:
+//   - name: The name of the type the nodes must have.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Type("Person").String()) // Output: type(Person)
+func Type(name string) *Function {
+	if !namePattern.MatchString(name) {
+		return NewFunction("type", Literal{name})
+	}
+	return NewFunction("type", Raw(name))
+}
+
+// Uid creates a uid(...) function from uids or uid variables.
+//
+// Values that are neither uids, such as 0x1a, nor variable names are rendered as quoted
+// strings, so they cannot inject DQL, and are reported by Validate.
+//
+// Parameters:
+//   - uids: One or more uids, given as strings or UIDs, or uid variable names.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Uid("0x1", UID(2)).String()) // Output: uid(0x1, 0x2)
+func Uid(uids ...any) *Function {
+	f := NewFunction("uid")
+	for _, uid := range uids {
+		f.Args = append(f.Args, uidArg(uid))
+	}
+	return f
+}
+
+// uidArg converts an argument of Uid into Criteria, quoting the values that are neither uids
+// nor variable names.
+func uidArg(v any) Criteria {
+	switch v := v.(type) {
+	case *Param:
+		return v.Ref()
+	case Criteria:
+		if isNode(v) {
+			return v
+		}
+	}
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if validateUidRef(s) != nil {
+		return Literal{s}
+	}
+	return Raw(s)
+}
+
+// Eq creates an eq(predicate, value) function.
+//
+// Values are rendered with SafeValue. When several values are given, the function matches
+// any of them.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - values: One or more values to compare the predicate with.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Eq("name", "Alice").String()) // Output: eq(name, "Alice")
+//	fmt.Println(Eq("age", 30, 31).String()) // Output: eq(age, [30, 31])
+func Eq(predicate any, values ...any) *Function {
+	if len(values) == 1 {
+		return NewFunction("eq", subject(predicate), value(values[0]))
+	}
+	list := make([]Criteria, len(values))
+	for i, v := range values {
+		list[i] = value(v)
+	}
+	return NewFunction("eq", subject(predicate), List(list))
+}
+
+// Le creates a le(predicate, value) function.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - v: The value to compare the predicate with, rendered with SafeValue.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Le(predicate any, v any) *Function {
+	return NewFunction("le", subject(predicate), value(v))
+}
+
+// Lt creates a lt(predicate, value) function.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - v: The value to compare the predicate with, rendered with SafeValue.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Lt(predicate any, v any) *Function {
+	return NewFunction("lt", subject(predicate), value(v))
+}
+
+// Ge creates a ge(predicate, value) function.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - v: The value to compare the predicate with, rendered with SafeValue.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Ge(predicate any, v any) *Function {
+	return NewFunction("ge", subject(predicate), value(v))
+}
+
+// Gt creates a gt(predicate, value) function.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - v: The value to compare the predicate with, rendered with SafeValue.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Gt(predicate any, v any) *Function {
+	return NewFunction("gt", subject(predicate), value(v))
+}
+
+// Between creates a between(predicate, from, to) function.
+//
+// Parameters:
+//   - predicate: The predicate to compare, or an expression such as Count or Len.
+//   - from: The lower bound, rendered with SafeValue.
+//   - to: The upper bound, rendered with SafeValue.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Between(predicate any, from, to any) *Function {
+	return NewFunction("between", subject(predicate), value(from), value(to))
+}
+
+// AllOfTerms creates an allofterms(predicate, terms) function.
+//
+// Parameters:
+//   - predicate: The predicate to search.
+//   - terms: The space-separated terms that must all match.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(AllOfTerms("name@en", "jones indiana").String()) // Output: allofterms(name@en, "jones indiana")
+func AllOfTerms(predicate string, terms string) *Function {
+	return NewFunction("allofterms", Raw(Predicate(predicate)), Literal{terms})
+}
+
+// AnyOfTerms creates an anyofterms(predicate, terms) function.
+//
+// Parameters:
+//   - predicate: The predicate to search.
+//   - terms: The space-separated terms of which at least one must match.
+//
+// Returns:
+//   - A pointer to a Function object.
+func AnyOfTerms(predicate string, terms string) *Function {
+	return NewFunction("anyofterms", Raw(Predicate(predicate)), Literal{terms})
+}
+
+// AllOfText creates an alloftext(predicate, text) function.
+//
+// Parameters:
+//   - predicate: The predicate to search.
+//   - text: The text whose stemmed terms must all match.
+//
+// Returns:
+//   - A pointer to a Function object.
+func AllOfText(predicate string, text string) *Function {
+	return NewFunction("alloftext", Raw(Predicate(predicate)), Literal{text})
+}
+
+// AnyOfText creates an anyoftext(predicate, text) function.
+//
+// Parameters:
+//   - predicate: The predicate to search.
+//   - text: The text of which at least one stemmed term must match.
+//
+// Returns:
+//   - A pointer to a Function object.
+func AnyOfText(predicate string, text string) *Function {
+	return NewFunction("anyoftext", Raw(Predicate(predicate)), Literal{text})
+}
+
+// Regexp creates a regexp(predicate, /pattern/flags) function.
+//
+// Unescaped slashes in the pattern are escaped, and so is a trailing backslash, so the pattern
+// cannot terminate the regular expression; escape sequences such as \d or \/ are kept. Flags
+// other than letters are dropped. The regular expression is a Literal, so strict mode reports
+// it and Parameterize lifts it into a string parameter holding /pattern/flags.
+//
+// Parameters:
+//   - predicate: The predicate to match.
+//   - pattern: The regular expression.
+//   - flags: The regular expression flags, e.g. "i" for case-insensitive matching.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Regexp("name", "^Steven", "i").String()) // Output: regexp(name, /^Steven/i)
+func Regexp(predicate string, pattern string, flags string) *Function {
+	flags = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, flags)
+	return NewFunction("regexp", Raw(Predicate(predicate)), Literal{regex{escapeRegexp(pattern), flags}})
+}
+
+// regex is a regular expression literal, whose pattern is already escaped.
+type regex struct {
+	pattern string
+	flags   string
+}
+
+// String renders the regular expression as /pattern/flags.
+func (r regex) String() string {
+	return "/" + r.pattern + "/" + r.flags
+}
+
+// escapeRegexp escapes the unescaped slashes of a regular expression and its trailing
+// backslash, keeping its escape sequences.
+func escapeRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteByte(c)
+			b.WriteByte(pattern[i+1])
+			i++
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '/':
+			b.WriteString(`\/`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Match creates a match(predicate, value, distance) fuzzy matching function.
+//
+// Parameters:
+//   - predicate: The predicate to match.
+//   - value: The string to match.
+//   - distance: The maximum Levenshtein distance.
+//
+// Returns:
+//   - A pointer to a Function object.
+func Match(predicate string, value string, distance int) *Function {
+	return NewFunction("match", Raw(Predicate(predicate)), Literal{value}, Raw(SafeValue(distance)))
+}
+
+// String generates a string representation of the function.
+//
+// Returns:
+//   - A string representation of the function.
+func (f *Function) String() string {
+	return f.Name + "(" + joinCriteria(f.Args) + ")"
+}
+
+func (f *Function) args() []Criteria {
+	return f.Args
+}
+
+func (f *Function) withArgs(args []Criteria) Criteria {
+	return &Function{Name: f.Name, Args: args}
+}
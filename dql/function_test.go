@@ -0,0 +1,99 @@
+package dql
+
+import (
+	"testing"
+	"time"
+)
+
+// stringer is a value implementing fmt.Stringer that is not a node of the package.
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestFunctionString(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   Criteria
+		want string
+	}{
+		{"has", Has("name"), "has(name)"},
+		{"type", Type("Person"), "type(Person)"},
+		{"uid", Uid("0x1", "0x2"), "uid(0x1, 0x2)"},
+		{"uid variable", Uid("friends"), "uid(friends)"},
+		{"eq string", Eq("name", "Alice"), `eq(name, "Alice")`},
+		{"eq values", Eq("age", 30, 31), "eq(age, [30, 31])"},
+		{"eq bool", Eq("active", true), "eq(active, true)"},
+		{"eq time", Eq("at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), `eq(at, "2024-01-02T03:04:05Z")`},
+		{"le", Le("age", 30), "le(age, 30)"},
+		{"lt", Lt("price", 9.5), "lt(price, 9.5)"},
+		{"ge", Ge("age", 18), "ge(age, 18)"},
+		{"gt", Gt("name", "M"), `gt(name, "M")`},
+		{"between", Between("age", 18, 30), "between(age, 18, 30)"},
+		{"allofterms", AllOfTerms("name", "star wars"), `allofterms(name, "star wars")`},
+		{"anyofterms", AnyOfTerms("name", "star wars"), `anyofterms(name, "star wars")`},
+		{"alloftext", AllOfText("bio", "graph databases"), `alloftext(bio, "graph databases")`},
+		{"anyoftext", AnyOfText("bio", "graph"), `anyoftext(bio, "graph")`},
+		{"regexp", Regexp("name", "^Al.*$", "i"), "regexp(name, /^Al.*$/i)"},
+		{"match", Match("name", "Alice", 2), `match(name, "Alice", 2)`},
+		{"custom", NewFunction("near", "loc", "[-122.4, 37.7]", "1000"), "near(loc, [-122.4, 37.7], 1000)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockCriteria(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"function", NewQuery("", NewQueryBlock("me", Has("user"))), "{ me (func: has(user)) { } }"},
+		{"string", NewQuery("", NewQueryBlock("me", "has(user)")), "{ me (func: has(user)) { } }"},
+		{"mixed", NewQuery("", NewQueryBlock("me", Uid("0x1")).WithCriteria("first: 1")), "{ me (func: uid(0x1), first: 1) { } }"},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("f"))).WithVarBlocks(NewVarBlock(Type("Person")).WithName("f")),
+			"{ f AS var (func: type(Person)) { } me (func: uid(f)) { } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFunctionInjection checks that values cannot break out of the arguments of a function.
+func TestFunctionInjection(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   Criteria
+		want string
+	}{
+		{"quote", Eq("name", `Alice") { uid } me(func: has(password`), `eq(name, "Alice\") { uid } me(func: has(password")`},
+		{"backslash", Eq("name", `Alice\`), `eq(name, "Alice\\")`},
+		{"newline", Eq("name", "Alice\nBob"), `eq(name, "Alice\nBob")`},
+		{"stringer", Eq("name", stringer(`x) OR has(password`)), `eq(name, "x) OR has(password")`},
+		{"terms", AnyOfTerms("name", `a") OR has(password`), `anyofterms(name, "a\") OR has(password")`},
+		{"regexp slash", Regexp("name", `a/) OR has(password`, ""), `regexp(name, /a\/) OR has(password/)`},
+		{"regexp escaped slash", Regexp("path", `^\/usr\/\d+/x$`, ""), `regexp(path, /^\/usr\/\d+\/x$/)`},
+		{"regexp escaped backslash before slash", Regexp("path", `a\\/) OR has(password`, ""), `regexp(path, /a\\\/) OR has(password/)`},
+		{"regexp trailing backslash", Regexp("name", `a\`, ""), `regexp(name, /a\\/)`},
+		{"regexp flags", Regexp("name", "a", "i) OR has(password"), `regexp(name, /a/iORhaspassword)`},
+		{"type", Type("Person) OR has(password"), `type("Person) OR has(password")`},
+		{"uid", Uid("0x1) OR has(password"), `uid("0x1) OR has(password")`},
+		{"uid variable", Uid("friends", UID(0x2a)), "uid(friends, 0x2a)"},
+		{"len", Len("x) OR has(password"), `len("x) OR has(password")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package dql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Geometry is a geographic value of a geo predicate: a Point, a Polygon or a MultiPolygon.
+//
+// Geometries render the same coordinates in every format Dgraph uses: as arguments of the geo
+// functions, as GeoJSON in JSON mutations through MarshalJSON, and as typed literals in N-Quad
+// mutations through NQuad.
+type Geometry interface {
+	// GeoJSON returns the GeoJSON representation of the geometry.
+	GeoJSON() string
+
+	// NQuad returns the geometry as the object of an N-Quad, a GeoJSON literal typed
+	// geo:geojson.
+	NQuad() string
+
+	// coordinates returns the coordinates of the geometry, as used by the geo functions.
+	coordinates() string
+
+	// geoType returns the GeoJSON type of the geometry.
+	geoType() string
+}
+
+// Point is a location given by its longitude and latitude, in degrees.
+type Point struct {
+	// Lng is the longitude of the point.
+	Lng float64
+
+	// Lat is the latitude of the point.
+	Lat float64
+}
+
+// Polygon is an area given by its rings: an outer boundary followed by optional holes.
+//
+// Rings do not need to repeat their first point at the end, they are closed when rendered.
+type Polygon [][]Point
+
+// MultiPolygon is an area made of several polygons.
+type MultiPolygon []Polygon
+
+// GeoJSON returns the GeoJSON representation of the point.
+//
+// Returns:
+//   - The GeoJSON of the point, e.g. {"type":"Point","coordinates":[-122.4,37.7]}.
+func (p Point) GeoJSON() string {
+	return geoJSON(p)
+}
+
+// NQuad returns the point as the object of an N-Quad.
+//
+// Returns:
+//   - The typed GeoJSON literal of the point.
+func (p Point) NQuad() string {
+	return nquad(p)
+}
+
+// MarshalJSON encodes the point as GeoJSON, for JSON mutations.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return []byte(p.GeoJSON()), nil
+}
+
+func (p Point) coordinates() string {
+	return "[" + strconv.FormatFloat(p.Lng, 'g', -1, 64) + ", " + strconv.FormatFloat(p.Lat, 'g', -1, 64) + "]"
+}
+
+func (p Point) geoType() string {
+	return "Point"
+}
+
+// GeoJSON returns the GeoJSON representation of the polygon.
+//
+// Returns:
+//   - The GeoJSON of the polygon.
+func (p Polygon) GeoJSON() string {
+	return geoJSON(p)
+}
+
+// NQuad returns the polygon as the object of an N-Quad.
+//
+// Returns:
+//   - The typed GeoJSON literal of the polygon.
+func (p Polygon) NQuad() string {
+	return nquad(p)
+}
+
+// MarshalJSON encodes the polygon as GeoJSON, for JSON mutations.
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	return []byte(p.GeoJSON()), nil
+}
+
+func (p Polygon) coordinates() string {
+	rings := []string{}
+	for _, ring := range p {
+		if len(ring) != 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		points := []string{}
+		for _, point := range ring {
+			points = append(points, point.coordinates())
+		}
+		rings = append(rings, "["+strings.Join(points, ", ")+"]")
+	}
+	return "[" + strings.Join(rings, ", ") + "]"
+}
+
+func (p Polygon) geoType() string {
+	return "Polygon"
+}
+
+// GeoJSON returns the GeoJSON representation of the multipolygon.
+//
+// Returns:
+//   - The GeoJSON of the multipolygon.
+func (m MultiPolygon) GeoJSON() string {
+	return geoJSON(m)
+}
+
+// NQuad returns the multipolygon as the object of an N-Quad.
+//
+// Returns:
+//   - The typed GeoJSON literal of the multipolygon.
+func (m MultiPolygon) NQuad() string {
+	return nquad(m)
+}
+
+// MarshalJSON encodes the multipolygon as GeoJSON, for JSON mutations.
+func (m MultiPolygon) MarshalJSON() ([]byte, error) {
+	return []byte(m.GeoJSON()), nil
+}
+
+func (m MultiPolygon) coordinates() string {
+	polygons := []string{}
+	for _, p := range m {
+		polygons = append(polygons, p.coordinates())
+	}
+	return "[" + strings.Join(polygons, ", ") + "]"
+}
+
+func (m MultiPolygon) geoType() string {
+	return "MultiPolygon"
+}
+
+// geoJSON renders the GeoJSON of a geometry, with its coordinates in compact form.
+func geoJSON(g Geometry) string {
+	return `{"type":"` + g.geoType() + `","coordinates":` + strings.ReplaceAll(g.coordinates(), ", ", ",") + "}"
+}
+
+// nquad renders a geometry as a typed N-Quad literal.
+func nquad(g Geometry) string {
+	return Quote(geoJSON(g)) + "^^<geo:geojson>"
+}
+
+// Near creates a near(predicate, [lng, lat], distance) function, matching the nodes located
+// within a distance of a point.
+//
+// Parameters:
+//   - predicate: The geo predicate.
+//   - p: The point.
+//   - distance: The maximum distance, in meters.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Near("loc", Point{Lng: -122.4, Lat: 37.7}, 1000).String()) // Output: near(loc, [-122.4, 37.7], 1000)
+//
+// See: https://dgraph.io/docs/query-language/functions/#near
+func Near(predicate string, p Point, distance float64) *Function {
+	return NewFunction("near", Raw(Predicate(predicate)), Raw(p.coordinates()), Raw(strconv.FormatFloat(distance, 'g', -1, 64)))
+}
+
+// Within creates a within(predicate, polygon) function, matching the nodes located inside an
+// area.
+//
+// Parameters:
+//   - predicate: The geo predicate.
+//   - area: The area, a Polygon or a MultiPolygon.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// See: https://dgraph.io/docs/query-language/functions/#within
+func Within(predicate string, area Geometry) *Function {
+	return NewFunction("within", Raw(Predicate(predicate)), Raw(area.coordinates()))
+}
+
+// Contains creates a contains(predicate, geometry) function, matching the nodes whose area
+// contains a point or a polygon.
+//
+// Parameters:
+//   - predicate: The geo predicate.
+//   - g: The contained Point or Polygon.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	fmt.Println(Contains("area", Point{Lng: 2.35, Lat: 48.85}).String()) // Output: contains(area, [2.35, 48.85])
+//
+// See: https://dgraph.io/docs/query-language/functions/#contains
+func Contains(predicate string, g Geometry) *Function {
+	return NewFunction("contains", Raw(Predicate(predicate)), Raw(g.coordinates()))
+}
+
+// Intersects creates an intersects(predicate, polygon) function, matching the nodes whose
+// area intersects another area.
+//
+// Parameters:
+//   - predicate: The geo predicate.
+//   - area: The area, a Polygon or a MultiPolygon.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// See: https://dgraph.io/docs/query-language/functions/#intersects
+func Intersects(predicate string, area Geometry) *Function {
+	return NewFunction("intersects", Raw(Predicate(predicate)), Raw(area.coordinates()))
+}
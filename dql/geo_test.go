@@ -0,0 +1,42 @@
+package dql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGeo(t *testing.T) {
+	square := Polygon{{{Lng: 0, Lat: 0}, {Lng: 1, Lat: 0}, {Lng: 1, Lat: 1}, {Lng: 0, Lat: 1}}}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"near", Near("loc", Point{Lng: -122.4, Lat: 37.77}, 1000).String(), "near(loc, [-122.4, 37.77], 1000)"},
+		{"within closes rings", Within("loc", square).String(), "within(loc, [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]])"},
+		{"contains", Contains("area", Point{Lng: 0.5, Lat: 0.5}).String(), "contains(area, [0.5, 0.5])"},
+		{"intersects", Intersects("area", MultiPolygon{square}).String(), "intersects(area, [[[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]]])"},
+		{"point geojson", Point{Lng: 1.5, Lat: 2}.GeoJSON(), `{"type":"Point","coordinates":[1.5,2]}`},
+		{"polygon nquad", square.NQuad(), `"{\"type\":\"Polygon\",\"coordinates\":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}"^^<geo:geojson>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+	if len(square[0]) != 4 {
+		t.Errorf("rendering modified the polygon: %v", square)
+	}
+}
+
+func TestGeoMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]any{"loc": Point{Lng: 1, Lat: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"loc":{"type":"Point","coordinates":[1,2]}}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
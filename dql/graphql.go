@@ -0,0 +1,270 @@
+package dql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AttributesFromGraphQL converts a GraphQL selection set into attributes.
+//
+// A subset of GraphQL is supported, which maps directly onto DQL:
+//   - fields, with optional aliases, become attributes,
+//   - nested selection sets become nested attributes,
+//   - fragment spreads such as ...UserFields are kept as spreads of DQL fragments,
+//   - the first, offset and after arguments become pagination arguments, and the orderasc
+//     and orderdesc arguments become ordering arguments. Values can be literals or variables
+//     such as $first, which become parameter references.
+//
+// Other arguments, directives, inline fragments and operation definitions are rejected.
+//
+// Parameters:
+//   - selectionSet: The GraphQL selection set, with or without its enclosing braces.
+//
+// Returns:
+//   - The converted attributes.
+//   - An error if the selection set is malformed or uses unsupported GraphQL.
+//
+// Example:
+//
+//	attrs, err := AttributesFromGraphQL(`{ name friends(first: 10) { fullName: name } }`)
+//	queryBlock := NewQueryBlock("me", Has("user")).WithAttributes(attrs...)
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) { name friends (first: 10) { fullName : name } }
+func AttributesFromGraphQL(selectionSet string) ([]*Attribute, error) {
+	p := &graphqlParser{tokens: []string{}}
+	if err := p.tokenize(selectionSet); err != nil {
+		return nil, err
+	}
+	braced := p.peek() == "{"
+	if braced {
+		p.next()
+	}
+	attrs, err := p.selections(braced)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "" {
+		return nil, fmt.Errorf("dql: graphql: unexpected %q", p.peek())
+	}
+	return attrs, nil
+}
+
+// NewQueryBlockFromGraphQL creates a query block selecting the fields of a GraphQL selection
+// set, see AttributesFromGraphQL.
+//
+// GraphQL has no equivalent of DQL root functions, so the root criteria of the block are
+// given separately.
+//
+// Parameters:
+//   - name: The name of the query block.
+//   - criteria: The root criteria of the query block, either a Criteria or a string.
+//   - selectionSet: The GraphQL selection set.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//   - An error if the selection set is malformed or uses unsupported GraphQL.
+func NewQueryBlockFromGraphQL(name string, criteria any, selectionSet string) (*QueryBlock, error) {
+	attrs, err := AttributesFromGraphQL(selectionSet)
+	if err != nil {
+		return nil, err
+	}
+	return NewQueryBlock(name, criteria).WithAttributes(attrs...), nil
+}
+
+// graphqlParser is a recursive descent parser for GraphQL selection sets.
+type graphqlParser struct {
+	tokens []string
+	pos    int
+}
+
+// tokenize splits the source into names, variables, numbers, strings and punctuation.
+// Commas, whitespace and comments are ignored, as in GraphQL.
+func (p *graphqlParser) tokenize(src string) error {
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return fmt.Errorf("dql: graphql: unterminated string")
+			}
+			p.tokens = append(p.tokens, string(runes[i:j+1]))
+			i = j + 1
+		case strings.HasPrefix(string(runes[i:]), "..."):
+			p.tokens = append(p.tokens, "...")
+			i += 3
+		case strings.ContainsRune("{}():@", r):
+			p.tokens = append(p.tokens, string(r))
+			i++
+		case r == '$' || r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '.' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			p.tokens = append(p.tokens, string(runes[i:j]))
+			i = j
+		default:
+			return fmt.Errorf("dql: graphql: unexpected character %q", r)
+		}
+	}
+	return nil
+}
+
+// peek returns the current token, or an empty string at the end of the input.
+func (p *graphqlParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// next consumes and returns the current token.
+func (p *graphqlParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// expect consumes the current token, failing if it is not want.
+func (p *graphqlParser) expect(want string) error {
+	if got := p.next(); got != want {
+		return fmt.Errorf("dql: graphql: expected %q, got %q", want, got)
+	}
+	return nil
+}
+
+// name consumes a name token.
+func (p *graphqlParser) name() (string, error) {
+	t := p.next()
+	if t == "" || !(t[0] == '_' || unicode.IsLetter(rune(t[0]))) {
+		return "", fmt.Errorf("dql: graphql: expected a name, got %q", t)
+	}
+	return t, nil
+}
+
+// selections parses fields up to the closing brace of a selection set, or up to the end of
+// the input when the selection set is not braced.
+func (p *graphqlParser) selections(braced bool) ([]*Attribute, error) {
+	attrs := []*Attribute{}
+	for {
+		switch p.peek() {
+		case "}":
+			if !braced {
+				return nil, fmt.Errorf("dql: graphql: unexpected %q", "}")
+			}
+			p.next()
+			return attrs, nil
+		case "":
+			if braced {
+				return nil, fmt.Errorf("dql: graphql: unterminated selection set")
+			}
+			return attrs, nil
+		}
+		attr, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+}
+
+// field parses a field or a fragment spread along with its arguments and selection set.
+func (p *graphqlParser) field() (*Attribute, error) {
+	if p.peek() == "..." {
+		p.next()
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if name == "on" {
+			return nil, fmt.Errorf("dql: graphql: inline fragments are not supported")
+		}
+		return NewAttribute("..." + name), nil
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	attr := NewAttribute(name)
+	if p.peek() == ":" {
+		p.next()
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+		attr = NewAttribute(name).WithAlias(attr.Name)
+	}
+	if p.peek() == "(" {
+		p.next()
+		if err := p.arguments(attr); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek() == "@" {
+		p.next()
+		return nil, fmt.Errorf("dql: graphql: field %q: directive @%s is not supported", name, p.peek())
+	}
+	if p.peek() == "{" {
+		p.next()
+		if attr.Attributes, err = p.selections(true); err != nil {
+			return nil, err
+		}
+	}
+	return attr, nil
+}
+
+// arguments parses the arguments of a field up to the closing parenthesis.
+func (p *graphqlParser) arguments(attr *Attribute) error {
+	for p.peek() != ")" {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		v := p.next()
+		var value any
+		switch {
+		case strings.HasPrefix(v, "$"):
+			value = ParamRef(v[1:])
+		case strings.HasPrefix(v, `"`):
+			s, err := strconv.Unquote(v)
+			if err != nil {
+				return fmt.Errorf("dql: graphql: field %q: invalid string %s", attr.Name, v)
+			}
+			value = s
+		default:
+			value = v
+		}
+		switch name {
+		case "first", "offset":
+			if _, ok := value.(ParamRef); !ok {
+				if _, err := strconv.Atoi(v); err != nil {
+					return fmt.Errorf("dql: graphql: field %q: %s must be an integer, got %s", attr.Name, name, v)
+				}
+			}
+			attr.WithArgs(NewArg(name, value))
+		case "after", "orderasc", "orderdesc":
+			attr.WithArgs(NewArg(name, value))
+		default:
+			return fmt.Errorf("dql: graphql: field %q: argument %q is not supported", attr.Name, name)
+		}
+	}
+	p.next()
+	return nil
+}
@@ -0,0 +1,61 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttributesFromGraphQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		selectionSet string
+		want         string
+		wantErr      string
+	}{
+		{"braced", `{ name friends(first: 10) { fullName: name } }`, "name friends (first: 10) { fullName : name }", ""},
+		{"unbraced with commas", `name, age # comment`, "name age", ""},
+		{"ordering and string", `friends(orderasc: name, after: "0x1") { name }`, "friends (orderasc: name, after: 0x1) { name }", ""},
+		{"param ref", `friends(first: $n) { name }`, "friends (first: $n) { name }", ""},
+		{"fragment spread", `{ ...userFields }`, "...userFields", ""},
+		{"inline fragment", `{ ... on User { name } }`, "", "dql: graphql: inline fragments are not supported"},
+		{"directive", `{ name @include(if: true) }`, "", `dql: graphql: field "name": directive @include is not supported`},
+		{"unsupported argument", `{ friends(filter: x) { name } }`, "", `dql: graphql: field "friends": argument "filter" is not supported`},
+		{"non-integer first", `{ friends(first: ten) { name } }`, "", `dql: graphql: field "friends": first must be an integer, got ten`},
+		{"unterminated selection", `{ name`, "", "dql: graphql: unterminated selection set"},
+		{"unterminated string", `{ friends(after: "0x1) }`, "", "dql: graphql: unterminated string"},
+		{"stray brace", `name }`, "", `dql: graphql: unexpected "}"`},
+		{"invalid character", `{ name! }`, "", `dql: graphql: unexpected character '!'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, err := AttributesFromGraphQL(tt.selectionSet)
+			if got := errString(err); got != tt.wantErr {
+				t.Fatalf("AttributesFromGraphQL() error = %q, want %q", got, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			parts := make([]string, len(attrs))
+			for i, attr := range attrs {
+				parts[i] = attr.String()
+			}
+			if got := strings.Join(parts, " "); got != tt.want {
+				t.Errorf("AttributesFromGraphQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewQueryBlockFromGraphQL(t *testing.T) {
+	block, err := NewQueryBlockFromGraphQL("me", Has("user"), `{ name friends(first: 10) { fullName: name } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "me (func: has(user)) { name friends (first: 10) { fullName : name } }"
+	if got := block.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if _, err := NewQueryBlockFromGraphQL("me", Has("user"), "{"); err == nil {
+		t.Error("NewQueryBlockFromGraphQL() accepted an unterminated selection set")
+	}
+}
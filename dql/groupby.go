@@ -0,0 +1,136 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupBy creates a @groupby directive grouping the results of a block by predicates.
+//
+// Parameters:
+//   - predicates: One or more predicates to group by.
+//
+// Returns:
+//   - A pointer to a Directive object.
+//
+// Example:
+//
+//	varBlock := NewVarBlock(Has("genre")).
+//	    WithDirectives(GroupBy("genre")).
+//	    WithAttributes(NewAttribute("count(uid)").WithVar("a"))
+//	fmt.Println(varBlock.String()) // Output: var (func: has(genre)) @groupby(genre) { a as count(uid) }
+//
+// See: https://dgraph.io/docs/query-language/groupby/
+func GroupBy(predicates ...string) *Directive {
+	d := NewDirective("groupby")
+	for _, p := range predicates {
+		d.Args = append(d.Args, Raw(p))
+	}
+	return d
+}
+
+// NewGroupByOrderBlock creates the block listing the groups of a @groupby ordered by an
+// aggregated value.
+//
+// Within a @groupby, a variable assigned to an aggregation such as count(uid) maps each
+// group to its value. The returned block starts from the groups of that variable and orders
+// them by its values, e.g. byCount(func: uid(a), orderdesc: val(a)).
+//
+// Parameters:
+//   - name: The name of the query block.
+//   - attr: The aggregation attribute of the @groupby, assigned to a variable with WithVar.
+//   - desc: Whether to order the groups by descending values.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//   - An error if the attribute is not assigned to a variable.
+//
+// Example:
+//
+//	count := NewAttribute("count(uid)").WithVar("a")
+//	byCount, err := NewGroupByOrderBlock("byCount", count, true)
+//	byCount.WithAttributes(NewAttribute("name"), NewAttribute("val(a)").WithAlias("total"))
+//	fmt.Println(byCount.String()) // Output: byCount (func: uid(a), orderdesc: val(a)) { name total : val(a) }
+func NewGroupByOrderBlock(name string, attr *Attribute, desc bool) (*QueryBlock, error) {
+	if attr.Var == "" {
+		return nil, fmt.Errorf("dql: groupby order block %q: attribute %q is not assigned to a variable", name, attr.Name)
+	}
+	qb := NewQueryBlock(name, Uid(attr.Var))
+	value := "val(" + attr.Var + ")"
+	if desc {
+		return qb.WithOrderDesc(value), nil
+	}
+	return qb.WithOrderAsc(value), nil
+}
+
+// NewCountByQuery creates a query counting nodes grouped by an edge, ordered by count.
+//
+// It builds the common aggregation pattern in one call: a variable block grouping the nodes
+// matching root by the groupBy edge and counting each group, followed by a @normalize block
+// returning the label of each group and its total, ordered by descending total.
+//
+// Dgraph only maps the groups of a @groupby to variables when grouping by a uid edge, so
+// groupBy must not be a scalar predicate such as age. With a schema set by WithSchema,
+// Validate reports a groupBy the schema declares with another type.
+//
+// Parameters:
+//   - name: The name of the result block.
+//   - root: The criteria selecting the nodes to count, either a Criteria or a string.
+//   - groupBy: The uid edge whose target nodes form the groups.
+//   - label: The predicate of the group nodes returned as their label, e.g. name@en. It is
+//     returned under its name without language tag.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := NewCountByQuery("byGenre", Has("genre"), "genre", "name@en")
+//	fmt.Println(query.PrettyPrint())
+//	// Output:
+//	// {
+//	//   var (func: has(genre)) @groupby(genre) {
+//	//     byGenre_count as count(uid)
+//	//   }
+//	//   byGenre (func: uid(byGenre_count), orderdesc: val(byGenre_count)) @normalize {
+//	//     name : name@en total : val(byGenre_count)
+//	//   }
+//	// }
+func NewCountByQuery(name string, root any, groupBy string, label string) *Query {
+	count := NewAttribute("count(uid)").WithVar(name + "_count")
+	varBlock := NewVarBlock(root).
+		WithDirectives(GroupBy(groupBy)).
+		WithAttributes(count)
+
+	labelAlias, _, _ := strings.Cut(label, "@")
+	qb, _ := NewGroupByOrderBlock(name, count, true)
+	qb.WithDirectives(NewDirective("normalize")).
+		WithAttributes(
+			NewAttribute(label).WithAlias(labelAlias),
+			NewAttribute("val("+count.Var+")").WithAlias("total"),
+		)
+
+	return NewQuery("", qb).WithVarBlocks(varBlock)
+}
+
+// validateGroupBy reports the predicates a @groupby of a variable block groups by that the
+// schema does not declare as uid edges, as only the groups of uid edges are assigned to the
+// variables of the block.
+func (s *Schema) validateGroupBy(vb *VarBlock) error {
+	for _, d := range vb.Directives {
+		if directiveName(d) != "groupby" {
+			continue
+		}
+		dir, ok := d.(*Directive)
+		if !ok {
+			continue
+		}
+		for _, arg := range dir.Args {
+			name := strings.TrimSpace(arg.String())
+			if p := s.Predicate(predicateOf(name)); p != nil && p.Type != "uid" {
+				return fmt.Errorf("predicate %q: variables of a @groupby need a uid edge, not %s", p.Name, p.Type)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package dql
+
+import "testing"
+
+func TestGroupBy(t *testing.T) {
+	total := NewAttribute("count(uid)").WithVar("total")
+	q := NewQuery("", NewQueryBlock("byAge", Has("user")).WithAttributes(
+		NewAttribute("friend").WithDirectives(GroupBy("age", "city")).WithAttributes(total)))
+	want := "{ byAge (func: has(user)) { friend @groupby(age, city) { total as count(uid) } } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestNewGroupByOrderBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		attr    *Attribute
+		desc    bool
+		want    string
+		wantErr string
+	}{
+		{"ascending", NewAttribute("count(uid)").WithVar("total"), false, "top (func: uid(total), orderasc: val(total)) { }", ""},
+		{"descending", NewAttribute("count(uid)").WithVar("total"), true, "top (func: uid(total), orderdesc: val(total)) { }", ""},
+		{"no variable", NewAttribute("count(uid)"), false, "",
+			`dql: groupby order block "top": attribute "count(uid)" is not assigned to a variable`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb, err := NewGroupByOrderBlock("top", tt.attr, tt.desc)
+			if got := errString(err); got != tt.wantErr {
+				t.Fatalf("NewGroupByOrderBlock() error = %q, want %q", got, tt.wantErr)
+			}
+			if err == nil && qb.String() != tt.want {
+				t.Errorf("String() = %s, want %s", qb, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCountByQuery(t *testing.T) {
+	q := NewCountByQuery("byGenre", Has("genre"), "genre", "name@en")
+	want := "{ var (func: has(genre)) @groupby(genre) { byGenre_count as count(uid) } " +
+		"byGenre (func: uid(byGenre_count), orderdesc: val(byGenre_count)) @normalize { name : name@en total : val(byGenre_count) } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestNewCountBySchema(t *testing.T) {
+	schema, err := ParseSchema("genre: [uid] .\nage: int .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		groupBy string
+		wantErr string
+	}{
+		{"uid edge", "genre", ""},
+		{"undeclared", "studio", ""},
+		{"scalar", "age", `dql: var block "": predicate "age": variables of a @groupby need a uid edge, not int`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewCountByQuery("byGroup", Has("genre"), tt.groupBy, "name").WithSchema(schema)
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package dql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// hydrateBlockName is the name of the block of the queries run by Hydrate.
+const hydrateBlockName = "nodes"
+
+// NewHydrateQuery creates a query fetching nodes by uid, e.g. the uids returned by an external
+// search index, with the given selection. The uid attribute is selected if it is not already,
+// so the results can be matched with the uids, see Hydrate.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - uids: The uids of the nodes.
+//   - attrs: The attributes to fetch.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := NewHydrateQuery("nodes", []UID{0x2a, 0x1f}, NewAttribute("name"))
+//	fmt.Println(query.String()) // Output: { nodes (func: uid(0x2a, 0x1f)) { name uid } }
+func NewHydrateQuery(name string, uids []UID, attrs ...*Attribute) *Query {
+	f := NewFunction("uid")
+	for _, uid := range uids {
+		f.Args = append(f.Args, uid)
+	}
+	qb := NewQueryBlock(name, f).WithAttributes(attrs...)
+	for _, a := range attrs {
+		if a.Name == PredicateUID && a.Alias == "" {
+			return NewQuery("", qb)
+		}
+	}
+	return NewQuery("", qb.WithAttributes(UIDAttribute()))
+}
+
+// Hydrate fetches nodes by uid and returns them in the order of the uids, completing a
+// two-phase fetch where another system, such as a search index, finds the uids of the nodes
+// and Dgraph holds their data.
+//
+// Dgraph returns the nodes of uid() in uid order, so the results are reordered to match uids.
+// Uids without a node are skipped: a node is missing when none of the selected predicates
+// other than uid is returned for it. Uids given several times yield the node several times.
+//
+// Parameters:
+//   - ctx: The context of the execution.
+//   - exec: The Executor running the query.
+//   - uids: The uids of the nodes, in the order of the results.
+//   - attrs: The attributes to fetch, decoded into T with Decode.
+//
+// Returns:
+//   - The decoded nodes, in the order of uids.
+//   - An error if the execution failed or the response cannot be decoded.
+//
+// Example:
+//
+//	uids := []UID{0x2a, 0x1f} // e.g. from a full-text search engine, best match first
+//	users, err := Hydrate[User](ctx, client, uids, NewAttribute("name"), NewAttribute("email"))
+func Hydrate[T any](ctx context.Context, exec Executor, uids []UID, attrs ...*Attribute) ([]T, error) {
+	res := []T{}
+	if len(uids) == 0 {
+		return res, nil
+	}
+	q := NewHydrateQuery(hydrateBlockName, uids, attrs...)
+	resp, err := exec.Execute(ctx, q, nil)
+	if err != nil {
+		return nil, err
+	}
+	var blocks map[string][]map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Json, &blocks); err != nil {
+		return nil, fmt.Errorf("dql: hydrate: %w", err)
+	}
+	onlyUID := len(q.QueryBlocks[0].Attributes) == 1
+	nodes := map[UID]json.RawMessage{}
+	for _, n := range blocks[hydrateBlockName] {
+		var uid UID
+		if err := json.Unmarshal(n[PredicateUID], &uid); err != nil {
+			return nil, fmt.Errorf("dql: hydrate: %w", err)
+		}
+		if len(n) == 1 && !onlyUID {
+			continue
+		}
+		data, _ := json.Marshal(n)
+		nodes[uid] = data
+	}
+	for _, uid := range uids {
+		data, ok := nodes[uid]
+		if !ok {
+			continue
+		}
+		var item T
+		if err := Decode(data, &item); err != nil {
+			return nil, fmt.Errorf("dql: hydrate %s: %w", uid, err)
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
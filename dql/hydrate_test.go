@@ -0,0 +1,48 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewHydrateQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []*Attribute
+		want  string
+	}{
+		{"adds uid", []*Attribute{NewAttribute("name")}, "{ users (func: uid(0x1, 0x2a)) { name uid } }"},
+		{"keeps uid", []*Attribute{UIDAttribute(), NewAttribute("name")}, "{ users (func: uid(0x1, 0x2a)) { uid name } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewHydrateQuery("users", []UID{0x1, 0x2a}, tt.attrs...).String(); got != tt.want {
+				t.Errorf("NewHydrateQuery() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHydrate(t *testing.T) {
+	type user struct {
+		UID  UID    `dql:"uid"`
+		Name string `dql:"name"`
+	}
+	calls := 0
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		calls++
+		return &Response{Json: []byte(`{"nodes": [{"uid": "0x1", "name": "Alice"}, {"uid": "0x3"}, {"uid": "0x2", "name": "Bob"}]}`)}, nil
+	})
+	users, err := Hydrate[user](context.Background(), exec, []UID{0x2, 0x3, 0x1, 0x9}, NewAttribute("name"))
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	if len(users) != 2 || users[0].UID != 0x2 || users[0].Name != "Bob" || users[1].Name != "Alice" {
+		t.Errorf("Hydrate() = %+v, want Bob then Alice", users)
+	}
+
+	users, err = Hydrate[user](context.Background(), exec, nil)
+	if err != nil || len(users) != 0 || calls != 1 {
+		t.Errorf("Hydrate() of no uids = %v, %v after %d calls, want no call", users, err, calls)
+	}
+}
@@ -0,0 +1,78 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IntersectionVarName returns the name of the variable holding the nodes matched by a root
+// of a query created by NewIntersectionQuery.
+//
+// Parameters:
+//   - name: The name of the result block.
+//   - i: The index of the root, starting at 0.
+//
+// Returns:
+//   - The name of the variable.
+func IntersectionVarName(name string, i int) string {
+	return fmt.Sprintf("%s_%d", name, i+1)
+}
+
+// NewIntersectionQuery creates a query returning the nodes matched by all of several root
+// functions.
+//
+// A Dgraph block accepts a single root function, so the query matches each root in a variable
+// block, then intersects the variables in the result block:
+//
+//	name_1 AS var(func: A) { uid }
+//	name_2 AS var(func: B) { uid }
+//	name(func: uid(name_1)) @filter(uid(name_2)) { }
+//
+// The variables are named with IntersectionVarName. The result block is the first block of
+// the query and can be customized with attributes and pagination. With a
+// single root, the query is made of a plain block.
+//
+// Parameters:
+//   - name: The name of the result block.
+//   - roots: The root functions, either Criteria or strings. At least one is required.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := NewIntersectionQuery("people", AnyOfTerms("name", "alice bob"), Ge("age", 18))
+//	query.QueryBlocks[0].WithAttributes(NewAttribute("name"))
+//	fmt.Println(query.PrettyPrint())
+//	// Output:
+//	// {
+//	//   people_1 AS var (func: anyofterms(name, "alice bob")) {
+//	//     uid
+//	//   }
+//	//   people_2 AS var (func: ge(age, 18)) {
+//	//     uid
+//	//   }
+//	//   people (func: uid(people_1)) @filter(uid(people_2)) {
+//	//     name
+//	//   }
+//	// }
+//
+// See: https://dgraph.io/docs/query-language/query-variables/
+func NewIntersectionQuery(name string, roots ...any) *Query {
+	if len(roots) == 1 {
+		return NewQuery("", NewQueryBlock(name, roots[0]))
+	}
+	varBlocks := make([]*VarBlock, len(roots))
+	filters := []string{}
+	for i, root := range roots {
+		varBlocks[i] = NewVarBlock(root).
+			WithName(IntersectionVarName(name, i)).
+			WithAttributes(UIDAttribute())
+		if i > 0 {
+			filters = append(filters, Uid(varBlocks[i].Name).String())
+		}
+	}
+	qb := NewQueryBlock(name, Uid(varBlocks[0].Name)).
+		WithDirectives(NewDirective("filter", Raw(strings.Join(filters, " AND "))))
+	return NewQuery("", qb).WithVarBlocks(varBlocks...)
+}
@@ -0,0 +1,28 @@
+package dql
+
+import "testing"
+
+func TestNewIntersectionQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		roots []any
+		want  string
+	}{
+		{"single root", []any{Has("user")}, "{ users (func: has(user)) { } }"},
+		{"two roots", []any{AnyOfTerms("bio", "go"), Eq("city", "Paris")},
+			`{ users_1 AS var (func: anyofterms(bio, "go")) { uid } users_2 AS var (func: eq(city, "Paris")) { uid } users (func: uid(users_1)) @filter(uid(users_2)) { } }`},
+		{"three roots", []any{Has("a"), Has("b"), Has("c")},
+			"{ users_1 AS var (func: has(a)) { uid } users_2 AS var (func: has(b)) { uid } users_3 AS var (func: has(c)) { uid } users (func: uid(users_1)) @filter(uid(users_2) AND uid(users_3)) { } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewIntersectionQuery("users", tt.roots...)
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if err := q.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package dql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// KeysetPage is a page of results of keyset pagination, see QueryBlock.WithKeyset.
+type KeysetPage[T any] struct {
+	// Items is the list of results of the page.
+	Items []T
+
+	// Next is the cursor of the next page, the value of the sort key of the last item, or an
+	// empty string if the page is the last one.
+	Next string
+}
+
+// WithKeyset paginates the query block by the values of a sort key rather than by offset, so
+// pages stay consistent when nodes are added or removed and deep pages cost no more than the
+// first one.
+//
+// The results are ordered by key and limited to first. From the second page on, the cursor is
+// the value of the key of the last item of the previous page, and only the nodes after it are
+// returned, with a gt filter, or lt for descending keys, combined with AND with an existing
+// @filter directive. The key is selected if it is not already, so DecodeKeysetPage can read
+// the cursor of the next page from the response.
+//
+// The values of the key must be unique, such as creation times precise enough to never be
+// equal: nodes sharing the value of the last item of a page would be skipped. Unlike after,
+// which pages by uid, the key can be any ordered predicate.
+//
+// Parameters:
+//   - key: The sort key, created with Asc or Desc.
+//   - first: The number of results per page, an int or a ParamRef.
+//   - cursor: The cursor returned with the previous page, a string or a ParamRef, or an
+//     empty string or nil for the first page.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	users := NewQueryBlock("users", Has("user")).
+//	    WithAttributes(NewAttribute("name")).
+//	    WithKeyset(Asc("created_at"), 10, "2024-01-02T15:04:05Z")
+//	fmt.Println(users.String())
+//	// Output: users (func: has(user), orderasc: created_at, first: 10) @filter(gt(created_at, "2024-01-02T15:04:05Z")) { name created_at }
+func (qb *QueryBlock) WithKeyset(key SortKey, first any, cursor any) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	criteria := []Criteria{}
+	for _, c := range qb.Criteria {
+		if argName(c) != "first" {
+			criteria = append(criteria, c)
+		}
+	}
+	qb.Criteria, qb.Directives = withOrder(criteria, qb.Directives, []SortKey{key})
+	qb.Criteria = append(qb.Criteria, NewArg("first", first))
+	if cursor != nil && cursor != "" {
+		after := Gt(key.Predicate, cursor)
+		if key.Desc {
+			after = Lt(key.Predicate, cursor)
+		}
+		qb.Directives = andFilter(qb.Directives, after)
+	}
+	for _, a := range qb.Attributes {
+		if a.Name == key.Predicate && a.Alias == "" {
+			return qb
+		}
+	}
+	qb.Attributes = append(qb.Attributes, NewAttribute(key.Predicate))
+	return qb
+}
+
+// DecodeKeysetPage decodes the response of a block paginated with WithKeyset.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//   - name: The name of the paginated block.
+//   - key: The predicate of the sort key.
+//   - first: The number of results per page, or 0 if unknown. A page with fewer results is
+//     the last one.
+//
+// Returns:
+//   - A pointer to a KeysetPage holding the decoded items and the cursor of the next page.
+//   - An error if the data cannot be decoded.
+//
+// Example:
+//
+//	page, err := DecodeKeysetPage[User](resp.Json, "users", "created_at", 10)
+//	if page.Next != "" {
+//	    next := NewQueryBlock("users", Has("user")).WithKeyset(Asc("created_at"), 10, page.Next)
+//	}
+func DecodeKeysetPage[T any](data []byte, name string, key string, first int) (*KeysetPage[T], error) {
+	var blocks map[string]json.RawMessage
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("dql: decode keyset page %q: %w", name, err)
+	}
+	page := &KeysetPage[T]{Items: []T{}}
+	raw, ok := blocks[name]
+	if !ok {
+		return page, nil
+	}
+	if err := json.Unmarshal(raw, &page.Items); err != nil {
+		return nil, fmt.Errorf("dql: decode keyset page %q: %w", name, err)
+	}
+	var nodes []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &nodes); err != nil {
+		return nil, fmt.Errorf("dql: decode keyset page %q: %w", name, err)
+	}
+	if len(nodes) == 0 || first > 0 && len(nodes) < first {
+		return page, nil
+	}
+	value, ok := nodes[len(nodes)-1][key]
+	if !ok {
+		return nil, fmt.Errorf("dql: decode keyset page %q: last item lacks %q", name, key)
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		s = strings.TrimSpace(string(value))
+	}
+	page.Next = s
+	return page, nil
+}
@@ -0,0 +1,65 @@
+package dql
+
+import "testing"
+
+func TestWithKeyset(t *testing.T) {
+	tests := []struct {
+		name   string
+		qb     *QueryBlock
+		key    SortKey
+		cursor any
+		want   string
+	}{
+		{"first page", NewQueryBlock("posts", Has("title")), Asc("created"), "",
+			"posts (func: has(title), orderasc: created, first: 10) { created }"},
+		{"next page", NewQueryBlock("posts", Has("title")), Asc("created"), "2024-01-01",
+			`posts (func: has(title), orderasc: created, first: 10) @filter(gt(created, "2024-01-01")) { created }`},
+		{"descending", NewQueryBlock("posts", Has("title")).WithAttributes(NewAttribute("score")), Desc("score"), 7,
+			"posts (func: has(title), orderdesc: score, first: 10) @filter(lt(score, 7)) { score }"},
+		{"existing filter", NewQueryBlock("posts", Has("title")).WithDirectives(NewDirective("filter", Eq("author", ParamRef("$author")))),
+			Asc("created"), ParamRef("$cursor"),
+			"posts (func: has(title), orderasc: created, first: 10) @filter(eq(author, $author) AND gt(created, $cursor)) { created }"},
+		{"replaces first", NewQueryBlock("posts", Has("title")).WithFirst(3), Asc("created"), nil,
+			"posts (func: has(title), orderasc: created, first: 10) { created }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.qb.WithKeyset(tt.key, 10, tt.cursor).String(); got != tt.want {
+				t.Errorf("WithKeyset() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeKeysetPage(t *testing.T) {
+	type post struct {
+		Title   string `json:"title"`
+		Created string `json:"created"`
+	}
+	tests := []struct {
+		name     string
+		data     string
+		key      string
+		wantLen  int
+		wantNext string
+		wantErr  string
+	}{
+		{"full page", `{"posts": [{"title": "a", "created": "2024-01-01"}, {"title": "b", "created": "2024-01-02"}]}`, "created", 2, "2024-01-02", ""},
+		{"number key", `{"posts": [{"title": "a", "created": "x", "score": 1}, {"title": "b", "created": "y", "score": 2.5}]}`, "score", 2, "2.5", ""},
+		{"last page", `{"posts": [{"title": "a", "created": "2024-01-01"}]}`, "created", 1, "", ""},
+		{"missing block", `{}`, "created", 0, "", ""},
+		{"missing key", `{"posts": [{"title": "a"}, {"title": "b"}]}`, "created", 0, "", `dql: decode keyset page "posts": last item lacks "created"`},
+		{"invalid", `[`, "created", 0, "", `dql: decode keyset page "posts": unexpected end of JSON input`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := DecodeKeysetPage[post]([]byte(tt.data), "posts", tt.key, 2)
+			if errString(err) != tt.wantErr {
+				t.Fatalf("DecodeKeysetPage() error = %q, want %q", errString(err), tt.wantErr)
+			}
+			if err == nil && (len(page.Items) != tt.wantLen || page.Next != tt.wantNext) {
+				t.Errorf("DecodeKeysetPage() = %d items, next %q, want %d, %q", len(page.Items), page.Next, tt.wantLen, tt.wantNext)
+			}
+		})
+	}
+}
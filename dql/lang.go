@@ -0,0 +1,86 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang returns a predicate name tagged with languages, e.g. name@en, escaping the name with
+// Predicate first so IRIs are tagged outside of their angle brackets.
+//
+// Several languages are tried in order, e.g. name@en:fr returns the French value when there
+// is no English one. The language "." selects a value in any language, and no language the
+// untagged value. The result can be passed to the typed function builders, such as
+// AllOfTerms, and to NewAttribute.
+//
+// Parameters:
+//   - predicate: The predicate name, without language tag.
+//   - langs: The languages, by decreasing preference.
+//
+// Returns:
+//   - The tagged predicate name.
+//
+// Example:
+//
+//	fmt.Println(AnyOfTerms(Lang("name", "en", "fr"), "jones").String()) // Output: anyofterms(name@en:fr, "jones")
+//	fmt.Println(Lang("http://schema.org/name", "en"))                   // Output: <http://schema.org/name>@en
+//
+// See: https://dgraph.io/docs/query-language/graphql-fundamentals/#language-support
+func Lang(predicate string, langs ...string) string {
+	name := Predicate(predicate)
+	if len(langs) == 0 {
+		return name
+	}
+	return name + "@" + strings.Join(langs, ":")
+}
+
+// splitLang splits a predicate name into the name and its language tag, if any.
+func splitLang(name string) (string, string, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "~")
+	start := 0
+	if strings.HasPrefix(name, "<") {
+		if start = strings.IndexByte(name, '>'); start < 0 {
+			return name, "", false
+		}
+	}
+	i := strings.IndexByte(name[start:], '@')
+	if i < 0 {
+		return name, "", false
+	}
+	return strings.Trim(name[:start+i], "<>"), name[start+i+1:], true
+}
+
+// validateLangs reports the language tags used under a node on predicates the schema does not
+// declare as strings, the only type whose values Dgraph tags with languages.
+func (s *Schema) validateLangs(n Node) error {
+	check := func(name string) error {
+		if isExpression(name) {
+			return nil
+		}
+		pred, lang, ok := splitLang(name)
+		if !ok {
+			return nil
+		}
+		if p := s.Predicate(pred); p != nil && p.Type != "string" {
+			return fmt.Errorf("predicate %q: language tag @%s requires a string predicate, not %s", pred, lang, p.Type)
+		}
+		return nil
+	}
+	var err error
+	Walk(n, func(n Node) bool {
+		if a, ok := n.(*Attribute); ok && !a.Raw && err == nil {
+			err = check(a.Name)
+		}
+		return err == nil
+	})
+	for _, list := range criteriaLists(n) {
+		for _, c := range list {
+			walkCriteria(c, func(c Criteria) {
+				if f, ok := c.(*Function); ok && len(f.Args) != 0 && err == nil {
+					err = check(f.Args[0].String())
+				}
+			})
+		}
+	}
+	return err
+}
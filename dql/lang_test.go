@@ -0,0 +1,51 @@
+package dql
+
+import "testing"
+
+func TestLang(t *testing.T) {
+	tests := []struct {
+		predicate string
+		langs     []string
+		want      string
+	}{
+		{"name", nil, "name"},
+		{"name", []string{"en"}, "name@en"},
+		{"name", []string{"fr", "en", "."}, "name@fr:en:."},
+		{"first name", []string{"en"}, "<first%20name>@en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Lang(tt.predicate, tt.langs...); got != tt.want {
+				t.Errorf("Lang() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLangs(t *testing.T) {
+	schema, err := ParseSchema("name: string @lang .\nage: int .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		qb      *QueryBlock
+		wantErr string
+	}{
+		{"string attribute", NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute(Lang("name", "en"))), ""},
+		{"string function", NewQueryBlock("me", Eq(Lang("name", "en"), "Alice")), ""},
+		{"undeclared predicate", NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute(Lang("nick", "en"))), ""},
+		{"int attribute", NewQueryBlock("me", Has("name")).WithAttributes(NewAttribute(Lang("age", "en"))),
+			`dql: query block "me": predicate "age": language tag @en requires a string predicate, not int`},
+		{"int function", NewQueryBlock("me", Eq(Lang("age", "en"), 1)),
+			`dql: query block "me": predicate "age": language tag @en requires a string predicate, not int`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery("", tt.qb).WithSchema(schema)
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package dql
+
+import "fmt"
+
+// Limits bounds the size of a query, see WithLimits.
+//
+// Zero fields mean no limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of attributes. Attributes selected directly by a
+	// block or a fragment are at depth 1.
+	MaxDepth int
+
+	// MaxAttributes is the maximum total number of attributes of the query, including nested
+	// attributes and the attributes of fragments.
+	MaxAttributes int
+
+	// MaxBlocks is the maximum number of variable, shortest path and query blocks.
+	MaxBlocks int
+}
+
+// WithLimits bounds the size of the query.
+//
+// Validate rejects queries exceeding the limits. This guards queries expanded
+// programmatically, e.g. from fields selected by users, against growing unbounded.
+//
+// Parameters:
+//   - limits: The limits of the query.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Has("user")).
+//	    WithAttributes(NewAttribute("friend").WithAttributes(NewAttribute("name")))).
+//	    WithLimits(Limits{MaxDepth: 1})
+//	fmt.Println(query.Validate()) // Output: dql: query block "me": depth 2 exceeds the limit of 1
+func (q *Query) WithLimits(limits Limits) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	q.Limits = &limits
+	return q
+}
+
+// validateLimits reports the first limit exceeded by the query.
+func (q *Query) validateLimits() error {
+	l := q.Limits
+	if blocks := len(q.VarBlocks) + len(q.ShortestPaths) + len(q.QueryBlocks); l.MaxBlocks != 0 && blocks > l.MaxBlocks {
+		return fmt.Errorf("dql: %d blocks exceed the limit of %d", blocks, l.MaxBlocks)
+	}
+
+	total := 0
+	check := func(kind string, name string, attrs []*Attribute) error {
+		depth, count := selectionSize(attrs)
+		total += count
+		if l.MaxDepth != 0 && depth > l.MaxDepth {
+			return fmt.Errorf("dql: %s %q: depth %d exceeds the limit of %d", kind, name, depth, l.MaxDepth)
+		}
+		return nil
+	}
+	for _, vb := range q.VarBlocks {
+		if err := check("var block", vb.Name, vb.Attributes); err != nil {
+			return err
+		}
+	}
+	for _, sp := range q.ShortestPaths {
+		if err := check("shortest path", sp.Name, sp.Attributes); err != nil {
+			return err
+		}
+	}
+	for _, qb := range q.QueryBlocks {
+		if err := check("query block", qb.Name, qb.Attributes); err != nil {
+			return err
+		}
+	}
+	for _, f := range q.fragments() {
+		if err := check("fragment", f.Name, f.Attributes); err != nil {
+			return err
+		}
+	}
+	if l.MaxAttributes != 0 && total > l.MaxAttributes {
+		return fmt.Errorf("dql: %d attributes exceed the limit of %d", total, l.MaxAttributes)
+	}
+	return nil
+}
+
+// selectionSize returns the nesting depth and the total number of attributes of a selection
+// set.
+func selectionSize(attrs []*Attribute) (depth int, count int) {
+	for _, a := range attrs {
+		d, c := selectionSize(a.Attributes)
+		depth = max(depth, d+1)
+		count += c + 1
+	}
+	return depth, count
+}
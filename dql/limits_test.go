@@ -0,0 +1,39 @@
+package dql
+
+import "testing"
+
+func TestLimits(t *testing.T) {
+	query := func() *Query {
+		return NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+			NewAttribute("name"),
+			NewAttribute("friend").WithAttributes(NewAttribute("name"), NewAttribute("friend").WithAttributes(NewAttribute("name"))))).
+			WithVarBlocks(NewVarBlock(Has("user")).WithName("u"))
+	}
+	tests := []struct {
+		name    string
+		limits  Limits
+		wantErr string
+	}{
+		{"no limits", Limits{}, ""},
+		{"within limits", Limits{MaxDepth: 3, MaxAttributes: 5, MaxBlocks: 2}, ""},
+		{"depth", Limits{MaxDepth: 2}, `dql: query block "me": depth 3 exceeds the limit of 2`},
+		{"attributes", Limits{MaxAttributes: 4}, "dql: 5 attributes exceed the limit of 4"},
+		{"blocks", Limits{MaxBlocks: 1}, "dql: 2 blocks exceed the limit of 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(query().WithLimits(tt.limits).Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimitsClone(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("me", Has("user"))).WithLimits(Limits{MaxDepth: 1})
+	c := q.Clone()
+	c.Limits.MaxDepth = 5
+	if q.Limits.MaxDepth != 1 {
+		t.Errorf("Clone() shares the limits")
+	}
+}
@@ -0,0 +1,140 @@
+package dql
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variableRefPattern matches the expressions referencing variables: uid(a), val(a), len(a)
+// and math(a + b).
+var variableRefPattern = regexp.MustCompile(`\b(?:uid|val|len|math)\(([^()]*)\)`)
+
+// wordPattern matches the words of an expression.
+var wordPattern = regexp.MustCompile(`\w+`)
+
+// Issue is a problem reported by Lint.
+type Issue struct {
+	// Block is the name of the block or fragment the issue was found in, empty for issues of
+	// the whole query.
+	Block string
+
+	// Message describes the issue.
+	Message string
+}
+
+// String generates a human-readable description of the issue.
+//
+// Returns:
+//   - A string representation of the issue.
+func (i Issue) String() string {
+	if i.Block == "" {
+		return i.Message
+	}
+	return i.Block + ": " + i.Message
+}
+
+// Lint reports likely mistakes in a query, beyond the errors reported by Validate.
+//
+// The following issues are reported:
+//   - the error returned by Validate, if any,
+//   - variables that are defined but never used with uid(), val() or math(),
+//   - fragments that are declared but never spread,
+//   - query blocks starting from has() without first, which read every node having the
+//     predicate.
+//
+// Parameters:
+//   - q: The query to lint.
+//
+// Returns:
+//   - The issues found, in order of the blocks.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Has("user"))).
+//	    WithVarBlocks(NewVarBlock(Has("admin")).WithName("admins"))
+//	for _, issue := range Lint(query) {
+//	    fmt.Println(issue) // Output: admins: variable "admins" is never used
+//	}
+func Lint(q *Query) []Issue {
+	issues := []Issue{}
+	if err := q.Validate(); err != nil {
+		issues = append(issues, Issue{Message: err.Error()})
+	}
+
+	rendered := q.String()
+	used := map[string]bool{}
+	for _, m := range variableRefPattern.FindAllStringSubmatch(rendered, -1) {
+		for _, name := range wordPattern.FindAllString(m[1], -1) {
+			used[name] = true
+		}
+	}
+	defined := func(block string, name string) {
+		if name != "" && !used[name] {
+			issues = append(issues, Issue{Block: block, Message: "variable \"" + name + "\" is never used"})
+		}
+	}
+	for _, vb := range q.VarBlocks {
+		if !vb.Raw {
+			defined(vb.Name, vb.Name)
+			lintAttributeVars(vb.Name, vb.Attributes, defined)
+		}
+	}
+	for _, sp := range q.ShortestPaths {
+		defined(sp.Name, sp.Name)
+	}
+	for _, qb := range q.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		lintAttributeVars(qb.Name, qb.Attributes, defined)
+		if len(qb.Criteria) != 0 && strings.HasPrefix(strings.TrimSpace(qb.Criteria[0].String()), "has(") && !hasArg(qb.Criteria, "first") {
+			issues = append(issues, Issue{Block: qb.Name, Message: "has() root without first reads every node with the predicate"})
+		}
+	}
+
+	spread := map[string]bool{}
+	Walk(q, func(n Node) bool {
+		if a, ok := n.(*Attribute); ok {
+			if name, ok := strings.CutPrefix(a.Name, "..."); ok {
+				spread[name] = true
+			}
+		}
+		return true
+	})
+	unused := []string{}
+	for _, f := range q.Fragments {
+		if !spread[f.Name] {
+			unused = append(unused, f.Name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		issues = append(issues, Issue{Block: name, Message: "fragment \"" + name + "\" is never used"})
+	}
+	return issues
+}
+
+// lintAttributeVars reports the variables defined by attributes to defined.
+func lintAttributeVars(block string, attrs []*Attribute, defined func(block string, name string)) {
+	for _, a := range attrs {
+		if a.Raw {
+			continue
+		}
+		defined(block, a.Var)
+		for _, name := range facetVars(a.Directives) {
+			defined(block, name)
+		}
+		lintAttributeVars(block, a.Attributes, defined)
+	}
+}
+
+// hasArg reports whether criteria include a named argument.
+func hasArg(criteria []Criteria, name string) bool {
+	for _, c := range criteria {
+		if argName(c) == name {
+			return true
+		}
+	}
+	return false
+}
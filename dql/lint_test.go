@@ -0,0 +1,58 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{
+			name: "clean",
+			query: NewQuery("", NewQueryBlock("me", Uid("friends")).WithAttributes(NewAttribute("name"))).
+				WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends").WithFirst(10)),
+			want: []string{},
+		},
+		{
+			name: "unused variables",
+			query: NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("age").WithVar("a"))).
+				WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends").WithFirst(10)),
+			want: []string{`friends: variable "friends" is never used`, `me: variable "a" is never used`},
+		},
+		{
+			name:  "has root without first",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("name"))),
+			want:  []string{"me: has() root without first reads every node with the predicate"},
+		},
+		{
+			name: "unused fragments",
+			query: NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("...used"))).
+				WithFragments(NewFragment("used").WithAttributes(NewAttribute("name")),
+					NewFragment("zeta").WithAttributes(NewAttribute("name")),
+					NewFragment("alpha").WithAttributes(NewAttribute("name"))),
+			want: []string{`alpha: fragment "alpha" is never used`, `zeta: fragment "zeta" is never used`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, issue := range Lint(tt.query) {
+				got = append(got, issue.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintInvalid(t *testing.T) {
+	issues := Lint(NewQuery("", NewQueryBlock("me", Eq("name", ParamRef("$name")))))
+	if len(issues) == 0 || issues[0].Block != "" {
+		t.Fatalf("Lint() = %v, want a validation issue first", issues)
+	}
+}
@@ -0,0 +1,162 @@
+package dql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Without creates a new Selection without the attributes reading some predicates, at any
+// depth. The selection itself is not modified.
+//
+// Attributes are matched by the predicate they read, so name@en and count(name) are removed
+// along with name.
+//
+// Parameters:
+//   - predicates: The predicates to remove.
+//
+// Returns:
+//   - A pointer to the new Selection object.
+//
+// Example:
+//
+//	public := userFields.Without("password", "ssn")
+func (s *Selection) Without(predicates ...string) *Selection {
+	return &Selection{attrs: withoutPredicates(cloneAttributes(s.attrs), predicateSet(predicates))}
+}
+
+// StripFieldsRewriter creates a Rewriter removing the attributes reading masked predicates
+// from queries, so sensitive predicates are never fetched whatever the callers select.
+//
+// Attributes are removed at any depth, in blocks and fragments. Queries that still reference
+// a masked predicate afterwards, in a filter, an ordering or a raw block, or that select
+// expand(), which could fetch masked predicates, are refused as with RefuseFieldsRewriter.
+//
+// Parameters:
+//   - predicates: The masked predicates.
+//
+// Returns:
+//   - A Rewriter.
+//
+// Example:
+//
+//	exec := NewRewritingExecutor(client, StripFieldsRewriter("password", "ssn"))
+//	// me(func: has(user)) { name password } runs as me(func: has(user)) { name }
+func StripFieldsRewriter(predicates ...string) Rewriter {
+	masked := predicateSet(predicates)
+	return func(ctx context.Context, q *Query) (*Query, error) {
+		Walk(q, func(n Node) bool {
+			switch n := n.(type) {
+			case *VarBlock:
+				n.Attributes = withoutPredicates(n.Attributes, masked)
+			case *ShortestPath:
+				n.Attributes = withoutPredicates(n.Attributes, masked)
+			case *QueryBlock:
+				n.Attributes = withoutPredicates(n.Attributes, masked)
+			case *Fragment:
+				n.Attributes = withoutPredicates(n.Attributes, masked)
+			}
+			return true
+		})
+		if err := checkFieldMask(q, masked); err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+}
+
+// RefuseFieldsRewriter creates a Rewriter refusing queries referencing masked predicates, so
+// sensitive predicates are neither fetched nor probed with filters.
+//
+// Every reference found by Predicates is checked, as well as the text of raw blocks and
+// attributes. Queries selecting expand() are refused too, since it could fetch masked
+// predicates.
+//
+// Parameters:
+//   - predicates: The masked predicates.
+//
+// Returns:
+//   - A Rewriter.
+//
+// Example:
+//
+//	exec := NewRewritingExecutor(client, RefuseFieldsRewriter("password", "ssn"))
+//	_, err := exec.Execute(ctx, query, nil)
+//	fmt.Println(err) // Output: dql: predicate "password" is masked
+func RefuseFieldsRewriter(predicates ...string) Rewriter {
+	masked := predicateSet(predicates)
+	return func(ctx context.Context, q *Query) (*Query, error) {
+		if err := checkFieldMask(q, masked); err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+}
+
+// predicateSet returns the set of the predicates read by names, see predicateOf.
+func predicateSet(names []string) map[string]bool {
+	res := map[string]bool{}
+	for _, name := range names {
+		if p := predicateOf(name); p != "" {
+			res[p] = true
+		}
+	}
+	return res
+}
+
+// withoutPredicates removes the attributes reading masked predicates from attrs and from
+// their nested attributes, in place.
+func withoutPredicates(attrs []*Attribute, masked map[string]bool) []*Attribute {
+	res := []*Attribute{}
+	for _, a := range attrs {
+		if !a.Raw && masked[predicateOf(a.Name)] {
+			continue
+		}
+		a.Attributes = withoutPredicates(a.Attributes, masked)
+		res = append(res, a)
+	}
+	return res
+}
+
+// checkFieldMask reports the first masked predicate referenced by a query.
+func checkFieldMask(q *Query, masked map[string]bool) error {
+	if len(masked) == 0 {
+		return nil
+	}
+	for _, p := range Predicates(q) {
+		if masked[p] {
+			return fmt.Errorf("dql: predicate %q is masked", p)
+		}
+	}
+	names := make([]string, 0, len(masked))
+	for p := range masked {
+		names = append(names, regexp.QuoteMeta(p))
+	}
+	rawPattern := regexp.MustCompile(`(^|[^\w.])(` + strings.Join(names, "|") + `)([^\w.]|$)`)
+	var err error
+	Walk(q, func(n Node) bool {
+		var raw string
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Raw {
+				raw = n.Name
+			}
+		case *QueryBlock:
+			if n.Raw {
+				raw = n.Name
+			}
+		case *Attribute:
+			if n.Raw {
+				raw = n.Name
+			} else if strings.HasPrefix(n.Name, "expand(") && err == nil {
+				err = fmt.Errorf("dql: %s could fetch masked predicates", n.Name)
+			}
+		}
+		if m := rawPattern.FindStringSubmatch(raw); m != nil && err == nil {
+			err = fmt.Errorf("dql: predicate %q is masked", m[2])
+		}
+		return err == nil
+	})
+	return err
+}
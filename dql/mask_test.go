@@ -0,0 +1,56 @@
+package dql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSelectionWithout(t *testing.T) {
+	user := NewSelection(NewAttribute("name"), NewAttribute("password"),
+		NewAttribute("friend").WithAttributes(NewAttribute("name"), NewAttribute("password@en")))
+	if got, want := user.Without("password").String(), "name friend { name }"; got != want {
+		t.Errorf("Without() = %q, want %q", got, want)
+	}
+	if got, want := user.String(), "name password friend { name password@en }"; got != want {
+		t.Errorf("Without() modified the selection: %q", got)
+	}
+}
+
+func TestFieldMaskRewriters(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          *Query
+		wantStrip  string
+		wantRefuse string
+	}{
+		{"attributes", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("name"), NewAttribute("ssn"),
+			NewAttribute("friend").WithAttributes(NewAttribute("ssn")))),
+			"", `dql: predicate "ssn" is masked`},
+		{"filter", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Eq("ssn", "1")))),
+			`dql: predicate "ssn" is masked`, `dql: predicate "ssn" is masked`},
+		{"expand", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("expand(_all_)"))),
+			"dql: expand(_all_) could fetch masked predicates", "dql: expand(_all_) could fetch masked predicates"},
+		{"raw attribute", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewRawAttribute("s : ssn"))),
+			`dql: predicate "ssn" is masked`, `dql: predicate "ssn" is masked`},
+		{"similar names", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewRawAttribute("ssn_hash"), NewAttribute("user.ssn"))), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped, err := StripFieldsRewriter("ssn")(context.Background(), tt.q.Clone())
+			if got := errString(err); got != tt.wantStrip {
+				t.Errorf("StripFieldsRewriter() error = %q, want %q", got, tt.wantStrip)
+			}
+			if err == nil {
+				for _, p := range Predicates(stripped) {
+					if p == "ssn" {
+						t.Errorf("StripFieldsRewriter() = %s, still reading ssn", stripped)
+					}
+				}
+			}
+			_, err = RefuseFieldsRewriter("ssn")(context.Background(), tt.q.Clone())
+			if got := errString(err); got != tt.wantRefuse {
+				t.Errorf("RefuseFieldsRewriter() error = %q, want %q", got, tt.wantRefuse)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements of executions, to export them to a monitoring system, e.g.
+// with the Prometheus collector of package dqlprom.
+//
+// Queries are measured by MetricsMiddleware, retries by ObservedRetryPolicy and mutations by
+// the executors running them, such as dqlhttp.Client. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	// ObserveQuery records an execution of a query.
+	ObserveQuery(ctx context.Context, obs QueryObservation)
+
+	// ObserveRetry records a retry after a failed attempt, numbered from 1.
+	ObserveRetry(attempt int, err error)
+
+	// ObserveMutation records a mutation with its size in bytes.
+	ObserveMutation(ctx context.Context, obs MutationObservation)
+}
+
+// QueryObservation is the measurement of an execution of a query.
+type QueryObservation struct {
+	// Name identifies the query: its name, or the names of its blocks joined with + if it has
+	// none.
+	Name string
+
+	// Fingerprint is the shape fingerprint of the query, see Query.ShapeFingerprint, so that
+	// executions differing only by their literal values share the same label instead of
+	// creating one series per value.
+	Fingerprint string
+
+	// Duration is the duration of the execution.
+	Duration time.Duration
+
+	// Err is the error of the execution, or nil.
+	Err error
+}
+
+// MutationObservation is the measurement of a mutation.
+type MutationObservation struct {
+	// Size is the size of the mutation in bytes.
+	Size int
+
+	// Duration is the duration of the mutation.
+	Duration time.Duration
+
+	// Err is the error of the mutation, or nil.
+	Err error
+}
+
+// MetricsMiddleware creates a Middleware reporting the executions of queries to m.
+//
+// Parameters:
+//   - m: The Metrics receiving the measurements.
+//
+// Returns:
+//   - A Middleware.
+//
+// Example:
+//
+//	collector := dqlprom.NewCollector()
+//	http.Handle("/metrics", collector)
+//	exec := Chain(client, MetricsMiddleware(collector), RetryMiddleware(ObservedRetryPolicy(DefaultRetryPolicy(), collector)))
+func MetricsMiddleware(m Metrics) Middleware {
+	return func(next Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Execute(ctx, q, vars)
+			m.ObserveQuery(ctx, QueryObservation{
+				Name:        MetricsName(q),
+				Fingerprint: q.ShapeFingerprint(),
+				Duration:    time.Since(start),
+				Err:         err,
+			})
+			return resp, err
+		})
+	}
+}
+
+// MetricsName returns the name identifying a query in metrics: its name, or the names of its
+// blocks joined with + if it has none.
+//
+// Parameters:
+//   - q: The query.
+//
+// Returns:
+//   - The name of the query.
+func MetricsName(q *Query) string {
+	if q.Name != "" {
+		return q.Name
+	}
+	names := []string{}
+	for _, qb := range q.QueryBlocks {
+		names = append(names, qb.Name)
+	}
+	return strings.Join(names, "+")
+}
+
+// ErrorKindName returns a short name of the kind of an error for metrics labels: validation,
+// unknown_variable, schema_mismatch, syntax, exec, or other for the errors of no kind.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - The name of the kind of the error.
+func ErrorKindName(err error) string {
+	var e *Error
+	if !errors.As(err, &e) {
+		return "other"
+	}
+	switch e.Kind {
+	case ErrUnknownVariable:
+		return "unknown_variable"
+	case ErrSchemaMismatch:
+		return "schema_mismatch"
+	case ErrValidation:
+		return "validation"
+	case ErrSyntax:
+		return "syntax"
+	case ErrExec:
+		return "exec"
+	}
+	return "other"
+}
+
+// ObservedRetryPolicy wraps a RetryPolicy to report its retries to m.
+//
+// Parameters:
+//   - policy: The RetryPolicy deciding the retries.
+//   - m: The Metrics receiving the retries.
+//
+// Returns:
+//   - A RetryPolicy.
+func ObservedRetryPolicy(policy RetryPolicy, m Metrics) RetryPolicy {
+	return observedRetryPolicy{policy: policy, metrics: m}
+}
+
+// observedRetryPolicy is the RetryPolicy of ObservedRetryPolicy.
+type observedRetryPolicy struct {
+	policy  RetryPolicy
+	metrics Metrics
+}
+
+// Backoff implements RetryPolicy.
+func (p observedRetryPolicy) Backoff(attempt int, err error) (time.Duration, bool) {
+	delay, ok := p.policy.Backoff(attempt, err)
+	if ok {
+		p.metrics.ObserveRetry(attempt, err)
+	}
+	return delay, ok
+}
@@ -0,0 +1,85 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// recordingMetrics records the measurements it receives.
+type recordingMetrics struct {
+	mu      sync.Mutex
+	queries []QueryObservation
+	retries int
+}
+
+func (m *recordingMetrics) ObserveQuery(ctx context.Context, obs QueryObservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, obs)
+}
+
+func (m *recordingMetrics) ObserveRetry(attempt int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *recordingMetrics) ObserveMutation(ctx context.Context, obs MutationObservation) {}
+
+func TestMetricsMiddleware(t *testing.T) {
+	m := &recordingMetrics{}
+	exec := Chain(&countingExecutor{}, MetricsMiddleware(m))
+	for _, name := range []string{"Alice", "Bob"} {
+		if _, err := exec.Execute(context.Background(), userQuery(name), nil); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if len(m.queries) != 2 {
+		t.Fatalf("observed %d queries, want 2", len(m.queries))
+	}
+	if m.queries[0].Fingerprint != userQuery("Alice").ShapeFingerprint() {
+		t.Errorf("fingerprint = %s, want the shape fingerprint of the query", m.queries[0].Fingerprint)
+	}
+	// Queries differing only by their values share their fingerprint label.
+	if m.queries[0].Fingerprint != m.queries[1].Fingerprint {
+		t.Errorf("fingerprints %s and %s differ", m.queries[0].Fingerprint, m.queries[1].Fingerprint)
+	}
+	if m.queries[0].Name != "me" {
+		t.Errorf("name = %s, want me", m.queries[0].Name)
+	}
+}
+
+func TestErrorKindName(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&Error{Kind: ErrValidation, Err: errors.New("x")}, "validation"},
+		{&Error{Kind: ErrSyntax, Err: errors.New("x")}, "syntax"},
+		{&Error{Kind: ErrExec, Err: errors.New("x")}, "exec"},
+		{errors.New("x"), "other"},
+	}
+	for _, tt := range tests {
+		if got := ErrorKindName(tt.err); got != tt.want {
+			t.Errorf("ErrorKindName(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestObservedRetryPolicy(t *testing.T) {
+	m := &recordingMetrics{}
+	policy := ObservedRetryPolicy(&ExponentialBackoff{MaxAttempts: 3, Retryable: func(error) bool { return true }}, m)
+	attempts := 0
+	err := Retry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("aborted")
+	})
+	if err == nil || attempts != 3 {
+		t.Fatalf("Retry() = %v after %d attempts, want an error after 3", err, attempts)
+	}
+	if m.retries != 2 {
+		t.Errorf("observed %d retries, want 2", m.retries)
+	}
+}
@@ -0,0 +1,52 @@
+package dql
+
+// Middleware wraps an Executor with behavior run around the execution of every query, such
+// as caching, metrics, rewriting or rate limiting.
+//
+// A Middleware returns an Executor that may inspect or replace the query and its variables,
+// call next zero or more times, and inspect or replace the response and the error.
+type Middleware func(next Executor) Executor
+
+// Chain wraps an Executor with middlewares.
+//
+// The first middleware is the outermost: it receives the queries first and the responses
+// last. Chain with no middleware returns exec.
+//
+// Parameters:
+//   - exec: The Executor running the queries, e.g. a dqlhttp.Client.
+//   - middlewares: The middlewares, from the outermost to the innermost.
+//
+// Returns:
+//   - An Executor.
+//
+// Example:
+//
+//	logging := func(next Executor) Executor {
+//	    return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+//	        start := time.Now()
+//	        resp, err := next.Execute(ctx, q, vars)
+//	        log.Printf("%s took %s", q.Name, time.Since(start))
+//	        return resp, err
+//	    })
+//	}
+//	exec := Chain(client, logging, RewriteMiddleware(FilterRewriter(tenantFilter)))
+func Chain(exec Executor, middlewares ...Middleware) Executor {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		exec = middlewares[i](exec)
+	}
+	return exec
+}
+
+// RewriteMiddleware creates a Middleware applying rewriters to every query, see
+// NewRewritingExecutor.
+//
+// Parameters:
+//   - rewriters: The rewriters applied to every query, in order.
+//
+// Returns:
+//   - A Middleware.
+func RewriteMiddleware(rewriters ...Rewriter) Middleware {
+	return func(next Executor) Executor {
+		return NewRewritingExecutor(next, rewriters...)
+	}
+}
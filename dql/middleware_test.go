@@ -0,0 +1,39 @@
+package dql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	calls := []string{}
+	trace := func(name string) Middleware {
+		return func(next Executor) Executor {
+			return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next.Execute(ctx, q, vars)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+	var executed string
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		calls = append(calls, "execute")
+		executed = q.String()
+		return &Response{}, nil
+	})
+	chained := Chain(exec, trace("outer"), RewriteMiddleware(FilterRewriter(tenantFilter)), trace("inner"))
+	q := NewQuery("", NewQueryBlock("me", Has("user")))
+	if _, err := chained.Execute(context.Background(), q, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := []string{"outer before", "inner before", "execute", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if want := `{ me (func: has(user)) @filter(eq(tenant, "acme")) { } }`; executed != want {
+		t.Errorf("executed query = %s, want %s", executed, want)
+	}
+}
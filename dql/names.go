@@ -0,0 +1,61 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// namePattern matches the names Dgraph accepts for queries, blocks, fragments, aliases and
+// variables: a letter or an underscore followed by letters, digits, underscores and dots.
+var namePattern = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_.]*$`)
+
+// reservedWords lists the keywords of DQL which Dgraph does not accept as the name of a query,
+// block, fragment, alias or variable, although the query renders without complaint.
+var reservedWords = map[string]bool{
+	"query":    true,
+	"mutation": true,
+	"fragment": true,
+	"schema":   true,
+	"upsert":   true,
+	"set":      true,
+	"delete":   true,
+	"var":      true,
+	"func":     true,
+	"as":       true,
+	"shortest": true,
+	"uid":      true,
+	"val":      true,
+	"and":      true,
+	"or":       true,
+	"not":      true,
+}
+
+// validateName checks a name against the character rules of Dgraph and the reserved words of
+// DQL. kind describes the name in errors, e.g. block name.
+func validateName(kind string, name string) error {
+	if reservedWords[name] {
+		return fmt.Errorf("invalid %s %q: %s is a reserved word", kind, name, name)
+	}
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid %s %q: must start with a letter or an underscore and contain only letters, digits, underscores and dots", kind, name)
+	}
+	return nil
+}
+
+// validateFacetVarNames checks the names of the variables assigned in @facets directives.
+func validateFacetVarNames(directives []Criteria) error {
+	for _, d := range directives {
+		d, ok := d.(*Directive)
+		if !ok || d.Name != "facets" {
+			continue
+		}
+		for _, arg := range d.Args {
+			if v, ok := arg.(FacetVar); ok {
+				if err := validateName("variable name", v.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package dql
+
+import "testing"
+
+func TestValidateNames(t *testing.T) {
+	me := func(attrs ...*Attribute) *QueryBlock {
+		return NewQueryBlock("me", Has("name")).WithAttributes(attrs...)
+	}
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"valid", NewQuery("Get_user.v2", NewQueryBlock("我的", Has("name"))), ""},
+		{"reserved query name", NewQuery("query", me()), `dql: invalid query name "query": query is a reserved word`},
+		{"block starting with a digit", NewQuery("", NewQueryBlock("1st", Has("name"))),
+			`dql: query block "1st": invalid block name "1st": must start with a letter or an underscore and contain only letters, digits, underscores and dots`},
+		{"reserved block name", NewQuery("", NewQueryBlock("var", Has("name"))), `dql: query block "var": invalid block name "var": var is a reserved word`},
+		{"alias", NewQuery("", me(NewAttribute("name").WithAlias("full-name"))),
+			`dql: query block "me": name: invalid alias "full-name": must start with a letter or an underscore and contain only letters, digits, underscores and dots`},
+		{"variable", NewQuery("", me(NewAttribute("age").WithVar("uid"))), `dql: query block "me": age: invalid variable name "uid": uid is a reserved word`},
+		{"facet variable", NewQuery("", me(NewAttribute("friend").WithDirectives(Facets(FacetVar{Name: "as", Facet: "w"})).WithAttributes(NewAttribute("name")))),
+			`dql: query block "me": friend: invalid variable name "as": as is a reserved word`},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("and"))).WithVarBlocks(NewVarBlock(Has("name")).WithName("and")),
+			`dql: var block "and": invalid variable name "and": and is a reserved word`},
+		{"shortest path", NewQuery("", NewQueryBlock("me", Uid("not"))).WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("not")),
+			`dql: shortest path "not": invalid variable name "not": not is a reserved word`},
+		{"fragment", NewQuery("", me(Spread(NewFragment("my frag").WithAttributes(NewAttribute("name"))))),
+			`dql: fragment "my frag": invalid fragment name "my frag": must start with a letter or an underscore and contain only letters, digits, underscores and dots`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package dql
+
+import "fmt"
+
+// Node is an element of a DQL query AST.
+//
+// Query, Param, VarBlock, ShortestPath, QueryBlock, Fragment and Attribute all implement Node,
+// which allows Walk and Rewrite to traverse a query uniformly. Leaf values such as
+// Function, Raw, Placeholder and ParamRef implement Node as well.
+type Node interface {
+	// String generates the DQL representation of the node.
+	String() string
+
+	node()
+}
+
+func (*Query) node()        {}
+func (*Param) node()        {}
+func (*VarBlock) node()     {}
+func (*ShortestPath) node() {}
+func (*QueryBlock) node()   {}
+func (*Fragment) node()     {}
+func (*Attribute) node()    {}
+func (*Function) node()     {}
+func (Raw) node()           {}
+func (Placeholder) node()   {}
+func (ParamRef) node()      {}
+
+// Walk traverses the AST rooted at n in depth-first order.
+//
+// The visit function is called for each node before its children. If it returns false,
+// the children of that node are skipped.
+//
+// The children of a Query are visited in rendering order: params, variable blocks,
+// shortest path blocks, query blocks and fragments.
+//
+// Parameters:
+//   - n: The root node of the traversal.
+//   - visit: The function called for each node.
+//
+// Example:
+//
+//	Walk(query, func(n Node) bool {
+//	    if attr, ok := n.(*Attribute); ok {
+//	        fmt.Println(attr.Name)
+//	    }
+//	    return true
+//	})
+func Walk(n Node, visit func(n Node) bool) {
+	if n == nil || !visit(n) {
+		return
+	}
+	for _, child := range children(n) {
+		Walk(child, visit)
+	}
+}
+
+// children returns the direct children of a node.
+func children(n Node) []Node {
+	res := []Node{}
+	switch n := n.(type) {
+	case *Query:
+		for _, p := range n.Params {
+			res = append(res, p)
+		}
+		for _, vb := range n.VarBlocks {
+			res = append(res, vb)
+		}
+		for _, sp := range n.ShortestPaths {
+			res = append(res, sp)
+		}
+		for _, qb := range n.QueryBlocks {
+			res = append(res, qb)
+		}
+		for _, f := range n.fragments() {
+			res = append(res, f)
+		}
+	case *VarBlock:
+		for _, a := range n.Attributes {
+			res = append(res, a)
+		}
+	case *ShortestPath:
+		for _, a := range n.Attributes {
+			res = append(res, a)
+		}
+	case *QueryBlock:
+		for _, a := range n.Attributes {
+			res = append(res, a)
+		}
+	case *Fragment:
+		for _, a := range n.Attributes {
+			res = append(res, a)
+		}
+	case *Attribute:
+		for _, a := range n.Attributes {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// Rewrite transforms a copy of a query by applying fn to every node below it.
+//
+// Nodes are rewritten bottom-up: the children of a node are rewritten before the node
+// itself is passed to fn. The value returned by fn replaces the node; returning nil removes
+// it from its parent. The replacement must have the same type as the original node, e.g. an
+// Attribute can only be replaced by another Attribute.
+//
+// fn receives the nodes of a copy of q, see Clone, so it may modify them in place: q is left
+// untouched, also when the rewrite fails partway, and may be frozen.
+//
+// Parameters:
+//   - q: The query to rewrite.
+//   - fn: The function applied to every node of the copy of the query.
+//
+// Returns:
+//   - The rewritten copy of the query.
+//   - An error if fn returned a node of the wrong type.
+//
+// Example:
+//
+//	// Add a tenant filter to every query block and drop debug attributes.
+//	query, err := Rewrite(query, func(n Node) Node {
+//	    switch n := n.(type) {
+//	    case *QueryBlock:
+//	        return n.WithDirectives("@filter(eq(tenant, \"acme\"))")
+//	    case *Attribute:
+//	        if n.Name == "debug_info" {
+//	            return nil
+//	        }
+//	    }
+//	    return n
+//	})
+func Rewrite(q *Query, fn func(n Node) Node) (*Query, error) {
+	res := q.Clone()
+	if err := rewriteChildren(res, fn); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// rewriteChildren rewrites the direct children of a node, recursing into each of them first.
+func rewriteChildren(n Node, fn func(n Node) Node) error {
+	var err error
+	switch n := n.(type) {
+	case *Query:
+		if n.Params, err = rewriteList(n.Params, fn); err != nil {
+			return err
+		}
+		if n.VarBlocks, err = rewriteList(n.VarBlocks, fn); err != nil {
+			return err
+		}
+		if n.ShortestPaths, err = rewriteList(n.ShortestPaths, fn); err != nil {
+			return err
+		}
+		if n.QueryBlocks, err = rewriteList(n.QueryBlocks, fn); err != nil {
+			return err
+		}
+		n.Fragments, err = rewriteList(n.Fragments, fn)
+	case *VarBlock:
+		n.Attributes, err = rewriteList(n.Attributes, fn)
+	case *ShortestPath:
+		n.Attributes, err = rewriteList(n.Attributes, fn)
+	case *QueryBlock:
+		n.Attributes, err = rewriteList(n.Attributes, fn)
+	case *Fragment:
+		n.Attributes, err = rewriteList(n.Attributes, fn)
+	case *Attribute:
+		n.Attributes, err = rewriteList(n.Attributes, fn)
+	}
+	return err
+}
+
+// rewriteList rewrites every node of a list, dropping the nodes for which fn returns nil.
+func rewriteList[T Node](list []T, fn func(n Node) Node) ([]T, error) {
+	if list == nil {
+		return nil, nil
+	}
+	var zero T
+	res := make([]T, 0, len(list))
+	for _, item := range list {
+		if err := rewriteChildren(item, fn); err != nil {
+			return nil, err
+		}
+		out := fn(item)
+		if out == nil {
+			continue
+		}
+		t, ok := out.(T)
+		if !ok {
+			return nil, fmt.Errorf("dql: rewrite replaced %T with %T", item, out)
+		}
+		if any(t) == any(zero) {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
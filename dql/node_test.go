@@ -0,0 +1,133 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+)
+
+// newUserQuery returns a query with every kind of node.
+func newUserQuery() *Query {
+	return NewQuery("Q", NewQueryBlock("me", "has(user)").WithAttributes(
+		NewAttribute("fullname"), NewAttribute("debug_info"),
+		NewAttribute("friend").WithAttributes(NewAttribute("fullname")))).
+		WithParam(NewParam("$name", "string")).
+		WithVarBlocks(NewVarBlock("has(friend)").WithName("f").WithAttributes(NewAttribute("uid"))).
+		WithFragments(NewFragment("userFields").WithAttributes(NewAttribute("email")))
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		skip string
+		want string
+	}{
+		{"all", "", "Query Param VarBlock uid QueryBlock fullname debug_info friend fullname Fragment email"},
+		{"skip children", "friend", "Query Param VarBlock uid QueryBlock fullname debug_info friend Fragment email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var visited []string
+			Walk(newUserQuery(), func(n Node) bool {
+				switch n := n.(type) {
+				case *Query:
+					visited = append(visited, "Query")
+				case *Param:
+					visited = append(visited, "Param")
+				case *VarBlock:
+					visited = append(visited, "VarBlock")
+				case *QueryBlock:
+					visited = append(visited, "QueryBlock")
+				case *Fragment:
+					visited = append(visited, "Fragment")
+				case *Attribute:
+					visited = append(visited, n.Name)
+					return n.Name != tt.skip
+				}
+				return true
+			})
+			if got := strings.Join(visited, " "); got != tt.want {
+				t.Errorf("visited %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(n Node) Node
+		want string
+	}{
+		{"rename predicate", func(n Node) Node {
+			if a, ok := n.(*Attribute); ok && a.Name == "fullname" {
+				a.Name = "name"
+			}
+			return n
+		}, "me (func: has(user)) { name debug_info friend { name } }"},
+		{"remove attribute", func(n Node) Node {
+			if a, ok := n.(*Attribute); ok && a.Name == "debug_info" {
+				return nil
+			}
+			return n
+		}, "me (func: has(user)) { fullname friend { fullname } }"},
+		{"bottom-up", func(n Node) Node {
+			if a, ok := n.(*Attribute); ok && len(a.Attributes) == 0 && a.Name == "fullname" {
+				return nil
+			}
+			if a, ok := n.(*Attribute); ok && a.Name == "friend" && len(a.Attributes) == 0 {
+				return nil
+			}
+			return n
+		}, "me (func: has(user)) { debug_info }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rewrite(newUserQuery(), tt.fn)
+			if err != nil {
+				t.Fatalf("Rewrite() error = %v", err)
+			}
+			if got := got.QueryBlocks[0].String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteWrongType(t *testing.T) {
+	q := newUserQuery()
+	want := q.String()
+	_, err := Rewrite(q, func(n Node) Node {
+		if a, ok := n.(*Attribute); ok {
+			if a.Name != "friend" {
+				a.Name = "renamed"
+				return a
+			}
+			return NewFragment("x")
+		}
+		return n
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "dql: rewrite replaced *dql.Attribute") {
+		t.Errorf("Rewrite() error = %v, want a type error", err)
+	}
+	if got := q.String(); got != want {
+		t.Errorf("query after a failed rewrite = %s, want %s", got, want)
+	}
+}
+
+// TestRewriteCopy checks that Rewrite leaves the query untouched, also when it is frozen.
+func TestRewriteCopy(t *testing.T) {
+	q := newUserQuery().Freeze()
+	want := q.String()
+	got, err := Rewrite(q, func(n Node) Node {
+		if a, ok := n.(*Attribute); ok && a.Name == "debug_info" {
+			return nil
+		}
+		return n
+	})
+	if err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+	if q.String() != want || got.String() == want || got.Frozen() {
+		t.Errorf("Rewrite() = %s of %s, want a modifiable rewritten copy", got, q)
+	}
+}
@@ -0,0 +1,193 @@
+package dql
+
+import "slices"
+
+// BlockOption configures a QueryBlock or a VarBlock created with NewQueryBlockOpt or
+// NewVarBlockOpt.
+//
+// Options are an alternative to method chaining for callers that assemble blocks from
+// dynamic lists, e.g. adding pagination only when it was requested.
+type BlockOption func(b blockParts)
+
+// blockParts gives options access to the parts shared by query and variable blocks.
+type blockParts struct {
+	criteria   *[]Criteria
+	directives *[]Criteria
+	attributes *[]*Attribute
+}
+
+// NewQueryBlockOpt creates a new QueryBlock configured by options.
+//
+// Parameters:
+//   - name: The name of the query block.
+//   - opts: The options applied to the query block, in order.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlockOpt("me",
+//	    Func(Has("user")),
+//	    First(10),
+//	    Filter(Has("email")),
+//	    Attributes(NewAttribute("name")),
+//	)
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user), first: 10) @filter(has(email)) { name }
+func NewQueryBlockOpt(name string, opts ...BlockOption) *QueryBlock {
+	qb := &QueryBlock{Name: name}
+	parts := blockParts{criteria: &qb.Criteria, directives: &qb.Directives, attributes: &qb.Attributes}
+	for _, opt := range opts {
+		opt(parts)
+	}
+	return qb
+}
+
+// NewVarBlockOpt creates a new VarBlock configured by options.
+//
+// Parameters:
+//   - name: The name of the variable block, or an empty string for an unnamed block.
+//   - opts: The options applied to the variable block, in order.
+//
+// Returns:
+//   - A pointer to a VarBlock object.
+//
+// Example:
+//
+//	varBlock := NewVarBlockOpt("users", Func(Has("user")), First(10))
+//	fmt.Println(varBlock.String()) // Output: users AS var (func: has(user), first: 10) { }
+func NewVarBlockOpt(name string, opts ...BlockOption) *VarBlock {
+	vb := &VarBlock{Name: name}
+	parts := blockParts{criteria: &vb.Criteria, directives: &vb.Directives, attributes: &vb.Attributes}
+	for _, opt := range opts {
+		opt(parts)
+	}
+	return vb
+}
+
+// Func sets the root function of the block, replacing any previous one. The root function
+// is placed first, before the arguments added by earlier options such as First.
+//
+// Parameters:
+//   - criteria: The root function, either a Criteria such as Has("user") or a string.
+//
+// Returns:
+//   - A BlockOption.
+func Func(criteria any) BlockOption {
+	return func(b blockParts) {
+		if len(*b.criteria) != 0 && isRootFunction((*b.criteria)[0]) {
+			(*b.criteria)[0] = toCriteria(criteria)
+			return
+		}
+		*b.criteria = slices.Insert(*b.criteria, 0, toCriteria(criteria))
+	}
+}
+
+// Args adds criteria following the root function of the block.
+//
+// Parameters:
+//   - criteria: One or more criteria, either Criteria or strings.
+//
+// Returns:
+//   - A BlockOption.
+func Args(criteria ...any) BlockOption {
+	return func(b blockParts) {
+		for _, c := range criteria {
+			*b.criteria = append(*b.criteria, toCriteria(c))
+		}
+	}
+}
+
+// First limits the block to the first n results.
+//
+// Parameters:
+//   - n: The number of results, an int or a ParamRef bound when the query is executed.
+//
+// Returns:
+//   - A BlockOption.
+func First(n any) BlockOption {
+	return Args(NewArg("first", n))
+}
+
+// Offset skips the first n results of the block.
+//
+// Parameters:
+//   - n: The number of results to skip, an int or a ParamRef.
+//
+// Returns:
+//   - A BlockOption.
+func Offset(n any) BlockOption {
+	return Args(NewArg("offset", n))
+}
+
+// After starts the results of the block after the given uid.
+//
+// Parameters:
+//   - uid: The uid after which results start, a string, a UID or a ParamRef.
+//
+// Returns:
+//   - A BlockOption.
+func After(uid any) BlockOption {
+	return Args(NewArg("after", uid))
+}
+
+// OrderAsc orders the results of the block by a predicate in ascending order.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - A BlockOption.
+func OrderAsc(predicate string) BlockOption {
+	return Args(NewArg("orderasc", predicate))
+}
+
+// OrderDesc orders the results of the block by a predicate in descending order.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - A BlockOption.
+func OrderDesc(predicate string) BlockOption {
+	return Args(NewArg("orderdesc", predicate))
+}
+
+// Filter adds an @filter directive to the block.
+//
+// Parameters:
+//   - criteria: The filter expression, either a Criteria such as Has("email") or a string.
+//
+// Returns:
+//   - A BlockOption.
+func Filter(criteria any) BlockOption {
+	return Directives(NewDirective("filter", criteria))
+}
+
+// Directives adds one or more directives to the block.
+//
+// Parameters:
+//   - directives: One or more directives, either Directive objects or strings.
+//
+// Returns:
+//   - A BlockOption.
+func Directives(directives ...any) BlockOption {
+	return func(b blockParts) {
+		for _, d := range directives {
+			*b.directives = append(*b.directives, toCriteria(d))
+		}
+	}
+}
+
+// Attributes adds one or more attributes to the block.
+//
+// Parameters:
+//   - attrs: One or more Attribute objects.
+//
+// Returns:
+//   - A BlockOption.
+func Attributes(attrs ...*Attribute) BlockOption {
+	return func(b blockParts) {
+		*b.attributes = append(*b.attributes, attrs...)
+	}
+}
@@ -0,0 +1,27 @@
+package dql
+
+import "testing"
+
+func TestBlockOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		b    interface{ String() string }
+		want string
+	}{
+		{"func first", NewQueryBlockOpt("me", Func(Has("user")), First(10)), "me (func: has(user), first: 10) { }"},
+		{"first func", NewQueryBlockOpt("me", First(10), Offset(5), Func(Has("user"))), "me (func: has(user), first: 10, offset: 5) { }"},
+		{"func replaced after first", NewQueryBlockOpt("me", Func(Has("user")), First(10), Func(Type("Person"))), "me (func: type(Person), first: 10) { }"},
+		{"func replaced", NewQueryBlockOpt("me", Func(Has("a")), Offset(5), Func(Has("b"))), "me (func: has(b), offset: 5) { }"},
+		{"order", NewQueryBlockOpt("me", Func(Has("user")), OrderAsc("name"), OrderDesc("age")), "me (func: has(user), orderasc: name, orderdesc: age) { }"},
+		{"after", NewQueryBlockOpt("me", Func(Has("user")), First(2), After("0x10")), "me (func: has(user), first: 2, after: 0x10) { }"},
+		{"filter", NewQueryBlockOpt("me", Func(Has("user")), Filter(Has("email")), Attributes(NewAttribute("name"))), "me (func: has(user)) @filter(has(email)) { name }"},
+		{"var block", NewVarBlockOpt("users", Func(Has("user")), First(10), Directives("@cascade")), "users AS var (func: has(user), first: 10) @cascade { }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package dql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// paginationArgs lists the block arguments that restrict or order the results of a block
+// without changing the set of nodes it matches.
+var paginationArgs = map[string]bool{
+	"first":     true,
+	"offset":    true,
+	"after":     true,
+	"orderasc":  true,
+	"orderdesc": true,
+}
+
+// Page is a page of results along with the total number of results across all pages.
+type Page[T any] struct {
+	// Items is the list of results of the page.
+	Items []T
+
+	// Total is the total number of results matched by the query.
+	Total int
+}
+
+// TotalBlockName returns the name of the block counting the results of a paginated block,
+// as generated by NewTotalCountBlock.
+//
+// Parameters:
+//   - name: The name of the paginated block.
+//
+// Returns:
+//   - The name of the count block.
+func TotalBlockName(name string) string {
+	return name + "_total"
+}
+
+// NewTotalCountBlock creates a block counting all the nodes matched by a paginated block.
+//
+// The count block has the same root function and @filter directives as qb, but none of its
+// pagination and ordering arguments, so it counts the nodes across all pages. Other
+// directives are not copied: @cascade depends on the attributes of qb, which the count block
+// does not select, so the total of a cascaded block counts the nodes before cascading. The
+// count block is named after qb with TotalBlockName and returns the count under the total key.
+//
+// Parameters:
+//   - qb: The paginated query block.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//
+// Example:
+//
+//	users := NewQueryBlock("users", Has("user")).WithFirst(10).WithOffset(20)
+//	total := NewTotalCountBlock(users)
+//	fmt.Println(total.String()) // Output: users_total (func: has(user)) { total : count(uid) }
+func NewTotalCountBlock(qb *QueryBlock) *QueryBlock {
+	res := &QueryBlock{Name: TotalBlockName(qb.Name)}
+	for _, c := range qb.Criteria {
+		if !paginationArgs[argName(c)] {
+			res.Criteria = append(res.Criteria, c)
+		}
+	}
+	for _, d := range qb.Directives {
+		if directiveName(d) == "filter" {
+			res.Directives = append(res.Directives, d)
+		}
+	}
+	return res.WithAttributes(NewAttribute("count(uid)").WithAlias("total"))
+}
+
+// NewPageQuery creates a query returning a page of results of a block along with the total
+// number of results, see NewTotalCountBlock. Use DecodePage to decode its response.
+//
+// Parameters:
+//   - qb: The paginated query block.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	users := NewQueryBlock("users", Has("user")).
+//	    WithFirst(10).
+//	    WithAttributes(NewAttribute("name"))
+//	query := NewPageQuery(users)
+//	fmt.Println(query.String())
+//	// Output: { users (func: has(user), first: 10) { name } users_total (func: has(user)) { total : count(uid) } }
+func NewPageQuery(qb *QueryBlock) *Query {
+	return NewQuery("", qb).WithQueryBlocks(NewTotalCountBlock(qb))
+}
+
+// DecodePage decodes the response of a query created by NewPageQuery.
+//
+// Parameters:
+//   - data: The JSON data of the response, i.e. the object holding the results of each block.
+//   - name: The name of the paginated block.
+//
+// Returns:
+//   - A pointer to a Page holding the decoded items and the total count.
+//   - An error if the data cannot be decoded.
+//
+// Example:
+//
+//	page, err := DecodePage[User](resp.Json, "users")
+//	fmt.Println(len(page.Items), page.Total)
+func DecodePage[T any](data []byte, name string) (*Page[T], error) {
+	var blocks map[string]json.RawMessage
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("dql: decode page %q: %w", name, err)
+	}
+	page := &Page[T]{Items: []T{}}
+	if raw, ok := blocks[name]; ok {
+		if err := json.Unmarshal(raw, &page.Items); err != nil {
+			return nil, fmt.Errorf("dql: decode page %q: %w", name, err)
+		}
+	}
+	var totals []struct {
+		Total int `json:"total"`
+	}
+	if raw, ok := blocks[TotalBlockName(name)]; ok {
+		if err := json.Unmarshal(raw, &totals); err != nil {
+			return nil, fmt.Errorf("dql: decode page %q: %w", name, err)
+		}
+	}
+	if len(totals) != 0 {
+		page.Total = totals[0].Total
+	}
+	return page, nil
+}
+
+// argName returns the name of a block argument, whether structured or written as a string
+// such as "first: 10", or an empty string if criteria is not a named argument.
+func argName(c Criteria) string {
+	if arg, ok := c.(*Arg); ok {
+		return arg.Name
+	}
+	if s := c.String(); namedArgPattern.MatchString(s) {
+		name, _, _ := strings.Cut(s, ":")
+		return strings.TrimSpace(name)
+	}
+	return ""
+}
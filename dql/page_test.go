@@ -0,0 +1,70 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPageQuery(t *testing.T) {
+	qb := NewQueryBlock("movies", AnyOfTerms("name", "star wars")).
+		WithFirst(10).WithOffset(20).WithOrderAsc("name").
+		WithDirectives(NewDirective("filter", Has("director"))).
+		WithAttributes(NewAttribute("name"))
+	want := `{ movies (func: anyofterms(name, "star wars"), first: 10, offset: 20, orderasc: name) @filter(has(director)) { name } ` +
+		`movies_total (func: anyofterms(name, "star wars")) @filter(has(director)) { total : count(uid) } }`
+	if got := NewPageQuery(qb).String(); got != want {
+		t.Errorf("NewPageQuery() = %s, want %s", got, want)
+	}
+}
+
+func TestNewTotalCountBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		qb   *QueryBlock
+		want string
+	}{
+		{"filter", NewQueryBlock("users", Has("user")).WithFirst(10).WithDirectives(NewDirective("filter", Has("email"))),
+			"users_total (func: has(user)) @filter(has(email)) { total : count(uid) }"},
+		{"raw filter", NewQueryBlock("users", Has("user")).WithDirectives("@filter(has(email))"),
+			"users_total (func: has(user)) @filter(has(email)) { total : count(uid) }"},
+		{"other directives", NewQueryBlock("users", Has("user")).WithFirst(10).
+			WithDirectives("@normalize", NewDirective("filter", Has("email"))).WithCascade("name").
+			WithAttributes(NewAttribute("name")),
+			"users_total (func: has(user)) @filter(has(email)) { total : count(uid) }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTotalCountBlock(tt.qb).String(); got != tt.want {
+				t.Errorf("NewTotalCountBlock() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePage(t *testing.T) {
+	type movie struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name    string
+		data    string
+		want    *Page[movie]
+		wantErr bool
+	}{
+		{"page", `{"movies": [{"name": "A New Hope"}, {"name": "The Empire Strikes Back"}], "movies_total": [{"total": 9}]}`,
+			&Page[movie]{Items: []movie{{"A New Hope"}, {"The Empire Strikes Back"}}, Total: 9}, false},
+		{"empty", `{"movies_total": [{"total": 0}]}`, &Page[movie]{Items: []movie{}}, false},
+		{"malformed", `{"movies": {}}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePage[movie]([]byte(tt.data), "movies")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodePage() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodePage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,34 +1,58 @@
 package dql
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParamType is the type of a query parameter.
+type ParamType string
+
+const (
+	// ParamInt is the type of integer parameters.
+	ParamInt ParamType = "int"
+
+	// ParamFloat is the type of floating-point parameters.
+	ParamFloat ParamType = "float"
+
+	// ParamBool is the type of boolean parameters.
+	ParamBool ParamType = "bool"
+
+	// ParamString is the type of string parameters.
+	ParamString ParamType = "string"
+)
 
 // Param represents a parameter for a DQL query.
 type Param struct {
-	// Name is the name of the parameter.
+	// Name is the name of the parameter, with or without the leading $.
 	Name string
 
 	// Type is the type of the parameter.
-	Type string
+	Type ParamType
 
 	// Default is the default value of the parameter (optional).
 	Default string
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewParam creates a new parameter for a DQL query.
 //
 // Parameters:
 //   - n: The name of the parameter.
-//   - t: The type of the parameter.
+//   - t: The type of the parameter, one of ParamInt, ParamFloat, ParamBool or ParamString.
 //
 // Returns:
 //   - A pointer to a Param object.
 //
 // Example:
 //   param := NewParam("id", "string")
-//   fmt.Println(param.String()) // Output: id: string
+//   fmt.Println(param.String()) // Output: $id: string
 //
 // See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#query-parameterization
-func NewParam(n string, t string) *Param {
+func NewParam(n string, t ParamType) *Param {
 	return &Param{
 		Name: n,
 		Type: t,
@@ -37,6 +61,8 @@ func NewParam(n string, t string) *Param {
 
 // WithDefault sets the default value for the parameter.
 //
+// The value is given unquoted: defaults of string parameters are quoted when rendered.
+//
 // Parameters:
 //   - val: The default value to set.
 //
@@ -45,12 +71,26 @@ func NewParam(n string, t string) *Param {
 //
 // Example:
 //   param := NewParam("id", "string").WithDefault("123")
-//   fmt.Println(param.String()) // Output: id: string = 123
+//   fmt.Println(param.String()) // Output: $id: string = "123"
 func (p *Param) WithDefault(val string) *Param {
+	mustBeMutable(p.frozen, "param", p.Name)
 	p.Default = val
 	return p
 }
 
+// Ref creates a reference to the parameter, usable as a function argument.
+//
+// Returns:
+//   - A ParamRef to the parameter.
+//
+// Example:
+//   name := NewParam("name", "string")
+//   query := NewQuery("GetUser", NewQueryBlock("me", Eq("name", name.Ref()))).WithParam(name)
+//   fmt.Println(query.String()) // Output: query GetUser ( $name: string ) { me (func: eq(name, $name)) { } }
+func (p *Param) Ref() ParamRef {
+	return ParamRef(p.Name)
+}
+
 // String generates a string representation of the parameter.
 //
 // The string includes the parameter's name, type, and default value (if set).
@@ -58,9 +98,67 @@ func (p *Param) WithDefault(val string) *Param {
 // Returns:
 //   - A string representation of the parameter.
 func (p *Param) String() string {
-	res := fmt.Sprintf("%s: %s", p.Name, p.Type)
+	res := fmt.Sprintf("%s: %s", p.Ref(), p.Type)
 	if p.Default != "" {
-		res += fmt.Sprintf(" = %s", p.Default)
+		def := p.Default
+		if p.Type == ParamString {
+			def = Quote(def)
+		}
+		res += fmt.Sprintf(" = %s", def)
 	}
 	return res
 }
+
+// Validate checks the type of the parameter and that its default value matches it.
+//
+// Returns:
+//   - An error describing the problem found, or nil if the parameter is valid.
+//
+// Example:
+//   param := NewParam("age", "int").WithDefault("abc")
+//   fmt.Println(param.Validate()) // Output: dql: param "age": invalid int default "abc"
+func (p *Param) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	switch p.Type {
+	case ParamString, ParamInt, ParamFloat, ParamBool:
+	default:
+		return fmt.Errorf("dql: param %q: unknown type %q", p.Name, p.Type)
+	}
+	if p.Default != "" && !p.accepts(p.Default) {
+		return fmt.Errorf("dql: param %q: invalid %s default %q", p.Name, p.Type, p.Default)
+	}
+	return nil
+}
+
+// accepts reports whether a value, given as the string of a variable or a default, suits the
+// type of the parameter.
+func (p *Param) accepts(v string) bool {
+	var err error
+	switch p.Type {
+	case ParamInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case ParamFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case ParamBool:
+		_, err = strconv.ParseBool(v)
+	}
+	return err == nil
+}
+
+// ParamRef is a reference to a query parameter, such as the $name of eq(name, $name).
+//
+// A ParamRef can be used wherever function arguments and criteria are accepted. It is
+// usually created with Param.Ref, which keeps the declaration of the parameter and its uses
+// linked: Query.Validate reports references to parameters the query does not declare.
+//
+// Example:
+//   fmt.Println(Eq("name", ParamRef("name")).String()) // Output: eq(name, $name)
+type ParamRef string
+
+// String generates the $name reference to the parameter.
+//
+// Returns:
+//   - A string representation of the reference.
+func (r ParamRef) String() string {
+	return "$" + strings.TrimPrefix(string(r), "$")
+}
@@ -0,0 +1,78 @@
+package dql
+
+import "testing"
+
+func TestParamString(t *testing.T) {
+	tests := []struct {
+		p    *Param
+		want string
+	}{
+		{NewParam("$name", ParamString), "$name: string"},
+		{NewParam("$name", ParamString).WithDefault(`Al"ice`), `$name: string = "Al\"ice"`},
+		{NewParam("$first", ParamInt).WithDefault("10"), "$first: int = 10"},
+		{NewParam("$score", ParamFloat).WithDefault("0.5"), "$score: float = 0.5"},
+		{NewParam("$active", ParamBool).WithDefault("true"), "$active: bool = true"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestParamValidate(t *testing.T) {
+	tests := []struct {
+		p       *Param
+		wantErr string
+	}{
+		{NewParam("$name", ParamString).WithDefault("anything"), ""},
+		{NewParam("$first", ParamInt).WithDefault("10"), ""},
+		{NewParam("$first", ParamInt), ""},
+		{NewParam("$first", ParamInt).WithDefault("ten"), `dql: param "$first": invalid int default "ten"`},
+		{NewParam("$score", ParamFloat).WithDefault("1,5"), `dql: param "$score": invalid float default "1,5"`},
+		{NewParam("$active", ParamBool).WithDefault("yes"), `dql: param "$active": invalid bool default "yes"`},
+		{NewParam("$at", "datetime"), `dql: param "$at": unknown type "datetime"`},
+	}
+	for _, tt := range tests {
+		err := NewQuery("", NewQueryBlock("me", Has("user"))).WithParam(tt.p).Validate()
+		if got := errString(err); got != tt.wantErr {
+			t.Errorf("Validate() of %s error = %q, want %q", tt.p, got, tt.wantErr)
+		}
+	}
+}
+
+// errString returns the message of an error, or an empty string if it is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestParamRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		want    string
+		wantErr string
+	}{
+		{"declared", NewQuery("Q", NewQueryBlock("me", Eq("name", NewParam("name", ParamString).Ref()))).
+			WithParam(NewParam("name", ParamString)),
+			"query Q ( $name: string ) { me (func: eq(name, $name)) { } }", ""},
+		{"dollar prefix", NewQuery("Q", NewQueryBlock("me", Eq("name", ParamRef("$name")))).
+			WithParam(NewParam("$name", ParamString)),
+			"query Q ( $name: string ) { me (func: eq(name, $name)) { } }", ""},
+		{"undeclared", NewQuery("Q", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Gt("age", ParamRef("age"))))),
+			"query Q { me (func: has(user)) @filter(gt(age, $age)) { } }", "dql: undeclared param $age"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
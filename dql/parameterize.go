@@ -0,0 +1,250 @@
+package dql
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithStrict enables the strict mode of the query.
+//
+// In strict mode, Validate rejects literal values used in criteria and directives, such as
+// the "Alice" of Eq("name", "Alice"), forcing all values to be passed through parameters.
+// Raw criteria, directives, blocks and attributes, including those of parsed queries, are
+// scanned for string, regular expression and number literals, and so are the endpoints of
+// shortest path blocks.
+// Parameterize can be used to lift the literals of a query into parameters automatically.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	query := NewQuery("Q", NewQueryBlock("me", Eq("name", "Alice"))).WithStrict()
+//	fmt.Println(query.Validate()) // Output: dql: query block "me": literal "Alice" in strict mode
+func (q *Query) WithStrict() *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	q.Strict = true
+	return q
+}
+
+// Parameterize lifts the literal values of the query into parameters.
+//
+// Each literal value is replaced by a reference to a new parameter, whose type is derived
+// from the Go type of the value, and identical values share the same parameter. The query
+// is left untouched; a parameterized copy is returned along with the variables to send with
+// it. Since parameters require a named query, the copy is named "q" if the query has no name.
+//
+// Returns:
+//   - A pointer to the parameterized copy of the query.
+//   - The values of the new parameters, indexed by parameter name.
+//
+// Example:
+//
+//	query := NewQuery("Q", NewQueryBlock("me", Eq("name", "Alice")))
+//	parameterized, vars := query.Parameterize()
+//	fmt.Println(parameterized.String()) // Output: query Q ( $v0: string ) { me (func: eq(name, $v0)) { } }
+//	fmt.Println(vars)                   // Output: map[$v0:Alice]
+func (q *Query) Parameterize() (*Query, map[string]string) {
+	res := q.Clone()
+	if res.Name == "" {
+		res.Name = "q"
+	}
+
+	declared := map[string]bool{}
+	for _, p := range res.Params {
+		declared[p.Ref().String()] = true
+	}
+	vars := map[string]string{}
+	refs := map[string]ParamRef{}
+	next := 0
+	lift := func(c Criteria) Criteria {
+		l, ok := c.(Literal)
+		if !ok {
+			return c
+		}
+		key := l.String()
+		if ref, ok := refs[key]; ok {
+			return ref
+		}
+		name := ""
+		for name == "" || declared[name] {
+			name = fmt.Sprintf("$v%d", next)
+			next++
+		}
+		declared[name] = true
+		typ, val := literalVar(l.Value)
+		param := NewParam(name, typ)
+		res.Params = append(res.Params, param)
+		vars[name] = val
+		refs[key] = param.Ref()
+		return refs[key]
+	}
+	for _, list := range criteriaLists(res) {
+		mapCriteriaList(list, lift)
+	}
+	return res, vars
+}
+
+// literalVar returns the parameter type and the variable value of a literal value.
+func literalVar(v any) (ParamType, string) {
+	switch v := v.(type) {
+	case string:
+		return ParamString, v
+	case bool:
+		return ParamBool, strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ParamInt, fmt.Sprint(v)
+	case float32, float64, *big.Float, decimal:
+		return ParamFloat, SafeValue(v)
+	case *big.Int:
+		return ParamInt, v.String()
+	case time.Time:
+		return ParamString, v.Format(time.RFC3339Nano)
+	case regex:
+		return ParamString, v.String()
+	default:
+		return ParamString, fmt.Sprint(v)
+	}
+}
+
+// criteriaLists returns every list of criteria and directives found under a node, including
+// the directives of the attributes.
+func criteriaLists(n Node) [][]Criteria {
+	lists := [][]Criteria{}
+	Walk(n, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			lists = append(lists, n.Criteria, n.Directives)
+		case *QueryBlock:
+			lists = append(lists, n.Criteria, n.Directives)
+		case *Attribute:
+			lists = append(lists, n.Args, n.Directives)
+		}
+		return true
+	})
+	return lists
+}
+
+// findLiteral returns the first literal value found in a list of criteria.
+func findLiteral(list []Criteria) (Literal, bool) {
+	var res Literal
+	found := false
+	for _, c := range list {
+		walkCriteria(c, func(c Criteria) {
+			if l, ok := c.(Literal); ok && !found {
+				res, found = l, true
+			}
+		})
+	}
+	return res, found
+}
+
+// checkStrict reports the first literal value found under a node: in its criteria and
+// directives, and in the text of raw criteria, directive arguments, blocks and attributes.
+// The raw arguments of typed functions, which the builders produce, are not scanned.
+func checkStrict(n Node) error {
+	var err error
+	Walk(n, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Raw {
+				err = checkRawLiteral(n.Name)
+			}
+		case *QueryBlock:
+			if n.Raw {
+				err = checkRawLiteral(n.Name)
+			}
+		case *Attribute:
+			if n.Raw {
+				err = checkRawLiteral(n.Name)
+			}
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, list := range criteriaLists(n) {
+		if l, ok := findLiteral(list); ok {
+			return fmt.Errorf("literal %s in strict mode", l.String())
+		}
+		for _, c := range list {
+			if r, ok := c.(Raw); ok {
+				err = checkRawLiteral(string(r))
+			}
+			walkCriteria(c, func(c Criteria) {
+				if d, ok := c.(*Directive); ok {
+					for _, arg := range d.Args {
+						if r, ok := arg.(Raw); ok && err == nil {
+							err = checkRawLiteral(string(r))
+						}
+					}
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRawLiteral reports the first literal value found in raw DQL, see rawLiteral.
+func checkRawLiteral(text string) error {
+	if l, ok := rawLiteral(text); ok {
+		return fmt.Errorf("literal %s in strict mode", l)
+	}
+	return nil
+}
+
+// rawLiteral returns the first literal value of raw DQL, see scanRawLiterals.
+func rawLiteral(text string) (string, bool) {
+	res, found := "", false
+	scanRawLiterals(text, func(start, end int) bool {
+		res, found = text[start:end], true
+		return false
+	})
+	return res, found
+}
+
+// scanRawLiterals calls visit with the bounds of each literal value of raw DQL, a string, a
+// regular expression or a number, until visit returns false. Hexadecimal uids and the values
+// of named arguments, such as the 10 of first: 10, are not considered literals, as with the
+// typed builders.
+func scanRawLiterals(text string, visit func(start, end int) bool) {
+	prev := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"' || c == '/' && (prev == ',' || prev == 0):
+			j := i + 1
+			for j < len(text) && text[j] != c {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			end := min(j+1, len(text))
+			if !visit(i, end) {
+				return
+			}
+			i, c = end-1, text[end-1]
+		case c >= '0' && c <= '9' && (i == 0 || !isWordByte(text[i-1]) && text[i-1] != '$'):
+			j := i
+			for j < len(text) && (isWordByte(text[j]) || text[j] == '.') {
+				j++
+			}
+			token := text[i:j]
+			if prev != ':' && !strings.HasPrefix(token, "0x") && !strings.HasPrefix(token, "0X") && !visit(i, j) {
+				return
+			}
+			i, c = j-1, text[j-1]
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			prev = c
+		}
+	}
+}
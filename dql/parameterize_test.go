@@ -0,0 +1,100 @@
+package dql
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr bool
+	}{
+		{"params", NewQuery("Q", NewQueryBlock("me", Eq("name", Raw("$name"))).WithCriteria("first: 10")).
+			WithParam(NewParam("$name", "string")), false},
+		{"typed literal", NewQuery("", NewQueryBlock("me", Eq("name", "Alice"))), true},
+		{"directive", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Gt("age", 30)))), true},
+		{"attribute filter", NewQuery("", NewQueryBlock("me", Has("user")).
+			WithAttributes(NewAttribute("friend").WithDirectives(NewDirective("filter", Gt("age", 30))))), true},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("f"))).WithVarBlocks(NewVarBlock(Eq("name", "Alice")).WithName("f")), true},
+		{"fragment", NewQuery("", NewQueryBlock("me", Has("user"))).WithFragments(NewFragment("f").WithAttributes(
+			NewAttribute("friend").WithDirectives(NewDirective("filter", AnyOfTerms("name", "Bob"))))), true},
+		{"regexp", NewQuery("", NewQueryBlock("me", Has("name")).WithDirectives(NewDirective("filter", Regexp("name", "^Al", "i")))), true},
+		{"raw string", NewQuery("", NewQueryBlock("me", Raw(`eq(name, "Alice")`))), true},
+		{"raw number", NewQuery("", NewQueryBlock("me", Raw("gt(age, 30)"))), true},
+		{"raw regexp", NewQuery("", NewQueryBlock("me", Raw("regexp(name, /^A/)"))), true},
+		{"raw uid", NewQuery("", NewQueryBlock("me", Raw("uid(0x1)")).WithCriteria("first: 10")), false},
+		{"raw directive", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Raw("lt(age, 18)")))), true},
+		{"shortest path edge filter", NewQuery("", NewQueryBlock("path", Uid("p"))).WithShortestPaths(
+			NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(
+				NewAttribute("friend").WithDirectives(NewDirective("filter", Raw("gt(age, 30)"))))), true},
+		{"shortest path uids", NewQuery("", NewQueryBlock("path", Uid("p"))).WithShortestPaths(
+			NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("friend"))), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.WithStrict().Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "in strict mode") {
+				t.Errorf("Validate() error = %v, want a strict mode error", err)
+			}
+		})
+	}
+}
+
+func TestStrictParsed(t *testing.T) {
+	q, err := Parse(`{ me(func: eq(name, "Alice")) { name } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.WithStrict().Validate(); err == nil || !strings.Contains(err.Error(), `literal "Alice" in strict mode`) {
+		t.Errorf("Validate() error = %v, want the literal of the parsed query", err)
+	}
+}
+
+func TestParameterize(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        *Query
+		want     string
+		wantVars map[string]string
+	}{
+		{"string", NewQuery("Q", NewQueryBlock("me", Eq("name", "Alice"))),
+			"query Q ( $v0: string ) { me (func: eq(name, $v0)) { } }", map[string]string{"$v0": "Alice"}},
+		{"shared value", NewQuery("", NewQueryBlock("me", Eq("name", "Alice")).
+			WithDirectives(NewDirective("filter", Eq("nick", "Alice")))),
+			`query q ( $v0: string ) { me (func: eq(name, $v0)) @filter(eq(nick, $v0)) { } }`, map[string]string{"$v0": "Alice"}},
+		{"int", NewQuery("Q", NewQueryBlock("me", Gt("age", 30))),
+			"query Q ( $v0: int ) { me (func: gt(age, $v0)) { } }", map[string]string{"$v0": "30"}},
+		{"float and bool", NewQuery("Q", NewQueryBlock("me", Lt("score", 1.5)).WithDirectives(NewDirective("filter", Eq("active", true)))),
+			"query Q ( $v0: float, $v1: bool ) { me (func: lt(score, $v0)) @filter(eq(active, $v1)) { } }",
+			map[string]string{"$v0": "1.5", "$v1": "true"}},
+		{"regexp", NewQuery("Q", NewQueryBlock("me", Regexp("name", "^Al/ice", "i"))),
+			"query Q ( $v0: string ) { me (func: regexp(name, $v0)) { } }", map[string]string{"$v0": `/^Al\/ice/i`}},
+		{"declared name", NewQuery("Q", NewQueryBlock("me", Eq("name", "Alice")).WithDirectives(NewDirective("filter", Eq("nick", Raw("$v0"))))).
+			WithParam(NewParam("$v0", "string")),
+			"query Q ( $v0: string, $v1: string ) { me (func: eq(name, $v1)) @filter(eq(nick, $v0)) { } }", map[string]string{"$v1": "Alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.q.String()
+			got, vars := tt.q.Parameterize()
+			if got.String() != tt.want {
+				t.Errorf("Parameterize() = %s, want %s", got, tt.want)
+			}
+			if !maps.Equal(vars, tt.wantVars) {
+				t.Errorf("Parameterize() vars = %v, want %v", vars, tt.wantVars)
+			}
+			if err := got.WithStrict().Validate(); err != nil {
+				t.Errorf("Validate() in strict mode error = %v", err)
+			}
+			if tt.q.String() != want {
+				t.Errorf("original = %s, want %s", tt.q, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,531 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// expressionAttributes lists the functions whose call is part of the name of an attribute,
+// such as count(friend), rather than arguments of the attribute.
+var expressionAttributes = map[string]bool{
+	"avg":      true,
+	"checkpwd": true,
+	"count":    true,
+	"expand":   true,
+	"math":     true,
+	"max":      true,
+	"min":      true,
+	"sum":      true,
+	"val":      true,
+}
+
+// knownDirectives lists the directives of DQL, to tell them apart from language tags
+// written right after a predicate name, such as name@en.
+var knownDirectives = map[string]bool{
+	"cascade":      true,
+	"facets":       true,
+	"filter":       true,
+	"groupby":      true,
+	"ignorereflex": true,
+	"normalize":    true,
+	"recurse":      true,
+}
+
+// paramDeclPattern matches a parameter declaration such as $name: string = "Alice".
+var paramDeclPattern = regexp.MustCompile(`^\$(\w+)\s*:\s*(\w+!?)\s*(?:=\s*(.+))?$`)
+
+// Parse parses a DQL query into a Query.
+//
+// Parse covers the query language as produced by this package: the query header and its
+// parameters, query blocks, variable blocks, shortest path blocks, attributes with aliases,
+// variables, arguments, directives and nested attributes, and fragments. Root functions,
+// filters and other expressions are kept as Raw criteria, while pagination and ordering
+// arguments are parsed into Arg values.
+//
+// Parameters:
+//   - src: The DQL query text.
+//
+// Returns:
+//   - A pointer to the parsed Query object.
+//   - An error giving the line and column of the first syntax error.
+//
+// Example:
+//
+//	query, err := Parse(`{ me(func: has(user), first: 10) @filter(has(email)) { name } }`)
+//	fmt.Println(query.String()) // Output: { me (func: has(user), first: 10) @filter(has(email)) { name } }
+func Parse(src string) (*Query, error) {
+	p := &parser{src: src}
+	q, err := p.query()
+	if err != nil {
+		return nil, wrapError(ErrSyntax, err)
+	}
+	return q, nil
+}
+
+// parser is a recursive descent parser for DQL queries.
+type parser struct {
+	src string
+	pos int
+}
+
+// errorf creates an error located at the current position of the parser.
+func (p *parser) errorf(format string, args ...any) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	col := 1 + utf8.RuneCountInString(p.src[strings.LastIndex(p.src[:p.pos], "\n")+1:p.pos])
+	return fmt.Errorf("dql: parse: line %d, column %d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		switch {
+		case unicode.IsSpace(r):
+			p.pos += size
+		case r == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end of the input.
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// consume skips whitespace and consumes s if the input continues with it.
+func (p *parser) consume(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// expect consumes s, failing if the input does not continue with it.
+func (p *parser) expect(s string) error {
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+// keyword consumes the word kw if it is the next word of the input.
+func (p *parser) keyword(kw string) bool {
+	start := p.pos
+	if w := p.word(); strings.EqualFold(w, kw) {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// word reads a name made of letters, digits, underscores, dots and a leading ~.
+func (p *parser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || (r == '~' && p.pos == start)) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos]
+}
+
+// name reads a predicate or block name, including IRIs in angle brackets and language tags
+// such as name@en.
+func (p *parser) name() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], "~<") {
+		p.pos++
+	}
+	if strings.HasPrefix(p.src[p.pos:], "<") {
+		end := strings.IndexByte(p.src[p.pos:], '>')
+		if end < 0 {
+			return "", p.errorf("unterminated IRI")
+		}
+		p.pos += end + 1
+	} else if p.word() == "" {
+		p.pos = start
+		return "", p.errorf("expected a name")
+	}
+	if p.pos+1 < len(p.src) && p.src[p.pos] == '@' {
+		tag := p.pos + 1
+		for tag < len(p.src) && (isWordByte(p.src[tag]) || strings.IndexByte(":.-*", p.src[tag]) >= 0) {
+			tag++
+		}
+		lang := p.src[p.pos+1 : tag]
+		if lang != "" && !knownDirectives[strings.TrimRight(lang, ":.-*")] {
+			p.pos = tag
+		}
+	}
+	return p.src[start:p.pos], nil
+}
+
+// balanced reads a parenthesized text, returning it without the outer parentheses.
+// Nested parentheses, brackets, string literals and regular expression literals are skipped
+// over.
+func (p *parser) balanced() (string, error) {
+	if err := p.expect("("); err != nil {
+		return "", err
+	}
+	start := p.pos
+	depth := 1
+	prev := byte(0)
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"', '/':
+			if c == '/' && prev != ',' {
+				break
+			}
+			p.pos++
+			for p.pos < len(p.src) && p.src[p.pos] != c {
+				if p.src[p.pos] == '\\' {
+					p.pos++
+				}
+				p.pos++
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth == 0 {
+				text := p.src[start:p.pos]
+				p.pos++
+				return strings.TrimSpace(text), nil
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			prev = c
+		}
+		p.pos++
+	}
+	p.pos = start
+	return "", p.errorf("unbalanced parentheses")
+}
+
+// query parses a whole query: its header, blocks and fragments.
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+	// Unnamed queries with parameters are rendered without the query keyword.
+	if p.keyword("query") || p.peek() == '(' {
+		q.Name = p.word()
+		if p.peek() == '(' {
+			params, err := p.balanced()
+			if err != nil {
+				return nil, err
+			}
+			if q.Params, err = p.params(params); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.consume("}") {
+		if p.peek() == 0 {
+			return nil, p.errorf("unterminated query")
+		}
+		if err := p.block(q); err != nil {
+			return nil, err
+		}
+	}
+	for p.keyword("fragment") {
+		f := NewFragment(p.word())
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		attrs, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		q.Fragments = append(q.Fragments, f.WithAttributes(attrs...))
+	}
+	if p.peek() != 0 {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+	}
+	return q, nil
+}
+
+// params parses the parameter declarations of the query header.
+func (p *parser) params(text string) ([]*Param, error) {
+	res := []*Param{}
+	for _, decl := range splitTopLevel(text) {
+		m := paramDeclPattern.FindStringSubmatch(decl)
+		if m == nil {
+			return nil, p.errorf("invalid param declaration %q", decl)
+		}
+		param := NewParam(m[1], ParamType(strings.TrimSuffix(m[2], "!")))
+		if def := strings.TrimSpace(m[3]); def != "" {
+			if s, err := strconv.Unquote(def); err == nil {
+				def = s
+			}
+			param.WithDefault(def)
+		}
+		res = append(res, param)
+	}
+	return res, nil
+}
+
+// block parses a query, variable or shortest path block and adds it to the query.
+func (p *parser) block(q *Query) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	varName := ""
+	if p.keyword("as") {
+		varName = name
+		if name, err = p.name(); err != nil {
+			return err
+		}
+	}
+	args, err := p.balanced()
+	if err != nil {
+		return err
+	}
+	directives, err := p.directives()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	attrs, err := p.selection()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case name == "var":
+		vb := &VarBlock{Name: varName, Criteria: blockCriteria(args), Directives: directives}
+		q.VarBlocks = append(q.VarBlocks, vb.WithAttributes(attrs...))
+	case name == "shortest":
+		sp, err := p.shortestPath(args)
+		if err != nil {
+			return err
+		}
+		sp.Name = varName
+		q.ShortestPaths = append(q.ShortestPaths, sp.WithAttributes(attrs...))
+	case varName != "":
+		return p.errorf("query block %q cannot be assigned to variable %q", name, varName)
+	default:
+		qb := &QueryBlock{Name: name, Criteria: blockCriteria(args), Directives: directives}
+		q.QueryBlocks = append(q.QueryBlocks, qb.WithAttributes(attrs...))
+	}
+	return nil
+}
+
+// shortestPath parses the arguments of a shortest path block.
+func (p *parser) shortestPath(args string) (*ShortestPath, error) {
+	sp := &ShortestPath{}
+	for _, arg := range splitTopLevel(args) {
+		name, value, ok := strings.Cut(arg, ":")
+		if !ok {
+			return nil, p.errorf("invalid shortest path argument %q", arg)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		var err error
+		switch name {
+		case "from":
+			sp.From = value
+		case "to":
+			sp.To = value
+		case "numpaths":
+			sp.NumPaths, err = strconv.Atoi(value)
+		case "depth":
+			sp.Depth, err = strconv.Atoi(value)
+		case "minweight", "maxweight":
+			var w float64
+			w, err = strconv.ParseFloat(value, 64)
+			if name == "minweight" {
+				sp.MinWeight = &w
+			} else {
+				sp.MaxWeight = &w
+			}
+		default:
+			return nil, p.errorf("unknown shortest path argument %q", name)
+		}
+		if err != nil {
+			return nil, p.errorf("invalid %s %q", name, value)
+		}
+	}
+	return sp, nil
+}
+
+// directives parses the directives following a block or an attribute.
+func (p *parser) directives() ([]Criteria, error) {
+	var res []Criteria
+	for p.peek() == '@' {
+		p.pos++
+		name := p.word()
+		if name == "" {
+			return nil, p.errorf("expected a directive name")
+		}
+		d := NewDirective(name)
+		if p.peek() == '(' {
+			args, err := p.balanced()
+			if err != nil {
+				return nil, err
+			}
+			d.Args = []Criteria{Raw(args)}
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// selection parses attributes up to the closing brace of a selection set.
+func (p *parser) selection() ([]*Attribute, error) {
+	attrs := []*Attribute{}
+	for {
+		for p.consume(",") {
+		}
+		if p.consume("}") {
+			return attrs, nil
+		}
+		if p.peek() == 0 {
+			return nil, p.errorf("unterminated selection set")
+		}
+		attr, err := p.attribute()
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+}
+
+// attribute parses an attribute, including its alias, variable, arguments, directives and
+// nested attributes.
+func (p *parser) attribute() (*Attribute, error) {
+	if p.consume("...") {
+		name := p.word()
+		if name == "" {
+			return nil, p.errorf("expected a fragment name")
+		}
+		return NewAttribute("..." + name), nil
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	attr := &Attribute{}
+	if p.peek() == ':' {
+		p.pos++
+		attr.Alias = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.keyword("as") {
+		attr.Var = name
+		if name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if expressionAttributes[name] && p.peek() == '(' {
+		expr, err := p.balanced()
+		if err != nil {
+			return nil, err
+		}
+		name += "(" + expr + ")"
+	}
+	attr.Name = name
+	if p.peek() == '(' {
+		args, err := p.balanced()
+		if err != nil {
+			return nil, err
+		}
+		attr.Args = blockCriteria(args)
+	}
+	if attr.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if len(attr.Args) == 0 && len(attr.Directives) != 0 && p.peek() == '(' {
+		args, err := p.balanced()
+		if err != nil {
+			return nil, err
+		}
+		attr.Args = blockCriteria(args)
+		attr.ArgsAfterDirectives = true
+	}
+	if p.consume("{") {
+		if attr.Attributes, err = p.selection(); err != nil {
+			return nil, err
+		}
+	}
+	return attr, nil
+}
+
+// blockCriteria converts the arguments of a block or an attribute into criteria.
+func blockCriteria(args string) []Criteria {
+	var res []Criteria
+	for _, arg := range splitTopLevel(args) {
+		if fn, ok := strings.CutPrefix(arg, "func:"); ok {
+			res = append(res, Raw(strings.TrimSpace(fn)))
+			continue
+		}
+		if name := argName(Raw(arg)); paginationArgs[name] {
+			_, value, _ := strings.Cut(arg, ":")
+			res = append(res, NewArg(name, Raw(strings.TrimSpace(value))))
+			continue
+		}
+		res = append(res, Raw(arg))
+	}
+	return res
+}
+
+// splitTopLevel splits a text on the commas that are not nested in parentheses, brackets or
+// string literals.
+func splitTopLevel(text string) []string {
+	res := []string{}
+	depth := 0
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			for i++; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, strings.TrimSpace(text[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		res = append(res, last)
+	}
+	return res
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,128 @@
+package dql
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseRoundTrip checks that queries rendered by the package are parsed back into
+// queries rendering the same text.
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"block", `{ me (func: has(user)) { name } }`},
+		{"pagination", `{ me (func: has(user), first: 10, offset: 20, orderasc: name) { name } }`},
+		{"filter", `{ me (func: eq(name, "Alice")) @filter(has(email) AND NOT eq(age, 30)) { name } }`},
+		{"params", `query Q ( $name: string = "Alice", $first: int ) { me (func: eq(name, $name), first: $first) { uid } }`},
+		{"alias and nested", `{ me (func: uid(0x1)) { n : name friend (first: 5) @filter(has(name)) { name } count(friend) } }`},
+		{"var block", `{ friends AS var (func: has(friend)) { f as friend } me (func: uid(friends)) { name } }`},
+		{"fragment", `{ me (func: has(user)) { ...userFields } } fragment userFields { name email }`},
+		{"regexp", `{ me (func: regexp(name, /^a"(b/i)) { name } }`},
+		{"shortest path", `{ p AS shortest(from: 0x1, to: 0x2) { friend } path (func: uid(p)) { name } }`},
+		{"directives", `{ me (func: has(user)) @recurse(depth: 5) @cascade { name@en friend @facets(since) } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := q.String(); got != tt.src {
+				t.Errorf("String() = %s, want %s", got, tt.src)
+			}
+		})
+	}
+}
+
+// TestParseBuilt checks that queries built with the builders are parsed into equal queries.
+func TestParseBuilt(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+	}{
+		{"functions", NewQuery("", NewQueryBlock("me", Eq("name", `Al"ice`)).
+			WithDirectives(NewDirective("filter", Regexp("bio", "a/b", "i"))).
+			WithAttributes(NewAttribute("name"), NewAttribute("friend").WithFirst(3)))},
+		{"params", NewQuery("Q", NewQueryBlock("me", Eq("name", ParamRef("$name")))).
+			WithParam(NewParam("name", ParamString).WithDefault("Bob"))},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("friends"))).
+			WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.q.String())
+			if err != nil {
+				t.Fatalf("Parse(%s) error = %v", tt.q, err)
+			}
+			if !Equal(parsed, tt.q) {
+				t.Errorf("Parse(%s) = %s, want an equal query", tt.q, parsed)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unbalanced", `{ me (func: has(user) { name } }`},
+		{"unterminated block", `{ me (func: has(user)) { name }`},
+		{"missing brace", `me (func: has(user)) { name }`},
+		{"unterminated string", `{ me (func: eq(name, "Alice)) { name } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Parse() error = %v, want a syntax error", err)
+			}
+		})
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"predicates", "name: string @index(exact, term) @lang .\nfriend: [uid] @reverse @count .", "name: string @index(exact, term) @lang .\nfriend: [uid] @reverse @count ."},
+		{"upsert and noconflict", "email: string @index(exact) @upsert .\nvisits: int @noconflict .", "email: string @index(exact) @upsert .\nvisits: int @noconflict ."},
+		{"type", "name: string .\ntype Person {\n  name\n  friend\n}", "name: string .\ntype Person { name friend }"},
+		{"predicate named type", "type: string @index(exact) .\ntype Item { type }", "type: string @index(exact) .\ntype Item { type }"},
+		{"predicate named type last", "name: string .\ntype : [uid] .", "name: string .\ntype: [uid] ."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchema(tt.src)
+			if err != nil {
+				t.Fatalf("ParseSchema() error = %v", err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"missing type", "name: ."},
+		{"unknown directive", "name: string @unknown ."},
+		{"missing dot", "name: string"},
+		{"type without name", "type { name }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema(tt.src); !errors.Is(err, ErrSyntax) {
+				t.Errorf("ParseSchema() error = %v, want a syntax error", err)
+			}
+		})
+	}
+}
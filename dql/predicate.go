@@ -0,0 +1,110 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// plainPredicatePattern matches predicate names that can be used without escaping,
+	// optionally reversed with ~ and followed by a language tag.
+	plainPredicatePattern = regexp.MustCompile(`^~?[\p{L}\p{N}_][\p{L}\p{N}_.]*(@[\w:.*-]*)?$`)
+
+	// iriPredicatePattern matches predicate names escaped with angle brackets.
+	iriPredicatePattern = regexp.MustCompile("^~?<[^<>\"{}|^`\\\\\\s]+>(@[\\w:.*-]*)?$")
+
+	// predicateExpressionPattern matches the count(predicate), val(variable) and len(variable)
+	// expressions accepted in place of a predicate, capturing their argument.
+	predicateExpressionPattern = regexp.MustCompile(`^(?:count|val|len)\(\s*([^()]*?)\s*\)$`)
+)
+
+// iriForbidden lists the characters that cannot appear between the angle brackets of an IRI,
+// besides whitespace and control characters.
+const iriForbidden = "<>\"{}|^`\\"
+
+// IRI escapes a predicate name with angle brackets, e.g. <http://schema.org/name>.
+//
+// Angle brackets allow predicate names made of characters Dgraph does not accept otherwise,
+// such as the IRIs used by RDF data. The characters an IRI cannot contain, such as > or
+// spaces, are percent-encoded, so the name cannot terminate the IRI and inject DQL.
+//
+// Parameters:
+//   - name: The predicate name to escape.
+//
+// Returns:
+//   - The escaped predicate name.
+//
+// Example:
+//
+//	fmt.Println(IRI("http://schema.org/name")) // Output: <http://schema.org/name>
+//	fmt.Println(IRI("a> b"))                    // Output: <a%3E%20b>
+//
+// See: https://dgraph.io/docs/dql/predicate-types/#predicate-name-rules
+func IRI(name string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c <= ' ' || c == 0x7f || strings.IndexByte(iriForbidden, c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// Predicate returns a predicate name usable in a query, escaping it with angle brackets when
+// it contains characters that require it.
+//
+// Names that are already escaped and the count(predicate), val(variable) and len(variable)
+// expressions are returned as-is. Any other name, including other expressions, is escaped
+// with IRI, so it cannot inject DQL. The typed function builders and the ordering arguments
+// apply Predicate to their predicate arguments.
+//
+// Parameters:
+//   - name: The predicate name.
+//
+// Returns:
+//   - The predicate name, escaped if needed.
+//
+// Example:
+//
+//	fmt.Println(Predicate("name@en"))                // Output: name@en
+//	fmt.Println(Predicate("http://schema.org/name")) // Output: <http://schema.org/name>
+func Predicate(name string) string {
+	if name == "" || isPredicateName(name) {
+		return name
+	}
+	if m := predicateExpressionPattern.FindStringSubmatch(name); m != nil && isPredicateName(m[1]) {
+		return name
+	}
+	return IRI(name)
+}
+
+// isPredicateName reports whether name is a plain or escaped predicate name.
+func isPredicateName(name string) bool {
+	return plainPredicatePattern.MatchString(name) || iriPredicatePattern.MatchString(name)
+}
+
+// ValidatePredicate checks that a predicate name is valid, either as a plain name or escaped
+// with angle brackets.
+//
+// Parameters:
+//   - name: The predicate name.
+//
+// Returns:
+//   - An error if the name is invalid, nil otherwise.
+func ValidatePredicate(name string) error {
+	if plainPredicatePattern.MatchString(name) || iriPredicatePattern.MatchString(name) {
+		return nil
+	}
+	return wrapError(ErrValidation, fmt.Errorf("dql: invalid predicate name %q", name))
+}
+
+// isExpression reports whether an attribute or predicate name is an expression, such as
+// count(friend) or val(a), or a fragment spread, rather than a predicate name.
+func isExpression(name string) bool {
+	return strings.ContainsAny(name, "()") || strings.HasPrefix(name, "...")
+}
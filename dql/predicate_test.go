@@ -0,0 +1,91 @@
+package dql
+
+import "testing"
+
+func TestPredicate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"name", "name"},
+		{"name@en", "name@en"},
+		{"name@en:fr", "name@en:fr"},
+		{"~friend", "~friend"},
+		{"dgraph.type", "dgraph.type"},
+		{"count(friend)", "count(friend)"},
+		{"<http://schema.org/name>", "<http://schema.org/name>"},
+		{"http://schema.org/name", "<http://schema.org/name>"},
+		{"first name", "<first%20name>"},
+		{"a> b", "<a%3E%20b>"},
+		{"val(x)", "val(x)"},
+		{"len(x)", "len(x)"},
+		{"count(name) OR has(password)", "<count(name)%20OR%20has(password)>"},
+		{"uid(x)", "<uid(x)>"},
+	}
+	for _, tt := range tests {
+		if got := Predicate(tt.in); got != tt.want {
+			t.Errorf("Predicate(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePredicate(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"name", true},
+		{"name@en", true},
+		{"~friend", true},
+		{"<http://schema.org/name>", true},
+		{"", false},
+		{"first name", false},
+		{"name) OR has(password", false},
+		{"<a b>", false},
+	}
+	for _, tt := range tests {
+		if err := ValidatePredicate(tt.in); (err == nil) != tt.valid {
+			t.Errorf("ValidatePredicate(%q) error = %v, want valid %v", tt.in, err, tt.valid)
+		}
+	}
+}
+
+func TestFunctionPredicate(t *testing.T) {
+	tests := []struct {
+		fn   Criteria
+		want string
+	}{
+		{Has("http://schema.org/name"), "has(<http://schema.org/name>)"},
+		{Eq("name@en", "Alice"), `eq(name@en, "Alice")`},
+		{Gt("count(friend)", 3), "gt(count(friend), 3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestValidateAttributeName(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("first name")))
+	if got, want := errString(q.Validate()), `dql: query block "me": invalid predicate name "first name"`; got != want {
+		t.Errorf("Validate() error = %q, want %q", got, want)
+	}
+}
+
+func TestOrderPredicate(t *testing.T) {
+	tests := []struct {
+		arg  *Arg
+		want string
+	}{
+		{NewArg("orderasc", "name"), "orderasc: name"},
+		{NewArg("orderdesc", "first name"), "orderdesc: <first%20name>"},
+		{NewArg("orderasc", "name, first: 1000"), "orderasc: <name,%20first:%201000>"},
+		{NewArg("orderasc", Raw("val(x)")), "orderasc: val(x)"},
+	}
+	for _, tt := range tests {
+		if got := tt.arg.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package dql
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// predicateFunctionPattern matches the functions taking a predicate as first argument, along
+// with that argument, e.g. eq(name and has(count(friend).
+var predicateFunctionPattern = regexp.MustCompile(`\b(has|eq|le|lt|ge|gt|between|allofterms|anyofterms|alloftext|anyoftext|regexp|match|uid_in|near|within|contains|intersects|type)\(\s*((?:count|val)\([^)]*\)|[^,()\s]+)`)
+
+// Predicates lists the predicates referenced by a query.
+//
+// Attributes, root functions, filters at every level, ordering arguments, @groupby and
+// @cascade arguments and aggregations such as count(friend) are taken into account.
+// Language tags, reverse markers and IRI brackets are removed, so name@en and ~name both
+// report name. Facet keys are not predicates and are not reported. Functions on types and
+// expand() read the dgraph.type predicate, which is reported; the predicates expand() selects
+// depend on the schema and are not reported. Raw attributes are skipped.
+//
+// The result is useful for access control checks and cache invalidation.
+//
+// Parameters:
+//   - q: The query to inspect.
+//
+// Returns:
+//   - The sorted, deduplicated names of the predicates.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Has("user")).
+//	    WithOrderAsc("name@en").
+//	    WithDirectives(NewDirective("filter", Eq("age", 30))).
+//	    WithAttributes(NewAttribute("count(friend)")))
+//	fmt.Println(Predicates(query)) // Output: [age friend name user]
+func Predicates(q *Query) []string {
+	found := map[string]bool{}
+	add := func(name string) {
+		if name = predicateOf(name); name != "" {
+			found[name] = true
+		}
+	}
+	scanCriteria := func(list []Criteria) {
+		for _, c := range list {
+			scanPredicates(c, add)
+		}
+	}
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if !n.Raw {
+				scanCriteria(n.Criteria)
+				scanCriteria(n.Directives)
+			}
+		case *QueryBlock:
+			if !n.Raw {
+				scanCriteria(n.Criteria)
+				scanCriteria(n.Directives)
+			}
+		case *Attribute:
+			if n.Raw {
+				return false
+			}
+			if !strings.HasPrefix(n.Name, "...") {
+				add(n.Name)
+			}
+			scanCriteria(n.Args)
+			scanCriteria(n.Directives)
+		}
+		return true
+	})
+
+	res := make([]string, 0, len(found))
+	for name := range found {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// scanPredicates reports the predicates referenced by a criteria, a block argument or a
+// directive to add.
+func scanPredicates(c Criteria, add func(name string)) {
+	text := strings.TrimSpace(c.String())
+	switch name := argName(c); name {
+	case "orderasc", "orderdesc":
+		_, value, _ := strings.Cut(text, ":")
+		add(value)
+		return
+	case "first", "offset", "after":
+		return
+	}
+	switch directiveName(c) {
+	case "facets":
+		return
+	case "groupby", "cascade":
+		if _, args, ok := strings.Cut(text, "("); ok {
+			for _, arg := range splitTopLevel(strings.TrimSuffix(args, ")")) {
+				add(arg)
+			}
+		}
+		return
+	}
+	for _, m := range predicateFunctionPattern.FindAllStringSubmatch(text, -1) {
+		if m[1] == "type" {
+			add(PredicateType)
+			continue
+		}
+		add(m[2])
+	}
+}
+
+// predicateOf returns the predicate read by an attribute name or a function argument, or an
+// empty string if it reads no predicate, such as val(a) or uid.
+func predicateOf(name string) string {
+	name = strings.TrimSpace(name)
+	if inner, ok := strings.CutPrefix(name, "count("); ok {
+		name = strings.TrimSpace(strings.TrimSuffix(inner, ")"))
+		if i := strings.IndexAny(name, " @"); i > 0 && !strings.HasPrefix(name, "<") {
+			name = name[:i]
+		}
+	}
+	if strings.HasPrefix(name, "expand(") {
+		return PredicateType
+	}
+	if isExpression(name) || strings.HasPrefix(name, "$") || name == PredicateUID || name == "" {
+		return ""
+	}
+	name = strings.TrimPrefix(name, "~")
+	if strings.HasPrefix(name, "<") {
+		end := strings.IndexByte(name, '>')
+		if end < 0 {
+			return ""
+		}
+		return name[1:end]
+	}
+	name, _, _ = strings.Cut(name, "@")
+	return name
+}
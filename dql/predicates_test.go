@@ -0,0 +1,57 @@
+package dql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{
+			name: "attributes, filters and ordering",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).
+				WithOrderAsc("name@en").
+				WithDirectives(NewDirective("filter", Eq("age", 30))).
+				WithAttributes(NewAttribute("count(friend)"))),
+			want: []string{"age", "friend", "name", "user"},
+		},
+		{
+			name: "reverse, language and nested filters",
+			query: NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(
+				NewAttribute("~owner").WithDirectives(NewDirective("filter", AllOfTerms("title", "go"))).
+					WithAttributes(NewAttribute("name@fr")),
+			)),
+			want: []string{"name", "owner", "title"},
+		},
+		{
+			name: "groupby, cascade and facets",
+			query: NewQuery("", NewQueryBlock("me", Uid("0x1")).WithDirectives("@cascade(email)").WithAttributes(
+				NewAttribute("friend").WithDirectives("@groupby(genre)", "@facets(since)").WithAttributes(NewAttribute("count(uid)")),
+			)),
+			want: []string{"email", "friend", "genre"},
+		},
+		{
+			name: "type functions and variables",
+			query: NewQuery("", NewQueryBlock("me", Type("Person")).WithAttributes(NewAttribute("val(a)"), NewAttribute("uid"))).
+				WithVarBlocks(NewVarBlock(Has("age")).WithAttributes(NewAttribute("age").WithVar("a"))),
+			want: []string{"age", PredicateType},
+		},
+		{
+			name: "fragment spreads and raw attributes",
+			query: NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(NewAttribute("...userFields"), NewRawAttribute("secret"))).
+				WithFragments(NewFragment("userFields").WithAttributes(NewAttribute("email"))),
+			want: []string{"email"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Predicates(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Predicates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package dql
+
+import "fmt"
+
+// SumUpVarName returns the name of the variable holding the values summed at a depth of the
+// traversal of attributes created by SumUp.
+//
+// Parameters:
+//   - name: The name of the variable holding the totals at the top of the traversal.
+//   - depth: The number of edges below the top of the traversal, starting at 0.
+//
+// Returns:
+//   - The name of the variable.
+func SumUpVarName(name string, depth int) string {
+	if depth == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", name, depth)
+}
+
+// SumUp creates the attributes summing a value computed at the end of a traversal up to the
+// nodes the traversal starts from, e.g. the scores of the films of each director.
+//
+// Dgraph sums the values of a variable defined in a nested block when it is aggregated with
+// sum(val(...)) in an enclosing block. The value is assigned to a variable at the deepest
+// edge, and each enclosing level sums the variable of the level below into its own, named
+// with SumUpVarName:
+//
+//	edge1 { edge2 { name_2 as value } name_1 as sum(val(name_2)) } name as sum(val(name_1))
+//
+// The variable name then maps each node of the enclosing block to its total, for use with
+// val(name) in later blocks, e.g. to order by it.
+//
+// Parameters:
+//   - name: The name of the variable holding the totals.
+//   - edges: The edges of the traversal, from the top. Without edges, the value is assigned to
+//     name directly.
+//   - value: The value computed at the end of the traversal, either an expression such as
+//     math(p + q) or count(genre), a value predicate, or an Attribute.
+//   - inputs: The attributes selected next to the value, such as the variables of a math()
+//     expression.
+//
+// Returns:
+//   - The attributes to add to the block the traversal starts from.
+//
+// Example:
+//
+//	attrs := SumUp("score", []string{"director.film"}, "math(p + q)",
+//	    NewAttribute("count(starring)").WithVar("p"),
+//	    NewAttribute("count(genre)").WithVar("q"),
+//	)
+//	directors := NewVarBlock(AnyOfTerms("name@en", "steven spielberg")).WithAttributes(attrs...)
+//	fmt.Println(directors.String())
+//	// Output: var (func: anyofterms(name@en, "steven spielberg")) { director.film { p as count(starring) q as count(genre) score_1 as math(p + q) } score as sum(val(score_1)) }
+//
+// See: https://dgraph.io/docs/query-language/value-variables/
+func SumUp(name string, edges []string, value any, inputs ...*Attribute) []*Attribute {
+	leaf, ok := value.(*Attribute)
+	if !ok {
+		leaf = NewAttribute(toCriteria(value).String())
+	}
+	leaf.WithVar(SumUpVarName(name, len(edges)))
+	attrs := append(append([]*Attribute{}, inputs...), leaf)
+	for depth := len(edges); depth > 0; depth-- {
+		edge := NewAttribute(edges[depth-1]).WithAttributes(attrs...)
+		sum := NewAttribute("sum(val(" + SumUpVarName(name, depth) + "))").WithVar(SumUpVarName(name, depth-1))
+		attrs = []*Attribute{edge, sum}
+	}
+	return attrs
+}
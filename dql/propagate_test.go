@@ -0,0 +1,34 @@
+package dql
+
+import "testing"
+
+func TestSumUp(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges []string
+		value any
+		want  string
+	}{
+		{"no edges", nil, "price", "total as price"},
+		{"one edge", []string{"items"}, "price", "items { total_1 as price } total as sum(val(total_1))"},
+		{"two edges", []string{"orders", "items"}, NewAttribute("price"),
+			"orders { items { total_2 as price } total_1 as sum(val(total_2)) } total as sum(val(total_1))"},
+		{"expression", []string{"items"}, "math(price * qty)", "items { total_1 as math(price * qty) } total as sum(val(total_1))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := NewQueryBlock("me", Uid("0x1")).WithAttributes(SumUp("total", tt.edges, tt.value)...)
+			if want := "me (func: uid(0x1)) { " + tt.want + " }"; qb.String() != want {
+				t.Errorf("SumUp() = %s, want %s", qb.String(), want)
+			}
+		})
+	}
+
+	q := NewQuery("", NewQueryBlock("me", Uid("0x1")).WithAttributes(SumUp("t", []string{"items"}, "price", NewAttribute("name"))...))
+	if err := q.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if want := "{ me (func: uid(0x1)) { items { name t_1 as price } t as sum(val(t_1)) } }"; q.String() != want {
+		t.Errorf("String() = %s, want %s", q.String(), want)
+	}
+}
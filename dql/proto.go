@@ -0,0 +1,678 @@
+package dql
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// MarshalProto encodes the query in the protobuf form defined by dql.proto, so query
+// definitions can be shipped across services and languages without re-parsing DQL text.
+//
+// Fragments spread with Spread are encoded as declared fragments. Criteria of types without a
+// representation of their own, such as custom Criteria implementations, are encoded as Raw
+// DQL, and so are literals of types other than strings, numbers, booleans, times and regular
+// expressions.
+//
+// Returns:
+//   - The protobuf encoding of the query.
+//   - An error if the query cannot be encoded.
+//
+// Example:
+//
+//	data, err := query.MarshalProto()
+//	// ... send data to another service ...
+//	query, err := UnmarshalQueryProto(data)
+func (q *Query) MarshalProto() ([]byte, error) {
+	w := &protoWriter{}
+	encodeQuery(w, q)
+	return w.buf, nil
+}
+
+// UnmarshalQueryProto decodes a query encoded by Query.MarshalProto, or by any protobuf
+// implementation of the Query message of dql.proto. Unknown fields are ignored.
+//
+// Parameters:
+//   - data: The protobuf encoding of the query.
+//
+// Returns:
+//   - A pointer to the decoded Query object, which is not frozen.
+//   - An error of kind ErrSyntax if the data is malformed.
+func UnmarshalQueryProto(data []byte) (*Query, error) {
+	q, err := decodeQuery(data)
+	if err != nil {
+		return nil, wrapError(ErrSyntax, fmt.Errorf("dql: proto: query: %w", err))
+	}
+	return q, nil
+}
+
+// MarshalProto encodes the schema in the protobuf form defined by dql.proto.
+//
+// Returns:
+//   - The protobuf encoding of the schema.
+//   - An error if the schema cannot be encoded.
+func (s *Schema) MarshalProto() ([]byte, error) {
+	w := &protoWriter{}
+	encodeSchema(w, s)
+	return w.buf, nil
+}
+
+// UnmarshalSchemaProto decodes a schema encoded by Schema.MarshalProto, or by any protobuf
+// implementation of the Schema message of dql.proto. Unknown fields are ignored.
+//
+// Parameters:
+//   - data: The protobuf encoding of the schema.
+//
+// Returns:
+//   - A pointer to the decoded Schema object.
+//   - An error of kind ErrSyntax if the data is malformed.
+func UnmarshalSchemaProto(data []byte) (*Schema, error) {
+	s, err := decodeSchema(data)
+	if err != nil {
+		return nil, wrapError(ErrSyntax, fmt.Errorf("dql: proto: schema: %w", err))
+	}
+	return s, nil
+}
+
+// encodeQuery encodes a Query message.
+func encodeQuery(w *protoWriter, q *Query) {
+	w.string(1, q.Name)
+	for _, p := range q.Params {
+		w.message(2, func(w *protoWriter) {
+			w.string(1, p.Name)
+			w.string(2, string(p.Type))
+			w.string(3, p.Default)
+		})
+	}
+	for _, qb := range q.QueryBlocks {
+		w.message(3, func(w *protoWriter) {
+			encodeBlock(w, qb.Name, qb.Criteria, qb.Directives, qb.Attributes, qb.Raw)
+		})
+	}
+	for _, vb := range q.VarBlocks {
+		w.message(4, func(w *protoWriter) {
+			encodeBlock(w, vb.Name, vb.Criteria, vb.Directives, vb.Attributes, vb.Raw)
+		})
+	}
+	for _, sp := range q.ShortestPaths {
+		w.message(5, func(w *protoWriter) { encodeShortestPath(w, sp) })
+	}
+	for _, f := range q.fragments() {
+		w.message(6, func(w *protoWriter) {
+			w.string(1, f.Name)
+			encodeAttributes(w, 2, f.Attributes)
+		})
+	}
+	w.bool(7, q.Strict)
+	w.bool(8, q.Debug)
+	if l := q.Limits; l != nil {
+		w.message(9, func(w *protoWriter) {
+			w.int(1, int64(l.MaxDepth))
+			w.int(2, int64(l.MaxAttributes))
+			w.int(3, int64(l.MaxBlocks))
+		})
+	}
+	if q.Schema != nil {
+		w.message(10, func(w *protoWriter) { encodeSchema(w, q.Schema) })
+	}
+	w.bool(11, q.Canonical)
+}
+
+// encodeBlock encodes a Block message.
+func encodeBlock(w *protoWriter, name string, criteria []Criteria, directives []Criteria, attrs []*Attribute, raw bool) {
+	w.string(1, name)
+	encodeCriteriaList(w, 2, criteria)
+	encodeCriteriaList(w, 3, directives)
+	encodeAttributes(w, 4, attrs)
+	w.bool(5, raw)
+}
+
+// encodeAttributes encodes a repeated Attribute field.
+func encodeAttributes(w *protoWriter, field int, attrs []*Attribute) {
+	for _, a := range attrs {
+		w.message(field, func(w *protoWriter) {
+			w.string(1, a.Alias)
+			w.string(2, a.Var)
+			w.string(3, a.Name)
+			encodeCriteriaList(w, 4, a.Args)
+			encodeCriteriaList(w, 5, a.Directives)
+			encodeAttributes(w, 6, a.Attributes)
+			w.bool(7, a.Raw)
+			w.bool(8, a.ArgsAfterDirectives)
+		})
+	}
+}
+
+// encodeShortestPath encodes a ShortestPath message.
+func encodeShortestPath(w *protoWriter, sp *ShortestPath) {
+	w.string(1, sp.Name)
+	w.string(2, sp.From)
+	w.string(3, sp.To)
+	w.int(4, int64(sp.NumPaths))
+	w.int(5, int64(sp.Depth))
+	if sp.MinWeight != nil {
+		w.fixed64(6, math.Float64bits(*sp.MinWeight))
+	}
+	if sp.MaxWeight != nil {
+		w.fixed64(7, math.Float64bits(*sp.MaxWeight))
+	}
+	encodeAttributes(w, 8, sp.Attributes)
+}
+
+// encodeCriteriaList encodes a repeated Criteria field.
+func encodeCriteriaList(w *protoWriter, field int, list []Criteria) {
+	for _, c := range list {
+		w.message(field, func(w *protoWriter) { encodeCriteria(w, c) })
+	}
+}
+
+// encodeCriteria encodes a Criteria message, falling back to raw DQL for the criteria without
+// a representation of their own.
+func encodeCriteria(w *protoWriter, c Criteria) {
+	switch c := c.(type) {
+	case *Function:
+		w.message(1, func(w *protoWriter) {
+			w.string(1, c.Name)
+			encodeCriteriaList(w, 2, c.Args)
+		})
+	case *Directive:
+		w.message(2, func(w *protoWriter) {
+			w.string(1, c.Name)
+			encodeCriteriaList(w, 2, c.Args)
+		})
+	case *Arg:
+		w.message(3, func(w *protoWriter) {
+			w.string(1, c.Name)
+			if c.Value != nil {
+				w.message(2, func(w *protoWriter) { encodeCriteria(w, c.Value) })
+			}
+		})
+	case Literal:
+		w.message(4, func(w *protoWriter) { encodeLiteral(w, c.Value) })
+	case List:
+		w.message(5, func(w *protoWriter) { encodeCriteriaList(w, 1, c) })
+	case Raw:
+		w.bytes(6, []byte(c))
+	case ParamRef:
+		w.bytes(7, []byte(c))
+	case FacetVar:
+		w.message(8, func(w *protoWriter) {
+			w.string(1, c.Name)
+			w.string(2, c.Facet)
+		})
+	case UID:
+		w.uvarint(9, uint64(c))
+	case Placeholder:
+		w.bytes(10, []byte(c))
+	case *Connective:
+		w.message(11, func(w *protoWriter) {
+			w.string(1, c.Op)
+			encodeCriteriaList(w, 2, c.Args)
+		})
+	default:
+		w.bytes(6, []byte(c.String()))
+	}
+}
+
+// encodeLiteral encodes a Literal message, falling back to the rendered DQL text for the
+// values of other types.
+func encodeLiteral(w *protoWriter, v any) {
+	switch v := v.(type) {
+	case string:
+		w.bytes(1, []byte(v))
+	case int:
+		w.uvarint(2, zigzag(int64(v)))
+	case int8:
+		w.uvarint(2, zigzag(int64(v)))
+	case int16:
+		w.uvarint(2, zigzag(int64(v)))
+	case int32:
+		w.uvarint(2, zigzag(int64(v)))
+	case int64:
+		w.uvarint(2, zigzag(v))
+	case uint:
+		w.uvarint(3, uint64(v))
+	case uint8:
+		w.uvarint(3, uint64(v))
+	case uint16:
+		w.uvarint(3, uint64(v))
+	case uint32:
+		w.uvarint(3, uint64(v))
+	case uint64:
+		w.uvarint(3, v)
+	case float64:
+		w.fixed64(4, math.Float64bits(v))
+	case float32:
+		w.fixed32(5, math.Float32bits(v))
+	case bool:
+		if v {
+			w.uvarint(6, 1)
+		} else {
+			w.uvarint(6, 0)
+		}
+	case time.Time:
+		w.bytes(7, []byte(v.Format(time.RFC3339Nano)))
+	case *big.Int:
+		w.bytes(8, []byte(v.String()))
+	case *big.Float:
+		w.bytes(9, []byte(v.Text('g', -1)))
+	case decimal:
+		w.bytes(10, []byte(v))
+	case regex:
+		w.bytes(12, []byte(v.String()))
+	default:
+		w.bytes(11, []byte(SafeValue(v)))
+	}
+}
+
+// encodeSchema encodes a Schema message.
+func encodeSchema(w *protoWriter, s *Schema) {
+	for _, p := range s.Predicates {
+		w.message(1, func(w *protoWriter) {
+			w.string(1, p.Name)
+			w.string(2, p.Type)
+			w.bool(3, p.List)
+			w.strings(4, p.Indexes)
+			w.bool(5, p.Reverse)
+			w.bool(6, p.Count)
+			w.bool(7, p.Lang)
+			w.bool(8, p.Upsert)
+			w.bool(9, p.NoConflict)
+		})
+	}
+	for _, t := range s.Types {
+		w.message(2, func(w *protoWriter) {
+			w.string(1, t.Name)
+			w.strings(2, t.Fields)
+		})
+	}
+}
+
+// decodeQuery decodes a Query message.
+func decodeQuery(data []byte) (*Query, error) {
+	q := &Query{}
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			q.Name = f.string()
+		case 2:
+			p := &Param{}
+			q.Params = append(q.Params, p)
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					p.Name = f.string()
+				case 2:
+					p.Type = ParamType(f.string())
+				case 3:
+					p.Default = f.string()
+				}
+				return nil
+			})
+		case 3:
+			qb := &QueryBlock{}
+			q.QueryBlocks = append(q.QueryBlocks, qb)
+			return decodeBlock(f.data, &qb.Name, &qb.Criteria, &qb.Directives, &qb.Attributes, &qb.Raw)
+		case 4:
+			vb := &VarBlock{}
+			q.VarBlocks = append(q.VarBlocks, vb)
+			return decodeBlock(f.data, &vb.Name, &vb.Criteria, &vb.Directives, &vb.Attributes, &vb.Raw)
+		case 5:
+			sp, err := decodeShortestPath(f.data)
+			if err != nil {
+				return fmt.Errorf("shortest path: %w", err)
+			}
+			q.ShortestPaths = append(q.ShortestPaths, sp)
+		case 6:
+			fragment := &Fragment{}
+			q.Fragments = append(q.Fragments, fragment)
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					fragment.Name = f.string()
+				case 2:
+					return decodeAttribute(f.data, &fragment.Attributes)
+				}
+				return nil
+			})
+		case 7:
+			q.Strict = f.bool()
+		case 8:
+			q.Debug = f.bool()
+		case 9:
+			q.Limits = &Limits{}
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					q.Limits.MaxDepth = int(f.int())
+				case 2:
+					q.Limits.MaxAttributes = int(f.int())
+				case 3:
+					q.Limits.MaxBlocks = int(f.int())
+				}
+				return nil
+			})
+		case 10:
+			s, err := decodeSchema(f.data)
+			if err != nil {
+				return fmt.Errorf("schema: %w", err)
+			}
+			q.Schema = s
+		case 11:
+			q.Canonical = f.bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// decodeBlock decodes a Block message into the fields of a query block or var block.
+func decodeBlock(data []byte, name *string, criteria *[]Criteria, directives *[]Criteria, attrs *[]*Attribute, raw *bool) error {
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			*name = f.string()
+		case 2:
+			return decodeCriteriaInto(f.data, criteria)
+		case 3:
+			return decodeCriteriaInto(f.data, directives)
+		case 4:
+			return decodeAttribute(f.data, attrs)
+		case 5:
+			*raw = f.bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("block %q: %w", *name, err)
+	}
+	return nil
+}
+
+// decodeAttribute decodes an Attribute message and appends it to attrs.
+func decodeAttribute(data []byte, attrs *[]*Attribute) error {
+	a := &Attribute{}
+	*attrs = append(*attrs, a)
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			a.Alias = f.string()
+		case 2:
+			a.Var = f.string()
+		case 3:
+			a.Name = f.string()
+		case 4:
+			return decodeCriteriaInto(f.data, &a.Args)
+		case 5:
+			return decodeCriteriaInto(f.data, &a.Directives)
+		case 6:
+			return decodeAttribute(f.data, &a.Attributes)
+		case 7:
+			a.Raw = f.bool()
+		case 8:
+			a.ArgsAfterDirectives = f.bool()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attribute %q: %w", a.Name, err)
+	}
+	return nil
+}
+
+// decodeShortestPath decodes a ShortestPath message.
+func decodeShortestPath(data []byte) (*ShortestPath, error) {
+	sp := &ShortestPath{}
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			sp.Name = f.string()
+		case 2:
+			sp.From = f.string()
+		case 3:
+			sp.To = f.string()
+		case 4:
+			sp.NumPaths = int(f.int())
+		case 5:
+			sp.Depth = int(f.int())
+		case 6:
+			weight := f.float64()
+			sp.MinWeight = &weight
+		case 7:
+			weight := f.float64()
+			sp.MaxWeight = &weight
+		case 8:
+			return decodeAttribute(f.data, &sp.Attributes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// decodeCriteriaInto decodes a Criteria message and appends it to list.
+func decodeCriteriaInto(data []byte, list *[]Criteria) error {
+	c, err := decodeCriteria(data)
+	if err != nil {
+		return err
+	}
+	*list = append(*list, c)
+	return nil
+}
+
+// decodeCriteria decodes a Criteria message.
+func decodeCriteria(data []byte) (Criteria, error) {
+	var c Criteria
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1, 2:
+			var name string
+			var args []Criteria
+			err := readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					name = f.string()
+				case 2:
+					return decodeCriteriaInto(f.data, &args)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if f.num == 1 {
+				c = &Function{Name: name, Args: args}
+			} else {
+				c = &Directive{Name: name, Args: args}
+			}
+		case 3:
+			arg := &Arg{}
+			c = arg
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					arg.Name = f.string()
+				case 2:
+					value, err := decodeCriteria(f.data)
+					if err != nil {
+						return fmt.Errorf("arg %s: %w", arg.Name, err)
+					}
+					arg.Value = value
+				}
+				return nil
+			})
+		case 4:
+			v, err := decodeLiteral(f.data)
+			if err != nil {
+				return err
+			}
+			c = Literal{v}
+		case 5:
+			list := List{}
+			err := readProto(f.data, func(f protoField) error {
+				if f.num == 1 {
+					return decodeCriteriaInto(f.data, (*[]Criteria)(&list))
+				}
+				return nil
+			})
+			c = list
+			return err
+		case 6:
+			c = Raw(f.string())
+		case 7:
+			c = ParamRef(f.string())
+		case 8:
+			v := FacetVar{}
+			err := readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					v.Name = f.string()
+				case 2:
+					v.Facet = f.string()
+				}
+				return nil
+			})
+			c = v
+			return err
+		case 9:
+			c = UID(f.value)
+		case 10:
+			c = Placeholder(f.string())
+		case 11:
+			conn := &Connective{}
+			c = conn
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					conn.Op = f.string()
+				case 2:
+					return decodeCriteriaInto(f.data, &conn.Args)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("criteria without a kind")
+	}
+	return c, nil
+}
+
+// decodeLiteral decodes the value of a Literal message.
+func decodeLiteral(data []byte) (any, error) {
+	var v any
+	err := readProto(data, func(f protoField) error {
+		var err error
+		switch f.num {
+		case 1:
+			v = f.string()
+		case 2:
+			v = int(f.int())
+		case 3:
+			v = f.value
+		case 4:
+			v = f.float64()
+		case 5:
+			v = math.Float32frombits(uint32(f.value))
+		case 6:
+			v = f.bool()
+		case 7:
+			v, err = time.Parse(time.RFC3339Nano, f.string())
+		case 8:
+			i, ok := new(big.Int).SetString(f.string(), 10)
+			if !ok {
+				return fmt.Errorf("literal: malformed big int %q", f.string())
+			}
+			v = i
+		case 9:
+			// Keep at least as many bits as the decimal digits carry, so no precision is lost.
+			prec := max(64, uint(len(f.data))*4)
+			v, _, err = big.ParseFloat(f.string(), 10, prec, big.ToNearestEven)
+		case 10:
+			v = decimal(f.string())
+		case 11:
+			v = Raw(f.string())
+		case 12:
+			text := f.string()
+			end := strings.LastIndexByte(text, '/')
+			if !strings.HasPrefix(text, "/") || end < 1 {
+				return fmt.Errorf("literal: malformed regular expression %q", text)
+			}
+			v = regex{text[1:end], text[end+1:]}
+		}
+		if err != nil {
+			return fmt.Errorf("literal: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("literal without a value")
+	}
+	return v, nil
+}
+
+// decodeSchema decodes a Schema message.
+func decodeSchema(data []byte) (*Schema, error) {
+	s := &Schema{}
+	err := readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			p := &SchemaPredicate{}
+			s.Predicates = append(s.Predicates, p)
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					p.Name = f.string()
+				case 2:
+					p.Type = f.string()
+				case 3:
+					p.List = f.bool()
+				case 4:
+					p.Indexes = append(p.Indexes, f.string())
+				case 5:
+					p.Reverse = f.bool()
+				case 6:
+					p.Count = f.bool()
+				case 7:
+					p.Lang = f.bool()
+				case 8:
+					p.Upsert = f.bool()
+				case 9:
+					p.NoConflict = f.bool()
+				}
+				return nil
+			})
+		case 2:
+			t := &SchemaType{}
+			s.Types = append(s.Types, t)
+			return readProto(f.data, func(f protoField) error {
+				switch f.num {
+				case 1:
+					t.Name = f.string()
+				case 2:
+					t.Fields = append(t.Fields, f.string())
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,86 @@
+package dql
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryProto(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	schema, err := ParseSchema("name: string @index(exact) @lang .\nfriend: [uid] @reverse @count .\ntype Person { name friend }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := NewFragment("base").WithAttributes(NewAttribute("uid"))
+	q := NewQuery("Q", NewQueryBlock("me", Eq("name", ParamRef("$name"))).
+		WithFirst(10).
+		WithDirectives(NewDirective("filter", Ge("created", Time(created))), "@cascade").
+		WithAttributes(
+			NewAttribute("name").WithAlias("n"),
+			NewAttribute("friend").WithVar("f").WithDirectives(Facets(FacetVar{Name: "w", Facet: "weight"})).WithAttributes(NewAttribute("name"), Spread(shared)),
+			NewAttribute("count").WithDirectives(NewDirective("filter", Eq("n", BigInt(huge)))),
+			NewAttribute("score").WithDirectives(NewDirective("filter", Lt("score", 1.5))),
+			NewAttribute("nick").WithDirectives(NewDirective("filter", Regexp("nick", "^a/b", "i"))),
+			NewAttribute("email").WithDirectives(NewDirective("filter", And(Has("email"), Or(Eq("tenant", "acme"), "has(admin)")))),
+			NewAttribute("active").WithDirectives(NewDirective("filter", Eq("active", true), Eq("tag", -3), Eq("v", uint64(7)))),
+		)).
+		WithParam(NewParam("name", ParamString).WithDefault("Alice")).
+		WithVarBlocks(NewVarBlock(Uid(UID(0x1), "0x2")).WithName("v")).
+		WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithWeights(1, 2).WithWeightedEdge("road", "distance")).
+		WithFragments(shared).
+		WithLimits(Limits{MaxDepth: 5}).
+		WithSchema(schema)
+	data, err := q.MarshalProto()
+	if err != nil {
+		t.Fatalf("MarshalProto() error = %v", err)
+	}
+	got, err := UnmarshalQueryProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalQueryProto() error = %v", err)
+	}
+	if got.String() != q.String() {
+		t.Errorf("round trip = %s, want %s", got.String(), q.String())
+	}
+	if got.Fingerprint() != q.Fingerprint() || !Equal(got, q) {
+		t.Errorf("round trip differs from the query")
+	}
+	if got.Limits == nil || got.Limits.MaxDepth != 5 || got.Schema.String() != schema.String() {
+		t.Errorf("round trip lost the limits or schema: %+v, %v", got.Limits, got.Schema)
+	}
+	lit := got.QueryBlocks[0].Directives[0].(*Directive).Args[0].(*Function).Args[1].(Literal)
+	if v, ok := lit.Value.(time.Time); !ok || !v.Equal(created) {
+		t.Errorf("time literal = %#v, want %v", lit.Value, created)
+	}
+	lit = got.QueryBlocks[0].Attributes[4].Directives[0].(*Directive).Args[0].(*Function).Args[1].(Literal)
+	if v, ok := lit.Value.(regex); !ok || v.String() != `/^a\/b/i` {
+		t.Errorf("regexp literal = %#v, want /^a\\/b/i", lit.Value)
+	}
+	if _, ok := got.QueryBlocks[0].Attributes[5].Directives[0].(*Directive).Args[0].(*Connective); !ok {
+		t.Errorf("filter = %#v, want a Connective", got.QueryBlocks[0].Attributes[5].Directives[0])
+	}
+	if _, err := UnmarshalQueryProto([]byte{0x0a, 0x05, 'a'}); !errors.Is(err, ErrSyntax) {
+		t.Errorf("UnmarshalQueryProto() of truncated data error = %v, want a syntax error", err)
+	}
+}
+
+func TestSchemaProto(t *testing.T) {
+	schema, err := ParseSchema("email: string @index(hash) @upsert .\nfriend: [uid] @reverse @count .\nvisits: int @noconflict .\ntype Person { email friend visits }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := schema.MarshalProto()
+	if err != nil {
+		t.Fatalf("MarshalProto() error = %v", err)
+	}
+	got, err := UnmarshalSchemaProto(data)
+	if err != nil {
+		t.Fatalf("UnmarshalSchemaProto() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, schema) {
+		t.Errorf("round trip = %v, want %v", got, schema)
+	}
+}
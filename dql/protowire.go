@@ -0,0 +1,174 @@
+package dql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Wire types of the protobuf encoding.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoWriter encodes a protobuf message.
+//
+// The methods named after a scalar type omit the default value of the type, as proto3 does;
+// uvarint, fixed64, fixed32 and bytes always write the field, for the members of oneofs.
+type protoWriter struct {
+	buf []byte
+}
+
+// tag writes the key of a field.
+func (w *protoWriter) tag(field int, wireType int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|uint64(wireType))
+}
+
+// uvarint writes a varint field.
+func (w *protoWriter) uvarint(field int, v uint64) {
+	w.tag(field, wireVarint)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// fixed64 writes a 64-bit field.
+func (w *protoWriter) fixed64(field int, v uint64) {
+	w.tag(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, v)
+}
+
+// fixed32 writes a 32-bit field.
+func (w *protoWriter) fixed32(field int, v uint32) {
+	w.tag(field, wireFixed32)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, v)
+}
+
+// bytes writes a length-delimited field.
+func (w *protoWriter) bytes(field int, b []byte) {
+	w.tag(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// message writes a nested message encoded by encode.
+func (w *protoWriter) message(field int, encode func(w *protoWriter)) {
+	nested := &protoWriter{}
+	encode(nested)
+	w.bytes(field, nested.buf)
+}
+
+// string writes a string field, unless empty.
+func (w *protoWriter) string(field int, s string) {
+	if s != "" {
+		w.bytes(field, []byte(s))
+	}
+}
+
+// strings writes a repeated string field.
+func (w *protoWriter) strings(field int, s []string) {
+	for _, v := range s {
+		w.bytes(field, []byte(v))
+	}
+}
+
+// bool writes a bool field, unless false.
+func (w *protoWriter) bool(field int, b bool) {
+	if b {
+		w.uvarint(field, 1)
+	}
+}
+
+// int writes a sint64 field, unless zero.
+func (w *protoWriter) int(field int, v int64) {
+	if v != 0 {
+		w.uvarint(field, zigzag(v))
+	}
+}
+
+// protoField is a field read from a protobuf message.
+type protoField struct {
+	// num is the number of the field.
+	num int
+
+	// wireType is the wire type of the field.
+	wireType int
+
+	// value is the value of varint and fixed fields.
+	value uint64
+
+	// data is the value of length-delimited fields.
+	data []byte
+}
+
+// string returns the value of a string field.
+func (f protoField) string() string {
+	return string(f.data)
+}
+
+// bool returns the value of a bool field.
+func (f protoField) bool() bool {
+	return f.value != 0
+}
+
+// int returns the value of a sint64 field.
+func (f protoField) int() int64 {
+	return unzigzag(f.value)
+}
+
+// float64 returns the value of a double field.
+func (f protoField) float64() float64 {
+	return math.Float64frombits(f.value)
+}
+
+// readProto calls read for each field of a protobuf message, in order.
+func readProto(data []byte, read func(f protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("malformed field key")
+		}
+		data = data[n:]
+		f := protoField{num: int(key >> 3), wireType: int(key & 7)}
+		switch f.wireType {
+		case wireVarint:
+			if f.value, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("field %d: malformed varint", f.num)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("field %d: truncated fixed64", f.num)
+			}
+			f.value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("field %d: truncated fixed32", f.num)
+			}
+			f.value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return fmt.Errorf("field %d: truncated bytes", f.num)
+			}
+			f.data, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", f.num, f.wireType)
+		}
+		if err := read(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zigzag encodes a signed integer as a sint64 varint.
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// unzigzag decodes a sint64 varint.
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
@@ -1,6 +1,7 @@
 package dql
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -18,8 +19,30 @@ type Query struct {
 	// VarBlocks is a list of variable blocks used in the query.
 	VarBlocks []*VarBlock
 
+	// ShortestPaths is a list of shortest path blocks used in the query.
+	ShortestPaths []*ShortestPath
+
 	// Fragments is a list of reusable fragments included in the query.
 	Fragments []*Fragment
+
+	// Strict rejects literal values in criteria and directives, see WithStrict.
+	Strict bool
+
+	// Debug requests debug information from Dgraph, see WithDebug.
+	Debug bool
+
+	// Canonical renders the query with the parts whose order does not matter sorted, see
+	// WithCanonical.
+	Canonical bool
+
+	// Limits bounds the size of the query, nil if unbounded, see WithLimits.
+	Limits *Limits
+
+	// Schema types the literal values of the query, nil if untyped, see WithSchema.
+	Schema *Schema
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewQuery creates a new DQL query.
@@ -46,6 +69,9 @@ func NewQuery(name string, queryBlock *QueryBlock) *Query {
 }
 
 func (q *Query) concatenate() []string {
+	if q.Canonical {
+		q = q.canonical()
+	}
 	components := []string{}
 	if q.Name != "" {
 		components = append(components, "query", q.Name)
@@ -63,11 +89,14 @@ func (q *Query) concatenate() []string {
 	for _, vBlock := range q.VarBlocks {
 		components = append(components, vBlock.String())
 	}
+	for _, sp := range q.ShortestPaths {
+		components = append(components, sp.String())
+	}
 	for _, qBlock := range q.QueryBlocks {
 		components = append(components, qBlock.String())
 	}
 	components = append(components, "}")
-	for _, f := range q.Fragments {
+	for _, f := range q.fragments() {
 		components = append(components, f.String())
 	}
 	return components
@@ -121,6 +150,21 @@ func (q Query) PrettyPrint() string {
 	return result.String()
 }
 
+// WithDebug requests debug information when the query is executed.
+//
+// The flag does not change the rendered query: it is sent as the debug=true request option,
+// with which Dgraph returns the uid of every node along with latency information.
+//
+// Returns:
+//   - The updated Query object.
+//
+// See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#debug
+func (q *Query) WithDebug() *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	q.Debug = true
+	return q
+}
+
 // WithParam adds one or more parameters to the query.
 //
 // Parameters:
@@ -134,8 +178,9 @@ func (q Query) PrettyPrint() string {
 //	param := NewParam("id", "string").WithDefault("123")
 //	query := NewQuery("GetUserQuery", NewQueryBlock("getUser", "has(user)")).
 //	    WithParam(param)
-//	fmt.Println(query.String()) // Output: query GetUserQuery($id: string = 123) { getUser(func: has(user)) { } }
+//	fmt.Println(query.String()) // Output: query GetUserQuery($id: string = "123") { getUser(func: has(user)) { } }
 func (q *Query) WithParam(params ...*Param) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
 	for _, p := range params {
 		q.Params = append(q.Params, p)
 	}
@@ -157,12 +202,35 @@ func (q *Query) WithParam(params ...*Param) *Query {
 //	    WithVarBlocks(varBlock)
 //	fmt.Println(query.String()) // Output: query GetUserQuery { userVar AS var(func: has(user)) { } getUser(func: has(user)) { } }
 func (q *Query) WithVarBlocks(vbs ...*VarBlock) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
 	for _, vb := range vbs {
 		q.VarBlocks = append(q.VarBlocks, vb)
 	}
 	return q
 }
 
+// WithShortestPaths adds one or more shortest path blocks to the query.
+//
+// Parameters:
+//   - sps: One or more ShortestPath objects to add to the query.
+//
+// Returns:
+//   - The updated Query object.
+//
+// Example:
+//
+//	path := NewShortestPath("0x2", "0x5").WithName("path").WithAttributes(NewAttribute("friend"))
+//	query := NewQuery("", NewQueryBlock("path", "uid(path)").WithAttributes(NewAttribute("name"))).
+//	    WithShortestPaths(path)
+//	fmt.Println(query.String()) // Output: { path AS shortest(from: 0x2, to: 0x5) { friend } path (func: uid(path)) { name } }
+func (q *Query) WithShortestPaths(sps ...*ShortestPath) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
+	for _, sp := range sps {
+		q.ShortestPaths = append(q.ShortestPaths, sp)
+	}
+	return q
+}
+
 // WithQueryBlocks adds one or more query blocks to the query.
 //
 // Parameters:
@@ -178,6 +246,7 @@ func (q *Query) WithVarBlocks(vbs ...*VarBlock) *Query {
 //	    WithQueryBlocks(NewQueryBlock("getPosts", "has(post)"))
 //	fmt.Println(query.String()) // Output: query GetUserQuery { getUser(func: has(user)) { } getPosts(func: has(post)) { } }
 func (q *Query) WithQueryBlocks(qbs ...*QueryBlock) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
 	for _, qb := range qbs {
 		q.QueryBlocks = append(q.QueryBlocks, qb)
 	}
@@ -199,8 +268,272 @@ func (q *Query) WithQueryBlocks(qbs ...*QueryBlock) *Query {
 //	    WithFragments(fragment)
 //	fmt.Println(query.String()) // Output: query GetUserQuery { getUser(func: has(user)) { ...userFragment } fragment userFragment { name age } }
 func (q *Query) WithFragments(fragments ...*Fragment) *Query {
+	mustBeMutable(q.frozen, "query", q.Name)
 	for _, f := range fragments {
 		q.Fragments = append(q.Fragments, f)
 	}
 	return q
 }
+
+// Merge combines the parameters, variable blocks, shortest path blocks, query blocks and
+// fragments of another query into this query.
+//
+// Parameters and fragments that are declared identically in both queries are kept once. The
+// query receives copies of the declarations of other, which is left untouched, see Clone.
+// Merge fails without modifying the query when the two queries conflict:
+//   - both declare a query block with the same name,
+//   - both define the same variable, whether as the name of a variable block or a shortest
+//     path block or by assigning an attribute or a facet,
+//   - both declare a parameter with the same name but a different type or default value,
+//   - both declare a fragment with the same name but different attributes.
+//
+// Parameters:
+//   - other: The query to merge into this query.
+//
+// Returns:
+//   - An error describing the first conflict found, or nil if the queries were merged.
+//
+// Example:
+//
+//	users := NewQuery("Q", NewQueryBlock("users", "has(user)"))
+//	posts := NewQuery("", NewQueryBlock("posts", "has(post)"))
+//	err := users.Merge(posts)
+//	fmt.Println(users.String()) // Output: query Q { users(func: has(user)) { } posts(func: has(post)) { } }
+func (q *Query) Merge(other *Query) error {
+	mustBeMutable(q.frozen, "query", q.Name)
+	// Merge copies, so that later changes to either query do not affect the other.
+	other = other.Clone()
+	params := map[string]*Param{}
+	for _, p := range q.Params {
+		params[p.Ref().String()] = p
+	}
+	newParams := []*Param{}
+	for _, p := range other.Params {
+		existing, ok := params[p.Ref().String()]
+		if !ok {
+			params[p.Ref().String()] = p
+			newParams = append(newParams, p)
+			continue
+		}
+		if existing.Type != p.Type || existing.Default != p.Default {
+			return fmt.Errorf("dql: merge: param %q declared as %q and %q", p.Name, existing.String(), p.String())
+		}
+	}
+
+	// Variables are global to a request, whether they name a var block or a shortest path
+	// block or are assigned to an attribute or a facet.
+	variables := map[string]bool{}
+	for _, name := range definedVariables(q) {
+		variables[name] = true
+	}
+	for _, name := range definedVariables(other) {
+		if variables[name] {
+			return fmt.Errorf("dql: merge: variable %q defined by both queries", name)
+		}
+	}
+
+	queryBlocks := map[string]bool{}
+	for _, qb := range q.QueryBlocks {
+		queryBlocks[qb.Name] = true
+	}
+	for _, qb := range other.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		if queryBlocks[qb.Name] {
+			return fmt.Errorf("dql: merge: duplicate query block %q", qb.Name)
+		}
+		queryBlocks[qb.Name] = true
+	}
+
+	fragments := map[string]*Fragment{}
+	for _, f := range q.Fragments {
+		fragments[f.Name] = f
+	}
+	newFragments := []*Fragment{}
+	for _, f := range other.Fragments {
+		existing, ok := fragments[f.Name]
+		if !ok {
+			fragments[f.Name] = f
+			newFragments = append(newFragments, f)
+			continue
+		}
+		if existing.String() != f.String() {
+			return fmt.Errorf("dql: merge: fragment %q declared twice with different attributes", f.Name)
+		}
+	}
+
+	q.Params = append(q.Params, newParams...)
+	q.VarBlocks = append(q.VarBlocks, other.VarBlocks...)
+	q.ShortestPaths = append(q.ShortestPaths, other.ShortestPaths...)
+	q.QueryBlocks = append(q.QueryBlocks, other.QueryBlocks...)
+	q.Fragments = append(q.Fragments, newFragments...)
+	return nil
+}
+
+// Validate checks every parameter, block and fragment of the query.
+//
+// References to parameters, variables and fragments the query does not declare are reported
+// as errors. In strict mode, literal values in criteria and directives are reported as errors
+// as well. Queries exceeding their Limits, see WithLimits, are rejected, and so are literal
+// values not suiting the type of their predicate in the Schema of the query, see WithSchema.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the query is valid.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", "has(user)").
+//	    WithAttributes(
+//	        NewAttribute("count(friend)").WithAlias("total"),
+//	        NewAttribute("count(follower)").WithAlias("total"),
+//	    )
+//	err := NewQuery("", queryBlock).Validate()
+//	fmt.Println(err) // Output: dql: query block "me": duplicate alias "total"
+func (q *Query) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if q.Name != "" {
+		if err := validateName("query name", q.Name); err != nil {
+			return fmt.Errorf("dql: %w", err)
+		}
+	}
+	for _, p := range q.Params {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, vb := range q.VarBlocks {
+		if err := vb.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, sp := range q.ShortestPaths {
+		if err := sp.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, qb := range q.QueryBlocks {
+		if err := qb.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, f := range q.fragments() {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := q.validateSpreads(); err != nil {
+		return err
+	}
+	if err := q.validateVariables(); err != nil {
+		return wrapError(ErrUnknownVariable, err)
+	}
+	if err := q.validateValueFilters(); err != nil {
+		return err
+	}
+	if err := q.validateParamRefs(); err != nil {
+		return wrapError(ErrUnknownVariable, err)
+	}
+	if err := q.validateNumbers(); err != nil {
+		return err
+	}
+	if q.Strict {
+		if err := q.validateStrict(); err != nil {
+			return err
+		}
+	}
+	if q.Schema != nil {
+		if err := q.validateValues(); err != nil {
+			return wrapError(ErrSchemaMismatch, err)
+		}
+		if err := q.validateParamTypes(); err != nil {
+			return wrapError(ErrSchemaMismatch, fmt.Errorf("dql: %w", err))
+		}
+	}
+	if q.Limits != nil {
+		return q.validateLimits()
+	}
+	return nil
+}
+
+// validateParamRefs reports references to parameters the query does not declare.
+func (q *Query) validateParamRefs() error {
+	declared := map[string]bool{}
+	for _, p := range q.Params {
+		declared[p.Ref().String()] = true
+	}
+	var err error
+	for _, list := range criteriaLists(q) {
+		for _, c := range list {
+			walkCriteria(c, func(c Criteria) {
+				if r, ok := c.(ParamRef); ok && !declared[r.String()] && err == nil {
+					err = fmt.Errorf("dql: undeclared param %s", r.String())
+				}
+			})
+		}
+	}
+	return err
+}
+
+// validateValues reports the first literal value of the blocks and fragments not suiting the
+// type of its predicate in the schema of the query, and the edges of shortest path blocks and
+// the @groupby predicates of variable blocks that are not uid predicates.
+func (q *Query) validateValues() error {
+	for _, vb := range q.VarBlocks {
+		err := q.Schema.validateValues(vb)
+		if err == nil {
+			err = q.Schema.validateGroupBy(vb)
+		}
+		if err != nil {
+			return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+		}
+	}
+	for _, sp := range q.ShortestPaths {
+		if err := q.Schema.validateWeightedEdges(sp); err != nil {
+			return fmt.Errorf("dql: shortest path %q: %w", sp.Name, err)
+		}
+	}
+	for _, qb := range q.QueryBlocks {
+		if err := q.Schema.validateValues(qb); err != nil {
+			return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+		}
+	}
+	for _, f := range q.fragments() {
+		if err := q.Schema.validateValues(f); err != nil {
+			return fmt.Errorf("dql: fragment %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateStrict reports the first literal value found in the blocks and fragments.
+func (q *Query) validateStrict() error {
+	for _, vb := range q.VarBlocks {
+		if err := checkStrict(vb); err != nil {
+			return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+		}
+	}
+	for _, sp := range q.ShortestPaths {
+		err := checkRawLiteral(sp.From)
+		if err == nil {
+			err = checkRawLiteral(sp.To)
+		}
+		if err == nil {
+			err = checkStrict(sp)
+		}
+		if err != nil {
+			return fmt.Errorf("dql: shortest path %q: %w", sp.Name, err)
+		}
+	}
+	for _, qb := range q.QueryBlocks {
+		if err := checkStrict(qb); err != nil {
+			return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+		}
+	}
+	for _, f := range q.fragments() {
+		if err := checkStrict(f); err != nil {
+			return fmt.Errorf("dql: fragment %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
@@ -13,20 +13,27 @@ type QueryBlock struct {
 	Name string
 
 	// Criteria defines the function or condition used in the query block.
-	Criteria []string
+	Criteria []Criteria
 
 	// Directives is a list of directives applied to the query block.
-	Directives []string
+	Directives []Criteria
 
 	// Attributes is a list of attributes included in the query block.
 	Attributes []*Attribute
+
+	// Raw marks the query block as verbatim DQL held in Name, see NewRawQueryBlock.
+	Raw bool
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewQueryBlock creates a new QueryBlock.
 //
 // Parameters:
 //   - name: The name of the query block.
-//   - criteria: The root criteria of the query block.
+//   - criteria: The root criteria of the query block, either a Criteria such as Has("user")
+//     or a string.
 //
 // Returns:
 //   - A pointer to a QueryBlock object.
@@ -37,17 +44,39 @@ type QueryBlock struct {
 //	fmt.Println(queryBlock.String()) // Output: getUser(func: has(user)) { }
 //
 // See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#query-block
-func NewQueryBlock(name string, criteria string) *QueryBlock {
+func NewQueryBlock(name string, criteria any) *QueryBlock {
 	return &QueryBlock{
-		Name:      name,
-		Criteria: []string{criteria},
+		Name:     name,
+		Criteria: []Criteria{toCriteria(criteria)},
 	}
 }
 
+// NewDebugBlock creates a new QueryBlock named debug.
+//
+// Dgraph returns the uid of every node of a debug block, which helps inspecting which nodes
+// a query matched. Combine it with Query.WithDebug to also receive latency information.
+//
+// Parameters:
+//   - criteria: The root criteria of the query block, either a Criteria such as Has("user")
+//     or a string.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewDebugBlock(Has("user"))
+//	fmt.Println(queryBlock.String()) // Output: debug (func: has(user)) { }
+//
+// See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#debug
+func NewDebugBlock(criteria any) *QueryBlock {
+	return NewQueryBlock("debug", criteria)
+}
+
 // WithCriteria adds one or more criteria to the query block.
 //
 // Parameters:
-//   - criteria: One or more criteria to add to the query block.
+//   - criteria: One or more criteria to add to the query block, either Criteria or strings.
 //
 // Returns:
 //   - The updated QueryBlock object.
@@ -57,18 +86,89 @@ func NewQueryBlock(name string, criteria string) *QueryBlock {
 //	queryBlock := NewQueryBlock("getUser", "has(user)").
 //	    WithCriteria("orderasc: name@en")
 //	fmt.Println(queryBlock.String()) // Output: getUser(func: has(user), orderasc: name@en) { }
-func (qb *QueryBlock) WithCriteria(criteria ...string) *QueryBlock {
+func (qb *QueryBlock) WithCriteria(criteria ...any) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
 	for _, c := range criteria {
-		qb.Criteria = append(qb.Criteria, c)
+		qb.Criteria = append(qb.Criteria, toCriteria(c))
 	}
 	return qb
 }
 
 
+// WithFirst limits the query block to the first n results.
+//
+// Parameters:
+//   - n: The number of results, an int or a ParamRef bound when the query is executed.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("getUser", "has(user)").
+//	    WithOrderAsc("name").
+//	    WithFirst(10)
+//	fmt.Println(queryBlock.String()) // Output: getUser (func: has(user), orderasc: name, first: 10) { }
+//
+//	// The page size is bound when the query is executed.
+//	query := NewQuery("GetUsers", NewQueryBlock("users", Has("user")).WithFirst(ParamRef("first"))).
+//	    WithParam(NewParam("first", ParamInt))
+func (qb *QueryBlock) WithFirst(n any) *QueryBlock {
+	return qb.WithCriteria(NewArg("first", n))
+}
+
+// WithOffset skips the first n results of the query block.
+//
+// Parameters:
+//   - n: The number of results to skip, an int or a ParamRef.
+//
+// Returns:
+//   - The updated QueryBlock object.
+func (qb *QueryBlock) WithOffset(n any) *QueryBlock {
+	return qb.WithCriteria(NewArg("offset", n))
+}
+
+// WithAfter starts the results of the query block after the given uid.
+//
+// Parameters:
+//   - uid: The uid after which results start, a string, a UID or a ParamRef.
+//
+// Returns:
+//   - The updated QueryBlock object.
+func (qb *QueryBlock) WithAfter(uid any) *QueryBlock {
+	return qb.WithCriteria(NewArg("after", uid))
+}
+
+// WithOrderAsc orders the results of the query block by a predicate in ascending order.
+//
+// Calling WithOrderAsc and WithOrderDesc several times adds secondary sort keys.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated QueryBlock object.
+func (qb *QueryBlock) WithOrderAsc(predicate string) *QueryBlock {
+	return qb.WithCriteria(NewArg("orderasc", predicate))
+}
+
+// WithOrderDesc orders the results of the query block by a predicate in descending order.
+//
+// Calling WithOrderAsc and WithOrderDesc several times adds secondary sort keys.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated QueryBlock object.
+func (qb *QueryBlock) WithOrderDesc(predicate string) *QueryBlock {
+	return qb.WithCriteria(NewArg("orderdesc", predicate))
+}
+
 // WithDirectives adds one or more directives to the query block.
 //
 // Parameters:
-//   - directives: One or more directives to add to the query block.
+//   - directives: One or more directives, either Directive objects or strings, to add to the query block.
 //
 // Returns:
 //   - The updated QueryBlock object.
@@ -78,9 +178,10 @@ func (qb *QueryBlock) WithCriteria(criteria ...string) *QueryBlock {
 //	queryBlock := NewQueryBlock("getUser", "has(user)").
 //	    WithDirectives("@filter(eq(name, \"John\"))")
 //	fmt.Println(queryBlock.String()) // Output: getUser(func: has(user)) @filter(eq(name, "John")) { }
-func (qb *QueryBlock) WithDirectives(directives ...string) *QueryBlock {
+func (qb *QueryBlock) WithDirectives(directives ...any) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
 	for _, d := range directives {
-		qb.Directives = append(qb.Directives, d)
+		qb.Directives = append(qb.Directives, toCriteria(d))
 	}
 	return qb
 }
@@ -99,6 +200,7 @@ func (qb *QueryBlock) WithDirectives(directives ...string) *QueryBlock {
 //	    WithAttributes(NewAttribute("name"), NewAttribute("age"))
 //	fmt.Println(queryBlock.String()) // Output: getUser(func: has(user)) { name age }
 func (qb *QueryBlock) WithAttributes(attrs ...*Attribute) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
 	for _, a := range attrs {
 		qb.Attributes = append(qb.Attributes, a)
 	}
@@ -112,9 +214,12 @@ func (qb *QueryBlock) WithAttributes(attrs ...*Attribute) *QueryBlock {
 // Returns:
 //   - A string representation of the query block.
 func (qb *QueryBlock) String() string {
-	components := []string{qb.Name, fmt.Sprintf("(func: %s)", strings.Join(qb.Criteria, ", "))}
+	if qb.Raw {
+		return qb.Name
+	}
+	components := []string{qb.Name, blockArguments(qb.Criteria)}
 	for _, f := range qb.Directives {
-		components = append(components, f)
+		components = append(components, f.String())
 	}
 	components = append(components, "{")
 	for _, attr := range qb.Attributes {
@@ -124,3 +229,40 @@ func (qb *QueryBlock) String() string {
 
 	return strings.Join(components, " ")
 }
+
+// Validate checks the query block and its attributes.
+//
+// Pagination arguments are checked as well: a negative offset, first: 0 or an after value
+// that is not a uid fail here rather than as a Dgraph error.
+//
+// Directives must be placed where Dgraph accepts them, e.g. @recurse only on blocks and
+// @facets only on attributes, and count() and len() expressions only as the first argument of
+// a comparison such as ge(count(friend), 3).
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the query block is valid.
+func (qb *QueryBlock) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if qb.Raw {
+		return nil
+	}
+	if err := validateName("block name", qb.Name); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	if err := validatePagination(qb.Criteria); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	if err := validateDirectives(qb.Directives, true, false); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	if err := validateUids(append(append([]Criteria{}, qb.Criteria...), qb.Directives...)); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	if err := validateCounts(append(append([]Criteria{}, qb.Criteria...), qb.Directives...)); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	if err := validateAttributes(qb.Attributes); err != nil {
+		return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		other *Query
+		want  string
+	}{
+		{"query block", NewQuery("", NewQueryBlock("posts", "has(post)")),
+			"query Q ( $first: int ) { users (func: has(user)) { ...fields } posts (func: has(post)) { } } fragment fields { name }"},
+		{"same param and fragment", NewQuery("", NewQueryBlock("posts", "has(post)")).
+			WithParam(NewParam("$first", "int")).
+			WithFragments(NewFragment("fields").WithAttributes(NewAttribute("name"))),
+			"query Q ( $first: int ) { users (func: has(user)) { ...fields } posts (func: has(post)) { } } fragment fields { name }"},
+		{"var block and fragment", NewQuery("", NewQueryBlock("posts", "uid(p)")).
+			WithVarBlocks(NewVarBlock("has(post)").WithName("p")).
+			WithFragments(NewFragment("postFields").WithAttributes(NewAttribute("title"))),
+			"query Q ( $first: int ) { p AS var (func: has(post)) { } users (func: has(user)) { ...fields } posts (func: uid(p)) { } } fragment fields { name } fragment postFields { title }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newMergeTarget()
+			if err := q.Merge(tt.other); err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// newMergeTarget returns the query other queries are merged into.
+func newMergeTarget() *Query {
+	return NewQuery("Q", NewQueryBlock("users", "has(user)").WithAttributes(NewAttribute("...fields"))).
+		WithParam(NewParam("$first", "int")).
+		WithFragments(NewFragment("fields").WithAttributes(NewAttribute("name")))
+}
+
+func TestMergeConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		other *Query
+	}{
+		{"query block", NewQuery("", NewQueryBlock("users", "has(person)"))},
+		{"param", NewQuery("", NewQueryBlock("posts", "has(post)")).WithParam(NewParam("$first", "string"))},
+		{"fragment", NewQuery("", NewQueryBlock("posts", "has(post)")).
+			WithFragments(NewFragment("fields").WithAttributes(NewAttribute("title")))},
+		{"attribute variable", NewQuery("", NewQueryBlock("posts", "has(post)").
+			WithAttributes(NewAttribute("title").WithVar("n")))},
+		{"shortest path", NewQuery("", NewQueryBlock("posts", "uid(n)")).
+			WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("n"))},
+		{"var block", NewQuery("", NewQueryBlock("posts", "uid(n)")).
+			WithVarBlocks(NewVarBlock("has(post)").WithName("n"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newMergeTarget()
+			q.QueryBlocks[0].WithAttributes(NewAttribute("name").WithVar("n"))
+			want := q.String()
+			if err := q.Merge(tt.other); err == nil {
+				t.Errorf("Merge() = nil, want a conflict")
+			}
+			if got := q.String(); got != want {
+				t.Errorf("String() after a failed merge = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMergeVarBlockConflict(t *testing.T) {
+	q := newMergeTarget().WithVarBlocks(NewVarBlock("has(post)").WithName("p"))
+	other := NewQuery("", NewQueryBlock("posts", "uid(p)")).WithVarBlocks(NewVarBlock("has(comment)").WithName("p"))
+	if err := q.Merge(other); err == nil {
+		t.Errorf("Merge() = nil, want a conflict")
+	}
+}
+
+// TestMergeCopies checks that the merged query does not share its blocks with the other query.
+func TestMergeCopies(t *testing.T) {
+	q := newMergeTarget()
+	other := NewQuery("", NewQueryBlock("posts", "has(post)").WithAttributes(NewAttribute("title")))
+	if err := q.Merge(other); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	want := q.String()
+	other.QueryBlocks[0].WithAttributes(NewAttribute("body"))
+	other.QueryBlocks[0].Name = "drafts"
+	if got := q.String(); got != want {
+		t.Errorf("String() after changing the other query = %s, want %s", got, want)
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"valid", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(
+			NewAttribute("count(friend)").WithAlias("friends"), NewAttribute("name"))), ""},
+		{"duplicate alias", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(
+			NewAttribute("count(friend)").WithAlias("total"), NewAttribute("count(follower)").WithAlias("total"))),
+			`dql: query block "me": duplicate alias "total"`},
+		{"alias of attribute", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(
+			NewAttribute("name"), NewAttribute("nickname").WithAlias("name"))),
+			`dql: query block "me": alias "name" conflicts with attribute "name"`},
+		{"nested", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(NewAttribute("friend").WithAttributes(
+			NewAttribute("a").WithAlias("x"), NewAttribute("b").WithAlias("x")))),
+			`dql: query block "me": friend: duplicate alias "x"`},
+		{"fragment", NewQuery("", NewQueryBlock("me", "has(user)")).WithFragments(NewFragment("f").WithAttributes(
+			NewAttribute("a").WithAlias("x"), NewAttribute("b").WithAlias("x"))),
+			`duplicate alias "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDebug(t *testing.T) {
+	q := NewQuery("", NewDebugBlock(Has("user")).WithAttributes(NewAttribute("name")))
+	if got, want := q.String(), "{ debug (func: has(user)) { name } }"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if q.Debug {
+		t.Errorf("Debug = true before WithDebug")
+	}
+	if !q.WithDebug().Debug {
+		t.Errorf("Debug = false after WithDebug")
+	}
+	if got := q.String(); strings.Contains(got, "debug=") {
+		t.Errorf("String() = %s, want the debug flag left to the executor", got)
+	}
+}
@@ -0,0 +1,79 @@
+package dql
+
+// Raw is a piece of DQL that is rendered verbatim.
+//
+// Raw is the escape hatch for syntax the typed builders do not cover yet. It can be used
+// wherever criteria are accepted, and NewRawAttribute, NewRawQueryBlock and NewRawVarBlock
+// wrap raw text into attributes and blocks. Raw nodes are marked as such in the AST, so
+// validation skips them and callers can find them to migrate them to typed builders.
+//
+// Example:
+//
+//	criteria := Raw("has(user)")
+//	fmt.Println(criteria.String()) // Output: has(user)
+type Raw string
+
+// String returns the raw DQL text.
+//
+// Returns:
+//   - The raw DQL text.
+func (r Raw) String() string {
+	return string(r)
+}
+
+// NewRawAttribute creates an Attribute that is rendered verbatim.
+//
+// Parameters:
+//   - text: The DQL text of the attribute, including any directives and nested attributes.
+//
+// Returns:
+//   - A pointer to an Attribute object marked as raw.
+//
+// Example:
+//
+//	attr := NewRawAttribute("friend @facets(since) { name }")
+//	fmt.Println(attr.String()) // Output: friend @facets(since) { name }
+func NewRawAttribute(text string) *Attribute {
+	return &Attribute{
+		Name: text,
+		Raw:  true,
+	}
+}
+
+// NewRawQueryBlock creates a QueryBlock that is rendered verbatim.
+//
+// Parameters:
+//   - text: The DQL text of the whole query block.
+//
+// Returns:
+//   - A pointer to a QueryBlock object marked as raw.
+//
+// Example:
+//
+//	queryBlock := NewRawQueryBlock("me(func: uid(0x1)) { name }")
+//	fmt.Println(queryBlock.String()) // Output: me(func: uid(0x1)) { name }
+func NewRawQueryBlock(text string) *QueryBlock {
+	return &QueryBlock{
+		Name: text,
+		Raw:  true,
+	}
+}
+
+// NewRawVarBlock creates a VarBlock that is rendered verbatim.
+//
+// Parameters:
+//   - text: The DQL text of the whole variable block.
+//
+// Returns:
+//   - A pointer to a VarBlock object marked as raw.
+//
+// Example:
+//
+//	varBlock := NewRawVarBlock("var(func: has(user)) { a as count(friend) }")
+//	fmt.Println(varBlock.String()) // Output: var(func: has(user)) { a as count(friend) }
+func NewRawVarBlock(text string) *VarBlock {
+	return &VarBlock{
+		Name: text,
+		Raw:  true,
+	}
+}
@@ -0,0 +1,29 @@
+package dql
+
+import "testing"
+
+func TestRaw(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"attribute", NewQuery("", NewQueryBlock("me", "has(user)").WithAttributes(
+			NewAttribute("name"), NewRawAttribute(`friend @facets(close) { name }`))),
+			"{ me (func: has(user)) { name friend @facets(close) { name } } }"},
+		{"query block", NewQuery("", NewRawQueryBlock(`me(func: has(user)) @cascade { name }`)),
+			"{ me(func: has(user)) @cascade { name } }"},
+		{"var block", NewQuery("", NewQueryBlock("me", "uid(f)")).WithVarBlocks(NewRawVarBlock(`var(func: has(friend)) { f as friend }`)),
+			"{ var(func: has(friend)) { f as friend } me (func: uid(f)) { } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if err := tt.q.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
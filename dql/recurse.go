@@ -0,0 +1,53 @@
+package dql
+
+// NewRecurseBlock creates a QueryBlock traversing the graph recursively with @recurse.
+//
+// Dgraph requires the predicates of a recursive block to be listed flat: every edge to follow
+// and every scalar to return is given once at the top of the block and applies at every level
+// of the traversal. NewRecurseBlock builds that flat selection from the list of predicates.
+//
+// Parameters:
+//   - name: The name of the query block.
+//   - root: The root criteria of the query block, either a Criteria such as Uid("0x1") or a string.
+//   - predicates: The edges to follow and the scalar predicates to return at every level.
+//   - depth: The maximum depth of the traversal, or 0 to let Dgraph traverse until no new
+//     nodes are found.
+//
+// Returns:
+//   - A pointer to a QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewRecurseBlock("recurse", Uid("0x1"), []string{"name", "friend"}, 3)
+//	fmt.Println(queryBlock.String()) // Output: recurse (func: uid(0x1)) @recurse(depth: 3) { name friend }
+//
+// See: https://dgraph.io/docs/query-language/recurse-query/
+func NewRecurseBlock(name string, root any, predicates []string, depth int) *QueryBlock {
+	directive := NewDirective("recurse")
+	if depth > 0 {
+		directive.Args = append(directive.Args, NewArg("depth", depth))
+	}
+	qb := NewQueryBlock(name, root).WithDirectives(directive)
+	for _, p := range predicates {
+		qb.WithAttributes(NewAttribute(p))
+	}
+	return qb
+}
+
+// RecurseQuery creates a Query made of a single recursive block named recurse.
+//
+// Parameters:
+//   - root: The root criteria of the traversal, either a Criteria such as Uid("0x1") or a string.
+//   - predicates: The edges to follow and the scalar predicates to return at every level.
+//   - depth: The maximum depth of the traversal, or 0 for no limit.
+//
+// Returns:
+//   - A pointer to a Query object.
+//
+// Example:
+//
+//	query := RecurseQuery(Uid("0x1"), []string{"name", "friend"}, 3)
+//	fmt.Println(query.String()) // Output: { recurse (func: uid(0x1)) @recurse(depth: 3) { name friend } }
+func RecurseQuery(root Criteria, predicates []string, depth int) *Query {
+	return NewQuery("", NewRecurseBlock("recurse", root, predicates, depth))
+}
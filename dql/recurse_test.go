@@ -0,0 +1,25 @@
+package dql
+
+import "testing"
+
+func TestRecurse(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"depth", RecurseQuery(Uid("0x1"), []string{"friend", "name"}, 3),
+			"{ recurse (func: uid(0x1)) @recurse(depth: 3) { friend name } }"},
+		{"unbounded", RecurseQuery(Has("parent"), []string{"parent"}, 0),
+			"{ recurse (func: has(parent)) @recurse { parent } }"},
+		{"block", NewQuery("", NewRecurseBlock("tree", "uid(0x2)", []string{"child"}, 2)),
+			"{ tree (func: uid(0x2)) @recurse(depth: 2) { child } }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
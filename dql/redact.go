@@ -0,0 +1,165 @@
+package dql
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces redacted string literals.
+const redactedValue = "***"
+
+// Redact replaces every string and regular expression literal of a DQL query with "***".
+//
+// It lets queries be logged without leaking the values they look up, such as names or email
+// addresses. The query is parsed, see Parse, so only literal values are redacted, including
+// those of raw criteria and directives, while uids, numbers and the structure of the query
+// are kept.
+//
+// Parameters:
+//   - query: The DQL query text.
+//
+// Returns:
+//   - The query with its literals redacted.
+//   - An error if the query cannot be parsed.
+//
+// Example:
+//
+//	redacted, err := Redact(`{ me(func: eq(email, "alice@example.com")) @filter(regexp(name, /^Al.*$/i)) { name } }`)
+//	fmt.Println(redacted) // Output: { me (func: eq(email, "***")) @filter(regexp(name, /***/i)) { name } }
+func Redact(query string) (string, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return "", err
+	}
+	return q.Redacted(), nil
+}
+
+// Redacted renders the query with its string and regular expression literals redacted, see
+// Redact.
+//
+// Literal values, raw criteria, directives, blocks and attributes and the string defaults of
+// parameters are redacted.
+//
+// Returns:
+//   - A string representation of the query safe for logging.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Eq("email", "alice@example.com")))
+//	fmt.Println(query.Redacted()) // Output: { me (func: eq(email, "***")) { } }
+func (q *Query) Redacted() string {
+	res := q.Clone()
+	for _, p := range res.Params {
+		if p.Type == ParamString && p.Default != "" {
+			p.Default = redactedValue
+		}
+	}
+	Walk(res, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			if n.Raw {
+				n.Name = redactRaw(n.Name)
+			}
+		case *QueryBlock:
+			if n.Raw {
+				n.Name = redactRaw(n.Name)
+			}
+		case *Attribute:
+			if n.Raw {
+				n.Name = redactRaw(n.Name)
+			}
+		}
+		return true
+	})
+	for _, list := range criteriaLists(res) {
+		mapCriteriaList(list, redactCriteria)
+	}
+	return res.String()
+}
+
+// redactCriteria redacts a literal rendered as a string, or the literals of raw DQL.
+func redactCriteria(c Criteria) Criteria {
+	switch c := c.(type) {
+	case Literal:
+		if r, ok := c.Value.(regex); ok {
+			return Literal{regex{redactedValue, r.flags}}
+		}
+		if strings.HasPrefix(c.String(), `"`) {
+			return Literal{redactedValue}
+		}
+	case Raw:
+		return Raw(redactRaw(string(c)))
+	}
+	return c
+}
+
+// redactRaw replaces the string and regular expression literals of raw DQL, found by
+// scanRawLiterals, keeping the flags of regular expressions.
+func redactRaw(text string) string {
+	var b strings.Builder
+	last := 0
+	scanRawLiterals(text, func(start, end int) bool {
+		if c := text[start]; c == '"' || c == '/' {
+			b.WriteString(text[last:start])
+			b.WriteByte(c)
+			b.WriteString(redactedValue)
+			b.WriteByte(c)
+			last = end
+		}
+		return true
+	})
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// LoggingMiddleware creates a Middleware logging every execution of a query with its
+// literals redacted, see Query.Redacted.
+//
+// Each execution is logged at the debug level, or at the error level if it failed, with the
+// name, the redacted text and the shape fingerprint of the query, the names of its variables
+// but not their values, the duration and the error.
+//
+// Parameters:
+//   - logger: The logger receiving the records.
+//
+// Returns:
+//   - A Middleware.
+//
+// Example:
+//
+//	exec := Chain(client, LoggingMiddleware(slog.Default()))
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Execute(ctx, q, vars)
+			level := slog.LevelDebug
+			if err != nil {
+				level = slog.LevelError
+			}
+			if !logger.Enabled(ctx, level) {
+				return resp, err
+			}
+			names := make([]string, 0, len(vars))
+			for name := range vars {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			attrs := []slog.Attr{
+				slog.String("name", MetricsName(q)),
+				slog.String("query", q.Redacted()),
+				slog.String("fingerprint", q.ShapeFingerprint()),
+				slog.Any("vars", names),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogAttrs(ctx, level, "dql query", attrs...)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package dql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"string", `{ me(func: eq(email, "alice@example.com")) { name } }`,
+			`{ me (func: eq(email, "***")) { name } }`},
+		{"escaped quote", `{ me(func: eq(name, "Al\"ice")) { name } }`,
+			`{ me (func: eq(name, "***")) { name } }`},
+		{"regexp", `{ me(func: has(name)) @filter(regexp(name, /^Al"(ice/i)) { name } }`,
+			`{ me (func: has(name)) @filter(regexp(name, /***/i)) { name } }`},
+		{"numbers and uids", `{ me(func: uid(0x1), first: 10) @filter(gt(age, 30)) { name } }`,
+			`{ me (func: uid(0x1), first: 10) @filter(gt(age, 30)) { name } }`},
+		{"param default", `query Q($name: string = "Alice", $first: int = 10) { me(func: eq(name, $name), first: $first) { name } }`,
+			`query Q ( $name: string = "***", $first: int = 10 ) { me (func: eq(name, $name), first: $first) { name } }`},
+		{"attribute filter", `{ me(func: has(name)) { friend @filter(anyofterms(name, "Bob Carol")) { name } } }`,
+			`{ me (func: has(name)) { friend @filter(anyofterms(name, "***")) { name } } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Redact(tt.src)
+			if err != nil {
+				t.Fatalf("Redact() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if _, err := Redact(`{ me(func: eq(name, "Alice")`); !errors.Is(err, ErrSyntax) {
+		t.Errorf("Redact() of a malformed query error = %v, want ErrSyntax", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"literal", NewQuery("", NewQueryBlock("me", Eq("email", "alice@example.com"))),
+			`{ me (func: eq(email, "***")) { } }`},
+		{"regexp", NewQuery("", NewQueryBlock("me", Regexp("name", "^Al/ice", "i"))),
+			`{ me (func: regexp(name, /***/i)) { } }`},
+		{"number", NewQuery("", NewQueryBlock("me", Ge("age", 18)).WithFirst(10)),
+			`{ me (func: ge(age, 18), first: 10) { } }`},
+		{"raw directive", NewQuery("", NewQueryBlock("me", Has("name")).WithDirectives(`@filter(eq(name, "Alice"))`)),
+			`{ me (func: has(name)) @filter(eq(name, "***")) { } }`},
+		{"raw block", NewQuery("", NewRawQueryBlock(`me(func: eq(name, "Alice")) { name }`)),
+			`{ me(func: eq(name, "***")) { name } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.q.String()
+			if got := tt.q.Redacted(); got != tt.want {
+				t.Errorf("Redacted() = %s, want %s", got, tt.want)
+			}
+			if got := tt.q.String(); got != want {
+				t.Errorf("String() after Redacted() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	errFailed := errors.New("failed")
+	next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		return nil, errFailed
+	})
+	exec := Chain(next, LoggingMiddleware(logger))
+	q := NewQuery("UserByEmail", NewQueryBlock("me", Eq("email", "alice@example.com")))
+	if _, err := exec.Execute(context.Background(), q, map[string]string{"$token": "s3cr3t"}); !errors.Is(err, errFailed) {
+		t.Fatalf("Execute() error = %v, want %v", err, errFailed)
+	}
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "name=UserByEmail", `eq(email, \"***\")`, "vars=[$token]", "error=failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	for _, leak := range []string{"alice@example.com", "s3cr3t"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("log %q leaks %q", out, leak)
+		}
+	}
+}
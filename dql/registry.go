@@ -0,0 +1,151 @@
+package dql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds named queries registered at startup and executed by name, like prepared
+// statements, so the queries a service runs are reviewed in one place and cannot drift.
+//
+// Queries are validated and fingerprinted once, when registered. Registry is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	queries map[string]*registeredQuery
+}
+
+// registeredQuery is a query held by a Registry.
+type registeredQuery struct {
+	query       *Query
+	fingerprint string
+}
+
+// NewRegistry creates a new, empty Registry.
+//
+// Returns:
+//   - A pointer to a Registry object.
+//
+// Example:
+//
+//	registry := NewRegistry()
+//	registry.MustRegister("userByEmail", NewQuery("UserByEmail",
+//	    NewQueryBlock("user", Eq("email", ParamRef("email"))).WithAttributes(NewAttribute("name")),
+//	).WithParam(NewParam("email", ParamString)))
+//	resp, err := registry.Execute(ctx, client, "userByEmail", map[string]string{"$email": "alice@example.com"})
+func NewRegistry() *Registry {
+	return &Registry{queries: map[string]*registeredQuery{}}
+}
+
+// Register validates a query and registers it under a name.
+//
+// The registry keeps a copy of the query, so modifying it afterwards has no effect.
+//
+// Parameters:
+//   - name: The name of the query.
+//   - q: The query.
+//
+// Returns:
+//   - An error if the name is already registered or the query is invalid.
+func (r *Registry) Register(name string, q *Query) error {
+	if err := q.Validate(); err != nil {
+		return fmt.Errorf("dql: register %q: %w", name, err)
+	}
+	q = q.Clone()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.queries[name]; ok {
+		return fmt.Errorf("dql: register %q: already registered", name)
+	}
+	r.queries[name] = &registeredQuery{query: q, fingerprint: q.Fingerprint()}
+	return nil
+}
+
+// MustRegister is like Register but panics on error, for registrations at startup.
+//
+// Parameters:
+//   - name: The name of the query.
+//   - q: The query.
+func (r *Registry) MustRegister(name string, q *Query) {
+	if err := r.Register(name, q); err != nil {
+		panic(err)
+	}
+}
+
+// Query returns a copy of a registered query.
+//
+// Parameters:
+//   - name: The name of the query.
+//
+// Returns:
+//   - A copy of the query, or nil if the name is not registered.
+func (r *Registry) Query(name string) *Query {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rq, ok := r.queries[name]; ok {
+		return rq.query.Clone()
+	}
+	return nil
+}
+
+// Fingerprint returns the fingerprint of a registered query, see Query.Fingerprint.
+//
+// Parameters:
+//   - name: The name of the query.
+//
+// Returns:
+//   - The fingerprint, or an empty string if the name is not registered.
+func (r *Registry) Fingerprint(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rq, ok := r.queries[name]; ok {
+		return rq.fingerprint
+	}
+	return ""
+}
+
+// Names returns the names of the registered queries.
+//
+// Returns:
+//   - The sorted names.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Execute runs a registered query with variables.
+//
+// The variables must match the parameters the query declares: unknown variables, values not
+// suiting the type of their parameter and missing values of parameters without default are
+// reported before the query is executed, see Query.ValidateVars.
+//
+// Parameters:
+//   - ctx: The context of the execution.
+//   - exec: The Executor running the query.
+//   - name: The name of the query.
+//   - vars: The variables, given by name with their leading $.
+//
+// Returns:
+//   - The response of the query.
+//   - An error if the name is not registered, the variables do not match or the execution
+//     failed.
+func (r *Registry) Execute(ctx context.Context, exec Executor, name string, vars map[string]string) (*Response, error) {
+	r.mu.RLock()
+	rq, ok := r.queries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("dql: query %q is not registered", name)
+	}
+	if err := rq.query.validateVars(vars); err != nil {
+		return nil, fmt.Errorf("dql: query %q: %w", name, err)
+	}
+	return exec.Execute(ctx, rq.query.Clone(), vars)
+}
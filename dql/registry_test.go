@@ -0,0 +1,91 @@
+package dql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// newUserRegistry returns a registry with a parameterized query and a query without params.
+func newUserRegistry() *Registry {
+	r := NewRegistry()
+	r.MustRegister("getUser", NewQuery("GetUser", NewQueryBlock("me", Eq("name", ParamRef("name"))).WithFirst(ParamRef("first"))).
+		WithParam(NewParam("name", ParamString), NewParam("first", ParamInt).WithDefault("10")))
+	r.MustRegister("allUsers", NewQuery("", NewQueryBlock("users", Has("user"))))
+	return r
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := newUserRegistry()
+	if got, want := r.Names(), []string{"allUsers", "getUser"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if got, want := r.Fingerprint("getUser"), r.Query("getUser").Fingerprint(); got != want || got == "" {
+		t.Errorf("Fingerprint() = %q, want %q", got, want)
+	}
+	if r.Query("missing") != nil || r.Fingerprint("missing") != "" {
+		t.Error("Query() and Fingerprint() of an unregistered query are not empty")
+	}
+
+	r.Query("getUser").QueryBlocks[0].WithAttributes(NewAttribute("secret"))
+	if got := r.Query("getUser").QueryBlocks[0].Attributes; len(got) != 0 {
+		t.Errorf("Query() shares the registered query")
+	}
+
+	tests := []struct {
+		name     string
+		register string
+		query    *Query
+		wantErr  string
+	}{
+		{"duplicate", "allUsers", NewQuery("", NewQueryBlock("users", Has("user"))), `dql: register "allUsers": already registered`},
+		{"invalid", "broken", NewQuery("", NewQueryBlock("users", Uid("undefined"))), `dql: register "broken": dql: undefined variable "undefined"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(r.Register(tt.register, tt.query)); got != tt.wantErr {
+				t.Errorf("Register() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister() of a duplicate did not panic")
+		}
+	}()
+	r.MustRegister("allUsers", NewQuery("", NewQueryBlock("users", Has("user"))))
+}
+
+func TestRegistryExecute(t *testing.T) {
+	r := newUserRegistry()
+	var executed *Query
+	exec := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		executed = q
+		return &Response{}, nil
+	})
+	tests := []struct {
+		name    string
+		query   string
+		vars    map[string]string
+		wantErr string
+	}{
+		{"all variables", "getUser", map[string]string{"$name": "Alice", "$first": "5"}, ""},
+		{"default value", "getUser", map[string]string{"$name": "Alice"}, ""},
+		{"missing variable", "getUser", map[string]string{"$first": "5"}, `dql: query "getUser": missing variable "$name"`},
+		{"mistyped variable", "getUser", map[string]string{"$name": "Alice", "$first": "five"}, `dql: query "getUser": variable "$first": "five" is not an int`},
+		{"undeclared variable", "allUsers", map[string]string{"$name": "Alice"}, `dql: query "allUsers": undeclared variable "$name"`},
+		{"unregistered", "missing", nil, `dql: query "missing" is not registered`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed = nil
+			_, err := r.Execute(context.Background(), exec, tt.query, tt.vars)
+			if got := errString(err); got != tt.wantErr {
+				t.Fatalf("Execute() error = %q, want %q", got, tt.wantErr)
+			}
+			if (executed != nil) != (tt.wantErr == "") {
+				t.Errorf("executed = %v, want a query only without error", executed)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"time"
+)
+
+// RetryPolicy decides whether and when failed executions are retried, see Retry and
+// RetryMiddleware.
+type RetryPolicy interface {
+	// Backoff returns the delay to wait before retrying after a failed attempt, numbered
+	// from 1, and false if the execution must not be retried, e.g. because the error is not
+	// transient or the attempts are exhausted.
+	Backoff(attempt int, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy doubling, or multiplying by Multiplier, the delay
+// between attempts, with random jitter so that clients failing together do not retry
+// together.
+type ExponentialBackoff struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+
+	// Max caps the delay between attempts, unless zero.
+	Max time.Duration
+
+	// Multiplier is the factor applied to the delay after each attempt, 2 if zero.
+	Multiplier float64
+
+	// Jitter is the fraction of the delay randomly taken off it, between 0 and 1.
+	Jitter float64
+
+	// Retryable reports whether an error is worth retrying, IsTransient if nil.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy creates the default RetryPolicy: up to 4 attempts of the transient errors
+// reported by IsTransient, 50ms apart at first, doubling up to 2s, with 50% jitter.
+//
+// Returns:
+//   - A pointer to an ExponentialBackoff object.
+func DefaultRetryPolicy() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxAttempts: 4,
+		Initial:     50 * time.Millisecond,
+		Max:         2 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.5,
+	}
+}
+
+// Backoff implements RetryPolicy.
+//
+// Parameters:
+//   - attempt: The number of the failed attempt, starting at 1.
+//   - err: The error of the failed attempt.
+//
+// Returns:
+//   - The delay before the next attempt.
+//   - False if the error is not retryable or the attempts are exhausted.
+func (b *ExponentialBackoff) Backoff(attempt int, err error) (time.Duration, bool) {
+	retryable := b.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+	if attempt >= b.MaxAttempts || !retryable(err) {
+		return 0, false
+	}
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	delay -= delay * b.Jitter * rand.Float64()
+	return time.Duration(delay), true
+}
+
+// IsTransient reports whether an error is transient, so that retrying the execution may
+// succeed: network errors, and errors reporting themselves as transient with a
+// Transient() bool method, such as the transaction aborts and unavailable Alphas reported by
+// dqlhttp.Client. Canceled and expired contexts are never transient.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - True if the error is transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var t interface{ Transient() bool }
+	if errors.As(err, &t) {
+		return t.Transient()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Retry runs an operation until it succeeds or the policy gives up, waiting between attempts
+// as the policy decides. The wait stops early when ctx is done, returning the error of the
+// last attempt.
+//
+// Parameters:
+//   - ctx: The context of the operation.
+//   - policy: The RetryPolicy, or nil to run the operation once.
+//   - op: The operation.
+//
+// Returns:
+//   - The error of the last attempt, or nil if an attempt succeeded.
+//
+// Example:
+//
+//	err := Retry(ctx, DefaultRetryPolicy(), func(ctx context.Context) error {
+//	    _, err := client.Mutate(ctx, mutation)
+//	    return err
+//	})
+func Retry(ctx context.Context, policy RetryPolicy, op func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil || policy == nil {
+			return err
+		}
+		delay, ok := policy.Backoff(attempt, err)
+		if !ok {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryMiddleware creates a Middleware retrying the queries whose execution fails, as the
+// policy decides, see Retry.
+//
+// Parameters:
+//   - policy: The RetryPolicy, e.g. DefaultRetryPolicy().
+//
+// Returns:
+//   - A Middleware.
+//
+// Example:
+//
+//	exec := Chain(client, RetryMiddleware(DefaultRetryPolicy()))
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+			var resp *Response
+			err := Retry(ctx, policy, func(ctx context.Context) error {
+				var err error
+				resp, err = next.Execute(ctx, q, vars)
+				return err
+			})
+			return resp, err
+		})
+	}
+}
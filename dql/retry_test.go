@@ -0,0 +1,110 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// transientError is an error reporting whether it is transient.
+type transientError bool
+
+func (e transientError) Error() string   { return "transient" }
+func (e transientError) Transient() bool { return bool(e) }
+
+// errBoom is a permanent error.
+var errBoom = errors.New("boom")
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errBoom, false},
+		{"transient", transientError(true), true},
+		{"wrapped permanent", &Error{Kind: ErrExec, Err: transientError(false)}, false},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("refused")}, true},
+		{"canceled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := &ExponentialBackoff{MaxAttempts: 5, Initial: 10 * time.Millisecond, Max: 30 * time.Millisecond}
+	for attempt, want := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond} {
+		if got, ok := b.Backoff(attempt+1, transientError(true)); got != want || !ok {
+			t.Errorf("Backoff(%d) = %v, %v, want %v, true", attempt+1, got, ok, want)
+		}
+	}
+	if _, ok := b.Backoff(5, transientError(true)); ok {
+		t.Error("Backoff() retried past MaxAttempts")
+	}
+	if _, ok := b.Backoff(1, errors.New("boom")); ok {
+		t.Error("Backoff() retried a permanent error")
+	}
+	b.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got, _ := b.Backoff(1, transientError(true)); got < 5*time.Millisecond || got > 10*time.Millisecond {
+			t.Fatalf("Backoff() with jitter = %v, want between 5ms and 10ms", got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := &ExponentialBackoff{MaxAttempts: 3, Initial: time.Millisecond}
+	tests := []struct {
+		name     string
+		ctx      func() context.Context
+		errs     []error
+		wantErr  error
+		attempts int
+	}{
+		{"success", context.Background, []error{nil}, nil, 1},
+		{"recovers", context.Background, []error{transientError(true), transientError(true), nil}, nil, 3},
+		{"gives up", context.Background, []error{transientError(true), transientError(true), transientError(true), nil}, transientError(true), 3},
+		{"permanent", context.Background, []error{errBoom, nil}, errBoom, 1},
+		{"canceled", func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, []error{transientError(true), nil}, transientError(true), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Retry(tt.ctx(), policy, func(ctx context.Context) error {
+				attempts++
+				return tt.errs[attempts-1]
+			})
+			if err != tt.wantErr || attempts != tt.attempts {
+				t.Errorf("Retry() = %v after %d attempts, want %v after %d", err, attempts, tt.wantErr, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	attempts := 0
+	next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		if attempts++; attempts == 1 {
+			return nil, transientError(true)
+		}
+		return &Response{Json: []byte(`{}`)}, nil
+	})
+	exec := RetryMiddleware(&ExponentialBackoff{MaxAttempts: 2, Initial: time.Millisecond})(next)
+	resp, err := exec.Execute(context.Background(), NewQuery("", NewQueryBlock("me", Has("name"))), nil)
+	if err != nil || string(resp.Json) != "{}" || attempts != 2 {
+		t.Errorf("Execute() = %v, %v after %d attempts, want {} after 2", resp, err, attempts)
+	}
+}
@@ -0,0 +1,141 @@
+package dql
+
+import (
+	"context"
+	"strings"
+)
+
+// Rewriter transforms a query before it is executed, e.g. to enforce access control.
+//
+// The query passed to a Rewriter is a copy owned by the executor, which the Rewriter may
+// modify in place.
+type Rewriter func(ctx context.Context, q *Query) (*Query, error)
+
+// NewRewritingExecutor creates an Executor applying rewriters to every query before passing
+// it to another Executor.
+//
+// Each query is cloned before the rewriters run, so the queries of the callers are never
+// modified. Rewriters are applied in order; if one fails, the query is not executed. Queries
+// served by a Cache without Scope fail, since its keys would ignore the rewriting.
+//
+// Parameters:
+//   - next: The Executor running the rewritten queries.
+//   - rewriters: The rewriters applied to every query, in order.
+//
+// Returns:
+//   - An Executor.
+//
+// Example:
+//
+//	exec := NewRewritingExecutor(client, FilterRewriter(func(ctx context.Context) (any, error) {
+//	    return Eq("tenant_id", TenantFromContext(ctx)), nil
+//	}))
+func NewRewritingExecutor(next Executor, rewriters ...Rewriter) Executor {
+	return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		if unscoped, _ := ctx.Value(unscopedCacheKey{}).(bool); unscoped && len(rewriters) != 0 {
+			return nil, errUnscopedCache
+		}
+		q = q.Clone()
+		for _, rewrite := range rewriters {
+			var err error
+			if q, err = rewrite(ctx, q); err != nil {
+				return nil, err
+			}
+		}
+		return next.Execute(ctx, q, vars)
+	})
+}
+
+// FilterRewriter creates a Rewriter restricting the nodes a query can reach with a filter,
+// e.g. to isolate the data of the tenants of a multi-tenant deployment.
+//
+// The filter is added to every query and variable block, and to every reverse edge, which
+// would otherwise lead from the nodes of a tenant to the nodes of others. It is added as well
+// to every edge of shortest path blocks, so that paths only go through nodes passing the
+// filter. It is combined with AND with existing @filter directives. Raw blocks and attributes
+// are left unchanged.
+//
+// Parameters:
+//   - filter: The function returning the filter for the context of the query, either a
+//     Criteria such as Eq("tenant_id", "acme") or a string.
+//
+// Returns:
+//   - A Rewriter.
+//
+// Example:
+//
+//	rewriter := FilterRewriter(func(ctx context.Context) (any, error) {
+//	    return Eq("tenant_id", "acme"), nil
+//	})
+//	// me(func: has(user)) { ~owner { name } } becomes
+//	// me(func: has(user)) @filter(eq(tenant_id, "acme")) { ~owner @filter(eq(tenant_id, "acme")) { name } }
+func FilterRewriter(filter func(ctx context.Context) (any, error)) Rewriter {
+	return func(ctx context.Context, q *Query) (*Query, error) {
+		f, err := filter(ctx)
+		if err != nil {
+			return nil, err
+		}
+		criteria := toCriteria(f)
+		Walk(q, func(n Node) bool {
+			switch n := n.(type) {
+			case *VarBlock:
+				if !n.Raw {
+					n.Directives = andFilter(n.Directives, criteria)
+				}
+			case *QueryBlock:
+				if !n.Raw {
+					n.Directives = andFilter(n.Directives, criteria)
+				}
+			case *ShortestPath:
+				// Reverse edges are filtered when their attributes are visited.
+				for _, a := range n.Attributes {
+					if !a.Raw && !strings.HasPrefix(a.Name, "...") && !isReverseEdge(a) {
+						a.Directives = andFilter(a.Directives, criteria)
+					}
+				}
+			case *Attribute:
+				if n.Raw {
+					return false
+				}
+				if isReverseEdge(n) {
+					n.Directives = andFilter(n.Directives, criteria)
+				}
+			}
+			return true
+		})
+		return q, nil
+	}
+}
+
+// isReverseEdge reports whether an attribute follows a reverse edge, e.g. ~owner.
+func isReverseEdge(a *Attribute) bool {
+	return strings.HasPrefix(strings.TrimPrefix(a.Name, "<"), "~")
+}
+
+// andFilter combines filter with the @filter directive of directives, or adds a new @filter
+// directive if there is none. The arguments of the existing directive are combined with
+// filter into an AND Connective, so they are kept as nodes; a raw directive is kept as raw
+// DQL.
+func andFilter(directives []Criteria, filter Criteria) []Criteria {
+	for i, d := range directives {
+		if directiveName(d) != "filter" {
+			continue
+		}
+		var args []Criteria
+		if dir, ok := d.(*Directive); ok {
+			args = dir.Args
+		} else {
+			existing := strings.TrimSpace(d.String())
+			args = []Criteria{Raw(strings.TrimSuffix(strings.TrimPrefix(existing, "@filter("), ")"))}
+		}
+		if len(args) == 1 {
+			if c, ok := args[0].(*Connective); ok && c.Op == "AND" {
+				args = c.Args
+			}
+		}
+		and := &Connective{Op: "AND", Args: append(append([]Criteria{}, args...), filter)}
+		directives[i] = NewDirective("filter", and)
+		return directives
+	}
+	return append(directives, NewDirective("filter", filter))
+}
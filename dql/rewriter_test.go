@@ -0,0 +1,98 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tenantFilter is the filter of the tenant acme.
+func tenantFilter(ctx context.Context) (any, error) {
+	return Eq("tenant", "acme"), nil
+}
+
+func TestFilterRewriter(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"query block", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("name"))),
+			`{ me (func: has(user)) @filter(eq(tenant, "acme")) { name } }`},
+		{"existing filter", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Has("email")))),
+			`{ me (func: has(user)) @filter(has(email) AND eq(tenant, "acme")) { } }`},
+		{"existing raw filter", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(`@filter(has(email) OR has(phone))`)),
+			`{ me (func: has(user)) @filter((has(email) OR has(phone)) AND eq(tenant, "acme")) { } }`},
+		{"existing connective", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", And(Has("email"), Or(Has("a"), Has("b")))))),
+			`{ me (func: has(user)) @filter(has(email) AND (has(a) OR has(b)) AND eq(tenant, "acme")) { } }`},
+		{"reverse edge", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("~owner").WithAttributes(NewAttribute("name")))),
+			`{ me (func: has(user)) @filter(eq(tenant, "acme")) { ~owner @filter(eq(tenant, "acme")) { name } } }`},
+		{"var block", NewQuery("", NewQueryBlock("me", Uid("f"))).WithVarBlocks(NewVarBlock(Has("friend")).WithName("f")),
+			`{ f AS var (func: has(friend)) @filter(eq(tenant, "acme")) { } me (func: uid(f)) @filter(eq(tenant, "acme")) { } }`},
+		{"shortest path", NewQuery("", NewQueryBlock("path", Uid("p"))).
+			WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("friend"), NewAttribute("~follows"))),
+			`{ p AS shortest(from: 0x1, to: 0x2) { friend @filter(eq(tenant, "acme")) ~follows @filter(eq(tenant, "acme")) } path (func: uid(p)) @filter(eq(tenant, "acme")) { } }`},
+		{"raw block", NewQuery("", NewRawQueryBlock(`me(func: has(user)) { name }`)),
+			`{ me(func: has(user)) { name } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := FilterRewriter(tenantFilter)(context.Background(), tt.q)
+			if err != nil {
+				t.Fatalf("FilterRewriter() error = %v", err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFilterRewriterNodes checks that combined filters keep their literals and parameters
+// as nodes, so strict mode and Parameterize still see them.
+func TestFilterRewriterNodes(t *testing.T) {
+	q := NewQuery("Q", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Eq("name", ParamRef("$name"))))).
+		WithParam(NewParam("$name", ParamString))
+	q, err := FilterRewriter(tenantFilter)(context.Background(), q)
+	if err != nil {
+		t.Fatalf("FilterRewriter() error = %v", err)
+	}
+	if err := q.WithStrict().Validate(); err == nil || !strings.Contains(err.Error(), `literal "acme" in strict mode`) {
+		t.Errorf("Validate() error = %v, want the literal of the tenant filter", err)
+	}
+	got, vars := q.Parameterize()
+	want := `query Q ( $name: string, $v0: string ) { me (func: has(user)) @filter(eq(name, $name) AND eq(tenant, $v0)) { } }`
+	if got.String() != want || vars["$v0"] != "acme" {
+		t.Errorf("Parameterize() = %s, %v, want %s", got, vars, want)
+	}
+}
+
+func TestRewritingExecutor(t *testing.T) {
+	var executed *Query
+	next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+		executed = q
+		return &Response{}, nil
+	})
+	q := NewQuery("", NewQueryBlock("me", Has("user"))).Freeze()
+	want := q.String()
+	if _, err := NewRewritingExecutor(next, FilterRewriter(tenantFilter)).Execute(context.Background(), q, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := q.String(); got != want {
+		t.Errorf("caller query = %s, want %s", got, want)
+	}
+	if got, want := executed.String(), `{ me (func: has(user)) @filter(eq(tenant, "acme")) { } }`; got != want {
+		t.Errorf("executed query = %s, want %s", got, want)
+	}
+
+	errDenied := errors.New("denied")
+	failing := FilterRewriter(func(ctx context.Context) (any, error) { return nil, errDenied })
+	executed = nil
+	if _, err := NewRewritingExecutor(next, failing).Execute(context.Background(), q, nil); !errors.Is(err, errDenied) {
+		t.Errorf("Execute() error = %v, want %v", err, errDenied)
+	}
+	if executed != nil {
+		t.Errorf("query executed after a failed rewrite")
+	}
+}
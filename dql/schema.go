@@ -0,0 +1,338 @@
+package dql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Schema is a Dgraph schema: the predicates of the database and the types grouping them.
+type Schema struct {
+	// Predicates is the list of predicate definitions.
+	Predicates []*SchemaPredicate
+
+	// Types is the list of type definitions.
+	Types []*SchemaType
+}
+
+// SchemaPredicate is the definition of a predicate in a Schema.
+type SchemaPredicate struct {
+	// Name is the name of the predicate.
+	Name string
+
+	// Type is the scalar type of the predicate, such as string, int or uid.
+	Type string
+
+	// List reports whether the predicate holds a list of values, e.g. [uid].
+	List bool
+
+	// Indexes is the list of tokenizers the predicate is indexed with, such as exact or term.
+	Indexes []string
+
+	// Reverse reports whether the reverse edges of the predicate are maintained.
+	Reverse bool
+
+	// Count reports whether the number of values of the predicate is indexed.
+	Count bool
+
+	// Lang reports whether the predicate holds values tagged with a language.
+	Lang bool
+
+	// Upsert reports whether the predicate is checked for conflicts in upserts.
+	Upsert bool
+
+	// NoConflict reports whether conflict detection is disabled for the predicate, so that
+	// concurrent transactions writing it do not abort each other.
+	NoConflict bool
+}
+
+// SchemaType is the definition of a type in a Schema.
+type SchemaType struct {
+	// Name is the name of the type.
+	Name string
+
+	// Fields is the list of the predicates of the type.
+	Fields []string
+}
+
+// ParseSchema parses a Dgraph schema in the format accepted by the /alter endpoint.
+//
+// Parameters:
+//   - src: The schema text.
+//
+// Returns:
+//   - A pointer to the parsed Schema object.
+//   - An error if the schema is malformed.
+//
+// Example:
+//
+//	schema, err := ParseSchema(`
+//	    name: string @index(exact) .
+//	    friend: [uid] @reverse .
+//	    type Person {
+//	        name
+//	        friend
+//	    }
+//	`)
+//
+// See: https://dgraph.io/docs/dql/dql-schema/
+func ParseSchema(src string) (_ *Schema, err error) {
+	defer func() { err = wrapError(ErrSyntax, err) }()
+	p := &parser{src: src}
+	s := &Schema{}
+	for p.peek() != 0 {
+		if p.typeDefinition() {
+			t, err := p.schemaType()
+			if err != nil {
+				return nil, err
+			}
+			s.Types = append(s.Types, t)
+			continue
+		}
+		pred, err := p.schemaPredicate()
+		if err != nil {
+			return nil, err
+		}
+		s.Predicates = append(s.Predicates, pred)
+	}
+	return s, nil
+}
+
+// Predicate looks up a predicate definition by name.
+//
+// Parameters:
+//   - name: The name of the predicate.
+//
+// Returns:
+//   - A pointer to the SchemaPredicate object, or nil if the schema does not define it.
+func (s *Schema) Predicate(name string) *SchemaPredicate {
+	for _, p := range s.Predicates {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Type looks up a type definition by name.
+//
+// Parameters:
+//   - name: The name of the type.
+//
+// Returns:
+//   - A pointer to the SchemaType object, or nil if the schema does not define it.
+func (s *Schema) Type(name string) *SchemaType {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// String generates the schema in the format accepted by the /alter endpoint.
+//
+// Returns:
+//   - A string representation of the schema.
+func (s *Schema) String() string {
+	lines := []string{}
+	for _, p := range s.Predicates {
+		lines = append(lines, p.String())
+	}
+	for _, t := range s.Types {
+		lines = append(lines, t.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Changes lists the changes applying the schema to a database whose schema is current would
+// make, e.g. to preview a schema change before applying it.
+//
+// Applying a schema adds or updates the predicates and types it defines and leaves the others
+// untouched, so only the definitions of the schema are compared. Indexes and type fields are
+// compared regardless of their order.
+//
+// Parameters:
+//   - current: The schema of the database.
+//
+// Returns:
+//   - The list of changes, empty if applying the schema changes nothing.
+//
+// Example:
+//
+//	current, _ := ParseSchema("name: string .")
+//	schema, _ := ParseSchema("name: string @index(exact) .\nage: int .")
+//	fmt.Println(schema.Changes(current))
+//	// Output: [predicate "name": "name: string ." -> "name: string @index(exact) ." add predicate "age": "age: int ."]
+func (s *Schema) Changes(current *Schema) []string {
+	changes := []string{}
+	for _, p := range s.Predicates {
+		old := current.Predicate(p.Name)
+		switch {
+		case old == nil:
+			changes = append(changes, fmt.Sprintf("add predicate %q: %q", p.Name, p.String()))
+		case old.canonical() != p.canonical():
+			changes = append(changes, fmt.Sprintf("predicate %q: %q -> %q", p.Name, old.String(), p.String()))
+		}
+	}
+	for _, t := range s.Types {
+		old := current.Type(t.Name)
+		switch {
+		case old == nil:
+			changes = append(changes, fmt.Sprintf("add type %q: %q", t.Name, t.String()))
+		case old.canonical() != t.canonical():
+			changes = append(changes, fmt.Sprintf("type %q: %q -> %q", t.Name, old.String(), t.String()))
+		}
+	}
+	return changes
+}
+
+// String generates the definition of the predicate, e.g. name: string @index(exact) .
+//
+// Returns:
+//   - A string representation of the predicate definition.
+func (p *SchemaPredicate) String() string {
+	t := p.Type
+	if p.List {
+		t = "[" + t + "]"
+	}
+	components := []string{p.Name + ":", t}
+	if len(p.Indexes) != 0 {
+		components = append(components, "@index("+strings.Join(p.Indexes, ", ")+")")
+	}
+	if p.Reverse {
+		components = append(components, "@reverse")
+	}
+	if p.Count {
+		components = append(components, "@count")
+	}
+	if p.Lang {
+		components = append(components, "@lang")
+	}
+	if p.Upsert {
+		components = append(components, "@upsert")
+	}
+	if p.NoConflict {
+		components = append(components, "@noconflict")
+	}
+	components = append(components, ".")
+	return strings.Join(components, " ")
+}
+
+// String generates the definition of the type.
+//
+// Returns:
+//   - A string representation of the type definition.
+func (t *SchemaType) String() string {
+	components := []string{"type", t.Name, "{"}
+	components = append(components, t.Fields...)
+	components = append(components, "}")
+	return strings.Join(components, " ")
+}
+
+// canonical generates the definition of the predicate with its indexes in order, to compare
+// definitions.
+func (p *SchemaPredicate) canonical() string {
+	c := *p
+	c.Indexes = slices.Sorted(slices.Values(p.Indexes))
+	return c.String()
+}
+
+// canonical generates the definition of the type with its fields in order, to compare
+// definitions.
+func (t *SchemaType) canonical() string {
+	c := *t
+	c.Fields = slices.Sorted(slices.Values(t.Fields))
+	return c.String()
+}
+
+// schemaPredicate parses a predicate definition.
+func (p *parser) schemaPredicate() (*SchemaPredicate, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	pred := &SchemaPredicate{Name: name}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	pred.List = p.consume("[")
+	if pred.Type = p.word(); pred.Type == "" {
+		return nil, p.errorf("predicate %q: expected a type", name)
+	}
+	if pred.List {
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	directives, err := p.directives()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directives {
+		d := d.(*Directive)
+		switch d.Name {
+		case "index":
+			for _, arg := range d.Args {
+				pred.Indexes = append(pred.Indexes, splitTopLevel(arg.String())...)
+			}
+		case "reverse":
+			pred.Reverse = true
+		case "count":
+			pred.Count = true
+		case "lang":
+			pred.Lang = true
+		case "upsert":
+			pred.Upsert = true
+		case "noconflict":
+			pred.NoConflict = true
+		default:
+			return nil, p.errorf("predicate %q: unknown directive @%s", name, d.Name)
+		}
+	}
+	if err := p.expect("."); err != nil {
+		return nil, fmt.Errorf("%w after predicate %q", err, name)
+	}
+	return pred, nil
+}
+
+// typeDefinition consumes the type keyword if a type definition follows, i.e. a name and an
+// opening brace, so that a predicate named type is parsed as a predicate.
+func (p *parser) typeDefinition() bool {
+	start := p.pos
+	if p.keyword("type") && p.word() != "" && p.peek() == '{' {
+		p.pos = start
+		return p.keyword("type")
+	}
+	p.pos = start
+	return false
+}
+
+// schemaType parses a type definition, once its type keyword has been read.
+func (p *parser) schemaType() (*SchemaType, error) {
+	t := &SchemaType{Name: p.word()}
+	if t.Name == "" {
+		return nil, p.errorf("expected a type name")
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.consume("}") {
+		if p.peek() == 0 {
+			return nil, p.errorf("type %q: unterminated definition", t.Name)
+		}
+		field, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		// Older schemas declare the type of each field, which is defined by the predicate.
+		if p.consume(":") {
+			p.consume("[")
+			p.word()
+			p.consume("]")
+			p.consume("!")
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return t, nil
+}
@@ -0,0 +1,209 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selection is a reusable, ordered set of attributes, to define common field sets once and
+// attach them to several blocks, fragments or attributes.
+//
+// Attaching a selection adds copies of its attributes, so modifying the attributes of one
+// block never affects the other blocks sharing the selection, nor the selection itself.
+type Selection struct {
+	attrs []*Attribute
+}
+
+// NewSelection creates a new Selection of attributes.
+//
+// The attributes are copied, so modifying them afterwards does not change the selection.
+//
+// Parameters:
+//   - attrs: The attributes of the selection, in order.
+//
+// Returns:
+//   - A pointer to a Selection object.
+//
+// Example:
+//
+//	userFields := NewSelection(NewAttribute("name"), NewAttribute("email"))
+//	me := NewQueryBlock("me", Has("user")).WithSelection(userFields)
+//	friends := NewAttribute("friend").WithSelection(userFields)
+func NewSelection(attrs ...*Attribute) *Selection {
+	return &Selection{attrs: cloneAttributes(attrs)}
+}
+
+// Extend creates a new Selection holding the attributes of the selection followed by more
+// attributes. The selection itself is not modified.
+//
+// Parameters:
+//   - attrs: The attributes to add.
+//
+// Returns:
+//   - A pointer to the new Selection object.
+//
+// Example:
+//
+//	adminFields := userFields.Extend(NewAttribute("permissions"))
+func (s *Selection) Extend(attrs ...*Attribute) *Selection {
+	res := &Selection{attrs: cloneAttributes(s.attrs)}
+	res.attrs = append(res.attrs, cloneAttributes(attrs)...)
+	return res
+}
+
+// Attributes returns copies of the attributes of the selection.
+//
+// Returns:
+//   - The copied attributes, in order.
+func (s *Selection) Attributes() []*Attribute {
+	return cloneAttributes(s.attrs)
+}
+
+// String generates a string representation of the selection.
+//
+// Returns:
+//   - The attributes of the selection, separated by spaces.
+func (s *Selection) String() string {
+	components := []string{}
+	for _, attr := range s.attrs {
+		components = append(components, attr.String())
+	}
+	return strings.Join(components, " ")
+}
+
+// WithSelection adds copies of the attributes of a selection to the query block.
+//
+// Parameters:
+//   - s: The selection to add.
+//
+// Returns:
+//   - The updated QueryBlock object.
+func (qb *QueryBlock) WithSelection(s *Selection) *QueryBlock {
+	return qb.WithAttributes(s.Attributes()...)
+}
+
+// WithSelection adds copies of the attributes of a selection to the variable block.
+//
+// Parameters:
+//   - s: The selection to add.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithSelection(s *Selection) *VarBlock {
+	return vb.WithAttributes(s.Attributes()...)
+}
+
+// WithSelection adds copies of the attributes of a selection to the fragment.
+//
+// Parameters:
+//   - s: The selection to add.
+//
+// Returns:
+//   - The updated Fragment object.
+func (f *Fragment) WithSelection(s *Selection) *Fragment {
+	return f.WithAttributes(s.Attributes()...)
+}
+
+// WithSelection adds copies of the attributes of a selection to the attribute.
+//
+// Parameters:
+//   - s: The selection to add.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithSelection(s *Selection) *Attribute {
+	return a.WithAttributes(s.Attributes()...)
+}
+
+// SelectionFromPaths creates a Selection from dot-separated attribute paths, such as the
+// fields of a ?fields=name,friends.name query parameter of a REST API.
+//
+// Each path selects an attribute nested in the attributes named by its previous segments,
+// which are created once in order of first appearance: name, friends.name and friends.age
+// select name and friends { name age }. Predicate names may contain dots, which are escaped
+// with a backslash within a segment: address\.city selects the address.city predicate.
+// Empty paths are ignored. Segments must be valid predicate names without language tags, so
+// client input cannot inject DQL.
+//
+// Parameters:
+//   - paths: The attribute paths.
+//   - allowed: The paths clients may select. The parents of an allowed path are allowed as
+//     well. A nil list allows every path.
+//
+// Returns:
+//   - A pointer to a Selection object.
+//   - An error if a path is malformed or not allowed.
+//
+// Example:
+//
+//	allowed := []string{"name", "age", "friends.name", "friends.age"}
+//	s, err := SelectionFromPaths(strings.Split(r.URL.Query().Get("fields"), ","), allowed)
+//	fmt.Println(s.String()) // Output: name friends { name age }
+func SelectionFromPaths(paths []string, allowed []string) (*Selection, error) {
+	var allowedPaths map[string]bool
+	if allowed != nil {
+		allowedPaths = map[string]bool{}
+		for _, path := range allowed {
+			segments, ok := splitFieldPath(strings.TrimSpace(path))
+			if !ok {
+				return nil, fmt.Errorf("dql: invalid allowed field path %q", path)
+			}
+			for i := range segments {
+				allowedPaths[strings.Join(segments[:i+1], "\x00")] = true
+			}
+		}
+	}
+	s := &Selection{}
+	created := map[string]*Attribute{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		segments, ok := splitFieldPath(path)
+		if !ok {
+			return nil, fmt.Errorf("dql: invalid field path %q", path)
+		}
+		if allowedPaths != nil && !allowedPaths[strings.Join(segments, "\x00")] {
+			return nil, fmt.Errorf("dql: field %q is not allowed", path)
+		}
+		attrs := &s.attrs
+		for i, segment := range segments {
+			if ValidatePredicate(segment) != nil || strings.ContainsAny(segment, "@~") {
+				return nil, fmt.Errorf("dql: invalid field path %q", path)
+			}
+			key := strings.Join(segments[:i+1], "\x00")
+			attr, ok := created[key]
+			if !ok {
+				attr = NewAttribute(segment)
+				created[key] = attr
+				*attrs = append(*attrs, attr)
+			}
+			attrs = &attr.Attributes
+		}
+	}
+	return s, nil
+}
+
+// splitFieldPath splits a field path into its segments at the dots that are not escaped with
+// a backslash. It reports false if a backslash escapes anything but a dot or a backslash.
+func splitFieldPath(path string) ([]string, bool) {
+	segments := []string{}
+	var segment strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i+1 == len(path) || (path[i+1] != '.' && path[i+1] != '\\') {
+				return nil, false
+			}
+			i++
+			segment.WriteByte(path[i])
+		case '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteByte(c)
+		}
+	}
+	return append(segments, segment.String()), true
+}
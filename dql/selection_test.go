@@ -0,0 +1,68 @@
+package dql
+
+import "testing"
+
+func TestSelection(t *testing.T) {
+	user := NewSelection(NewAttribute("name"), NewAttribute("email"))
+	withFriends := user.Extend(NewAttribute("friend").WithSelection(user))
+	if got, want := user.String(), "name email"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := withFriends.String(), "name email friend { name email }"; got != want {
+		t.Errorf("Extend() = %q, want %q", got, want)
+	}
+
+	q := NewQuery("", NewQueryBlock("me", Has("user")).WithSelection(withFriends)).
+		WithVarBlocks(NewVarBlock(Has("user")).WithSelection(user)).
+		WithFragments(NewFragment("userFields").WithSelection(user))
+	want := "{ var (func: has(user)) { name email } me (func: has(user)) { name email friend { name email } } } fragment userFields { name email }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestSelectionIsolation(t *testing.T) {
+	name := NewAttribute("name")
+	user := NewSelection(name)
+	name.Name = "email"
+	block := NewQueryBlock("me", Has("user")).WithSelection(user)
+	block.Attributes[0].Name = "age"
+	user.Attributes()[0].Name = "age"
+	if got := user.String(); got != "name" {
+		t.Errorf("String() = %q, want name", got)
+	}
+}
+
+func TestSelectionFromPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		allowed []string
+		want    string
+		wantErr string
+	}{
+		{"nested", []string{"name", "friends.name", "friends.email", " age "}, nil, "name friends { name email } age", ""},
+		{"empty paths", []string{"", "name"}, nil, "name", ""},
+		{"allowed", []string{"friends", "friends.name"}, []string{"name", "friends.name"}, "friends { name }", ""},
+		{"not allowed", []string{"friends.email"}, []string{"friends.name"}, "", `dql: field "friends.email" is not allowed`},
+		{"malformed", []string{"friends..name"}, nil, "", `dql: invalid field path "friends..name"`},
+		{"language tag", []string{"name@en"}, nil, "", `dql: invalid field path "name@en"`},
+		{"reverse edge", []string{"~owner"}, nil, "", `dql: invalid field path "~owner"`},
+		{"escaped dot", []string{`address\.city`, `friends.address\.city`}, []string{`address\.city`, `friends.address\.city`},
+			"address.city friends { address.city }", ""},
+		{"escaped dot not allowed", []string{`address\.city`}, []string{"address.city"}, "", `dql: field "address\\.city" is not allowed`},
+		{"invalid escape", []string{`name\x`}, nil, "", `dql: invalid field path "name\\x"`},
+		{"injection", []string{"name } secret {"}, nil, "", `dql: invalid field path "name } secret {"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := SelectionFromPaths(tt.paths, tt.allowed)
+			if got := errString(err); got != tt.wantErr {
+				t.Fatalf("SelectionFromPaths() error = %q, want %q", got, tt.wantErr)
+			}
+			if err == nil && s.String() != tt.want {
+				t.Errorf("SelectionFromPaths() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
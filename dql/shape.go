@@ -0,0 +1,144 @@
+package dql
+
+import "strings"
+
+// Shape describes the JSON value returned by Dgraph for a block or an attribute.
+//
+// Shapes are derived from the query alone. Without the schema, edges with nested attributes
+// are described as lists, which is how Dgraph returns [uid] predicates, and scalar values are
+// left untyped.
+type Shape struct {
+	// Key is the key of the value in its parent object.
+	Key string
+
+	// Path is the dotted path of the value from the root of the response, e.g. me.friend.name.
+	Path string
+
+	// List reports whether the value is a list of objects.
+	List bool
+
+	// Fields is the list of fields of the objects of the value, empty for scalar values.
+	Fields []*Shape
+}
+
+// ResponseShape describes the expected shape of the response of the query.
+//
+// Only query blocks are described: variable blocks are not returned, and shortest paths are
+// returned under the _path_ key with a shape of their own. Aliases, fragment spreads and
+// @normalize blocks, whose response only holds the aliased attributes flattened into each
+// result, are taken into account. Raw blocks and attributes cannot be described and are
+// skipped.
+//
+// Returns:
+//   - The shapes of the query blocks, in order.
+//
+// Example:
+//
+//	query := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+//	    NewAttribute("name"),
+//	    NewAttribute("friend").WithAttributes(NewAttribute("name")),
+//	))
+//	for _, s := range query.ResponseShape() {
+//	    fmt.Println(s.Path, s.List, len(s.Fields)) // Output: me true 2
+//	}
+func (q *Query) ResponseShape() []*Shape {
+	fragments := map[string]*Fragment{}
+	for _, f := range q.fragments() {
+		fragments[f.Name] = f
+	}
+	res := []*Shape{}
+	for _, qb := range q.QueryBlocks {
+		if qb.Raw {
+			continue
+		}
+		s := &Shape{Key: qb.Name, Path: qb.Name, List: true}
+		normalize := false
+		for _, d := range qb.Directives {
+			if directiveName(d) == "normalize" {
+				normalize = true
+			}
+		}
+		if normalize {
+			s.Fields = normalizedShapes(qb.Name, qb.Attributes, fragments)
+		} else {
+			s.Fields = attributeShapes(qb.Name, qb.Attributes, fragments)
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// JSONSchema converts the shape into a JSON Schema, e.g. to document the responses of a
+// service proxying Dgraph results in an OpenAPI specification.
+//
+// Returns:
+//   - The JSON Schema of the value, ready to be encoded with encoding/json.
+func (s *Shape) JSONSchema() map[string]any {
+	if len(s.Fields) == 0 && !s.List {
+		return map[string]any{}
+	}
+	properties := map[string]any{}
+	for _, f := range s.Fields {
+		properties[f.Key] = f.JSONSchema()
+	}
+	object := map[string]any{"type": "object", "properties": properties}
+	if s.List {
+		return map[string]any{"type": "array", "items": object}
+	}
+	return object
+}
+
+// attributeShapes describes the fields returned for a selection set.
+func attributeShapes(path string, attrs []*Attribute, fragments map[string]*Fragment) []*Shape {
+	res := []*Shape{}
+	for _, a := range attrs {
+		if a.Raw {
+			continue
+		}
+		if f, ok := spreadFragment(a, fragments); ok {
+			res = append(res, attributeShapes(path, f.Attributes, fragments)...)
+			continue
+		}
+		key := a.Name
+		if a.Alias != "" {
+			key = a.Alias
+		}
+		s := &Shape{Key: key, Path: path + "." + key}
+		if len(a.Attributes) != 0 {
+			s.List = true
+			s.Fields = attributeShapes(s.Path, a.Attributes, fragments)
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// normalizedShapes describes the fields returned for a selection set under @normalize, made
+// of the aliased attributes of all levels.
+func normalizedShapes(path string, attrs []*Attribute, fragments map[string]*Fragment) []*Shape {
+	res := []*Shape{}
+	for _, a := range attrs {
+		if a.Raw {
+			continue
+		}
+		if f, ok := spreadFragment(a, fragments); ok {
+			res = append(res, normalizedShapes(path, f.Attributes, fragments)...)
+			continue
+		}
+		if a.Alias != "" {
+			res = append(res, &Shape{Key: a.Alias, Path: path + "." + a.Alias})
+		}
+		res = append(res, normalizedShapes(path, a.Attributes, fragments)...)
+	}
+	return res
+}
+
+// spreadFragment returns the fragment spread by an attribute such as ...UserFields.
+func spreadFragment(a *Attribute, fragments map[string]*Fragment) (*Fragment, bool) {
+	name, ok := strings.CutPrefix(a.Name, "...")
+	if !ok {
+		return nil, false
+	}
+	f, ok := fragments[name]
+	return f, ok
+}
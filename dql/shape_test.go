@@ -0,0 +1,90 @@
+package dql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// describeShape renders a shape as path[list]{fields} for comparisons.
+func describeShape(s *Shape) string {
+	res := s.Path
+	if s.List {
+		res += "[]"
+	}
+	if len(s.Fields) != 0 {
+		res += "{"
+		for i, f := range s.Fields {
+			if i > 0 {
+				res += " "
+			}
+			res += describeShape(f)
+		}
+		res += "}"
+	}
+	return res
+}
+
+func TestResponseShape(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{
+			name: "nested attributes and alias",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+				NewAttribute("name").WithAlias("fullName"),
+				NewAttribute("friend").WithAttributes(NewAttribute("name")),
+			)),
+			want: []string{"me[]{me.fullName me.friend[]{me.friend.name}}"},
+		},
+		{
+			name: "fragment spread",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("...userFields"))).
+				WithFragments(NewFragment("userFields").WithAttributes(NewAttribute("name"), NewAttribute("age"))),
+			want: []string{"me[]{me.name me.age}"},
+		},
+		{
+			name: "normalize keeps aliased attributes only",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives("@normalize").WithAttributes(
+				NewAttribute("name").WithAlias("n"),
+				NewAttribute("friend").WithAttributes(NewAttribute("name").WithAlias("friendName"), NewAttribute("age")),
+			)),
+			want: []string{"me[]{me.n me.friendName}"},
+		},
+		{
+			name: "var and raw blocks are skipped",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("name"), NewRawAttribute("count(uid)"))).
+				WithVarBlocks(NewVarBlock(Has("user"))).
+				WithQueryBlocks(NewRawQueryBlock("raw(func: has(user)) { name }")),
+			want: []string{"me[]{me.name}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, s := range tt.query.ResponseShape() {
+				got = append(got, describeShape(s))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResponseShape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapeJSONSchema(t *testing.T) {
+	query := NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+		NewAttribute("name"),
+		NewAttribute("friend").WithAttributes(NewAttribute("name")),
+	))
+	b, err := json.Marshal(query.ResponseShape()[0].JSONSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"items":{"properties":{"friend":{"items":{"properties":{"name":{}},"type":"object"},"type":"array"},"name":{}},"type":"object"},"type":"array"}`
+	if got := string(b); got != want {
+		t.Errorf("JSONSchema() = %s, want %s", got, want)
+	}
+}
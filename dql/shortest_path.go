@@ -0,0 +1,301 @@
+package dql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShortestPath represents a shortest path block in a DQL query.
+//
+// A ShortestPath block finds the paths between two nodes following the edges listed in its
+// attributes. The paths are bound to the variable named by Name, which later blocks can use
+// with uid(). Edge weights are read from a facet of the edges, see WithWeightedEdge.
+type ShortestPath struct {
+	// Name is the name of the variable the path is bound to.
+	Name string
+
+	// From is the uid or uid variable the paths start from.
+	From string
+
+	// To is the uid or uid variable the paths end at.
+	To string
+
+	// NumPaths is the number of paths to find, starting with the shortest. Zero finds one path.
+	NumPaths int
+
+	// Depth limits the number of edges of a path. Zero means no limit.
+	Depth int
+
+	// MinWeight is the minimum total weight of a path, nil if unbounded.
+	MinWeight *float64
+
+	// MaxWeight is the maximum total weight of a path, nil if unbounded.
+	MaxWeight *float64
+
+	// Attributes is the list of edges the paths can follow.
+	Attributes []*Attribute
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
+}
+
+// NewShortestPath creates a new ShortestPath block between two nodes.
+//
+// Parameters:
+//   - from: The uid or uid variable the paths start from, a string or a UID.
+//   - to: The uid or uid variable the paths end at, a string or a UID.
+//
+// Returns:
+//   - A pointer to a ShortestPath object.
+//
+// Example:
+//
+//	path := NewShortestPath("0x2", "0x5").
+//	    WithName("path").
+//	    WithAttributes(NewAttribute("friend"))
+//	fmt.Println(path.String()) // Output: path AS shortest(from: 0x2, to: 0x5) { friend }
+//
+// See: https://dgraph.io/docs/query-language/shortest-path-queries/
+func NewShortestPath(from any, to any) *ShortestPath {
+	return &ShortestPath{
+		From: toCriteria(from).String(),
+		To:   toCriteria(to).String(),
+	}
+}
+
+// WithName sets the name of the variable the path is bound to.
+//
+// Parameters:
+//   - name: The name of the variable.
+//
+// Returns:
+//   - The updated ShortestPath object.
+func (sp *ShortestPath) WithName(name string) *ShortestPath {
+	mustBeMutable(sp.frozen, "shortest path", sp.Name)
+	sp.Name = name
+	return sp
+}
+
+// WithNumPaths sets the number of paths to find, turning the block into a k-shortest paths
+// query.
+//
+// Parameters:
+//   - n: The number of paths to find.
+//
+// Returns:
+//   - The updated ShortestPath object.
+//
+// Example:
+//
+//	path := NewShortestPath("0x2", "0x5").WithNumPaths(3)
+//	fmt.Println(path.String()) // Output: shortest(from: 0x2, to: 0x5, numpaths: 3) { }
+func (sp *ShortestPath) WithNumPaths(n int) *ShortestPath {
+	mustBeMutable(sp.frozen, "shortest path", sp.Name)
+	sp.NumPaths = n
+	return sp
+}
+
+// WithDepth limits the number of edges of a path.
+//
+// Parameters:
+//   - depth: The maximum number of edges.
+//
+// Returns:
+//   - The updated ShortestPath object.
+func (sp *ShortestPath) WithDepth(depth int) *ShortestPath {
+	mustBeMutable(sp.frozen, "shortest path", sp.Name)
+	sp.Depth = depth
+	return sp
+}
+
+// WithWeights bounds the total weight of the paths.
+//
+// Parameters:
+//   - min: The minimum total weight of a path.
+//   - max: The maximum total weight of a path.
+//
+// Returns:
+//   - The updated ShortestPath object.
+//
+// Example:
+//
+//	path := NewShortestPath("0x2", "0x5").WithWeights(1, 10)
+//	fmt.Println(path.String()) // Output: shortest(from: 0x2, to: 0x5, minweight: 1, maxweight: 10) { }
+func (sp *ShortestPath) WithWeights(min float64, max float64) *ShortestPath {
+	mustBeMutable(sp.frozen, "shortest path", sp.Name)
+	sp.MinWeight = &min
+	sp.MaxWeight = &max
+	return sp
+}
+
+// WithAttributes adds one or more edges the paths can follow.
+//
+// Parameters:
+//   - attrs: One or more Attribute objects.
+//
+// Returns:
+//   - The updated ShortestPath object.
+func (sp *ShortestPath) WithAttributes(attrs ...*Attribute) *ShortestPath {
+	mustBeMutable(sp.frozen, "shortest path", sp.Name)
+	for _, a := range attrs {
+		sp.Attributes = append(sp.Attributes, a)
+	}
+	return sp
+}
+
+// WithWeightedEdge adds an edge the paths can follow, weighted by one of its facets, see
+// WeightedEdge.
+//
+// Parameters:
+//   - predicate: The edge predicate.
+//   - facet: The numeric facet holding the weight of the edge.
+//
+// Returns:
+//   - The updated ShortestPath object.
+//
+// Example:
+//
+//	path := NewShortestPath("0x2", "0x5").WithWeightedEdge("friend", "weight")
+//	fmt.Println(path.String()) // Output: shortest(from: 0x2, to: 0x5) { friend @facets(weight) }
+func (sp *ShortestPath) WithWeightedEdge(predicate string, facet string) *ShortestPath {
+	return sp.WithAttributes(WeightedEdge(predicate, facet))
+}
+
+// WeightedEdge creates an edge of a shortest path block weighted by one of its facets, e.g.
+// connected @facets(weight). Edges without weight count as 1.
+//
+// The attribute can be refined before it is added with ShortestPath.WithAttributes, e.g. with
+// a @filter directive restricting the nodes the paths go through. Dgraph weighs an edge with a
+// single facet, which Validate checks. Dgraph schemas do not type facets, so the facet must
+// hold numbers in the data: with a schema, Validate checks that the predicate is a uid edge.
+//
+// Parameters:
+//   - predicate: The edge predicate.
+//   - facet: The numeric facet holding the weight of the edge.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+//
+// Example:
+//
+//	road := WeightedEdge("road", "distance").WithDirectives(NewDirective("filter", Eq("open", true)))
+//	path := NewShortestPath("0x2", "0x5").WithName("route").WithAttributes(road)
+//	fmt.Println(path.String()) // Output: route AS shortest(from: 0x2, to: 0x5) { road @facets(distance) @filter(eq(open, true)) }
+//
+// See: https://dgraph.io/docs/query-language/shortest-path-queries/
+func WeightedEdge(predicate string, facet string) *Attribute {
+	return NewAttribute(predicate).WithDirectives(Facets(facet))
+}
+
+// Validate checks the shortest path block and its attributes.
+//
+// The endpoints must be uids, uid variables or parameters, see UidLit, and each edge must be
+// weighted by at most one facet.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the block is valid.
+func (sp *ShortestPath) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if sp.Name != "" {
+		if err := validateName("variable name", sp.Name); err != nil {
+			return fmt.Errorf("dql: shortest path %q: %w", sp.Name, err)
+		}
+	}
+	for _, weight := range []*float64{sp.MinWeight, sp.MaxWeight} {
+		if weight != nil && !isFinite(*weight) {
+			return fmt.Errorf("dql: shortest path %q: weight %v is not a finite number", sp.Name, *weight)
+		}
+	}
+	if sp.MinWeight != nil && sp.MaxWeight != nil && *sp.MinWeight > *sp.MaxWeight {
+		return fmt.Errorf("dql: shortest path %q: minweight %v is greater than maxweight %v", sp.Name, *sp.MinWeight, *sp.MaxWeight)
+	}
+	for _, endpoint := range []string{sp.From, sp.To} {
+		if err := validateUidRef(endpoint); err != nil {
+			return fmt.Errorf("dql: shortest path %q: %w", sp.Name, err)
+		}
+	}
+	for _, a := range sp.Attributes {
+		if weights := weightFacets(a); len(weights) > 1 {
+			return fmt.Errorf("dql: shortest path %q: %s: an edge is weighted by a single facet, got %s", sp.Name, a.Name, strings.Join(weights, ", "))
+		}
+	}
+	if err := validateAttributes(sp.Attributes); err != nil {
+		return fmt.Errorf("dql: shortest path %q: %w", sp.Name, err)
+	}
+	return nil
+}
+
+// weightFacets returns the facets an edge of a shortest path block is weighted by: the facets
+// its @facets directives return, leaving out facet filters.
+func weightFacets(a *Attribute) []string {
+	res := []string{}
+	if a.Raw {
+		return res
+	}
+	for _, d := range a.Directives {
+		if directiveName(d) != "facets" {
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(d.String()), "@facets"))
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "("), ")")
+		if strings.Contains(text, "(") {
+			continue
+		}
+		for _, f := range strings.Split(text, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				res = append(res, f)
+			}
+		}
+	}
+	return res
+}
+
+// validateWeightedEdges reports the edges of a shortest path block the schema does not declare
+// as uid predicates.
+func (s *Schema) validateWeightedEdges(sp *ShortestPath) error {
+	for _, a := range sp.Attributes {
+		if a.Raw || isExpression(a.Name) {
+			continue
+		}
+		if p := s.Predicate(predicateOf(a.Name)); p != nil && p.Type != "uid" {
+			return fmt.Errorf("predicate %q: shortest paths follow uid edges, not %s", p.Name, p.Type)
+		}
+	}
+	return nil
+}
+
+// arguments renders the arguments of the shortest function.
+func (sp *ShortestPath) arguments() string {
+	args := []string{"from: " + sp.From, "to: " + sp.To}
+	if sp.NumPaths != 0 {
+		args = append(args, fmt.Sprintf("numpaths: %d", sp.NumPaths))
+	}
+	if sp.Depth != 0 {
+		args = append(args, fmt.Sprintf("depth: %d", sp.Depth))
+	}
+	if sp.MinWeight != nil {
+		args = append(args, "minweight: "+SafeValue(*sp.MinWeight))
+	}
+	if sp.MaxWeight != nil {
+		args = append(args, "maxweight: "+SafeValue(*sp.MaxWeight))
+	}
+	return strings.Join(args, ", ")
+}
+
+// String generates a string representation of the shortest path block.
+//
+// Returns:
+//   - A string representation of the shortest path block.
+func (sp *ShortestPath) String() string {
+	components := []string{}
+	if sp.Name != "" {
+		components = append(components, sp.Name, "AS")
+	}
+	components = append(components, fmt.Sprintf("shortest(%s)", sp.arguments()))
+	components = append(components, "{")
+	for _, attr := range sp.Attributes {
+		components = append(components, attr.String())
+	}
+	components = append(components, "}")
+	return strings.Join(components, " ")
+}
@@ -0,0 +1,93 @@
+package dql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShortestPath(t *testing.T) {
+	tests := []struct {
+		name string
+		sp   *ShortestPath
+		want string
+	}{
+		{"plain", NewShortestPath("0x1", "0x2").WithAttributes(NewAttribute("friend")),
+			"shortest(from: 0x1, to: 0x2) { friend }"},
+		{"named k-shortest", NewShortestPath("0x1", "0x2").WithName("p").WithNumPaths(3).WithDepth(5).WithAttributes(NewAttribute("friend")),
+			"p AS shortest(from: 0x1, to: 0x2, numpaths: 3, depth: 5) { friend }"},
+		{"weights", NewShortestPath("0x1", "0x2").WithWeights(1, 10.5).WithWeightedEdge("road", "distance"),
+			"shortest(from: 0x1, to: 0x2, minweight: 1, maxweight: 10.5) { road @facets(distance) }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sp.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortestPathQuery(t *testing.T) {
+	q := NewQuery("", NewQueryBlock("path", Uid("p")).WithAttributes(NewAttribute("name"))).
+		WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("friend")))
+	if got, want := q.String(), "{ p AS shortest(from: 0x1, to: 0x2) { friend } path (func: uid(p)) { name } }"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	c := q.Clone()
+	c.ShortestPaths[0].WithDepth(2)
+	if q.ShortestPaths[0].Depth != 0 {
+		t.Errorf("Clone() shares the shortest path")
+	}
+	if Equal(q, c) {
+		t.Errorf("Equal() = true for different shortest paths")
+	}
+}
+
+func TestShortestPathValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sp      *ShortestPath
+		wantErr string
+	}{
+		{"valid", NewShortestPath("0x1", "0x2").WithName("p").WithWeights(1, 2), ""},
+		{"weights", NewShortestPath("0x1", "0x2").WithName("p").WithWeights(5, 2), `dql: shortest path "p": minweight 5 is greater than maxweight 2`},
+		{"weighted edge", NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(
+			WeightedEdge("road", "distance").WithDirectives(NewDirective("filter", Eq("open", true)))), ""},
+		{"two weights", NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(NewAttribute("road").WithDirectives(Facets("distance", "toll"))),
+			`dql: shortest path "p": road: an edge is weighted by a single facet, got distance, toll`},
+		{"alias", NewShortestPath("0x1", "0x2").WithAttributes(NewAttribute("a").WithAlias("x"), NewAttribute("b").WithAlias("x")), `duplicate alias "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewQuery("", NewQueryBlock("me", Uid("p"))).WithShortestPaths(tt.sp).Validate()
+			if (err == nil) != (tt.wantErr == "") || err != nil && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShortestPathSchema(t *testing.T) {
+	schema, err := ParseSchema("road: [uid] .\nname: string .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		edge    *Attribute
+		wantErr string
+	}{
+		{"uid edge", WeightedEdge("road", "distance"), ""},
+		{"undeclared", WeightedEdge("rail", "distance"), ""},
+		{"scalar", NewAttribute("name"), `dql: shortest path "p": predicate "name": shortest paths follow uid edges, not string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery("", NewQueryBlock("me", Uid("p"))).
+				WithShortestPaths(NewShortestPath("0x1", "0x2").WithName("p").WithAttributes(tt.edge)).WithSchema(schema)
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
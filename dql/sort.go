@@ -0,0 +1,169 @@
+package dql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// valuePattern matches an ordering by a value variable, e.g. val(score).
+var valuePattern = regexp.MustCompile(`^val\(\s*([A-Za-z_][A-Za-z0-9_]*)\s*\)$`)
+
+// SortKey is a key ordering the results of a block or an edge, see WithOrder.
+type SortKey struct {
+	// Predicate is the predicate to order by, or a value variable such as val(score).
+	Predicate string
+
+	// Desc orders by descending values.
+	Desc bool
+
+	// SkipMissing removes the nodes lacking a value for the key from the results.
+	SkipMissing bool
+}
+
+// Asc creates a SortKey ordering by ascending values.
+//
+// Parameters:
+//   - predicate: The predicate to order by, or a value variable such as val(score).
+//
+// Returns:
+//   - A SortKey.
+func Asc(predicate string) SortKey {
+	return SortKey{Predicate: predicate}
+}
+
+// Desc creates a SortKey ordering by descending values.
+//
+// Parameters:
+//   - predicate: The predicate to order by, or a value variable such as val(score).
+//
+// Returns:
+//   - A SortKey.
+func Desc(predicate string) SortKey {
+	return SortKey{Predicate: predicate, Desc: true}
+}
+
+// WithoutMissing returns a copy of the key removing the nodes lacking a value for it, rather
+// than returning them in the order Dgraph gives them.
+//
+// The nodes are removed with a has(predicate) filter, or with a uid(variable) filter for
+// value variables, which only holds the nodes the variable has a value for.
+//
+// Returns:
+//   - The updated SortKey.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("people", Has("name")).WithOrder(Asc("age").WithoutMissing(), Asc("name"))
+//	fmt.Println(queryBlock.String()) // Output: people (func: has(name), orderasc: age, orderasc: name) @filter(has(age)) { }
+func (k SortKey) WithoutMissing() SortKey {
+	k.SkipMissing = true
+	return k
+}
+
+// arg returns the ordering argument of the key.
+func (k SortKey) arg() *Arg {
+	if k.Desc {
+		return NewArg("orderdesc", Predicate(k.Predicate))
+	}
+	return NewArg("orderasc", Predicate(k.Predicate))
+}
+
+// guard returns the filter removing the nodes lacking a value for the key.
+func (k SortKey) guard() Criteria {
+	if m := valuePattern.FindStringSubmatch(strings.TrimSpace(k.Predicate)); m != nil {
+		return Uid(m[1])
+	}
+	return Has(k.Predicate)
+}
+
+// WithOrder sets the sort keys of the query block, replacing its orderasc and orderdesc
+// arguments.
+//
+// Keys are applied in order: each key orders the nodes the previous keys consider equal.
+// Keys created WithoutMissing add a filter removing the nodes lacking them, combined with AND
+// with an existing @filter directive.
+//
+// Parameters:
+//   - keys: The sort keys, by decreasing priority.
+//
+// Returns:
+//   - The updated QueryBlock object.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("people", Has("name")).WithOrder(Desc("age"), Asc("name"))
+//	fmt.Println(queryBlock.String()) // Output: people (func: has(name), orderdesc: age, orderasc: name) { }
+//
+// See: https://dgraph.io/docs/query-language/sorting/
+func (qb *QueryBlock) WithOrder(keys ...SortKey) *QueryBlock {
+	mustBeMutable(qb.frozen, "query block", qb.Name)
+	qb.Criteria, qb.Directives = withOrder(qb.Criteria, qb.Directives, keys)
+	return qb
+}
+
+// WithOrder sets the sort keys of the variable block, replacing its orderasc and orderdesc
+// arguments, see QueryBlock.WithOrder.
+//
+// Parameters:
+//   - keys: The sort keys, by decreasing priority.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithOrder(keys ...SortKey) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
+	vb.Criteria, vb.Directives = withOrder(vb.Criteria, vb.Directives, keys)
+	return vb
+}
+
+// WithOrder sets the sort keys of the edge, replacing its orderasc and orderdesc arguments,
+// see QueryBlock.WithOrder.
+//
+// Parameters:
+//   - keys: The sort keys, by decreasing priority.
+//
+// Returns:
+//   - The updated Attribute object.
+func (a *Attribute) WithOrder(keys ...SortKey) *Attribute {
+	mustBeMutable(a.frozen, "attribute", a.Name)
+	a.Args, a.Directives = withOrder(a.Args, a.Directives, keys)
+	return a
+}
+
+// Order sets the sort keys of the block, see QueryBlock.WithOrder.
+//
+// Parameters:
+//   - keys: The sort keys, by decreasing priority.
+//
+// Returns:
+//   - A BlockOption.
+func Order(keys ...SortKey) BlockOption {
+	return func(b blockParts) {
+		*b.criteria, *b.directives = withOrder(*b.criteria, *b.directives, keys)
+	}
+}
+
+// withOrder replaces the ordering arguments of args with keys, and adds their guards to the
+// @filter directive of directives.
+func withOrder(args []Criteria, directives []Criteria, keys []SortKey) ([]Criteria, []Criteria) {
+	res := []Criteria{}
+	for _, c := range args {
+		if name := argName(c); name != "orderasc" && name != "orderdesc" {
+			res = append(res, c)
+		}
+	}
+	guards := &Connective{Op: "AND"}
+	for _, k := range keys {
+		res = append(res, k.arg())
+		if k.SkipMissing {
+			guards.Args = append(guards.Args, k.guard())
+		}
+	}
+	switch len(guards.Args) {
+	case 0:
+	case 1:
+		directives = andFilter(directives, guards.Args[0])
+	default:
+		directives = andFilter(directives, guards)
+	}
+	return res, directives
+}
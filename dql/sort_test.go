@@ -0,0 +1,31 @@
+package dql
+
+import "testing"
+
+func TestWithOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"keys by priority", NewQueryBlock("me", Has("user")).WithFirst(10).WithOrder(Desc("age"), Asc("name@en")).String(),
+			"me (func: has(user), first: 10, orderdesc: age, orderasc: name@en) { }"},
+		{"replaces existing ordering", NewQueryBlock("me", Has("user")).WithOrderAsc("name").WithOrder(Desc("age")).String(),
+			"me (func: has(user), orderdesc: age) { }"},
+		{"without missing", NewQueryBlock("me", Has("user")).WithOrder(Asc("age").WithoutMissing(), Desc("val(score)").WithoutMissing()).String(),
+			"me (func: has(user), orderasc: age, orderdesc: val(score)) @filter(has(age) AND uid(score)) { }"},
+		{"existing filter", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", Has("email"))).WithOrder(Asc("age").WithoutMissing()).String(),
+			"me (func: has(user), orderasc: age) @filter(has(email) AND has(age)) { }"},
+		{"var block", NewVarBlock(Has("user")).WithOrder(Asc("name")).String(), "var (func: has(user), orderasc: name) { }"},
+		{"attribute", NewAttribute("friend").WithOrder(Desc("age").WithoutMissing()).WithAttributes(NewAttribute("name")).String(),
+			"friend (orderdesc: age) @filter(has(age)) { name }"},
+		{"option", NewQueryBlockOpt("me", Func(Has("user")), Order(Asc("name"))).String(), "me (func: has(user), orderasc: name) { }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("String() = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
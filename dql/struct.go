@@ -0,0 +1,148 @@
+package dql
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// AttributesFromStruct generates the attributes selecting the fields of a struct type.
+//
+// The name of each attribute is taken from the `dql` tag of the field, falling back to its
+// `dgraph` and `json` tags and then to the field name, so the selection matches what decoding
+// the response into T with Decode, or with encoding/json for untagged `dql` fields, expects. Fields tagged "-" and unexported fields are skipped, and
+// embedded structs are flattened like encoding/json does.
+//
+// Fields of struct type, and pointers, slices and arrays of them, become nested attributes
+// selecting the fields of that struct. Types that decode themselves, such as time.Time or
+// types implementing json.Unmarshaler, are selected as scalars. When a struct type refers to
+// itself, the recursive edge selects only uid.
+//
+// Fields named predicate|facet, e.g. `dql:"friend|since"`, hold facets: they add a @facets
+// directive to the attribute of the predicate rather than an attribute of their own. The
+// facets of a uid edge are declared in the struct of the nodes it leads to.
+//
+// Returns:
+//   - The list of attributes selecting the fields of T.
+//
+// Example:
+//
+//	type Person struct {
+//	    Name    string `json:"name"`
+//	    Friends []struct {
+//	        Name string `json:"name"`
+//	    } `json:"friend"`
+//	}
+//	queryBlock := NewQueryBlock("me", Has("name")).
+//	    WithAttributes(AttributesFromStruct[Person]()...)
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(name)) { name friend { name } }
+func AttributesFromStruct[T any]() []*Attribute {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	attrs, _ := attributesFromType(t, map[reflect.Type]bool{})
+	return attrs
+}
+
+// attributesFromType generates the attributes of a struct type. The seen set holds the struct
+// types of the current path and is used to stop on recursive types.
+//
+// The facets of predicates not selected by the struct type, i.e. of the uid edge leading to
+// it, are returned by predicate.
+func attributesFromType(t reflect.Type, seen map[reflect.Type]bool) ([]*Attribute, map[string][]any) {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct || isScalarType(t) {
+		return nil, nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	attrs := []*Attribute{}
+	facets := map[string][]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+		if field.Anonymous && name == "" && elemType(field.Type).Kind() == reflect.Struct {
+			embedded, embeddedFacets := attributesFromType(field.Type, seen)
+			attrs = append(attrs, embedded...)
+			for pred, f := range embeddedFacets {
+				facets[pred] = append(facets[pred], f...)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if pred, facet, ok := strings.Cut(name, "|"); ok {
+			facets[pred] = append(facets[pred], facet)
+			continue
+		}
+
+		attr := NewAttribute(name)
+		ft := elemType(field.Type)
+		if ft.Kind() == reflect.Struct && !isScalarType(ft) {
+			if seen[ft] {
+				attr.WithAttributes(UIDAttribute())
+			} else {
+				nested, edgeFacets := attributesFromType(ft, seen)
+				attr.WithAttributes(nested...)
+				facets[name] = append(facets[name], edgeFacets[name]...)
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	for _, attr := range attrs {
+		if f, ok := facets[attr.Name]; ok {
+			if len(f) != 0 {
+				attr.WithDirectives(Facets(f...))
+			}
+			delete(facets, attr.Name)
+		}
+	}
+	return attrs, facets
+}
+
+// fieldName returns the name given to a field by its tags, and false if the field is skipped.
+func fieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("dql")
+	if !ok {
+		tag, ok = field.Tag.Lookup("dgraph")
+	}
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+// elemType unwraps pointer, slice and array types.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isScalarType reports whether a struct type decodes itself from a scalar value.
+func isScalarType(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
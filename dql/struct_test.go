@@ -0,0 +1,50 @@
+package dql
+
+import (
+	"testing"
+	"time"
+)
+
+type structAddress struct {
+	City string `json:"city"`
+}
+
+type structBase struct {
+	UID string `json:"uid"`
+}
+
+type structPerson struct {
+	structBase
+	Name     string          `json:"name"`
+	Nick     string          `dgraph:"nick@en" json:"nickname"`
+	Born     time.Time       `json:"born"`
+	Address  *structAddress  `json:"address"`
+	Friends  []*structPerson `json:"friend"`
+	Password string          `json:"-"`
+	Plain    int
+	secret   string
+}
+
+func TestAttributesFromStruct(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []*Attribute
+		want  string
+	}{
+		{"person", AttributesFromStruct[structPerson](),
+			"me (func: has(name)) { uid name nick@en born address { city } friend { uid } Plain }"},
+		{"pointer", AttributesFromStruct[*structAddress](), "me (func: has(name)) { city }"},
+		{"not a struct", AttributesFromStruct[string](), "me (func: has(name)) { }"},
+		{"facets", AttributesFromStruct[decodeUser](),
+			"me (func: has(name)) { uid name @facets(lang) nick@en age friend @facets(since) { name } tag @facets(weight) Score }"},
+		{"scalar struct", AttributesFromStruct[time.Time](), "me (func: has(name)) { }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewQueryBlock("me", Has("name")).WithAttributes(tt.attrs...).String()
+			if got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Placeholder is a named hole in a query, filled in when a Template is bound.
+//
+// A Placeholder can be used as criteria, inside directives such as Filter, or as an attribute
+// through its Attribute method. Until it is bound, it renders as {{name}}.
+//
+// Example:
+//
+//	queryBlock := NewQueryBlock("me", Has("user")).
+//	    WithDirectives(NewDirective("filter", Placeholder("extraFilter")))
+//	fmt.Println(queryBlock.String()) // Output: me (func: has(user)) @filter({{extraFilter}}) { }
+type Placeholder string
+
+// String generates the {{name}} marker of the placeholder.
+//
+// Returns:
+//   - A string representation of the placeholder.
+func (p Placeholder) String() string {
+	return "{{" + string(p) + "}}"
+}
+
+// Attribute creates an attribute standing for the placeholder.
+//
+// When the template is bound, the attribute is replaced by the bound Attribute, or by the
+// rendered value of any other bound node.
+//
+// Returns:
+//   - A pointer to an Attribute object.
+func (p Placeholder) Attribute() *Attribute {
+	return NewRawAttribute(p.String())
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
+
+// Template is a query containing placeholders that are filled in at runtime.
+type Template struct {
+	// Query is the base query containing the placeholders.
+	Query *Query
+}
+
+// NewTemplate creates a new Template from a base query.
+//
+// Parameters:
+//   - q: The base query containing placeholders.
+//
+// Returns:
+//   - A pointer to a Template object.
+//
+// Example:
+//
+//	template := NewTemplate(NewQuery("", NewQueryBlockOpt("me",
+//	    Func(Has("user")),
+//	    Filter(Placeholder("extraFilter")),
+//	    Attributes(NewAttribute("name")),
+//	)))
+//	query, err := template.Bind(map[string]Node{"extraFilter": Has("email")})
+//	fmt.Println(query.String()) // Output: { me (func: has(user)) @filter(has(email)) { name } }
+func NewTemplate(q *Query) *Template {
+	return &Template{
+		Query: q,
+	}
+}
+
+// Placeholders lists the names of the placeholders of the template.
+//
+// Returns:
+//   - The sorted names of the placeholders.
+func (t *Template) Placeholders() []string {
+	names := map[string]bool{}
+	collect := func(s string) {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			names[m[1]] = true
+		}
+	}
+	collectCriteria := func(list []Criteria) {
+		for _, c := range list {
+			walkCriteria(c, func(c Criteria) {
+				switch c := c.(type) {
+				case Placeholder:
+					names[string(c)] = true
+				case Literal, compositeCriteria:
+				default:
+					collect(c.String())
+				}
+			})
+		}
+	}
+	Walk(t.Query, func(n Node) bool {
+		switch n := n.(type) {
+		case *Param:
+			collect(n.Default)
+		case *VarBlock:
+			collectCriteria(n.Criteria)
+			collectCriteria(n.Directives)
+		case *QueryBlock:
+			collectCriteria(n.Criteria)
+			collectCriteria(n.Directives)
+		case *Attribute:
+			collect(n.Name)
+			collectCriteria(n.Args)
+			collectCriteria(n.Directives)
+		}
+		return true
+	})
+
+	res := make([]string, 0, len(names))
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Bind creates a query from the template by replacing its placeholders with nodes.
+//
+// The base query is left untouched. A placeholder used as criteria, as a directive argument
+// or as an attribute is replaced by the bound node itself; a placeholder inside raw text is
+// replaced by the rendered node.
+//
+// Parameters:
+//   - values: The nodes to bind, indexed by placeholder name.
+//
+// Returns:
+//   - A pointer to the bound Query object.
+//   - An error if a placeholder has no value or a value matches no placeholder.
+func (t *Template) Bind(values map[string]Node) (*Query, error) {
+	names := t.Placeholders()
+	known := map[string]bool{}
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("dql: template: unbound placeholder %q", name)
+		}
+		known[name] = true
+	}
+	unknown := []string{}
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("dql: template: unknown placeholder %q", unknown[0])
+	}
+
+	replace := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+			return values[m[2:len(m)-2]].String()
+		})
+	}
+	bindCriteria := func(list []Criteria) {
+		mapCriteriaList(list, func(c Criteria) Criteria {
+			switch c := c.(type) {
+			case Placeholder:
+				return values[string(c)]
+			case Literal, compositeCriteria:
+				return c
+			}
+			if s := c.String(); placeholderPattern.MatchString(s) {
+				return Raw(replace(s))
+			}
+			return c
+		})
+	}
+
+	return Rewrite(t.Query, func(n Node) Node {
+		switch n := n.(type) {
+		case *Param:
+			n.Default = replace(n.Default)
+		case *VarBlock:
+			bindCriteria(n.Criteria)
+			bindCriteria(n.Directives)
+		case *QueryBlock:
+			bindCriteria(n.Criteria)
+			bindCriteria(n.Directives)
+		case *Attribute:
+			if m := placeholderPattern.FindStringSubmatch(n.Name); n.Raw && m != nil && m[0] == n.Name {
+				if attr, ok := values[m[1]].(*Attribute); ok {
+					return attr.Clone()
+				}
+			}
+			n.Name = replace(n.Name)
+			bindCriteria(n.Args)
+			bindCriteria(n.Directives)
+		}
+		return n
+	})
+}
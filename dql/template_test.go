@@ -0,0 +1,61 @@
+package dql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newUserTemplate returns a template with a placeholder in each position.
+func newUserTemplate() *Template {
+	return NewTemplate(NewQuery("Q", NewQueryBlock("me", Placeholder("root")).
+		WithDirectives("@filter("+Placeholder("filter").String()+")").
+		WithAttributes(NewAttribute("name"), Placeholder("extra").Attribute())))
+}
+
+func TestTemplatePlaceholders(t *testing.T) {
+	if got, want := newUserTemplate().Placeholders(), []string{"extra", "filter", "root"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders() = %v, want %v", got, want)
+	}
+}
+
+func TestTemplateBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]Node
+		want    string
+		wantErr string
+	}{
+		{"bound", map[string]Node{
+			"root":   Has("user"),
+			"filter": Raw(`eq(name, "Alice")`),
+			"extra":  NewAttribute("friend").WithAttributes(NewAttribute("name")),
+		}, `query Q { me (func: has(user)) @filter(eq(name, "Alice")) { name friend { name } } }`, ""},
+		{"unbound", map[string]Node{"root": Has("user"), "filter": Has("email")}, "", `unbound placeholder "extra"`},
+		{"unknown", map[string]Node{
+			"root": Has("user"), "filter": Has("email"), "extra": NewAttribute("age"), "other": Has("x"),
+		}, "", `unknown placeholder "other"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := newUserTemplate()
+			want := tmpl.Query.String()
+			q, err := tmpl.Bind(tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Bind() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("Bind() = %s, want %s", got, tt.want)
+			}
+			if got := tmpl.Query.String(); got != want {
+				t.Errorf("template after Bind() = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package dql
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tracer starts the spans of the executions of queries, to export them to a tracing system,
+// e.g. with an adapter around an OpenTelemetry tracer.
+//
+// Implementations must be safe for concurrent use.
+type Tracer interface {
+	// StartSpan starts the span of an execution of a query, named after the query, and
+	// returns the context of the execution carrying it.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the span of an execution of a query, started by a Tracer.
+type Span interface {
+	// SetAttribute records an attribute of the execution, a string or an int64.
+	SetAttribute(key string, value any)
+
+	// End ends the span with the error of the execution, or nil.
+	End(err error)
+}
+
+// Attributes recorded by TracingMiddleware on the spans of the executions.
+const (
+	// SpanQueryName is the name of the query, see MetricsName.
+	SpanQueryName = "dql.query.name"
+
+	// SpanQueryFingerprint is the shape fingerprint of the query, see Query.ShapeFingerprint.
+	SpanQueryFingerprint = "dql.query.fingerprint"
+
+	// SpanErrorKind is the kind of the error of a failed execution, see ErrorKindName.
+	SpanErrorKind = "dql.error.kind"
+
+	// SpanParsingNs is the time Dgraph spent parsing the query, in nanoseconds.
+	SpanParsingNs = "dql.server_latency.parsing_ns"
+
+	// SpanProcessingNs is the time Dgraph spent processing the query, in nanoseconds.
+	SpanProcessingNs = "dql.server_latency.processing_ns"
+
+	// SpanEncodingNs is the time Dgraph spent encoding the response, in nanoseconds.
+	SpanEncodingNs = "dql.server_latency.encoding_ns"
+
+	// SpanTotalNs is the total time Dgraph spent on the query, in nanoseconds.
+	SpanTotalNs = "dql.server_latency.total_ns"
+)
+
+// serverLatency is the latency breakdown Dgraph reports in the extensions of a response.
+type serverLatency struct {
+	ParsingNs    int64 `json:"parsing_ns"`
+	ProcessingNs int64 `json:"processing_ns"`
+	EncodingNs   int64 `json:"encoding_ns"`
+	TotalNs      int64 `json:"total_ns"`
+}
+
+// TracingMiddleware creates a Middleware recording a span for every execution of a query.
+//
+// Each span is named after the query and carries its name and shape fingerprint, and, when
+// the Executor reports the extensions of the response, the latency of each phase of the
+// execution in Dgraph. Failed executions carry the kind of their error. Placed before
+// RetryMiddleware in Chain, the span covers every attempt, which ObservedRetryPolicy counts.
+//
+// Parameters:
+//   - t: The Tracer starting the spans.
+//
+// Returns:
+//   - A Middleware.
+//
+// Example:
+//
+//	// tracer implements Tracer on top of the tracer of the application.
+//	exec := Chain(client, TracingMiddleware(tracer), RetryMiddleware(DefaultRetryPolicy()))
+func TracingMiddleware(t Tracer) Middleware {
+	return func(next Executor) Executor {
+		return ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+			name := MetricsName(q)
+			ctx, span := t.StartSpan(ctx, name)
+			span.SetAttribute(SpanQueryName, name)
+			span.SetAttribute(SpanQueryFingerprint, q.ShapeFingerprint())
+			resp, err := next.Execute(ctx, q, vars)
+			if err != nil {
+				span.SetAttribute(SpanErrorKind, ErrorKindName(err))
+			} else if resp != nil && len(resp.Extensions) != 0 {
+				var ext struct {
+					ServerLatency serverLatency `json:"server_latency"`
+				}
+				// Extensions that cannot be decoded only leave the latency out of the span.
+				if json.Unmarshal(resp.Extensions, &ext) == nil {
+					span.SetAttribute(SpanParsingNs, ext.ServerLatency.ParsingNs)
+					span.SetAttribute(SpanProcessingNs, ext.ServerLatency.ProcessingNs)
+					span.SetAttribute(SpanEncodingNs, ext.ServerLatency.EncodingNs)
+					span.SetAttribute(SpanTotalNs, ext.ServerLatency.TotalNs)
+				}
+			}
+			span.End(err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package dql
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// recordingTracer records the spans it starts.
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+// recordingSpan is a span recorded by a recordingTracer.
+type recordingSpan struct {
+	name  string
+	attrs map[string]any
+	ended bool
+	err   error
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	s := &recordingSpan{name: name, attrs: map[string]any{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (s *recordingSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+
+func (s *recordingSpan) End(err error) { s.ended, s.err = true, err }
+
+func TestTracingMiddleware(t *testing.T) {
+	errFailed := &Error{Kind: ErrExec, Err: errors.New("unavailable")}
+	tests := []struct {
+		name      string
+		resp      *Response
+		err       error
+		wantAttrs map[string]any
+	}{
+		{"latency", &Response{Extensions: []byte(`{"server_latency": {"parsing_ns": 10, "processing_ns": 20, "encoding_ns": 5, "total_ns": 35}}`)}, nil,
+			map[string]any{SpanParsingNs: int64(10), SpanProcessingNs: int64(20), SpanEncodingNs: int64(5), SpanTotalNs: int64(35)}},
+		{"no extensions", &Response{}, nil, map[string]any{}},
+		{"error", nil, errFailed, map[string]any{SpanErrorKind: "exec"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &recordingTracer{}
+			next := ExecutorFunc(func(ctx context.Context, q *Query, vars map[string]string) (*Response, error) {
+				return tt.resp, tt.err
+			})
+			q := NewQuery("UserByName", NewQueryBlock("me", Eq("name", "Alice")))
+			if _, err := Chain(next, TracingMiddleware(tracer)).Execute(context.Background(), q, nil); err != tt.err {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.err)
+			}
+			if len(tracer.spans) != 1 {
+				t.Fatalf("started %d spans, want 1", len(tracer.spans))
+			}
+			s := tracer.spans[0]
+			if s.name != "UserByName" || !s.ended || s.err != tt.err {
+				t.Errorf("span %q ended %v with %v, want UserByName ended with %v", s.name, s.ended, s.err, tt.err)
+			}
+			want := map[string]any{SpanQueryName: "UserByName", SpanQueryFingerprint: q.ShapeFingerprint()}
+			for k, v := range tt.wantAttrs {
+				want[k] = v
+			}
+			if len(s.attrs) != len(want) {
+				t.Errorf("attributes = %v, want %v", s.attrs, want)
+			}
+			for k, v := range want {
+				if s.attrs[k] != v {
+					t.Errorf("attribute %s = %v, want %v", k, s.attrs[k], v)
+				}
+			}
+		})
+	}
+}
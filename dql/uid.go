@@ -0,0 +1,212 @@
+package dql
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// uidRefPattern matches the values accepted where Dgraph expects uids: hexadecimal and decimal
+// uids, variable names, parameter references and uid(variable) expressions.
+var uidRefPattern = regexp.MustCompile(`^(0[xX][0-9a-fA-F]+|[0-9]+|\$?[A-Za-z_][A-Za-z0-9_.]*|uid\(\s*[A-Za-z_][A-Za-z0-9_]*\s*\))$`)
+
+// UID is the uid of a node.
+//
+// UIDs render in lowercase hexadecimal, as Dgraph does, wherever a uid is expected: Uid,
+// WithAfter and NewShortestPath accept them as is. They marshal to JSON and text as hexadecimal
+// strings, and unmarshal from hexadecimal or decimal strings and from numbers, so they can be
+// used as the type of the uid field of a struct decoded with Decode, or of the uid lists of its
+// edges. The zero UID is not a valid uid and means no node.
+//
+// Example:
+//
+//	var node struct {
+//	    UID     UID   `dql:"uid"`
+//	    Friends []UID `dql:"friend"`
+//	}
+//	err := Decode([]byte(`{"uid": "0x1a", "friend": [{"uid": "0x2"}]}`), &node)
+//	fmt.Println(Uid(node.UID).String()) // Output: uid(0x1a)
+type UID uint64
+
+// ParseUID parses a uid given in hexadecimal, such as 0x1a, or in decimal.
+//
+// Parameters:
+//   - s: The uid.
+//
+// Returns:
+//   - The UID.
+//   - An error if s is not a valid uid.
+func ParseUID(s string) (UID, error) {
+	lit, err := UidLit(s)
+	if err != nil {
+		return 0, wrapError(ErrSyntax, err)
+	}
+	n, _ := strconv.ParseUint(lit[2:], 16, 64)
+	return UID(n), nil
+}
+
+// String generates the hexadecimal representation of the uid, e.g. 0x1a.
+//
+// Returns:
+//   - A string representation of the uid.
+func (u UID) String() string {
+	return "0x" + strconv.FormatUint(uint64(u), 16)
+}
+
+// IsZero reports whether the uid is the zero UID, which means no node.
+//
+// Returns:
+//   - true if the uid is zero, false otherwise.
+func (u UID) IsZero() bool {
+	return u == 0
+}
+
+// MarshalText encodes the uid in hexadecimal.
+func (u UID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText decodes a uid given in hexadecimal or in decimal.
+func (u *UID) UnmarshalText(text []byte) error {
+	v, err := ParseUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// MarshalJSON encodes the uid as a hexadecimal string.
+func (u UID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes a uid given as a hexadecimal or decimal string, or as a number. null
+// leaves the uid unchanged.
+func (u *UID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("dql: invalid uid %s", data)
+		}
+		s = n.String()
+	}
+	return u.UnmarshalText([]byte(s))
+}
+
+// UidLit formats a uid literal, validating it.
+//
+// Uids are given as hexadecimal strings such as 0x1a, as decimal strings, as UIDs or as
+// unsigned integers, and are always rendered in lowercase hexadecimal. The result can be passed to
+// Uid, WithAfter and NewShortestPath.
+//
+// Parameters:
+//   - v: The uid, a string, a UID or an integer.
+//
+// Returns:
+//   - The uid in hexadecimal, e.g. 0x1a.
+//   - An error if v is not a valid uid.
+//
+// Example:
+//
+//	uid, err := UidLit(uint64(26))
+//	fmt.Println(Uid(uid).String()) // Output: uid(0x1a)
+//	_, err = UidLit("0xZZ")
+//	fmt.Println(err) // Output: dql: invalid uid "0xZZ"
+//
+// See: https://dgraph.io/docs/query-language/functions/#uid
+func UidLit(v any) (string, error) {
+	var n uint64
+	switch v := v.(type) {
+	case string:
+		s, base := strings.TrimSpace(v), 10
+		if uidPattern.MatchString(s) {
+			s, base = s[2:], 16
+		}
+		var err error
+		if n, err = strconv.ParseUint(s, base, 64); err != nil {
+			return "", fmt.Errorf("dql: invalid uid %q", v)
+		}
+	case UID:
+		n = uint64(v)
+	case uint64:
+		n = v
+	case uint:
+		n = uint64(v)
+	case uint32:
+		n = uint64(v)
+	case int:
+		if v < 0 {
+			return "", fmt.Errorf("dql: invalid uid %d", v)
+		}
+		n = uint64(v)
+	case int64:
+		if v < 0 {
+			return "", fmt.Errorf("dql: invalid uid %d", v)
+		}
+		n = uint64(v)
+	default:
+		return "", fmt.Errorf("dql: invalid uid %v of type %T", v, v)
+	}
+	if n == 0 {
+		return "", fmt.Errorf("dql: invalid uid %v: uids start at 0x1", v)
+	}
+	return "0x" + strconv.FormatUint(n, 16), nil
+}
+
+// validateUids checks the arguments of the uid and uid_in functions of criteria, and
+// reports malformed uids, as well as the arguments of type functions which are not type names.
+func validateUids(criteria []Criteria) error {
+	var err error
+	for _, c := range criteria {
+		walkCriteria(c, func(c Criteria) {
+			f, ok := c.(*Function)
+			if !ok || err != nil {
+				return
+			}
+			args := f.Args
+			switch f.Name {
+			case "type":
+				for _, a := range args {
+					switch a.(type) {
+					case ParamRef, Placeholder:
+						continue
+					}
+					if err == nil && !namePattern.MatchString(a.String()) {
+						err = fmt.Errorf("invalid type name %s", a)
+					}
+				}
+				return
+			case "uid":
+			case "uid_in":
+				if len(args) < 2 {
+					return
+				}
+				args = args[1:]
+			default:
+				return
+			}
+			for _, a := range args {
+				if err == nil {
+					err = validateUidRef(a.String())
+				}
+			}
+		})
+	}
+	return err
+}
+
+// validateUidRef reports a value that is neither a uid, a variable nor a parameter.
+func validateUidRef(s string) error {
+	s = strings.TrimSpace(s)
+	if uidRefPattern.MatchString(s) || s != "" && placeholderPattern.FindString(s) == s {
+		return nil
+	}
+	return fmt.Errorf("invalid uid %q", s)
+}
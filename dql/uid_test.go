@@ -0,0 +1,96 @@
+package dql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUidLit(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		want    string
+		wantErr string
+	}{
+		{"hex", "0x2A", "0x2a", ""},
+		{"decimal string", " 42 ", "0x2a", ""},
+		{"int", 42, "0x2a", ""},
+		{"uint64", uint64(1 << 63), "0x8000000000000000", ""},
+		{"zero", "0x0", "", `dql: invalid uid 0x0: uids start at 0x1`},
+		{"negative", -1, "", "dql: invalid uid -1"},
+		{"not a number", "alice", "", `dql: invalid uid "alice"`},
+		{"overflow", "0x10000000000000000", "", `dql: invalid uid "0x10000000000000000"`},
+		{"unsupported type", 1.5, "", "dql: invalid uid 1.5 of type float64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UidLit(tt.v)
+			if got != tt.want || errString(err) != tt.wantErr {
+				t.Errorf("UidLit() = %q, %q, want %q, %q", got, errString(err), tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUids(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"uids and variables", NewQuery("", NewQueryBlock("me", Uid("0x1", "42", "friends"))).WithVarBlocks(NewVarBlock(Has("friend")).WithName("friends")), ""},
+		{"param", NewQuery("Q", NewQueryBlock("me", Uid("$id"))).WithParam(NewParam("id", ParamString)), ""},
+		{"root", NewQuery("", NewQueryBlock("me", Uid("0x1 OR 1=1"))), `dql: query block "me": invalid uid "\"0x1 OR 1=1\""`},
+		{"filter", NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives(NewDirective("filter", NewFunction("uid_in", Raw("friend"), Raw("0xZZ"))))),
+			`dql: query block "me": invalid uid "0xZZ"`},
+		{"edge filter", NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(
+			NewAttribute("friend").WithDirectives(NewDirective("filter", Uid("a-b"))).WithAttributes(NewAttribute("name")))),
+			`dql: query block "me": friend: invalid uid "\"a-b\""`},
+		{"type", NewQuery("", NewQueryBlock("me", Type("Person Admin"))), `dql: query block "me": invalid type name "Person Admin"`},
+		{"shortest path", NewQuery("", NewQueryBlock("path", Uid("p"))).WithShortestPaths(NewShortestPath("0x1", "0x2)").WithName("p")),
+			`dql: shortest path "p": invalid uid "0x2)"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    UID
+		wantErr string
+	}{
+		{"hex", `"0x2A"`, 0x2a, ""},
+		{"decimal string", `"42"`, 0x2a, ""},
+		{"number", `42`, 0x2a, ""},
+		{"null", `null`, 0, ""},
+		{"zero", `"0x0"`, 0, "dql: invalid uid 0x0: uids start at 0x1"},
+		{"not a uid", `true`, 0, "dql: invalid uid true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got UID
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if got != tt.want || errString(err) != tt.wantErr {
+				t.Errorf("Unmarshal() = %v, %q, want %v, %q", got, errString(err), tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	data, err := json.Marshal(map[UID]UID{0x1: 0xff})
+	if err != nil || string(data) != `{"0x1":"0xff"}` {
+		t.Errorf("Marshal() = %s, %v, want {\"0x1\":\"0xff\"}", data, err)
+	}
+	if got := Uid(UID(0x1a), "0x2").String(); got != "uid(0x1a, 0x2)" {
+		t.Errorf("Uid() = %q, want uid(0x1a, 0x2)", got)
+	}
+	if u, err := ParseUID("26"); u != 0x1a || err != nil || u.IsZero() {
+		t.Errorf("ParseUID() = %v, %v, want 0x1a", u, err)
+	}
+}
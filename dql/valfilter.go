@@ -0,0 +1,130 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// valRefPattern matches the value variables read with val(), e.g. the score of
+// ge(val(score), 10).
+var valRefPattern = regexp.MustCompile(`\bval\(\s*([A-Za-z_][A-Za-z0-9_]*)\s*\)`)
+
+// Val creates a val(variable) expression, the value a value variable holds for each node, to
+// compare in filters such as ge(val(score), 10).
+//
+// The variable must be a value variable, assigned to a scalar predicate, an aggregation or a
+// math() expression with WithVar, or to a facet. Dgraph computes the filtered nodes before
+// their selection, so the variable must not be defined at or below the level the filter
+// applies to: in another block, typically a var block, or at an enclosing level of the same
+// block. Validate reports the other uses.
+//
+// Parameters:
+//   - variable: The name of the value variable.
+//
+// Returns:
+//   - A pointer to a Function object.
+//
+// Example:
+//
+//	scores := NewVarBlock(Has("director.film")).WithAttributes(
+//	    NewAttribute("director.film").WithAttributes(NewAttribute("count(starring)").WithVar("n")),
+//	    NewAttribute("sum(val(n))").WithVar("score"),
+//	)
+//	top := NewQueryBlock("top", Uid("score")).
+//	    WithDirectives(NewDirective("filter", Ge(Val("score"), 10))).
+//	    WithAttributes(NewAttribute("name"))
+//	query := NewQuery("", top).WithVarBlocks(scores)
+//	fmt.Println(query.String())
+//	// Output: { var (func: has(director.film)) { director.film { n as count(starring) } score as sum(val(n)) } top (func: uid(score)) @filter(ge(val(score), 10)) { name } }
+//
+// See: https://dgraph.io/docs/query-language/value-variables/
+func Val(variable string) *Function {
+	return NewFunction("val", Raw(variable))
+}
+
+// validateValueFilters reports the value variables compared in the root functions and @filter
+// directives of the blocks of the query that are uid variables, or that are defined at or below
+// the level they filter, see Val. Queries holding raw blocks or attributes are not checked.
+func (q *Query) validateValueFilters() error {
+	uidVars := map[string]bool{}
+	raw := false
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			raw = raw || n.Raw
+			uidVars[n.Name] = true
+		case *ShortestPath:
+			uidVars[n.Name] = true
+		case *QueryBlock:
+			raw = raw || n.Raw
+		case *Attribute:
+			raw = raw || n.Raw
+			if n.Var != "" && (len(n.Attributes) != 0 || n.Name == PredicateUID) {
+				uidVars[n.Var] = true
+			}
+		}
+		return true
+	})
+	if raw {
+		return nil
+	}
+	for _, vb := range q.VarBlocks {
+		if err := checkValueFilters(vb.Criteria, vb.Directives, vb.Attributes, uidVars); err != nil {
+			return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+		}
+	}
+	for _, qb := range q.QueryBlocks {
+		if err := checkValueFilters(qb.Criteria, qb.Directives, qb.Attributes, uidVars); err != nil {
+			return fmt.Errorf("dql: query block %q: %w", qb.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkValueFilters checks the value variables compared by the root functions and @filter
+// directives of a level whose selection set is attrs, then those of the nested levels.
+func checkValueFilters(criteria []Criteria, directives []Criteria, attrs []*Attribute, uidVars map[string]bool) error {
+	texts := []string{}
+	for _, c := range criteria {
+		if isRootFunction(c) {
+			texts = append(texts, c.String())
+		}
+	}
+	for _, d := range directives {
+		if directiveName(d) == "filter" {
+			texts = append(texts, d.String())
+		}
+	}
+	below := map[string]bool{}
+	valueVarsBelow(attrs, below)
+	for _, text := range texts {
+		for _, m := range valRefPattern.FindAllStringSubmatch(text, -1) {
+			switch name := m[1]; {
+			case uidVars[name]:
+				return fmt.Errorf("val(%s): %q is a uid variable, compare it with uid() instead", name, name)
+			case below[name]:
+				return fmt.Errorf("val(%s): %q is defined within the filtered level", name, name)
+			}
+		}
+	}
+	for _, a := range attrs {
+		if err := checkValueFilters(nil, a.Directives, a.Attributes, uidVars); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// valueVarsBelow adds the variables defined by a selection set and its nested selection sets
+// to vars.
+func valueVarsBelow(attrs []*Attribute, vars map[string]bool) {
+	for _, a := range attrs {
+		if a.Var != "" {
+			vars[a.Var] = true
+		}
+		for _, name := range facetVars(a.Directives) {
+			vars[name] = true
+		}
+		valueVarsBelow(a.Attributes, vars)
+	}
+}
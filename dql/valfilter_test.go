@@ -0,0 +1,37 @@
+package dql
+
+import "testing"
+
+func TestValueFilters(t *testing.T) {
+	scores := NewVarBlock(Has("score")).WithAttributes(NewAttribute("score").WithVar("s"))
+	tests := []struct {
+		name    string
+		q       *Query
+		want    string
+		wantErr string
+	}{
+		{"value variable", NewQuery("", NewQueryBlock("top", Has("score")).WithDirectives(NewDirective("filter", Gt(Val("s"), 10)))).WithVarBlocks(scores),
+			"{ var (func: has(score)) { s as score } top (func: has(score)) @filter(gt(val(s), 10)) { } }", ""},
+		{"uid variable", NewQuery("", NewQueryBlock("top", Has("score")).WithDirectives(NewDirective("filter", Gt(Val("f"), 1)))).
+			WithVarBlocks(NewVarBlock(Has("friend")).WithAttributes(NewAttribute("friend").WithVar("f").WithAttributes(NewAttribute("name")))),
+			"", `dql: query block "top": val(f): "f" is a uid variable, compare it with uid() instead`},
+		{"var block name", NewQuery("", NewQueryBlock("top", Eq(Val("v"), 1))).WithVarBlocks(NewVarBlock(Has("friend")).WithName("v")),
+			"", `dql: query block "top": val(v): "v" is a uid variable, compare it with uid() instead`},
+		{"defined below", NewQuery("", NewQueryBlock("top", Has("score")).WithDirectives(NewDirective("filter", Gt(Val("a"), 1))).
+			WithAttributes(NewAttribute("age").WithVar("a"))),
+			"", `dql: query block "top": val(a): "a" is defined within the filtered level`},
+		{"nested edge", NewQuery("", NewQueryBlock("top", Has("score")).WithAttributes(
+			NewAttribute("friend").WithDirectives(NewDirective("filter", Gt(Val("a"), 1))).WithAttributes(NewAttribute("age").WithVar("a")))),
+			"", `dql: query block "top": friend: val(a): "a" is defined within the filtered level`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+			if tt.want != "" && tt.q.String() != tt.want {
+				t.Errorf("String() = %s, want %s", tt.q.String(), tt.want)
+			}
+		})
+	}
+}
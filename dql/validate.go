@@ -0,0 +1,222 @@
+package dql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// uidPattern matches uid literals such as 0x1a.
+var uidPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]+$`)
+
+// validatePagination checks the pagination arguments of a block or an edge.
+//
+// The offset must not be negative, first must not be zero, since it would always return
+// no results, and after must be a uid. Arguments bound to query parameters or placeholders
+// are only known at runtime and are not checked.
+func validatePagination(criteria []Criteria) error {
+	for _, c := range criteria {
+		name := argName(c)
+		if name != "first" && name != "offset" && name != "after" {
+			continue
+		}
+		var v string
+		if arg, ok := c.(*Arg); ok {
+			v = arg.Value.String()
+		} else {
+			_, v, _ = strings.Cut(c.String(), ":")
+		}
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "$") || placeholderPattern.MatchString(v) {
+			continue
+		}
+		if name == "after" {
+			if !uidPattern.MatchString(v) {
+				return fmt.Errorf("after: %q is not a uid", v)
+			}
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not an integer", name, v)
+		}
+		if name == "first" && n == 0 {
+			return fmt.Errorf("first: must not be zero")
+		}
+		if name == "offset" && n < 0 {
+			return fmt.Errorf("offset: %d is negative", n)
+		}
+	}
+	return nil
+}
+
+// rootDirectives lists the directives only allowed on the root of a block.
+var rootDirectives = map[string]bool{"recurse": true, "ignorereflex": true}
+
+// edgeDirectives lists the directives only allowed on attributes.
+var edgeDirectives = map[string]bool{"facets": true}
+
+// nestedDirectives lists the directives only allowed on blocks and on attributes with nested
+// attributes, since they apply to the nodes an edge leads to.
+var nestedDirectives = map[string]bool{"normalize": true, "groupby": true}
+
+// validateDirectives checks that directives are placed where Dgraph accepts them: on the root
+// of a block if root is set, and on an attribute otherwise, without nested attributes if leaf
+// is set.
+func validateDirectives(directives []Criteria, root bool, leaf bool) error {
+	for _, d := range directives {
+		name := directiveName(d)
+		switch {
+		case root && edgeDirectives[name]:
+			return fmt.Errorf("@%s is not allowed on blocks", name)
+		case !root && rootDirectives[name]:
+			return fmt.Errorf("@%s is only allowed on blocks", name)
+		case leaf && nestedDirectives[name]:
+			return fmt.Errorf("@%s is not allowed on attributes without nested attributes", name)
+		}
+	}
+	return nil
+}
+
+// validateAttributes checks a selection set and all of its nested selection sets.
+//
+// Attribute names must be valid predicate names unless they are expressions such as
+// count(uid), pagination arguments are checked with validatePagination and directives with
+// validateDirectives and validateFacetFilters, and expand() attributes with validateExpand.
+// Aliases must be unique within a selection set and must not shadow the name of an unaliased
+// sibling, since both would end up under the same key of the response. Raw attributes are not
+// checked.
+func validateAttributes(attrs []*Attribute) error {
+	aliases := map[string]bool{}
+	names := map[string]bool{}
+	for _, a := range attrs {
+		if a.Raw {
+			continue
+		}
+		if err := validatePagination(a.Args); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if a.ArgsAfterDirectives && len(a.Args) != 0 && len(a.Directives) != 0 && !a.argsAfterDirectives() {
+			return fmt.Errorf("%s: arguments cannot follow %s, which has no arguments", a.Name, strings.TrimSpace(a.Directives[len(a.Directives)-1].String()))
+		}
+		if isExpand(a.Name) {
+			if err := validateExpand(a); err != nil {
+				return fmt.Errorf("%s: %w", a.Name, err)
+			}
+		}
+		if err := validateDirectives(a.Directives, false, len(a.Attributes) == 0); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if err := validateFacetFilters(a.Directives, len(a.Attributes) == 0); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if err := validateUids(a.Directives); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if err := validateCounts(a.Directives); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if err := validateFacetVarNames(a.Directives); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if a.Var != "" {
+			if err := validateName("variable name", a.Var); err != nil {
+				return fmt.Errorf("%s: %w", a.Name, err)
+			}
+		}
+		if !isExpression(a.Name) {
+			if err := ValidatePredicate(a.Name); err != nil {
+				return fmt.Errorf("invalid predicate name %q", a.Name)
+			}
+		}
+		if a.Alias == "" {
+			names[a.Name] = true
+			continue
+		}
+		if err := validateName("alias", a.Alias); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		if aliases[a.Alias] {
+			return fmt.Errorf("duplicate alias %q", a.Alias)
+		}
+		aliases[a.Alias] = true
+	}
+	for alias := range aliases {
+		if names[alias] {
+			return fmt.Errorf("alias %q conflicts with attribute %q", alias, alias)
+		}
+	}
+	for _, a := range attrs {
+		if err := validateAttributes(a.Attributes); err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// variableTokenPattern matches the words of an expression referencing variables, such as the
+// a, b, $p and 2 of math(a + b * $p / 2), and the uids of uid(0x1).
+var variableTokenPattern = regexp.MustCompile(`\$?[A-Za-z0-9_]+`)
+
+// validateVariables reports references to variables the query does not define.
+//
+// Variables are defined by named variable and shortest path blocks, by attributes assigned
+// with WithVar and by facets assigned in @facets directives. Queries holding raw blocks or
+// attributes are not checked, since their definitions are unknown.
+func (q *Query) validateVariables() error {
+	defined := map[string]bool{}
+	refs := []string{}
+	raw := false
+	texts := func(lists ...[]Criteria) {
+		for _, list := range lists {
+			for _, c := range list {
+				refs = append(refs, c.String())
+			}
+		}
+	}
+	Walk(q, func(n Node) bool {
+		switch n := n.(type) {
+		case *VarBlock:
+			raw = raw || n.Raw
+			defined[n.Name] = true
+			texts(n.Criteria, n.Directives)
+		case *ShortestPath:
+			defined[n.Name] = true
+			for _, endpoint := range []string{n.From, n.To} {
+				if variableTokenPattern.FindString(endpoint) == endpoint {
+					endpoint = "uid(" + endpoint + ")"
+				}
+				refs = append(refs, endpoint)
+			}
+		case *QueryBlock:
+			raw = raw || n.Raw
+			texts(n.Criteria, n.Directives)
+		case *Attribute:
+			raw = raw || n.Raw
+			defined[n.Var] = true
+			for _, name := range facetVars(n.Directives) {
+				defined[name] = true
+			}
+			refs = append(refs, n.Name)
+			texts(n.Args, n.Directives)
+		}
+		return true
+	})
+	if raw {
+		return nil
+	}
+	for _, ref := range refs {
+		for _, m := range variableRefPattern.FindAllStringSubmatch(ref, -1) {
+			for _, name := range variableTokenPattern.FindAllString(m[1], -1) {
+				if name[0] == '$' || name[0] >= '0' && name[0] <= '9' {
+					continue
+				}
+				if !defined[name] {
+					return fmt.Errorf("dql: undefined variable %q", name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package dql
+
+import "testing"
+
+func TestValidateDirectives(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *Query
+		wantErr string
+	}{
+		{
+			name: "valid placement",
+			query: NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives("@recurse(depth: 3)", "@normalize").WithAttributes(
+				NewAttribute("friend").WithDirectives("@facets(since)", "@normalize").WithAttributes(NewAttribute("name")),
+			)),
+		},
+		{
+			name:    "facets on a block",
+			query:   NewQuery("", NewQueryBlock("me", Has("user")).WithDirectives("@facets")),
+			wantErr: `dql: query block "me": @facets is not allowed on blocks`,
+		},
+		{
+			name:    "recurse on an attribute",
+			query:   NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("friend").WithDirectives("@recurse").WithAttributes(NewAttribute("name")))),
+			wantErr: `dql: query block "me": friend: @recurse is only allowed on blocks`,
+		},
+		{
+			name:    "groupby on a leaf attribute",
+			query:   NewQuery("", NewQueryBlock("me", Has("user")).WithAttributes(NewAttribute("friend").WithDirectives("@groupby(age)"))),
+			wantErr: `dql: query block "me": friend: @groupby is not allowed on attributes without nested attributes`,
+		},
+		{
+			name:    "facets on a var block",
+			query:   NewQuery("", NewQueryBlock("me", Uid("f"))).WithVarBlocks(NewVarBlock(Has("user")).WithName("f").WithDirectives("@facets")),
+			wantErr: `dql: var block "f": @facets is not allowed on blocks`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.query.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
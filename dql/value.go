@@ -0,0 +1,203 @@
+package dql
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quoteReplacer escapes the characters that cannot appear as-is in a DQL string literal.
+var quoteReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+// Quote renders a string as a DQL string literal.
+//
+// Backslashes, double quotes, newlines, carriage returns and tabs are escaped, so user input
+// cannot terminate the literal and inject DQL into the query.
+//
+// Parameters:
+//   - s: The string to quote.
+//
+// Returns:
+//   - The quoted string literal.
+//
+// Example:
+//
+//	fmt.Println(Quote(`Robert"); drop`)) // Output: "Robert\"); drop"
+func Quote(s string) string {
+	return `"` + quoteReplacer.Replace(s) + `"`
+}
+
+// Literal is a value used as the argument of a function, such as the "Alice" of
+// eq(name, "Alice").
+//
+// Literals are rendered with SafeValue. Keeping them apart from the other arguments allows
+// the strict mode of a Query to find them and lift them into parameters.
+type Literal struct {
+	// Value is the Go value of the literal.
+	Value any
+}
+
+// String generates the DQL literal of the value.
+//
+// Returns:
+//   - The DQL literal of the value.
+func (l Literal) String() string {
+	return SafeValue(l.Value)
+}
+
+// List is a list of values such as the [30, 31] of eq(age, [30, 31]).
+type List []Criteria
+
+// String generates a string representation of the list.
+//
+// Returns:
+//   - A string representation of the list.
+func (l List) String() string {
+	return "[" + joinCriteria(l) + "]"
+}
+
+func (l List) args() []Criteria {
+	return l
+}
+
+func (l List) withArgs(args []Criteria) Criteria {
+	return List(args)
+}
+
+// value wraps a function argument into a Literal unless it already is a node of the package,
+// such as Raw or a Function, and replaces parameters with a reference to them. Other values
+// implementing fmt.Stringer, such as times or durations, are literals.
+func value(v any) Criteria {
+	if p, ok := v.(*Param); ok {
+		return p.Ref()
+	}
+	if c, ok := v.(Criteria); ok && isNode(c) {
+		return c
+	}
+	return Literal{v}
+}
+
+// isNode reports whether c is one of the criteria of the package, which render valid DQL,
+// rather than another fmt.Stringer whose rendering cannot be trusted.
+func isNode(c Criteria) bool {
+	switch c.(type) {
+	case Raw, ParamRef, *Function, Literal, List, Placeholder, UID, FacetVar, *Directive, *Arg, *Connective:
+		return true
+	}
+	return false
+}
+
+// SafeValue renders a Go value as a DQL literal.
+//
+// Strings are quoted with Quote, booleans and numbers are rendered as-is, including *big.Int
+// and *big.Float values without rounding, and times are rendered as quoted RFC 3339 strings.
+// DQL has no literal for NaN and infinite floats, which are quoted, e.g. "NaN" or "+Inf", so the
+// query stays well-formed; Validate reports them.
+// The criteria of the package, such as Raw, are rendered verbatim, which allows passing
+// expressions like val(a). Any other value, including other fmt.Stringer values such as
+// time.Duration, is formatted with fmt.Sprint and quoted.
+//
+// Parameters:
+//   - v: The value to render.
+//
+// Returns:
+//   - The DQL literal of the value.
+//
+// Example:
+//
+//	fmt.Println(SafeValue("Alice"), SafeValue(42), SafeValue(true)) // Output: "Alice" 42 true
+func SafeValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		if !isFinite(v) {
+			return Quote(strconv.FormatFloat(float64(v), 'g', -1, 32))
+		}
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		if !isFinite(v) {
+			return Quote(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return Quote(v.Format(time.RFC3339Nano))
+	case *big.Int:
+		return v.String()
+	case *big.Float:
+		if v.IsInf() {
+			return Quote(v.Text('g', -1))
+		}
+		return v.Text('g', -1)
+	case decimal:
+		return string(v)
+	case regex:
+		return v.String()
+	case Criteria:
+		if isNode(v) {
+			return v.String()
+		}
+		return Quote(v.String())
+	default:
+		return Quote(fmt.Sprint(v))
+	}
+}
+
+// isFinite reports whether v is not a NaN or infinite float. Values of other types are finite.
+func isFinite(v any) bool {
+	switch v := v.(type) {
+	case float32:
+		return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	case *big.Float:
+		return !v.IsInf()
+	}
+	return true
+}
+
+// validateNumbers reports the first literal of the query that is a NaN or infinite float.
+func (q *Query) validateNumbers() error {
+	var err error
+	for _, list := range criteriaLists(q) {
+		for _, c := range list {
+			walkCriteria(c, func(c Criteria) {
+				if l, ok := c.(Literal); ok && !isFinite(l.Value) && err == nil {
+					err = fmt.Errorf("dql: literal %v is not a finite number", l.Value)
+				}
+			})
+		}
+	}
+	return err
+}
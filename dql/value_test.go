@@ -0,0 +1,77 @@
+package dql
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Alice", `"Alice"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\dir`, `"C:\\dir"`},
+		{"tab\tline\r\n", `"tab\tline\r\n"`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSafeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"string", "Alice", `"Alice"`},
+		{"int", 42, "42"},
+		{"negative int64", int64(-7), "-7"},
+		{"uint8", uint8(255), "255"},
+		{"float", 1.5, "1.5"},
+		{"float32", float32(0.25), "0.25"},
+		{"NaN", math.NaN(), `"NaN"`},
+		{"infinite", math.Inf(-1), `"-Inf"`},
+		{"bool", false, "false"},
+		{"time", time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), `"2024-01-02T03:04:05.000000006Z"`},
+		{"function", Has("name"), "has(name)"},
+		{"raw", Raw("val(x)"), "val(x)"},
+		{"other", []int{1}, `"[1]"`},
+		{"duration", time.Second, `"1s"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeValue(tt.in); got != tt.want {
+				t.Errorf("SafeValue(%v) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query
+		wantErr string
+	}{
+		{"finite", NewQuery("", NewQueryBlock("me", Ge("score", 1.5))), ""},
+		{"NaN", NewQuery("", NewQueryBlock("me", Ge("score", math.NaN()))), "dql: literal NaN is not a finite number"},
+		{"filter", NewQuery("", NewQueryBlock("me", Has("score")).WithDirectives(NewDirective("filter", Lt("score", math.Inf(1))))),
+			"dql: literal +Inf is not a finite number"},
+		{"weight", NewQuery("", NewQueryBlock("me", Uid("p"))).WithShortestPaths(NewShortestPath("0x1", "0x2").
+			WithName("p").WithAttributes(NewAttribute("road")).WithWeights(0, math.Inf(1))),
+			`dql: shortest path "p": weight +Inf is not a finite number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(tt.q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -13,19 +13,26 @@ type VarBlock struct {
 	Name string
 
 	// Criteria defines the function or condition used in the variable block.
-	Criteria []string
+	Criteria []Criteria
 
 	// Attributes is a list of attributes included in the variable block.
 	Attributes []*Attribute
 
 	// Directives is a list of directives applied to the variable block.
-	Directives []string
+	Directives []Criteria
+
+	// Raw marks the variable block as verbatim DQL held in Name, see NewRawVarBlock.
+	Raw bool
+
+	// frozen rejects modifications, see Query.Freeze.
+	frozen bool
 }
 
 // NewVarBlock creates a new VarBlock with the specified criteria.
 //
 // Parameters:
-//   - criteria: The function or condition used in the variable block.
+//   - criteria: The function or condition used in the variable block, either a Criteria
+//     such as Has("user") or a string.
 //
 // Returns:
 //   - A pointer to a VarBlock object.
@@ -36,9 +43,9 @@ type VarBlock struct {
 //	fmt.Println(varBlock.String()) // Output: var(func: has(user)) { }
 //
 // See: https://dgraph.io/docs/dql/dql-syntax/dql-query/#variable-var-block
-func NewVarBlock(criteria string) *VarBlock {
+func NewVarBlock(criteria any) *VarBlock {
 	return &VarBlock{
-		Criteria: []string{criteria},
+		Criteria: []Criteria{toCriteria(criteria)},
 	}
 }
 
@@ -55,6 +62,7 @@ func NewVarBlock(criteria string) *VarBlock {
 //	varBlock := NewVarBlock("has(user)").WithName("userVar")
 //	fmt.Println(varBlock.String()) // Output: userVar AS var(func: has(user)) { }
 func (vb *VarBlock) WithName(name string) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
 	vb.Name = name
 	return vb
 }
@@ -62,7 +70,7 @@ func (vb *VarBlock) WithName(name string) *VarBlock {
 // WithCriteria adds one or more criteria to the var block.
 //
 // Parameters:
-//   - criteria: One or more criteria to add to the var block.
+//   - criteria: One or more criteria to add to the var block, either Criteria or strings.
 //
 // Returns:
 //   - The updated VarBlock object.
@@ -72,17 +80,84 @@ func (vb *VarBlock) WithName(name string) *VarBlock {
 //	varBlock := NewVarBlock("has(user)").
 //	    WithCriteria("orderasc: name@en")
 //	fmt.Println(varBlock.String()) // Output: var(func: has(user), orderasc: name@en) { }
-func (qb *VarBlock) WithCriteria(criteria ...string) *VarBlock {
+func (qb *VarBlock) WithCriteria(criteria ...any) *VarBlock {
+	mustBeMutable(qb.frozen, "var block", qb.Name)
 	for _, c := range criteria {
-		qb.Criteria = append(qb.Criteria, c)
+		qb.Criteria = append(qb.Criteria, toCriteria(c))
 	}
 	return qb
 }
 
+// WithFirst limits the variable block to the first n results.
+//
+// Parameters:
+//   - n: The number of results, an int or a ParamRef bound when the query is executed.
+//
+// Returns:
+//   - The updated VarBlock object.
+//
+// Example:
+//
+//	varBlock := NewVarBlock("has(user)").
+//	    WithOrderAsc("name").
+//	    WithFirst(10)
+//	fmt.Println(varBlock.String()) // Output: var (func: has(user), orderasc: name, first: 10) { }
+func (vb *VarBlock) WithFirst(n any) *VarBlock {
+	return vb.WithCriteria(NewArg("first", n))
+}
+
+// WithOffset skips the first n results of the variable block.
+//
+// Parameters:
+//   - n: The number of results to skip, an int or a ParamRef.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithOffset(n any) *VarBlock {
+	return vb.WithCriteria(NewArg("offset", n))
+}
+
+// WithAfter starts the results of the variable block after the given uid.
+//
+// Parameters:
+//   - uid: The uid after which results start, a string, a UID or a ParamRef.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithAfter(uid any) *VarBlock {
+	return vb.WithCriteria(NewArg("after", uid))
+}
+
+// WithOrderAsc orders the results of the variable block by a predicate in ascending order.
+//
+// Calling WithOrderAsc and WithOrderDesc several times adds secondary sort keys.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithOrderAsc(predicate string) *VarBlock {
+	return vb.WithCriteria(NewArg("orderasc", predicate))
+}
+
+// WithOrderDesc orders the results of the variable block by a predicate in descending order.
+//
+// Calling WithOrderAsc and WithOrderDesc several times adds secondary sort keys.
+//
+// Parameters:
+//   - predicate: The predicate to order by.
+//
+// Returns:
+//   - The updated VarBlock object.
+func (vb *VarBlock) WithOrderDesc(predicate string) *VarBlock {
+	return vb.WithCriteria(NewArg("orderdesc", predicate))
+}
+
 // WithDirectives adds one or more directives to the variable block.
 //
 // Parameters:
-//   - directives: One or more directives to add to the variable block.
+//   - directives: One or more directives, either Directive objects or strings, to add to the variable block.
 //
 // Returns:
 //   - The updated VarBlock object.
@@ -92,9 +167,10 @@ func (qb *VarBlock) WithCriteria(criteria ...string) *VarBlock {
 //	varBlock := NewVarBlock("has(user)").
 //	    WithDirectives("@filter(eq(name, \"John\"))")
 //	fmt.Println(varBlock.String()) // Output: var(func: has(user)) @filter(eq(name, "John")) { }
-func (vb *VarBlock) WithDirectives(directives ...string) *VarBlock {
+func (vb *VarBlock) WithDirectives(directives ...any) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
 	for _, d := range directives {
-		vb.Directives = append(vb.Directives, d)
+		vb.Directives = append(vb.Directives, toCriteria(d))
 	}
 	return vb
 }
@@ -113,6 +189,7 @@ func (vb *VarBlock) WithDirectives(directives ...string) *VarBlock {
 //	    WithAttributes(NewAttribute("name"), NewAttribute("age"))
 //	fmt.Println(varBlock.String()) // Output: var(func: has(user)) { name age }
 func (vb *VarBlock) WithAttributes(attrs ...*Attribute) *VarBlock {
+	mustBeMutable(vb.frozen, "var block", vb.Name)
 	for _, a := range attrs {
 		vb.Attributes = append(vb.Attributes, a)
 	}
@@ -126,13 +203,16 @@ func (vb *VarBlock) WithAttributes(attrs ...*Attribute) *VarBlock {
 // Returns:
 //   - A string representation of the variable block.
 func (vb *VarBlock) String() string {
+	if vb.Raw {
+		return vb.Name
+	}
 	components := []string{}
 	if vb.Name != "" {
 		components = append(components, vb.Name, "AS")
 	}
-	components = append(components, "var", fmt.Sprintf("(func: %s)", strings.Join(vb.Criteria, ", ")))
+	components = append(components, "var", blockArguments(vb.Criteria))
 	for _, f := range vb.Directives {
-		components = append(components, f)
+		components = append(components, f.String())
 	}
 	components = append(components, "{")
 	for _, attr := range vb.Attributes {
@@ -141,3 +221,42 @@ func (vb *VarBlock) String() string {
 	components = append(components, "}")
 	return strings.Join(components, " ")
 }
+
+// Validate checks the variable block and its attributes.
+//
+// Pagination arguments are checked as well: a negative offset, first: 0 or an after value
+// that is not a uid fail here rather than as a Dgraph error.
+//
+// Directives must be placed where Dgraph accepts them, e.g. @recurse only on blocks and
+// @facets only on attributes, and count() and len() expressions only as the first argument of
+// a comparison such as ge(count(friend), 3).
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the variable block is valid.
+func (vb *VarBlock) Validate() (err error) {
+	defer func() { err = wrapError(ErrValidation, err) }()
+	if vb.Raw {
+		return nil
+	}
+	if vb.Name != "" {
+		if err := validateName("variable name", vb.Name); err != nil {
+			return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+		}
+	}
+	if err := validatePagination(vb.Criteria); err != nil {
+		return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+	}
+	if err := validateDirectives(vb.Directives, true, false); err != nil {
+		return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+	}
+	if err := validateUids(append(append([]Criteria{}, vb.Criteria...), vb.Directives...)); err != nil {
+		return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+	}
+	if err := validateCounts(append(append([]Criteria{}, vb.Criteria...), vb.Directives...)); err != nil {
+		return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+	}
+	if err := validateAttributes(vb.Attributes); err != nil {
+		return fmt.Errorf("dql: var block %q: %w", vb.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package dql
+
+import (
+	"fmt"
+	"slices"
+)
+
+// paramPredicateTypes lists, by parameter type, the types of the predicates a parameter can be
+// compared with. String parameters are compared with any predicate, since Dgraph converts
+// their values to the type of the predicate as it does for string literals.
+var paramPredicateTypes = map[ParamType][]string{
+	ParamInt:   {"int", "float", "bigfloat"},
+	ParamFloat: {"float", "bigfloat"},
+	ParamBool:  {"bool"},
+}
+
+// ValidateVars checks the variables a query is executed with against the parameters it
+// declares.
+//
+// Variables must be declared by the query and their values must suit the type of their
+// parameter, e.g. an int parameter cannot be bound to "abc". Parameters without default must
+// be given a value.
+//
+// Parameters:
+//   - vars: The variables, given by name with their leading $.
+//
+// Returns:
+//   - An error describing the first problem found, or nil if the variables are valid.
+//
+// Example:
+//
+//	age := NewParam("age", ParamInt)
+//	query := NewQuery("Adults", NewQueryBlock("adults", Ge("age", age))).WithParam(age)
+//	fmt.Println(query.ValidateVars(map[string]string{"$age": "eighteen"}))
+//	// Output: dql: variable "$age": "eighteen" is not an int
+func (q *Query) ValidateVars(vars map[string]string) error {
+	if err := q.validateVars(vars); err != nil {
+		return fmt.Errorf("dql: %w", err)
+	}
+	return nil
+}
+
+// validateVars implements ValidateVars, without the dql: prefix of its errors.
+func (q *Query) validateVars(vars map[string]string) error {
+	declared := map[string]*Param{}
+	for _, p := range q.Params {
+		declared[p.Ref().String()] = p
+	}
+	for v, value := range vars {
+		p := declared[v]
+		if p == nil {
+			return wrapError(ErrUnknownVariable, fmt.Errorf("undeclared variable %q", v))
+		}
+		if !p.accepts(value) {
+			article := "a"
+			if p.Type == ParamInt {
+				article = "an"
+			}
+			return wrapError(ErrValidation, fmt.Errorf("variable %q: %q is not %s %s", v, value, article, p.Type))
+		}
+	}
+	for ref, p := range declared {
+		if _, ok := vars[ref]; !ok && p.Default == "" {
+			return wrapError(ErrValidation, fmt.Errorf("missing variable %q", ref))
+		}
+	}
+	return nil
+}
+
+// validateParamTypes reports the parameters compared by eq, le, lt, ge, gt and between with a
+// predicate whose type in the schema of the query does not suit the type of the parameter.
+func (q *Query) validateParamTypes() error {
+	declared := map[string]*Param{}
+	for _, p := range q.Params {
+		declared[p.Ref().String()] = p
+	}
+	var err error
+	for _, list := range criteriaLists(q) {
+		for _, c := range list {
+			if directiveName(c) == "facets" {
+				continue
+			}
+			walkCriteria(c, func(c Criteria) {
+				f, ok := c.(*Function)
+				if !ok || err != nil || !comparisonFunctions[f.Name] || len(f.Args) < 2 || isExpression(f.Args[0].String()) {
+					return
+				}
+				pred := q.Schema.Predicate(predicateOf(f.Args[0].String()))
+				if pred == nil {
+					return
+				}
+				args := f.Args[1:]
+				if list, ok := args[0].(List); ok && len(args) == 1 {
+					args = list
+				}
+				for _, arg := range args {
+					r, ok := arg.(ParamRef)
+					p := declared[r.String()]
+					if !ok || p == nil {
+						continue
+					}
+					if types, ok := paramPredicateTypes[p.Type]; ok && !slices.Contains(types, pred.Type) {
+						err = fmt.Errorf("%s(%s): param %s of type %s cannot be compared with %s predicate %q", f.Name, f.Args[0], r, p.Type, pred.Type, pred.Name)
+						return
+					}
+				}
+			})
+		}
+	}
+	return err
+}
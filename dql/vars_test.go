@@ -0,0 +1,70 @@
+package dql
+
+import "testing"
+
+func TestParamValues(t *testing.T) {
+	name := NewParam("name", ParamString)
+	age := NewParam("age", ParamInt)
+	q := NewQuery("Q", NewQueryBlock("me", Eq("name", name)).WithDirectives(NewDirective("filter", Ge("age", age)))).WithParam(name, age)
+	want := "query Q ( $name: string, $age: int ) { me (func: eq(name, $name)) @filter(ge(age, $age)) { } }"
+	if got := q.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if err := q.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateVars(t *testing.T) {
+	q := NewQuery("Q", NewQueryBlock("me", Has("name"))).WithParam(
+		NewParam("first", ParamInt), NewParam("score", ParamFloat), NewParam("active", ParamBool).WithDefault("true"))
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantErr string
+	}{
+		{"valid", map[string]string{"$first": "3", "$score": "1.5"}, ""},
+		{"all", map[string]string{"$first": "3", "$score": "2", "$active": "false"}, ""},
+		{"not an int", map[string]string{"$first": "3.5", "$score": "1"}, `dql: variable "$first": "3.5" is not an int`},
+		{"not a float", map[string]string{"$first": "3", "$score": "high"}, `dql: variable "$score": "high" is not a float`},
+		{"not a bool", map[string]string{"$first": "3", "$score": "1", "$active": "yes"}, `dql: variable "$active": "yes" is not a bool`},
+		{"missing", map[string]string{"$first": "3"}, `dql: missing variable "$score"`},
+		{"undeclared", map[string]string{"$first": "3", "$score": "1", "$x": "1"}, `dql: undeclared variable "$x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errString(q.ValidateVars(tt.vars)); got != tt.wantErr {
+				t.Errorf("ValidateVars() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateParamTypes(t *testing.T) {
+	schema, err := ParseSchema("age: int .\nscore: float .\nname: string .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		param   *Param
+		filter  *Function
+		wantErr string
+	}{
+		{"int against int", NewParam("v", ParamInt), Eq("age", ParamRef("$v")), ""},
+		{"int against float", NewParam("v", ParamInt), Gt("score", ParamRef("$v")), ""},
+		{"string against int", NewParam("v", ParamString), Eq("age", ParamRef("$v")), ""},
+		{"float against int", NewParam("v", ParamFloat), Eq("age", ParamRef("$v")),
+			`dql: eq(age): param $v of type float cannot be compared with int predicate "age"`},
+		{"bool in list", NewParam("v", ParamBool), Eq("name", "a", ParamRef("$v")),
+			`dql: eq(name): param $v of type bool cannot be compared with string predicate "name"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery("Q", NewQueryBlock("me", tt.filter)).WithParam(tt.param).WithSchema(schema)
+			if got := errString(q.Validate()); got != tt.wantErr {
+				t.Errorf("Validate() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
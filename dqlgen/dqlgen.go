@@ -0,0 +1,306 @@
+// Package dqlgen generates Go code building DQL queries with the dql package.
+//
+// It eases the migration from hand-written queries to programmatic ones: each query is
+// parsed with dql.Parse and turned into a function returning the equivalent *dql.Query,
+// ready to be edited further.
+package dqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"dql/dql"
+)
+
+// ImportPath is the import path of the dql package used by the generated code.
+const ImportPath = "dql/dql"
+
+// Source is a DQL query to generate a builder function for.
+type Source struct {
+	// Func is the name of the generated function.
+	Func string
+
+	// Name is the name of the file the query was read from, mentioned in the generated code.
+	Name string
+
+	// DQL is the text of the query.
+	DQL string
+}
+
+// FuncName derives the name of a builder function from the name of a .dql file, e.g.
+// GetUserQuery for queries/get_user.dql.
+//
+// Parameters:
+//   - filename: The name of the .dql file.
+//
+// Returns:
+//   - The name of the function.
+func FuncName(filename string) string {
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return identifier(base, "") + "Query"
+}
+
+// Generate generates a Go source file with one builder function per query.
+//
+// Parameters:
+//   - pkg: The name of the package of the generated file.
+//   - sources: The queries to generate functions for.
+//
+// Returns:
+//   - The formatted Go source.
+//   - An error if a query cannot be parsed or converted.
+//
+// Example:
+//
+//	src, err := Generate("queries", Source{
+//	    Func: "GetUserQuery",
+//	    Name: "get_user.dql",
+//	    DQL:  `{ me(func: uid(0x1)) { name } }`,
+//	})
+func Generate(pkg string, sources ...Source) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by dqlgen. DO NOT EDIT.\n\npackage %s\n\nimport %q\n", pkg, ImportPath)
+	for _, src := range sources {
+		q, err := dql.Parse(src.DQL)
+		if err != nil {
+			return nil, fmt.Errorf("dqlgen: %s: %w", src.Name, err)
+		}
+		body, err := QueryExpr(q)
+		if err != nil {
+			return nil, fmt.Errorf("dqlgen: %s: %w", src.Name, err)
+		}
+		if src.Name != "" {
+			fmt.Fprintf(&b, "\n// %s builds the query of %s.\n", src.Func, filepath.Base(src.Name))
+		} else {
+			fmt.Fprintf(&b, "\n// %s builds the query.\n", src.Func)
+		}
+		fmt.Fprintf(&b, "func %s() *dql.Query {\n\treturn %s\n}\n", src.Func, body)
+	}
+	res, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("dqlgen: format generated code: %w", err)
+	}
+	return res, nil
+}
+
+// QueryExpr generates the Go expression building a query.
+//
+// Parameters:
+//   - q: The query to generate the expression for.
+//
+// Returns:
+//   - The Go expression, using the dql package.
+//   - An error if the query cannot be expressed with the builders, e.g. because it has no
+//     query block.
+func QueryExpr(q *dql.Query) (string, error) {
+	if len(q.QueryBlocks) == 0 {
+		return "", fmt.Errorf("query %q has no query block", q.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "dql.NewQuery(%q, %s)", q.Name, queryBlock(q.QueryBlocks[0]))
+	if len(q.Params) != 0 {
+		b.WriteString(".\n\tWithParam(")
+		for _, p := range q.Params {
+			fmt.Fprintf(&b, "\n\t\t%s,", param(p))
+		}
+		b.WriteString("\n\t)")
+	}
+	if len(q.VarBlocks) != 0 {
+		b.WriteString(".\n\tWithVarBlocks(")
+		for _, vb := range q.VarBlocks {
+			fmt.Fprintf(&b, "\n\t\t%s,", varBlock(vb))
+		}
+		b.WriteString("\n\t)")
+	}
+	if len(q.ShortestPaths) != 0 {
+		b.WriteString(".\n\tWithShortestPaths(")
+		for _, sp := range q.ShortestPaths {
+			expr, err := shortestPath(sp)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\n\t\t%s,", expr)
+		}
+		b.WriteString("\n\t)")
+	}
+	if len(q.QueryBlocks) > 1 {
+		b.WriteString(".\n\tWithQueryBlocks(")
+		for _, qb := range q.QueryBlocks[1:] {
+			fmt.Fprintf(&b, "\n\t\t%s,", queryBlock(qb))
+		}
+		b.WriteString("\n\t)")
+	}
+	if len(q.Fragments) != 0 {
+		b.WriteString(".\n\tWithFragments(")
+		for _, f := range q.Fragments {
+			fmt.Fprintf(&b, "\n\t\tdql.NewFragment(%q)%s,", f.Name, withAttributes(f.Attributes))
+		}
+		b.WriteString("\n\t)")
+	}
+	if q.Strict {
+		b.WriteString(".WithStrict()")
+	}
+	if q.Debug {
+		b.WriteString(".WithDebug()")
+	}
+	if l := q.Limits; l != nil {
+		fmt.Fprintf(&b, ".WithLimits(dql.Limits{MaxDepth: %d, MaxAttributes: %d, MaxBlocks: %d})", l.MaxDepth, l.MaxAttributes, l.MaxBlocks)
+	}
+	return b.String(), nil
+}
+
+// param generates the expression building a parameter.
+func param(p *dql.Param) string {
+	types := map[dql.ParamType]string{
+		dql.ParamInt:    "dql.ParamInt",
+		dql.ParamFloat:  "dql.ParamFloat",
+		dql.ParamBool:   "dql.ParamBool",
+		dql.ParamString: "dql.ParamString",
+	}
+	t, ok := types[p.Type]
+	if !ok {
+		t = fmt.Sprintf("dql.ParamType(%q)", p.Type)
+	}
+	res := fmt.Sprintf("dql.NewParam(%q, %s)", p.Name, t)
+	if p.Default != "" {
+		res += fmt.Sprintf(".WithDefault(%q)", p.Default)
+	}
+	return res
+}
+
+// queryBlock generates the expression building a query block.
+func queryBlock(qb *dql.QueryBlock) string {
+	if qb.Raw {
+		return fmt.Sprintf("dql.NewRawQueryBlock(%q)", qb.Name)
+	}
+	var res string
+	if len(qb.Criteria) == 0 {
+		res = fmt.Sprintf("(&dql.QueryBlock{Name: %q})", qb.Name)
+	} else {
+		res = fmt.Sprintf("dql.NewQueryBlock(%q, %s)", qb.Name, criteria(qb.Criteria[0]))
+		res += withCriteria(qb.Criteria[1:])
+	}
+	return res + withDirectives(qb.Directives) + withAttributes(qb.Attributes)
+}
+
+// varBlock generates the expression building a variable block.
+func varBlock(vb *dql.VarBlock) string {
+	if vb.Raw {
+		return fmt.Sprintf("dql.NewRawVarBlock(%q)", vb.Name)
+	}
+	var res string
+	if len(vb.Criteria) == 0 {
+		res = "(&dql.VarBlock{})"
+	} else {
+		res = fmt.Sprintf("dql.NewVarBlock(%s)", criteria(vb.Criteria[0]))
+		res += withCriteria(vb.Criteria[1:])
+	}
+	if vb.Name != "" {
+		res += fmt.Sprintf(".WithName(%q)", vb.Name)
+	}
+	return res + withDirectives(vb.Directives) + withAttributes(vb.Attributes)
+}
+
+// shortestPath generates the expression building a shortest path block.
+func shortestPath(sp *dql.ShortestPath) (string, error) {
+	res := fmt.Sprintf("dql.NewShortestPath(%q, %q)", sp.From, sp.To)
+	if sp.Name != "" {
+		res += fmt.Sprintf(".WithName(%q)", sp.Name)
+	}
+	if sp.NumPaths != 0 {
+		res += fmt.Sprintf(".WithNumPaths(%d)", sp.NumPaths)
+	}
+	if sp.Depth != 0 {
+		res += fmt.Sprintf(".WithDepth(%d)", sp.Depth)
+	}
+	switch {
+	case sp.MinWeight != nil && sp.MaxWeight != nil:
+		res += fmt.Sprintf(".WithWeights(%s, %s)", formatFloat(*sp.MinWeight), formatFloat(*sp.MaxWeight))
+	case sp.MinWeight != nil || sp.MaxWeight != nil:
+		return "", fmt.Errorf("shortest path %q: minweight and maxweight must be set together", sp.Name)
+	}
+	return res + withAttributes(sp.Attributes), nil
+}
+
+// attribute generates the expression building an attribute.
+func attribute(a *dql.Attribute) string {
+	if a.Raw {
+		return fmt.Sprintf("dql.NewRawAttribute(%q)", a.Name)
+	}
+	res := fmt.Sprintf("dql.NewAttribute(%q)", a.Name)
+	if a.Alias != "" {
+		res += fmt.Sprintf(".WithAlias(%q)", a.Alias)
+	}
+	if a.Var != "" {
+		res += fmt.Sprintf(".WithVar(%q)", a.Var)
+	}
+	if len(a.Args) != 0 {
+		res += ".WithArgs(" + criteriaList(a.Args) + ")"
+	}
+	return res + withDirectives(a.Directives) + withAttributes(a.Attributes)
+}
+
+// withCriteria generates the WithCriteria call adding criteria to a block.
+func withCriteria(list []dql.Criteria) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return ".WithCriteria(" + criteriaList(list) + ")"
+}
+
+// withDirectives generates the WithDirectives call adding directives to a node.
+func withDirectives(list []dql.Criteria) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return ".WithDirectives(" + criteriaList(list) + ")"
+}
+
+// withAttributes generates the WithAttributes call adding attributes to a node.
+func withAttributes(attrs []*dql.Attribute) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(".WithAttributes(")
+	for _, a := range attrs {
+		b.WriteString("\n" + attribute(a) + ",")
+	}
+	b.WriteString("\n)")
+	return b.String()
+}
+
+// criteriaList generates the comma-separated expressions of a list of criteria.
+func criteriaList(list []dql.Criteria) string {
+	res := make([]string, len(list))
+	for i, c := range list {
+		res[i] = criteria(c)
+	}
+	return strings.Join(res, ", ")
+}
+
+// criteria generates the expression building a criteria. Criteria other than arguments,
+// directives and parameter references are generated as their rendered Raw text.
+func criteria(c dql.Criteria) string {
+	switch c := c.(type) {
+	case *dql.Arg:
+		return fmt.Sprintf("dql.NewArg(%q, %s)", c.Name, criteria(c.Value))
+	case *dql.Directive:
+		if len(c.Args) == 0 {
+			return fmt.Sprintf("dql.NewDirective(%q)", c.Name)
+		}
+		return fmt.Sprintf("dql.NewDirective(%q, %s)", c.Name, criteriaList(c.Args))
+	case dql.ParamRef:
+		return fmt.Sprintf("dql.ParamRef(%q)", string(c))
+	}
+	return fmt.Sprintf("dql.Raw(%q)", c.String())
+}
+
+// formatFloat formats a float as a Go literal.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,97 @@
+package dqlgen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"dql/dql"
+)
+
+func TestFuncName(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"queries/get_user.dql", "GetUserQuery"},
+		{"list-friends.dql", "ListFriendsQuery"},
+		{"me", "MeQuery"},
+	}
+	for _, tt := range tests {
+		if got := FuncName(tt.filename); got != tt.want {
+			t.Errorf("FuncName(%q) = %s, want %s", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     Source
+		want    []string
+		wantErr string
+	}{
+		{"query", Source{Func: "GetUserQuery", Name: "queries/get_user.dql", DQL: `query Q($name: string) { me(func: eq(name, $name), first: 10) { name f as friend { uid } } }`},
+			[]string{"// GetUserQuery builds the query of get_user.dql.", `dql.NewQuery("Q", dql.NewQueryBlock("me", dql.Raw("eq(name, $name)"))`, `WithVar("f")`, `dql.NewParam("name", dql.ParamString)`}, ""},
+		{"escaped", Source{Func: "EscapedQuery", DQL: `{ me(func: eq(name, "a\"b")) { name } }`},
+			[]string{"// EscapedQuery builds the query.", `dql.Raw("eq(name, \"a\\\"b\")")`}, ""},
+		{"syntax error", Source{Func: "BadQuery", Name: "bad.dql", DQL: `{ me(func: has(name)) {`}, nil, "dqlgen: bad.dql:"},
+		{"no query block", Source{Func: "VarQuery", Name: "var.dql", DQL: `{ var(func: has(name)) { n as name } }`}, nil, "has no query block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := Generate("queries", tt.src)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "queries.go", src, 0); err != nil {
+				t.Fatalf("generated code does not parse: %v\n%s", err, src)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("generated code has no %s:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		want    []string
+		wantErr bool
+	}{
+		{"type", "name: string @index(exact) .\nfriend: [uid] @reverse .\ntype Person { name friend }",
+			[]string{`PredName   = "name"`, "type Person struct {", "Friend []Node `json:\"friend,omitempty\"`",
+				"func (b *PersonQueryBuilder) Friend(sel Selection) *PersonQueryBuilder {"}, false},
+		{"bigfloat", "balance: bigfloat .\ntype Account { balance }",
+			[]string{"Balance json.Number `json:\"balance,omitempty\"`"}, false},
+		{"undefined predicate", "name: string .\ntype Person { name email }", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := dql.ParseSchema(tt.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src, err := GenerateSchema("model", schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateSchema() error = %v, want error %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("generated code has no %s:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package dqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"dql/dql"
+)
+
+// scalarTypes maps the scalar types of Dgraph to Go types.
+var scalarTypes = map[string]string{
+	"default":  "any",
+	"string":   "string",
+	"int":      "int64",
+	"float":    "float64",
+	"bigfloat": "json.Number",
+	"bool":     "bool",
+	"datetime": "time.Time",
+	"geo":      "json.RawMessage",
+	"password": "string",
+}
+
+// GenerateSchema generates a Go source file with typed code for a schema.
+//
+// The generated file holds:
+//   - a Pred constant per predicate, e.g. PredName for name,
+//   - a struct per type, whose fields are tagged to decode query responses,
+//   - a query builder per type, e.g. PersonQuery().Name().Friend(PersonQuery().Name()),
+//     with one method per field, so selections are checked against the schema at compile
+//     time.
+//
+// Edges point to the generated Node struct, since Dgraph types do not declare the type of
+// the nodes an edge leads to.
+//
+// Parameters:
+//   - pkg: The name of the package of the generated file.
+//   - schema: The schema to generate code for.
+//
+// Returns:
+//   - The formatted Go source.
+//   - An error if a type refers to a predicate the schema does not define.
+//
+// Example:
+//
+//	schema, err := dql.ParseSchema(`name: string . friend: [uid] . type Person { name friend }`)
+//	src, err := GenerateSchema("model", schema)
+func GenerateSchema(pkg string, schema *dql.Schema) ([]byte, error) {
+	var body bytes.Buffer
+	imports := map[string]bool{}
+
+	body.WriteString("\n// Predicates of the schema.\nconst (\n")
+	for _, p := range schema.Predicates {
+		fmt.Fprintf(&body, "\t%s = %q\n", predConst(p.Name), p.Name)
+	}
+	body.WriteString(")\n")
+
+	body.WriteString(`
+// Selection is implemented by the query builders of the types, to select the nested
+// attributes of an edge.
+type Selection interface {
+	Attributes() []*dql.Attribute
+}
+
+// Node is a node reached through an edge.
+type Node struct {
+	UID        string   ` + "`json:\"uid,omitempty\"`" + `
+	DgraphType []string ` + "`json:\"dgraph.type,omitempty\"`" + `
+}
+`)
+
+	for _, t := range schema.Types {
+		preds := []*dql.SchemaPredicate{}
+		for _, field := range t.Fields {
+			if strings.HasPrefix(strings.Trim(field, "<"), "~") {
+				continue
+			}
+			p := schema.Predicate(field)
+			if p == nil {
+				return nil, fmt.Errorf("dqlgen: type %q: undefined predicate %q", t.Name, field)
+			}
+			preds = append(preds, p)
+		}
+		typeName := identifier(t.Name, "")
+		builder := typeName + "QueryBuilder"
+
+		fmt.Fprintf(&body, "\n// %s is a node of type %s.\ntype %s struct {\n", typeName, t.Name, typeName)
+		body.WriteString("\tUID string `json:\"uid,omitempty\"`\n")
+		for _, p := range preds {
+			goType := "*Node"
+			if p.Type != "uid" {
+				goType = scalarTypes[p.Type]
+				if goType == "" {
+					goType = "any"
+				}
+				if goType == "time.Time" {
+					imports["time"] = true
+				}
+				if strings.HasPrefix(goType, "json.") {
+					imports["encoding/json"] = true
+				}
+			}
+			if p.List {
+				goType = "[]" + strings.TrimPrefix(goType, "*")
+			}
+			fmt.Fprintf(&body, "\t%s %s `json:\"%s,omitempty\"`\n", fieldName(t.Name, p.Name), goType, p.Name)
+		}
+		body.WriteString("}\n")
+
+		fmt.Fprintf(&body, `
+// %[1]s builds the selection of nodes of type %[2]s.
+type %[1]s struct {
+	attrs []*dql.Attribute
+}
+
+// %[3]sQuery creates a query builder for nodes of type %[2]s.
+func %[3]sQuery() *%[1]s {
+	return &%[1]s{}
+}
+
+// Attributes returns the selected attributes.
+func (b *%[1]s) Attributes() []*dql.Attribute {
+	return b.attrs
+}
+
+// Block creates a query block selecting the nodes of type %[2]s.
+func (b *%[1]s) Block(name string) *dql.QueryBlock {
+	return dql.NewQueryBlock(name, dql.Type(%[4]q)).WithAttributes(b.attrs...)
+}
+
+// UID selects the uid of the nodes.
+func (b *%[1]s) UID() *%[1]s {
+	b.attrs = append(b.attrs, dql.UIDAttribute())
+	return b
+}
+`, builder, t.Name, typeName, t.Name)
+		for _, p := range preds {
+			method := fieldName(t.Name, p.Name)
+			if method == "UID" || method == "Attributes" || method == "Block" {
+				method += "Field"
+			}
+			if p.Type == "uid" {
+				fmt.Fprintf(&body, `
+// %[2]s selects the %[3]s edge and the attributes of the nodes it leads to.
+func (b *%[1]s) %[2]s(sel Selection) *%[1]s {
+	b.attrs = append(b.attrs, dql.NewAttribute(%[4]s).WithAttributes(sel.Attributes()...))
+	return b
+}
+`, builder, method, p.Name, predConst(p.Name))
+				continue
+			}
+			fmt.Fprintf(&body, `
+// %[2]s selects the %[3]s predicate.
+func (b *%[1]s) %[2]s() *%[1]s {
+	b.attrs = append(b.attrs, dql.NewAttribute(%[4]s))
+	return b
+}
+`, builder, method, p.Name, predConst(p.Name))
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by dqlgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	for _, imp := range []string{"encoding/json", "time"} {
+		if imports[imp] {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+	}
+	fmt.Fprintf(&b, "\n\t%q\n)\n", ImportPath)
+	b.Write(body.Bytes())
+	res, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("dqlgen: format generated code: %w", err)
+	}
+	return res, nil
+}
+
+// predConst returns the name of the constant of a predicate.
+func predConst(name string) string {
+	return identifier(name, "Pred")
+}
+
+// fieldName returns the name of the struct field and builder method of a predicate of a
+// type, dropping the type prefix of predicates such as Person.name.
+func fieldName(typeName string, pred string) string {
+	return identifier(strings.TrimPrefix(pred, typeName+"."), "")
+}
+
+// identifier converts a name into an exported Go identifier with a prefix, e.g. PredFirstName
+// for first_name.
+func identifier(name string, prefix string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	res := b.String()
+	if res == "" || unicode.IsDigit(rune(res[0])) {
+		res = "X" + res
+	}
+	return res
+}
@@ -0,0 +1,636 @@
+// Package dqlhttp runs DQL queries against the HTTP API of a Dgraph Alpha.
+package dqlhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"dql/dql"
+)
+
+// Client runs queries and schema changes against the HTTP endpoint of a Dgraph Alpha.
+// Client implements dql.Executor and is safe for concurrent use.
+type Client struct {
+	// URL is the URL of the HTTP endpoint of the Dgraph Alpha, e.g. http://localhost:8080.
+	URL string
+
+	// HTTPClient is the client sending the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+
+	// AuthToken is sent in the X-Dgraph-AuthToken header, for Alphas started with an
+	// --security token.
+	AuthToken string
+
+	// APIKey is sent in the Dg-Auth header, for backends hosted on Dgraph Cloud, see
+	// NewCloudClient.
+	APIKey string
+
+	// ReadOnly runs queries in read-only transactions, which do not wait for pending writes.
+	ReadOnly bool
+
+	// BestEffort runs read-only queries with best-effort consistency, which may read
+	// slightly stale data in exchange for lower latency. It implies ReadOnly.
+	BestEffort bool
+
+	// QueryRetry retries the queries failing with transient errors, such as an unavailable
+	// Alpha, e.g. dql.DefaultRetryPolicy(). Queries are not retried if nil.
+	QueryRetry dql.RetryPolicy
+
+	// MutationRetry retries the mutations and upserts failing with transient errors, such as
+	// transaction aborts. Mutations are not retried if nil. A mutation whose response was
+	// lost may have been committed, so retried mutations should be idempotent, e.g. upserts.
+	MutationRetry dql.RetryPolicy
+
+	// Metrics receives the measurements of the mutations and upserts of the client and of the
+	// retries of its requests, if not nil. Queries are measured with dql.MetricsMiddleware.
+	Metrics dql.Metrics
+
+	mu           sync.Mutex
+	namespace    uint64
+	accessToken  string
+	refreshToken string
+	user         string
+	password     string
+}
+
+// NewClient creates a new Client for a Dgraph Alpha.
+//
+// Parameters:
+//   - url: The URL of the HTTP endpoint of the Dgraph Alpha.
+//
+// Returns:
+//   - A pointer to a Client object.
+//
+// Example:
+//
+//	client := NewClient("http://localhost:8080")
+//	resp, err := client.Execute(ctx, query, map[string]string{"$name": "Alice"})
+func NewClient(url string) *Client {
+	return &Client{URL: strings.TrimSuffix(url, "/")}
+}
+
+// Login logs into a namespace with the credentials of an ACL user.
+//
+// Subsequent queries and schema changes of the client apply to that namespace, so a
+// multi-tenant deployment can manage the schema of each tenant by logging a client into the
+// namespace of the tenant. Namespace 0 is the default namespace, the only one of deployments
+// without multi-tenancy.
+//
+// When the access token expires, the client refreshes it and retries the request, logging in
+// again with the credentials if the refresh token expired as well, so long-running services
+// need no refresh loop of their own.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - user: The name of the user.
+//   - password: The password of the user.
+//   - namespace: The namespace to log into.
+//
+// Returns:
+//   - An error if the credentials were rejected or the request failed.
+//
+// Example:
+//
+//	client := NewClient("http://localhost:8080")
+//	if err := client.Login(ctx, "groot", "password", 1); err != nil {
+//	    return err
+//	}
+//	err := client.Alter(ctx, schema) // Applies the schema to namespace 1.
+//
+// See: https://dgraph.io/docs/enterprise-features/multitenancy/
+func (c *Client) Login(ctx context.Context, user string, password string, namespace uint64) error {
+	err := c.login(ctx, map[string]any{"userid": user, "password": password, "namespace": namespace})
+	if err != nil {
+		return fmt.Errorf("dqlhttp: login %q: %w", user, err)
+	}
+	c.mu.Lock()
+	c.namespace = namespace
+	c.user = user
+	c.password = password
+	c.mu.Unlock()
+	return nil
+}
+
+// SetTokens sets the tokens of the client, for services given tokens rather than the
+// credentials of a user.
+//
+// The access token is refreshed with the refresh token when it expires. Without a refresh
+// token, requests fail once the access token expires.
+//
+// Parameters:
+//   - accessToken: The access JWT sent with every request.
+//   - refreshToken: The refresh JWT, or an empty string.
+func (c *Client) SetTokens(accessToken string, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	c.refreshToken = refreshToken
+}
+
+// Namespace returns the namespace the client is logged into, 0 before Login.
+//
+// Returns:
+//   - The namespace of the client.
+func (c *Client) Namespace() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.namespace
+}
+
+// Alter applies a schema to the namespace of the client.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - schema: The schema to apply.
+//
+// Returns:
+//   - An error if the schema was rejected or the request failed.
+//
+// See: https://dgraph.io/docs/dql/dql-schema/
+func (c *Client) Alter(ctx context.Context, schema *dql.Schema) error {
+	if _, err := c.post(ctx, "/alter", "application/rdf", []byte(schema.String())); err != nil {
+		return fmt.Errorf("dqlhttp: alter namespace %d: %w", c.Namespace(), err)
+	}
+	return nil
+}
+
+// Health checks that the Dgraph Alpha is up and serving requests.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//
+// Returns:
+//   - An error of kind dql.ErrExec if the Alpha is unreachable or reports being unhealthy.
+//
+// See: https://dgraph.io/docs/deploy/dgraph-administration/#health
+func (c *Client) Health(ctx context.Context) (err error) {
+	defer func() { err = execError(err) }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	if c.APIKey != "" {
+		req.Header.Set(apiKeyHeader, c.APIKey)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: data}
+	}
+	return nil
+}
+
+// Execute runs a query in the namespace of the client.
+//
+// Queries with Debug set are sent with the debug=true option, and the latency and metrics
+// Dgraph reports are returned in the Extensions of the response.
+//
+// The deadline of ctx, if any, is sent as the timeout option of the query, so Dgraph stops
+// working on queries the caller has given up on, see dql.QueryTimeout.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - q: The query to run.
+//   - vars: The values of the variables of the query, by name with their leading $.
+//
+// Returns:
+//   - The response of Dgraph.
+//   - An error if the query was rejected or the request failed.
+func (c *Client) Execute(ctx context.Context, q *dql.Query, vars map[string]string) (*dql.Response, error) {
+	body, err := json.Marshal(map[string]any{"query": q.String(), "variables": vars})
+	if err != nil {
+		return nil, err
+	}
+	options := []string{}
+	if c.ReadOnly || c.BestEffort {
+		options = append(options, "ro=true")
+	}
+	if c.BestEffort {
+		options = append(options, "be=true")
+	}
+	if q.Debug {
+		options = append(options, "debug=true")
+	}
+	if timeout, ok := dql.QueryTimeout(ctx); ok {
+		options = append(options, fmt.Sprintf("timeout=%dms", timeout.Milliseconds()))
+	}
+	path := "/query"
+	if len(options) != 0 {
+		path += "?" + strings.Join(options, "&")
+	}
+	res, err := c.retry(ctx, c.QueryRetry, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: query %q: %w", q.Name, err)
+	}
+	return &dql.Response{Json: res.Data, Extensions: res.Extensions}, nil
+}
+
+// Mutate runs a JSON mutation in the namespace of the client and commits it.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - mutation: The JSON mutation, e.g. {"set": [{"name": "Alice"}]}.
+//
+// Returns:
+//   - The uids assigned to the blank nodes of the mutation, by blank node name.
+//   - An error if the mutation was rejected or the request failed.
+//
+// Example:
+//
+//	uids, err := client.Mutate(ctx, []byte(`{"set": [{"uid": "_:alice", "name": "Alice"}]}`))
+//	fmt.Println(uids["alice"]) // Output: 0x1
+//
+// See: https://dgraph.io/docs/dql/dql-mutation/
+func (c *Client) Mutate(ctx context.Context, mutation []byte) (map[string]dql.UID, error) {
+	res, err := c.mutate(ctx, mutation)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: mutate: %w", err)
+	}
+	var data struct {
+		Uids map[string]dql.UID `json:"uids"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		return nil, fmt.Errorf("dqlhttp: mutate: %w", err)
+	}
+	return data.Uids, nil
+}
+
+// UpsertResult is the result of an upsert, see Client.Upsert.
+type UpsertResult struct {
+	// Matched holds the uids of the nodes the query part of the upsert found, by variable.
+	// Only the variables bound to the uid of the nodes of a query block are reported, e.g. v of
+	// q(func: eq(email, "alice@example.com")) { v as uid }, since Dgraph does not return the
+	// nodes of var blocks.
+	Matched map[string][]dql.UID
+
+	// Created holds the uids of the nodes the mutation part created, by blank node name.
+	Created map[string]dql.UID
+
+	// Queries is the JSON data of the query part, holding the results of each block.
+	Queries []byte
+}
+
+// Node returns the uid of the node an upsert either found with a variable or created with a
+// blank node, the common case of upserts creating a node unless it already exists.
+//
+// Parameters:
+//   - variable: The variable bound to the uid of the matched node.
+//   - blankNode: The name of the blank node of the created node, without its _: prefix, or
+//     the uid(v) expression of the variable when the mutation creates the node with it.
+//
+// Returns:
+//   - The uid of the node, zero if it was neither found nor created.
+//   - true if the node was created, false if it was found.
+func (r *UpsertResult) Node(variable string, blankNode string) (dql.UID, bool) {
+	if uids := r.Matched[variable]; len(uids) != 0 {
+		return uids[0], false
+	}
+	uid, ok := r.Created[blankNode]
+	return uid, ok
+}
+
+// Upsert runs an upsert block in the namespace of the client and commits it: the query finds
+// the existing nodes and binds them to variables, which the mutation uses with uid(), e.g. to
+// update a node or create it when uid(v) is empty.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - q: The query part of the upsert. Variables are not supported by the upsert endpoint.
+//   - mutation: The JSON mutation part, with set, delete and cond fields, or the mutations
+//     field holding several conditional mutations.
+//
+// Returns:
+//   - The uids the query found and the mutation created.
+//   - An error if the upsert was rejected or the request failed.
+//
+// Example:
+//
+//	q := dql.NewQuery("", dql.NewQueryBlock("user", dql.Eq("email", "alice@example.com")).
+//	    WithAttributes(dql.UIDAttribute().WithVar("v")))
+//	res, err := client.Upsert(ctx, q, []byte(`{"set": [{"uid": "uid(v)", "email": "alice@example.com"}], "cond": "@if(eq(len(v), 0))"}`))
+//	uid, created := res.Node("v", "uid(v)")
+//
+// See: https://dgraph.io/docs/dql/dql-mutation/#upsert-block
+func (c *Client) Upsert(ctx context.Context, q *dql.Query, mutation []byte) (*UpsertResult, error) {
+	req, err := upsertRequest(q, mutation)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: upsert %q: %w", q.Name, err)
+	}
+	res, err := c.mutate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: upsert %q: %w", q.Name, err)
+	}
+	return upsertResult(q, res)
+}
+
+// upsertRequest merges the query part of an upsert into its mutation part.
+func upsertRequest(q *dql.Query, mutation []byte) ([]byte, error) {
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(mutation, &body); err != nil {
+		return nil, err
+	}
+	query, err := json.Marshal(q.String())
+	if err != nil {
+		return nil, err
+	}
+	body["query"] = query
+	return json.Marshal(body)
+}
+
+// upsertResult reads the uids an upsert found and created from its response.
+func upsertResult(q *dql.Query, res *response) (*UpsertResult, error) {
+	var err error
+	var data struct {
+		Uids    map[string]dql.UID         `json:"uids"`
+		Queries map[string]json.RawMessage `json:"queries"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		return nil, fmt.Errorf("dqlhttp: upsert %q: %w", q.Name, err)
+	}
+	result := &UpsertResult{Matched: map[string][]dql.UID{}, Created: data.Uids}
+	if result.Created == nil {
+		result.Created = map[string]dql.UID{}
+	}
+	if result.Queries, err = json.Marshal(data.Queries); err != nil {
+		return nil, err
+	}
+	for _, qb := range q.QueryBlocks {
+		for _, a := range qb.Attributes {
+			if a.Name != dql.PredicateUID || a.Var == "" {
+				continue
+			}
+			key := a.Name
+			if a.Alias != "" {
+				key = a.Alias
+			}
+			var nodes []map[string]json.RawMessage
+			if raw, ok := data.Queries[qb.Name]; ok {
+				if err := json.Unmarshal(raw, &nodes); err != nil {
+					return nil, fmt.Errorf("dqlhttp: upsert %q: block %q: %w", q.Name, qb.Name, err)
+				}
+			}
+			uids := []dql.UID{}
+			for _, n := range nodes {
+				var uid dql.UID
+				if raw, ok := n[key]; ok && json.Unmarshal(raw, &uid) == nil {
+					uids = append(uids, uid)
+				}
+			}
+			result.Matched[a.Var] = uids
+		}
+	}
+	return result, nil
+}
+
+// login requests new tokens from the /login endpoint and stores them.
+func (c *Client) login(ctx context.Context, request map[string]any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	res, err := c.send(ctx, "/login", "application/json", body)
+	if err != nil {
+		return err
+	}
+	var data struct {
+		AccessJWT  string `json:"accessJWT"`
+		RefreshJWT string `json:"refreshJWT"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		return err
+	}
+	c.SetTokens(data.AccessJWT, data.RefreshJWT)
+	return nil
+}
+
+// refresh replaces an expired access token, with the refresh token if possible and with the
+// credentials of the user otherwise.
+func (c *Client) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	refreshToken, user, password, namespace := c.refreshToken, c.user, c.password, c.namespace
+	c.mu.Unlock()
+	err := fmt.Errorf("no refresh token")
+	if refreshToken != "" {
+		if err = c.login(ctx, map[string]any{"refresh_token": refreshToken}); err == nil {
+			return nil
+		}
+	}
+	if user != "" {
+		if err = c.login(ctx, map[string]any{"userid": user, "password": password, "namespace": namespace}); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("refresh expired token: %w", err)
+}
+
+// mutate posts a mutation to the Dgraph Alpha, retrying it as MutationRetry decides, and
+// reports it to Metrics.
+func (c *Client) mutate(ctx context.Context, mutation []byte) (*response, error) {
+	start := time.Now()
+	res, err := c.retry(ctx, c.MutationRetry, "/mutate?commitNow=true", mutation)
+	if c.Metrics != nil {
+		c.Metrics.ObserveMutation(ctx, dql.MutationObservation{Size: len(mutation), Duration: time.Since(start), Err: err})
+	}
+	return res, err
+}
+
+// retry posts a JSON request to the Dgraph Alpha, retrying it as policy decides.
+func (c *Client) retry(ctx context.Context, policy dql.RetryPolicy, path string, body []byte) (*response, error) {
+	if policy != nil && c.Metrics != nil {
+		policy = dql.ObservedRetryPolicy(policy, c.Metrics)
+	}
+	var res *response
+	err := dql.Retry(ctx, policy, func(ctx context.Context) error {
+		var err error
+		res, err = c.post(ctx, path, "application/json", body)
+		return err
+	})
+	return res, err
+}
+
+// post sends a request to the Dgraph Alpha and returns the data of its response, refreshing
+// the access token and retrying once if it expired.
+func (c *Client) post(ctx context.Context, path string, contentType string, body []byte) (*response, error) {
+	res, err := c.send(ctx, path, contentType, body)
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), "token is expired") {
+		return res, err
+	}
+	if err := c.refresh(ctx); err != nil {
+		return nil, execError(err)
+	}
+	return c.send(ctx, path, contentType, body)
+}
+
+// send sends a request to the Dgraph Alpha and returns the data of its response.
+//
+// Its errors are of kind dql.ErrExec. Errors reported by Dgraph are DgraphError values, and
+// other responses with a status other than 2xx or a body that is not JSON are StatusError
+// values.
+func (c *Client) send(ctx context.Context, path string, contentType string, body []byte) (_ *response, err error) {
+	defer func() { err = execError(err) }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.AuthToken != "" {
+		req.Header.Set("X-Dgraph-AuthToken", c.AuthToken)
+	}
+	if c.APIKey != "" {
+		req.Header.Set(apiKeyHeader, c.APIKey)
+	}
+	c.mu.Lock()
+	if c.accessToken != "" {
+		req.Header.Set("X-Dgraph-AccessToken", c.accessToken)
+	}
+	c.mu.Unlock()
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	res := &response{}
+	jsonErr := json.Unmarshal(data, res)
+	if jsonErr == nil && len(res.Errors) != 0 {
+		e := res.Errors[0]
+		return nil, &DgraphError{StatusCode: resp.StatusCode, Code: e.Extensions.Code, Message: e.Message}
+	}
+	// Responses that failed without Dgraph errors, such as the JSON error bodies of proxies,
+	// are errors too.
+	if jsonErr != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: data}
+	}
+	return res, nil
+}
+
+// execError classifies an error of a request as a dql.ErrExec error, unless it is nil or
+// already classified.
+func execError(err error) error {
+	var e *dql.Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	return &dql.Error{Kind: dql.ErrExec, Err: err}
+}
+
+// DgraphError is an error reported by Dgraph in the response of a request, such as a query
+// rejected by the Alpha. It is wrapped in an error of kind dql.ErrExec.
+//
+// Example:
+//
+//	_, err := client.Execute(ctx, query, nil)
+//	var dgErr *dqlhttp.DgraphError
+//	if errors.As(err, &dgErr) && dgErr.Code == "ErrorInvalidRequest" {
+//	    // the query was rejected, retrying will not help
+//	}
+type DgraphError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the code of the error, such as ErrorInvalidRequest, or empty if Dgraph sent none.
+	Code string
+
+	// Message is the message of the error.
+	Message string
+}
+
+// Error returns the message of the error, prefixed with dgraph.
+//
+// Returns:
+//   - The message of the error.
+func (e *DgraphError) Error() string {
+	return "dgraph: " + e.Message
+}
+
+// Transient reports whether retrying the request may succeed: Dgraph aborted the transaction
+// because of a conflict, or the Alpha is overloaded or unavailable.
+//
+// Returns:
+//   - True if the error is transient, see dql.IsTransient.
+func (e *DgraphError) Transient() bool {
+	return transientStatus(e.StatusCode) || strings.Contains(strings.ToLower(e.Message), "aborted")
+}
+
+// StatusError is the error of a response with a status other than 2xx and no errors of
+// Dgraph, or with a body that is not JSON, e.g. an error page of a proxy. It is wrapped in an
+// error of kind dql.ErrExec.
+//
+// Example:
+//
+//	_, err := client.Execute(ctx, query, nil)
+//	var statusErr *dqlhttp.StatusError
+//	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+//	    // the credentials were rejected before reaching Dgraph
+//	}
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Status is the HTTP status of the response, such as "502 Bad Gateway".
+	Status string
+
+	// Body is the body of the response.
+	Body []byte
+}
+
+// Error returns the status and the body of the response.
+//
+// Returns:
+//   - The status and the body of the response.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// Transient reports whether retrying the request may succeed: the server is overloaded or
+// unavailable.
+//
+// Returns:
+//   - True if the error is transient, see dql.IsTransient.
+func (e *StatusError) Transient() bool {
+	return transientStatus(e.StatusCode)
+}
+
+// transientStatus reports whether an HTTP status is worth retrying: too many requests, or a
+// server error such as an unavailable Alpha behind a load balancer.
+func transientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// response is the JSON envelope of the responses of Dgraph.
+type response struct {
+	Data       json.RawMessage `json:"data"`
+	Extensions json.RawMessage `json:"extensions"`
+	Errors     []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
@@ -0,0 +1,402 @@
+package dqlhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dql/dql"
+)
+
+// request is a request received by a fakeAlpha.
+type request struct {
+	path    string
+	query   string
+	header  http.Header
+	body    string
+	decoded map[string]any
+}
+
+// fakeAlpha is a Dgraph Alpha answering each path with the responses of handle.
+type fakeAlpha struct {
+	mu       sync.Mutex
+	requests []request
+	handle   func(r request) (int, string)
+}
+
+// newFakeAlpha starts a server answering requests with handle.
+func newFakeAlpha(t *testing.T, handle func(r request) (int, string)) (*fakeAlpha, *Client) {
+	a := &fakeAlpha{handle: handle}
+	srv := httptest.NewServer(a)
+	t.Cleanup(srv.Close)
+	return a, NewClient(srv.URL + "/")
+}
+
+func (a *fakeAlpha) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := request{path: r.URL.Path, query: r.URL.RawQuery, header: r.Header, body: string(body)}
+	json.Unmarshal(body, &req.decoded)
+	a.mu.Lock()
+	a.requests = append(a.requests, req)
+	a.mu.Unlock()
+	status, resp := a.handle(req)
+	w.WriteHeader(status)
+	io.WriteString(w, resp)
+}
+
+// last returns the last request received.
+func (a *fakeAlpha) last() request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests[len(a.requests)-1]
+}
+
+// ok answers every request with empty data.
+func ok(r request) (int, string) {
+	return http.StatusOK, `{"data": {}}`
+}
+
+func TestClientExecute(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"me": [{"name": "Alice"}]}}`
+	})
+	q := dql.NewQuery("Q", dql.NewQueryBlock("me", dql.Eq("name", dql.ParamRef("$name")))).
+		WithParam(dql.NewParam("name", dql.ParamString))
+	resp, err := c.Execute(context.Background(), q, map[string]string{"$name": "Alice"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := string(resp.Json), `{"me": [{"name": "Alice"}]}`; got != want {
+		t.Errorf("Json = %s, want %s", got, want)
+	}
+	r := alpha.last()
+	if r.path != "/query" || r.header.Get("Content-Type") != "application/json" {
+		t.Errorf("request %s %s, want /query application/json", r.path, r.header.Get("Content-Type"))
+	}
+	if r.decoded["query"] != q.String() {
+		t.Errorf("query = %v, want %s", r.decoded["query"], q)
+	}
+	if vars, _ := r.decoded["variables"].(map[string]any); vars["$name"] != "Alice" {
+		t.Errorf("variables = %v, want $name: Alice", r.decoded["variables"])
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"rejected", http.StatusOK, `{"errors": [{"message": "line 1: syntax error"}]}`, `dqlhttp: query "": dgraph: line 1: syntax error`},
+		{"not json", http.StatusServiceUnavailable, "no healthy upstream", `dqlhttp: query "": 503 Service Unavailable: no healthy upstream`},
+		{"json without errors", http.StatusBadGateway, `{"message": "upstream unavailable"}`,
+			`dqlhttp: query "": 502 Bad Gateway: {"message": "upstream unavailable"}`},
+		{"rejected with status", http.StatusBadRequest, `{"errors": [{"message": "bad query"}]}`, `dqlhttp: query "": dgraph: bad query`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newFakeAlpha(t, func(r request) (int, string) { return tt.status, tt.body })
+			_, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Execute() error = %v, want %s", err, tt.wantErr)
+			}
+			if !errors.Is(err, dql.ErrExec) {
+				t.Errorf("Execute() error = %v, want an execution error", err)
+			}
+		})
+	}
+
+	_, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"errors": [{"message": "bad", "extensions": {"code": "ErrorInvalidRequest"}}]}`
+	})
+	_, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+	var dgraphErr *DgraphError
+	if !errors.As(err, &dgraphErr) || dgraphErr.Code != "ErrorInvalidRequest" || dgraphErr.StatusCode != http.StatusOK {
+		t.Errorf("Execute() error = %#v, want a DgraphError", err)
+	}
+
+	_, c = newFakeAlpha(t, func(r request) (int, string) { return http.StatusServiceUnavailable, `{"data": {}}` })
+	_, err = c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable || !dql.IsTransient(err) {
+		t.Errorf("Execute() error = %#v, want a transient StatusError", err)
+	}
+}
+
+func TestClientLogin(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		if r.path == "/login" {
+			if r.decoded["userid"] != "groot" || r.decoded["password"] != "password" || r.decoded["namespace"] != 1.0 {
+				return http.StatusOK, `{"errors": [{"message": "invalid credentials"}]}`
+			}
+			return http.StatusOK, `{"data": {"accessJWT": "access"}}`
+		}
+		return ok(r)
+	})
+	if err := c.Login(context.Background(), "groot", "wrong", 1); err == nil {
+		t.Fatal("Login() with a wrong password error = nil")
+	}
+	if c.Namespace() != 0 {
+		t.Errorf("Namespace() after a failed login = %d, want 0", c.Namespace())
+	}
+	if err := c.Login(context.Background(), "groot", "password", 1); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if c.Namespace() != 1 {
+		t.Errorf("Namespace() = %d, want 1", c.Namespace())
+	}
+
+	schema, err := dql.ParseSchema("name: string @index(exact) .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Alter(context.Background(), schema); err != nil {
+		t.Fatalf("Alter() error = %v", err)
+	}
+	r := alpha.last()
+	if r.path != "/alter" || r.body != schema.String() || r.header.Get("X-Dgraph-AccessToken") != "access" {
+		t.Errorf("request %s %q with token %q", r.path, r.body, r.header.Get("X-Dgraph-AccessToken"))
+	}
+}
+
+// TestClientRefresh checks that an expired access token is refreshed and the request retried.
+func TestClientRefresh(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(c *Client)
+		refreshOK  bool
+		wantLogins []string
+		wantErr    bool
+	}{
+		{"refresh token", func(c *Client) { c.SetTokens("old", "refresh") }, true, []string{"refresh_token"}, false},
+		{"credentials after an expired refresh token", func(c *Client) {
+			c.SetTokens("old", "refresh")
+			c.user, c.password = "groot", "password"
+		}, false, []string{"refresh_token", "userid"}, false},
+		{"no refresh token", func(c *Client) { c.SetTokens("old", "") }, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins := []string{}
+			alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+				switch {
+				case r.path == "/login" && r.decoded["refresh_token"] != nil:
+					logins = append(logins, "refresh_token")
+					if !tt.refreshOK {
+						return http.StatusOK, `{"errors": [{"message": "Token is expired"}]}`
+					}
+					return http.StatusOK, `{"data": {"accessJWT": "new", "refreshJWT": "refresh2"}}`
+				case r.path == "/login":
+					logins = append(logins, "userid")
+					return http.StatusOK, `{"data": {"accessJWT": "new", "refreshJWT": "refresh2"}}`
+				case r.header.Get("X-Dgraph-AccessToken") == "old":
+					return http.StatusOK, `{"errors": [{"message": "Token is expired"}]}`
+				}
+				return ok(r)
+			})
+			tt.setup(c)
+			_, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, want error %v", err, tt.wantErr)
+			}
+			if strings.Join(logins, ",") != strings.Join(tt.wantLogins, ",") {
+				t.Errorf("logins = %v, want %v", logins, tt.wantLogins)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := alpha.last().header.Get("X-Dgraph-AccessToken"); got != "new" {
+				t.Errorf("access token = %q, want new", got)
+			}
+		})
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(c *Client)
+		wantQuery string
+	}{
+		{"plain", func(c *Client) {}, ""},
+		{"read only", func(c *Client) { c.ReadOnly = true }, "ro=true"},
+		{"best effort", func(c *Client) { c.BestEffort = true }, "ro=true&be=true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alpha, c := newFakeAlpha(t, ok)
+			tt.configure(c)
+			if _, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if r := alpha.last(); r.path != "/query" || r.query != tt.wantQuery {
+				t.Errorf("request %s?%s, want /query?%s", r.path, r.query, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestClientHeaders(t *testing.T) {
+	alpha, c := newFakeAlpha(t, ok)
+	c.AuthToken = "auth"
+	c.SetTokens("access", "refresh")
+	if _, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	h := alpha.last().header
+	for name, want := range map[string]string{
+		"X-Dgraph-AuthToken":   "auth",
+		"X-Dgraph-AccessToken": "access",
+		"Content-Type":         "application/json",
+	} {
+		if got := h.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	attempts := 0
+	_, c := newFakeAlpha(t, func(r request) (int, string) {
+		switch attempts++; attempts {
+		case 1:
+			return http.StatusServiceUnavailable, "unavailable"
+		case 2:
+			return http.StatusServiceUnavailable, `{"data": {}}`
+		}
+		return ok(r)
+	})
+	c.QueryRetry = &dql.ExponentialBackoff{MaxAttempts: 3, Initial: time.Millisecond}
+	if _, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+
+	attempts = 0
+	if _, err := c.Mutate(context.Background(), []byte(`{"set": []}`)); !dql.IsTransient(err) {
+		t.Errorf("Mutate() error = %v, want a transient error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("mutation attempts = %d, want 1 without MutationRetry", attempts)
+	}
+}
+
+// mutationMetrics records the mutations and retries it observes.
+type mutationMetrics struct {
+	mutations []dql.MutationObservation
+	retries   int
+}
+
+func (m *mutationMetrics) ObserveQuery(ctx context.Context, obs dql.QueryObservation) {}
+
+func (m *mutationMetrics) ObserveRetry(attempt int, err error) { m.retries++ }
+
+func (m *mutationMetrics) ObserveMutation(ctx context.Context, obs dql.MutationObservation) {
+	m.mutations = append(m.mutations, obs)
+}
+
+func TestClientMetrics(t *testing.T) {
+	attempts := 0
+	_, c := newFakeAlpha(t, func(r request) (int, string) {
+		if attempts++; attempts == 1 {
+			return http.StatusServiceUnavailable, "unavailable"
+		}
+		return ok(r)
+	})
+	m := &mutationMetrics{}
+	c.Metrics = m
+	c.MutationRetry = &dql.ExponentialBackoff{MaxAttempts: 2, Initial: time.Millisecond}
+	mutation := []byte(`{"set": []}`)
+	if _, err := c.Mutate(context.Background(), mutation); err != nil {
+		t.Fatalf("Mutate() error = %v", err)
+	}
+	if len(m.mutations) != 1 || m.mutations[0].Size != len(mutation) || m.mutations[0].Err != nil {
+		t.Errorf("mutations = %+v, want one of %d bytes", m.mutations, len(mutation))
+	}
+	if m.retries != 1 {
+		t.Errorf("retries = %d, want 1", m.retries)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	alpha, c := newFakeAlpha(t, ok)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := c.Execute(ctx, dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if r := alpha.last(); !strings.HasPrefix(r.query, "timeout=") || !strings.HasSuffix(r.query, "ms") {
+		t.Errorf("request /query?%s, want a timeout", r.query)
+	}
+}
+
+func TestClientMutate(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"code": "Success", "uids": {"alice": "0x2a"}}}`
+	})
+	uids, err := c.Mutate(context.Background(), []byte(`{"set": [{"uid": "_:alice", "name": "Alice"}]}`))
+	if err != nil {
+		t.Fatalf("Mutate() error = %v", err)
+	}
+	if uids["alice"] != 0x2a {
+		t.Errorf("uids = %v, want alice: 0x2a", uids)
+	}
+	if r := alpha.last(); r.path != "/mutate" || r.query != "commitNow=true" || r.body != `{"set": [{"uid": "_:alice", "name": "Alice"}]}` {
+		t.Errorf("request %s?%s %s, want /mutate?commitNow=true", r.path, r.query, r.body)
+	}
+}
+
+func TestClientUpsert(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"code": "Success", "uids": {"bob": "0x7"},
+			"queries": {"alice": [{"uid": "0x2a"}], "bob": []}}}`
+	})
+	q := dql.NewQuery("U", dql.NewQueryBlock("alice", dql.Eq("email", "a@x.io")).
+		WithAttributes(dql.NewAttribute(dql.PredicateUID).WithVar("a")))
+	q.WithQueryBlocks(dql.NewQueryBlock("bob", dql.Eq("email", "b@x.io")).
+		WithAttributes(dql.NewAttribute(dql.PredicateUID).WithVar("b")))
+	res, err := c.Upsert(context.Background(), q, []byte(`{"set": [{"uid": "uid(a)", "age": 30}, {"uid": "_:bob"}]}`))
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if r := alpha.last(); r.path != "/mutate" || r.decoded["query"] != q.String() || r.decoded["set"] == nil {
+		t.Errorf("request %s %s, want the query and mutation in one body", r.path, r.body)
+	}
+	if uid, created := res.Node("a", "alice"); uid != 0x2a || created {
+		t.Errorf("Node(a) = %v, %v, want 0x2a, false", uid, created)
+	}
+	if uid, created := res.Node("b", "bob"); uid != 0x7 || !created {
+		t.Errorf("Node(b) = %v, %v, want 0x7, true", uid, created)
+	}
+
+	if _, err := c.Upsert(context.Background(), q, []byte(`set`)); err == nil {
+		t.Error("Upsert() with an invalid mutation succeeded")
+	}
+}
+
+func TestClientDebug(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"me": []}, "extensions": {"server_latency": {"total_ns": 10}}}`
+	})
+	c.ReadOnly = true
+	resp, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))).WithDebug(), nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if r := alpha.last(); r.query != "ro=true&debug=true" {
+		t.Errorf("request /query?%s, want /query?ro=true&debug=true", r.query)
+	}
+	if got, want := string(resp.Extensions), `{"server_latency": {"total_ns": 10}}`; got != want {
+		t.Errorf("Extensions = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package dqlhttp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// apiKeyHeader is the header carrying the API key of Dgraph Cloud backends.
+const apiKeyHeader = "Dg-Auth"
+
+// cloudPaths lists the endpoint paths stripped from the URLs of Dgraph Cloud backends, which
+// the console shows as their GraphQL endpoint.
+var cloudPaths = []string{"/graphql", "/admin", "/query", "/mutate", "/alter"}
+
+// CloudConfig configures a Client for a backend hosted on Dgraph Cloud.
+type CloudConfig struct {
+	// Endpoint is the URL of the backend as shown in the cloud console, e.g.
+	// https://blue-surf-1234.us-east-1.aws.cloud.dgraph.io/graphql. The scheme and the path
+	// are optional.
+	Endpoint string
+
+	// APIKey is a client or admin API key of the backend, sent in the Dg-Auth header. Admin
+	// keys are needed for schema changes.
+	APIKey string
+
+	// TLS is the TLS configuration of the connections, e.g. with custom root CAs. Connections
+	// require TLS 1.2 or later if nil.
+	TLS *tls.Config
+}
+
+// NewCloudClient creates a new Client for a backend hosted on Dgraph Cloud.
+//
+// The DQL endpoints are served by the host of the backend over HTTPS, so the endpoint is
+// reduced to its host, and the client sends the API key with every request.
+//
+// Parameters:
+//   - cfg: The configuration of the backend.
+//
+// Returns:
+//   - A pointer to a Client object.
+//   - An error if the endpoint is not a valid HTTPS URL or the API key is missing.
+//
+// Example:
+//
+//	client, err := NewCloudClient(CloudConfig{
+//	    Endpoint: "https://blue-surf-1234.us-east-1.aws.cloud.dgraph.io/graphql",
+//	    APIKey:   os.Getenv("DGRAPH_API_KEY"),
+//	})
+//	fmt.Println(client.URL) // Output: https://blue-surf-1234.us-east-1.aws.cloud.dgraph.io
+//	resp, err := client.Execute(ctx, query, nil)
+//
+// See: https://dgraph.io/docs/cloud/admin/authentication/
+func NewCloudClient(cfg CloudConfig) (*Client, error) {
+	endpoint, err := CloudURL(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("dqlhttp: cloud endpoint %q: missing API key", cfg.Endpoint)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLS != nil {
+		tlsConfig = cfg.TLS.Clone()
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c := NewClient(endpoint)
+	c.APIKey = cfg.APIKey
+	c.HTTPClient = &http.Client{Transport: transport}
+	return c, nil
+}
+
+// CloudURL returns the base URL of the DQL endpoints of a Dgraph Cloud backend from its URL
+// as shown in the cloud console.
+//
+// Parameters:
+//   - endpoint: The URL of the backend, with or without the https scheme and the /graphql
+//     path.
+//
+// Returns:
+//   - The base URL, e.g. https://blue-surf-1234.us-east-1.aws.cloud.dgraph.io.
+//   - An error if the endpoint is not a valid HTTPS URL.
+//
+// Example:
+//
+//	endpoint, err := CloudURL("blue-surf-1234.us-east-1.aws.cloud.dgraph.io/graphql")
+//	fmt.Println(endpoint) // Output: https://blue-surf-1234.us-east-1.aws.cloud.dgraph.io
+func CloudURL(endpoint string) (string, error) {
+	raw := strings.TrimSpace(endpoint)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("dqlhttp: cloud endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("dqlhttp: cloud endpoint %q: must use https", endpoint)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("dqlhttp: cloud endpoint %q: missing host", endpoint)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("dqlhttp: cloud endpoint %q: unexpected credentials, query or fragment", endpoint)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	for _, p := range cloudPaths {
+		if cut, ok := strings.CutSuffix(path, p); ok {
+			path = cut
+			break
+		}
+	}
+	return "https://" + u.Host + path, nil
+}
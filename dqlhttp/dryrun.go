@@ -0,0 +1,322 @@
+package dqlhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"dql/dql"
+)
+
+// DryRunMode selects how far a dry run of a write goes, see Client.DryRunMutate.
+type DryRunMode int
+
+const (
+	// DryRunValidate renders and validates the write without sending it to Dgraph.
+	DryRunValidate DryRunMode = iota
+
+	// DryRunDiscard also runs the write in a transaction that is always discarded, so that
+	// Dgraph checks it against the data and reports the uids it would assign.
+	DryRunDiscard
+)
+
+// Preview is the result of a dry run of a mutation or upsert: what the write would change,
+// without changing anything.
+type Preview struct {
+	// Request is the JSON request the write would send to Dgraph.
+	Request []byte
+
+	// Set holds the number of values the write would set, by predicate.
+	Set map[string]int
+
+	// Delete holds the number of values the write would delete, by predicate. Nodes deleted
+	// with all their predicates, given by their uid alone, are counted under *.
+	Delete map[string]int
+
+	// Discarded reports whether the write ran in a discarded transaction, with DryRunDiscard.
+	Discarded bool
+
+	// Created holds the uids the write would assign to its blank nodes, by blank node name.
+	// It is only filled with DryRunDiscard, and the uids are not reserved.
+	Created map[string]dql.UID
+
+	// Matched holds the uids the query part of an upsert found, by variable, see
+	// UpsertResult.Matched. It is only filled with DryRunDiscard.
+	Matched map[string][]dql.UID
+}
+
+// DryRunMutate previews a JSON mutation without committing it, e.g. for admin tooling showing
+// the effect of a write before running it.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - mutation: The JSON mutation, e.g. {"set": [{"name": "Alice"}]}.
+//   - mode: DryRunValidate to only validate the mutation, or DryRunDiscard to also run it in
+//     a discarded transaction.
+//
+// Returns:
+//   - The preview of the mutation.
+//   - An error if the mutation is invalid, was rejected or the request failed.
+//
+// Example:
+//
+//	preview, err := client.DryRunMutate(ctx, []byte(`{"set": [{"uid": "_:alice", "name": "Alice"}]}`), DryRunDiscard)
+//	fmt.Println(preview.Set, preview.Created) // Output: map[name:1] map[alice:0x4e21]
+func (c *Client) DryRunMutate(ctx context.Context, mutation []byte, mode DryRunMode) (*Preview, error) {
+	preview, err := newPreview(mutation)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run mutate: %w", err)
+	}
+	if mode != DryRunDiscard {
+		return preview, nil
+	}
+	res, err := c.discard(ctx, mutation)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run mutate: %w", err)
+	}
+	var data struct {
+		Uids map[string]dql.UID `json:"uids"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run mutate: %w", err)
+	}
+	preview.Discarded = true
+	preview.Created = data.Uids
+	return preview, nil
+}
+
+// DryRunUpsert previews an upsert block without committing it, see Client.Upsert and
+// Client.DryRunMutate. The query part is validated before anything is sent.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - q: The query part of the upsert.
+//   - mutation: The JSON mutation part of the upsert.
+//   - mode: DryRunValidate to only validate the upsert, or DryRunDiscard to also run it in a
+//     discarded transaction.
+//
+// Returns:
+//   - The preview of the upsert.
+//   - An error if the upsert is invalid, was rejected or the request failed.
+func (c *Client) DryRunUpsert(ctx context.Context, q *dql.Query, mutation []byte, mode DryRunMode) (*Preview, error) {
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run upsert %q: %w", q.Name, err)
+	}
+	req, err := upsertRequest(q, mutation)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run upsert %q: %w", q.Name, err)
+	}
+	preview, err := newPreview(req)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run upsert %q: %w", q.Name, err)
+	}
+	if mode != DryRunDiscard {
+		return preview, nil
+	}
+	res, err := c.discard(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run upsert %q: %w", q.Name, err)
+	}
+	result, err := upsertResult(q, res)
+	if err != nil {
+		return nil, err
+	}
+	preview.Discarded = true
+	preview.Created = result.Created
+	preview.Matched = result.Matched
+	return preview, nil
+}
+
+// DryRunAlter previews a schema change without applying it: it validates the schema and
+// compares it with the schema of the namespace of the client, see dql.Schema.Changes. Dgraph
+// cannot run schema changes in a transaction, so nothing is sent to /alter.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - schema: The schema to apply.
+//
+// Returns:
+//   - The changes applying the schema would make.
+//   - An error if the schema is invalid or the request failed.
+//
+// Example:
+//
+//	changes, err := client.DryRunAlter(ctx, schema)
+//	for _, change := range changes {
+//	    fmt.Println(change) // e.g. predicate "name": "name: string ." -> "name: string @index(exact) ."
+//	}
+func (c *Client) DryRunAlter(ctx context.Context, schema *dql.Schema) ([]string, error) {
+	if _, err := dql.ParseSchema(schema.String()); err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run alter namespace %d: %w", c.Namespace(), err)
+	}
+	current, err := c.Schema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: dry run alter namespace %d: %w", c.Namespace(), err)
+	}
+	return schema.Changes(current), nil
+}
+
+// Schema returns the schema of the namespace of the client, including the predicates of
+// Dgraph such as dgraph.type.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//
+// Returns:
+//   - A pointer to the Schema object.
+//   - An error if the request failed.
+//
+// See: https://dgraph.io/docs/dql/dql-schema/#querying-schema
+func (c *Client) Schema(ctx context.Context) (*dql.Schema, error) {
+	res, err := c.post(ctx, "/query", "application/dql", []byte("schema {}"))
+	if err != nil {
+		return nil, fmt.Errorf("dqlhttp: schema: %w", err)
+	}
+	var data struct {
+		Schema []struct {
+			Predicate  string   `json:"predicate"`
+			Type       string   `json:"type"`
+			List       bool     `json:"list"`
+			Tokenizer  []string `json:"tokenizer"`
+			Reverse    bool     `json:"reverse"`
+			Count      bool     `json:"count"`
+			Lang       bool     `json:"lang"`
+			Upsert     bool     `json:"upsert"`
+			NoConflict bool     `json:"no_conflict"`
+		} `json:"schema"`
+		Types []struct {
+			Name   string `json:"name"`
+			Fields []struct {
+				Name string `json:"name"`
+			} `json:"fields"`
+		} `json:"types"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		return nil, fmt.Errorf("dqlhttp: schema: %w", err)
+	}
+	schema := &dql.Schema{}
+	for _, p := range data.Schema {
+		schema.Predicates = append(schema.Predicates, &dql.SchemaPredicate{
+			Name:       p.Predicate,
+			Type:       p.Type,
+			List:       p.List,
+			Indexes:    p.Tokenizer,
+			Reverse:    p.Reverse,
+			Count:      p.Count,
+			Lang:       p.Lang,
+			Upsert:     p.Upsert,
+			NoConflict: p.NoConflict,
+		})
+	}
+	for _, t := range data.Types {
+		st := &dql.SchemaType{Name: t.Name}
+		for _, f := range t.Fields {
+			st.Fields = append(st.Fields, f.Name)
+		}
+		schema.Types = append(schema.Types, st)
+	}
+	return schema, nil
+}
+
+// discard runs a mutation in a new transaction and discards the transaction, so that nothing
+// is committed.
+func (c *Client) discard(ctx context.Context, mutation []byte) (*response, error) {
+	res, err := c.post(ctx, "/mutate", "application/json", mutation)
+	if err != nil {
+		return nil, err
+	}
+	var ext struct {
+		Txn struct {
+			StartTs uint64 `json:"start_ts"`
+		} `json:"txn"`
+	}
+	if err := json.Unmarshal(res.Extensions, &ext); err != nil {
+		return nil, err
+	}
+	// The transaction is discarded even if ctx is done, so that it does not hold its locks
+	// until it times out.
+	path := fmt.Sprintf("/commit?startTs=%d&abort=true", ext.Txn.StartTs)
+	if _, err := c.post(context.WithoutCancel(ctx), path, "application/json", nil); err != nil {
+		return nil, fmt.Errorf("discard transaction %d: %w", ext.Txn.StartTs, err)
+	}
+	return res, nil
+}
+
+// newPreview validates a JSON mutation request and counts the values it sets and deletes.
+func newPreview(request []byte) (*Preview, error) {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(request, &body); err != nil {
+		return nil, err
+	}
+	preview := &Preview{Request: request, Set: map[string]int{}, Delete: map[string]int{}}
+	mutations := []map[string]json.RawMessage{body}
+	if raw, ok := body["mutations"]; ok {
+		if err := json.Unmarshal(raw, &mutations); err != nil {
+			return nil, fmt.Errorf("mutations: %w", err)
+		}
+	}
+	for _, m := range mutations {
+		for key := range m {
+			switch key {
+			case "set", "delete", "cond", "query", "mutations":
+			default:
+				return nil, fmt.Errorf("unknown field %q", key)
+			}
+		}
+		if err := countValues(m["set"], preview.Set, false); err != nil {
+			return nil, fmt.Errorf("set: %w", err)
+		}
+		if err := countValues(m["delete"], preview.Delete, true); err != nil {
+			return nil, fmt.Errorf("delete: %w", err)
+		}
+	}
+	if len(preview.Set) == 0 && len(preview.Delete) == 0 {
+		return nil, fmt.Errorf("no values to set or delete")
+	}
+	return preview, nil
+}
+
+// countValues counts the values of the nodes of the set or delete field of a JSON mutation,
+// by predicate, including the values of nested nodes.
+func countValues(raw json.RawMessage, counts map[string]int, deletion bool) error {
+	if raw == nil {
+		return nil
+	}
+	var nodes []map[string]any
+	if err := json.Unmarshal(raw, &nodes); err != nil {
+		node := map[string]any{}
+		if err := json.Unmarshal(raw, &node); err != nil {
+			return fmt.Errorf("expected a node or a list of nodes")
+		}
+		nodes = []map[string]any{node}
+	}
+	for _, n := range nodes {
+		if _, ok := n[dql.PredicateUID]; ok && len(n) == 1 && deletion {
+			counts["*"]++
+			continue
+		}
+		countNode(n, counts)
+	}
+	return nil
+}
+
+// countNode counts the values of a node of a JSON mutation and of its nested nodes. Facets,
+// given as predicate|facet keys, are not counted.
+func countNode(n map[string]any, counts map[string]int) {
+	for key, v := range n {
+		if key == dql.PredicateUID || strings.Contains(key, "|") {
+			continue
+		}
+		values, ok := v.([]any)
+		if !ok {
+			values = []any{v}
+		}
+		for _, value := range values {
+			counts[key]++
+			if nested, ok := value.(map[string]any); ok {
+				countNode(nested, counts)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package dqlhttp
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"dql/dql"
+)
+
+func TestDryRunMutate(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutation   string
+		wantSet    map[string]int
+		wantDelete map[string]int
+		wantErr    string
+	}{
+		{"set", `{"set": [{"uid": "_:a", "name": "Alice", "friend": [{"name": "Bob"}, {"uid": "0x2"}]}]}`,
+			map[string]int{"name": 2, "friend": 2}, map[string]int{}, ""},
+		{"delete node", `{"delete": {"uid": "0x1"}}`, map[string]int{}, map[string]int{"*": 1}, ""},
+		{"delete values", `{"delete": [{"uid": "0x1", "name": null, "name|since": 1}]}`, map[string]int{}, map[string]int{"name": 1}, ""},
+		{"unknown field", `{"sett": []}`, nil, nil, `dqlhttp: dry run mutate: unknown field "sett"`},
+		{"empty", `{"set": []}`, nil, nil, "dqlhttp: dry run mutate: no values to set or delete"},
+		{"not a node", `{"set": 1}`, nil, nil, "dqlhttp: dry run mutate: set: expected a node or a list of nodes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alpha, c := newFakeAlpha(t, ok)
+			p, err := c.DryRunMutate(context.Background(), []byte(tt.mutation), DryRunValidate)
+			if got := errString(err); got != tt.wantErr {
+				t.Fatalf("DryRunMutate() error = %q, want %q", got, tt.wantErr)
+			}
+			if len(alpha.requests) != 0 {
+				t.Errorf("DryRunValidate sent %d requests", len(alpha.requests))
+			}
+			if err == nil && (!reflect.DeepEqual(p.Set, tt.wantSet) || !reflect.DeepEqual(p.Delete, tt.wantDelete)) {
+				t.Errorf("DryRunMutate() = set %v, delete %v, want %v, %v", p.Set, p.Delete, tt.wantSet, tt.wantDelete)
+			}
+		})
+	}
+}
+
+func TestDryRunDiscard(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		if r.path == "/commit" {
+			return http.StatusOK, `{"data": {"code": "Success"}}`
+		}
+		return http.StatusOK, `{"data": {"uids": {"a": "0x9"}, "queries": {"q": [{"uid": "0x4"}]}}, "extensions": {"txn": {"start_ts": 17}}}`
+	})
+	p, err := c.DryRunMutate(context.Background(), []byte(`{"set": {"uid": "_:a", "name": "Alice"}}`), DryRunDiscard)
+	if err != nil {
+		t.Fatalf("DryRunMutate() error = %v", err)
+	}
+	if !p.Discarded || p.Created["a"] != 0x9 {
+		t.Errorf("DryRunMutate() = %+v, want a discarded mutation creating a", p)
+	}
+	if first, last := alpha.requests[0], alpha.last(); first.path != "/mutate" || first.query != "" || last.path != "/commit" || last.query != "startTs=17&abort=true" {
+		t.Errorf("requests %s?%s then %s?%s, want an uncommitted mutation then an abort", first.path, first.query, last.path, last.query)
+	}
+
+	q := dql.NewQuery("", dql.NewQueryBlock("q", dql.Eq("email", "a@x.io")).WithAttributes(dql.NewAttribute(dql.PredicateUID).WithVar("u")))
+	p, err = c.DryRunUpsert(context.Background(), q, []byte(`{"set": {"uid": "uid(u)", "name": "Alice"}}`), DryRunDiscard)
+	if err != nil {
+		t.Fatalf("DryRunUpsert() error = %v", err)
+	}
+	if !p.Discarded || len(p.Matched["u"]) != 1 || p.Matched["u"][0] != 0x4 || p.Set["name"] != 1 {
+		t.Errorf("DryRunUpsert() = %+v, want a discarded upsert matching 0x4", p)
+	}
+	if _, err := c.DryRunUpsert(context.Background(), dql.NewQuery("", dql.NewQueryBlock("q", dql.Uid("x"))), []byte(`{"set": {"name": "A"}}`), DryRunValidate); err == nil {
+		t.Error("DryRunUpsert() of an invalid query succeeded")
+	}
+}
+
+func TestDryRunAlter(t *testing.T) {
+	alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"schema": [
+			{"predicate": "name", "type": "string", "tokenizer": ["term", "exact"]},
+			{"predicate": "age", "type": "int"}
+		], "types": [{"name": "Person", "fields": [{"name": "name"}]}]}}`
+	})
+	schema, err := dql.ParseSchema("name: string @index(exact, term) .\nage: int @index(int) .\nemail: string .\ntype Person { name age }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := c.DryRunAlter(context.Background(), schema)
+	if err != nil {
+		t.Fatalf("DryRunAlter() error = %v", err)
+	}
+	want := []string{
+		`predicate "age": "age: int ." -> "age: int @index(int) ."`,
+		`add predicate "email": "email: string ."`,
+		`type "Person": "type Person { name }" -> "type Person { name age }"`,
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("DryRunAlter() = %q, want %q", changes, want)
+	}
+	if r := alpha.last(); r.path != "/query" || r.body != "schema {}" {
+		t.Errorf("request %s %s, want a schema query", r.path, r.body)
+	}
+}
+
+func TestClientSchema(t *testing.T) {
+	_, c := newFakeAlpha(t, func(r request) (int, string) {
+		return http.StatusOK, `{"data": {"schema": [
+			{"predicate": "name", "type": "string", "index": true, "tokenizer": ["exact"]},
+			{"predicate": "friend", "type": "uid", "list": true, "reverse": true},
+			{"predicate": "visits", "type": "int", "no_conflict": true}
+		], "types": [{"name": "Person", "fields": [{"name": "name"}, {"name": "friend"}]}]}}`
+	})
+	s, err := c.Schema(context.Background())
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	want := "name: string @index(exact) .\nfriend: [uid] @reverse .\nvisits: int @noconflict .\ntype Person { name friend }"
+	if got := s.String(); got != want {
+		t.Errorf("Schema() = %q, want %q", got, want)
+	}
+}
+
+// errString returns the message of err, or "" if err is nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
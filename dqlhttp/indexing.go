@@ -0,0 +1,165 @@
+package dqlhttp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dql/dql"
+)
+
+// DefaultPollInterval is the delay between two polls of AlterAndWait when the given interval
+// is not positive.
+const DefaultPollInterval = time.Second
+
+// IndexStatus is the indexing status of a predicate of a schema applied in the background.
+type IndexStatus struct {
+	// Predicate is the name of the predicate.
+	Predicate string
+
+	// Ready reports whether the definition reported by Dgraph matches the applied one, i.e.
+	// its indexes are built.
+	Ready bool
+
+	// Current is the definition of the predicate reported by Dgraph, nil if Dgraph does not
+	// report the predicate yet.
+	Current *dql.SchemaPredicate
+}
+
+// AlterInBackground applies a schema to the namespace of the client without waiting for its
+// indexes to be built.
+//
+// Dgraph returns as soon as the schema is accepted and builds the indexes in the background,
+// keeping the previous definition of the predicates until then. IndexStatus reports the
+// progress, and AlterAndWait waits for it.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - schema: The schema to apply.
+//
+// Returns:
+//   - An error if the schema was rejected or the request failed.
+//
+// See: https://dgraph.io/docs/dql/predicate-indexing/
+func (c *Client) AlterInBackground(ctx context.Context, schema *dql.Schema) error {
+	if _, err := c.post(ctx, "/alter?runInBackground=true", "application/rdf", []byte(schema.String())); err != nil {
+		return fmt.Errorf("dqlhttp: alter namespace %d: %w", c.Namespace(), err)
+	}
+	return nil
+}
+
+// IndexStatus reports, for each predicate of a schema, whether the schema of the namespace of
+// the client matches its definition, e.g. after AlterInBackground.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - schema: The applied schema.
+//
+// Returns:
+//   - The status of each predicate, in the order of the schema.
+//   - An error if the request failed.
+func (c *Client) IndexStatus(ctx context.Context, schema *dql.Schema) ([]IndexStatus, error) {
+	current, err := c.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexStatus(schema, current), nil
+}
+
+// AlterAndWait applies a schema in the background, see AlterInBackground, and polls the schema
+// of the namespace every interval until the indexes of every predicate are built or ctx is
+// done, so the deadline of ctx bounds the wait.
+//
+// Parameters:
+//   - ctx: The context of the requests.
+//   - schema: The schema to apply.
+//   - interval: The delay between two polls, DefaultPollInterval if it is not positive.
+//
+// Returns:
+//   - The status of each predicate, in the order of the schema, as of the last successful
+//     poll, also when an error is returned.
+//   - An error if the schema was rejected, a request failed or ctx was done before every
+//     index was built.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+//	defer cancel()
+//	statuses, err := client.AlterAndWait(ctx, schema, 5*time.Second)
+//	for _, s := range statuses {
+//	    if !s.Ready {
+//	        log.Printf("predicate %s is still being indexed", s.Predicate)
+//	    }
+//	}
+func (c *Client) AlterAndWait(ctx context.Context, schema *dql.Schema, interval time.Duration) ([]IndexStatus, error) {
+	if err := c.AlterInBackground(ctx, schema); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last []IndexStatus
+	for {
+		statuses, err := c.IndexStatus(ctx, schema)
+		if err != nil {
+			return last, fmt.Errorf("dqlhttp: wait for indexing: %w", err)
+		}
+		if indexesReady(statuses) {
+			return statuses, nil
+		}
+		last = statuses
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("dqlhttp: wait for indexing: %w", execError(ctx.Err()))
+		case <-ticker.C:
+		}
+	}
+}
+
+// AlterAndWait applies a schema in the background through the pinned Alpha and waits for its
+// indexes, see Client.AlterAndWait.
+//
+// Parameters:
+//   - ctx: The context of the requests.
+//   - schema: The schema to apply.
+//   - interval: The delay between two polls, DefaultPollInterval if it is not positive.
+//
+// Returns:
+//   - The status of each predicate, in the order of the schema, as of the last successful
+//     poll.
+//   - An error if the schema was rejected, a request failed or ctx was done before every
+//     index was built.
+func (p *Pool) AlterAndWait(ctx context.Context, schema *dql.Schema, interval time.Duration) ([]IndexStatus, error) {
+	var res []IndexStatus
+	err := p.write(func(c *Client) (err error) {
+		res, err = c.AlterAndWait(ctx, schema, interval)
+		return err
+	})
+	return res, err
+}
+
+// indexStatus compares the predicates of an applied schema with the current schema.
+func indexStatus(schema *dql.Schema, current *dql.Schema) []IndexStatus {
+	res := make([]IndexStatus, len(schema.Predicates))
+	for i, p := range schema.Predicates {
+		want := &dql.Schema{Predicates: []*dql.SchemaPredicate{p}}
+		res[i] = IndexStatus{
+			Predicate: p.Name,
+			Ready:     len(want.Changes(current)) == 0,
+			Current:   current.Predicate(p.Name),
+		}
+	}
+	return res
+}
+
+// indexesReady reports whether every predicate is ready.
+func indexesReady(statuses []IndexStatus) bool {
+	for _, s := range statuses {
+		if !s.Ready {
+			return false
+		}
+	}
+	return true
+}
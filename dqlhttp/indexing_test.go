@@ -0,0 +1,74 @@
+package dqlhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"dql/dql"
+)
+
+func TestAlterAndWait(t *testing.T) {
+	schema, err := dql.ParseSchema("name: string @index(exact) .\nvisits: int @noconflict .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		interval time.Duration
+		polls    int
+	}{
+		{"ready at once", time.Millisecond, 1},
+		{"ready after polls", time.Millisecond, 3},
+		{"zero interval", 0, 1},
+		{"negative interval", -time.Second, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			alpha, c := newFakeAlpha(t, func(r request) (int, string) {
+				if r.path != "/query" {
+					return ok(r)
+				}
+				if polls++; polls < tt.polls {
+					return http.StatusOK, `{"data": {"schema": [{"predicate": "name", "type": "string"}]}}`
+				}
+				return http.StatusOK, `{"data": {"schema": [
+					{"predicate": "name", "type": "string", "index": true, "tokenizer": ["exact"]},
+					{"predicate": "visits", "type": "int", "no_conflict": true}
+				]}}`
+			})
+			statuses, err := c.AlterAndWait(context.Background(), schema, tt.interval)
+			if err != nil {
+				t.Fatalf("AlterAndWait() error = %v", err)
+			}
+			if polls != tt.polls || len(statuses) != 2 || !statuses[0].Ready || !statuses[1].Ready {
+				t.Errorf("AlterAndWait() = %+v after %d polls, want ready after %d", statuses, polls, tt.polls)
+			}
+			if r := alpha.requests[0]; r.path != "/alter" || r.query != "runInBackground=true" || r.body != schema.String() {
+				t.Errorf("alter request %s?%s %q, want the schema in the background", r.path, r.query, r.body)
+			}
+		})
+	}
+}
+
+func TestAlterAndWaitDeadline(t *testing.T) {
+	_, c := newFakeAlpha(t, func(r request) (int, string) {
+		if r.path != "/query" {
+			return ok(r)
+		}
+		return http.StatusOK, `{"data": {"schema": []}}`
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	schema, _ := dql.ParseSchema("name: string .")
+	statuses, err := c.AlterAndWait(ctx, schema, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("AlterAndWait() error = %v, want DeadlineExceeded", err)
+	}
+	if len(statuses) != 1 || statuses[0].Ready || statuses[0].Current != nil {
+		t.Errorf("AlterAndWait() = %+v, want name not ready", statuses)
+	}
+}
@@ -0,0 +1,266 @@
+package dqlhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"dql/dql"
+)
+
+// Pool runs queries and mutations against several Dgraph Alphas of a cluster.
+//
+// Queries are balanced across the healthy Alphas in turn and fail over to the next Alpha on
+// connection errors. Mutations, upserts and schema changes are pinned to a single Alpha, so
+// that successive writes are ordered; on a connection error the pool pins the next healthy
+// Alpha for later writes but does not resend the failed one, which may have been committed.
+// Alphas are marked unhealthy when a request fails to reach them, and healthy again by
+// CheckHealth. Pool implements dql.Executor and is safe for concurrent use.
+type Pool struct {
+	clients []*Client
+
+	mu      sync.Mutex
+	healthy []bool
+	next    int
+	pinned  int
+}
+
+// NewPool creates a Pool of clients, one for each Alpha.
+//
+// The clients can be configured before use, e.g. with retry policies or an HTTP client,
+// through Clients.
+//
+// Parameters:
+//   - urls: The URLs of the HTTP endpoints of the Alphas.
+//
+// Returns:
+//   - A pointer to a Pool object.
+//
+// Example:
+//
+//	pool := NewPool("http://alpha1:8080", "http://alpha2:8080", "http://alpha3:8080")
+//	go pool.MonitorHealth(ctx, 10*time.Second)
+//	resp, err := pool.Execute(ctx, query, nil)
+func NewPool(urls ...string) *Pool {
+	p := &Pool{healthy: make([]bool, len(urls))}
+	for i, url := range urls {
+		p.clients = append(p.clients, NewClient(url))
+		p.healthy[i] = true
+	}
+	return p
+}
+
+// Clients returns the clients of the pool, one for each Alpha.
+//
+// Returns:
+//   - The clients, in the order of the URLs given to NewPool.
+func (p *Pool) Clients() []*Client {
+	return p.clients
+}
+
+// Login logs every client of the pool into a namespace, see Client.Login.
+//
+// Parameters:
+//   - ctx: The context of the requests.
+//   - user: The name of the user.
+//   - password: The password of the user.
+//   - namespace: The namespace to log into.
+//
+// Returns:
+//   - An error if the credentials were rejected or a request failed.
+func (p *Pool) Login(ctx context.Context, user string, password string, namespace uint64) error {
+	for _, c := range p.clients {
+		if err := c.Login(ctx, user, password, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute runs a query on the next healthy Alpha, failing over to the other Alphas on
+// connection errors.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - q: The query to run.
+//   - vars: The values of the variables of the query, by name with their leading $.
+//
+// Returns:
+//   - The response of Dgraph.
+//   - An error if the query was rejected or no Alpha could be reached.
+func (p *Pool) Execute(ctx context.Context, q *dql.Query, vars map[string]string) (*dql.Response, error) {
+	if len(p.clients) == 0 {
+		return nil, fmt.Errorf("dqlhttp: pool: no alphas")
+	}
+	p.mu.Lock()
+	order := p.order(p.next)
+	p.next = (order[0] + 1) % len(p.clients)
+	p.mu.Unlock()
+	var err error
+	for _, i := range order {
+		var resp *dql.Response
+		resp, err = p.clients[i].Execute(ctx, q, vars)
+		if !isConnectionError(err) {
+			return resp, err
+		}
+		p.setHealthy(i, false)
+	}
+	return nil, err
+}
+
+// Mutate runs a JSON mutation on the pinned Alpha, see Client.Mutate.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - mutation: The JSON mutation.
+//
+// Returns:
+//   - The uids assigned to the blank nodes of the mutation, by blank node name.
+//   - An error if the mutation was rejected or the request failed.
+func (p *Pool) Mutate(ctx context.Context, mutation []byte) (map[string]dql.UID, error) {
+	var res map[string]dql.UID
+	err := p.write(func(c *Client) error {
+		var err error
+		res, err = c.Mutate(ctx, mutation)
+		return err
+	})
+	return res, err
+}
+
+// Upsert runs an upsert block on the pinned Alpha, see Client.Upsert.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - q: The query part of the upsert.
+//   - mutation: The JSON mutation part of the upsert.
+//
+// Returns:
+//   - The uids the query found and the mutation created.
+//   - An error if the upsert was rejected or the request failed.
+func (p *Pool) Upsert(ctx context.Context, q *dql.Query, mutation []byte) (*UpsertResult, error) {
+	var res *UpsertResult
+	err := p.write(func(c *Client) error {
+		var err error
+		res, err = c.Upsert(ctx, q, mutation)
+		return err
+	})
+	return res, err
+}
+
+// Alter applies a schema through the pinned Alpha, see Client.Alter.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - schema: The schema to apply.
+//
+// Returns:
+//   - An error if the schema was rejected or the request failed.
+func (p *Pool) Alter(ctx context.Context, schema *dql.Schema) error {
+	return p.write(func(c *Client) error {
+		return c.Alter(ctx, schema)
+	})
+}
+
+// CheckHealth checks the health of every Alpha, see Client.Health, and marks them healthy or
+// not accordingly.
+//
+// Parameters:
+//   - ctx: The context of the requests.
+//
+// Returns:
+//   - An error if no Alpha is healthy.
+func (p *Pool) CheckHealth(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.clients))
+	for i, c := range p.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.Health(ctx)
+			p.setHealthy(i, errs[i] == nil)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("dqlhttp: pool: no healthy alpha: %w", errors.Join(errs...))
+}
+
+// MonitorHealth checks the health of the Alphas every interval, until ctx is done. It is
+// usually run in its own goroutine.
+//
+// Parameters:
+//   - ctx: The context stopping the checks.
+//   - interval: The delay between two checks.
+func (p *Pool) MonitorHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// write runs a write on the pinned Alpha, pinning the next healthy Alpha on connection errors.
+func (p *Pool) write(op func(c *Client) error) error {
+	if len(p.clients) == 0 {
+		return fmt.Errorf("dqlhttp: pool: no alphas")
+	}
+	p.mu.Lock()
+	i := p.order(p.pinned)[0]
+	p.pinned = i
+	p.mu.Unlock()
+	err := op(p.clients[i])
+	if isConnectionError(err) {
+		p.setHealthy(i, false)
+		p.mu.Lock()
+		if p.pinned == i {
+			p.pinned = (i + 1) % len(p.clients)
+		}
+		p.mu.Unlock()
+	}
+	return err
+}
+
+// order returns the indexes of the clients to try from start: the healthy ones first, then
+// the others, in case they recovered since they were marked. p.mu must be held.
+func (p *Pool) order(start int) []int {
+	healthy, unhealthy := []int{}, []int{}
+	for k := range p.clients {
+		i := (start + k) % len(p.clients)
+		if p.healthy[i] {
+			healthy = append(healthy, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// setHealthy marks an Alpha healthy or unhealthy.
+func (p *Pool) setHealthy(i int, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.healthy[i] = healthy
+}
+
+// isConnectionError reports whether an error is a failure to reach an Alpha, rather than an
+// error reported by Dgraph: a network error, or an error status of a proxy or load balancer.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	var status *StatusError
+	return errors.As(err, &netErr) || errors.As(err, &status) && status.StatusCode >= 502 && status.StatusCode <= 504
+}
@@ -0,0 +1,70 @@
+package dqlhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dql/dql"
+)
+
+// deadURL returns the URL of a server that is no longer listening.
+func deadURL() string {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestPoolExecute(t *testing.T) {
+	a, ca := newFakeAlpha(t, ok)
+	b, cb := newFakeAlpha(t, ok)
+	p := NewPool(ca.URL, deadURL(), cb.URL)
+	q := dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name")))
+	for i := 0; i < 4; i++ {
+		if _, err := p.Execute(context.Background(), q, nil); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if len(a.requests) != 2 || len(b.requests) != 2 {
+		t.Errorf("requests = %d, %d, want 2, 2 spread over the live alphas", len(a.requests), len(b.requests))
+	}
+	if p.healthy[1] {
+		t.Errorf("dead alpha still healthy")
+	}
+}
+
+func TestPoolWrite(t *testing.T) {
+	a, ca := newFakeAlpha(t, ok)
+	b, cb := newFakeAlpha(t, ok)
+	p := NewPool(deadURL(), ca.URL, cb.URL)
+	if _, err := p.Mutate(context.Background(), []byte(`{"set": []}`)); err == nil {
+		t.Fatal("Mutate() to a dead alpha succeeded")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.Mutate(context.Background(), []byte(`{"set": []}`)); err != nil {
+			t.Fatalf("Mutate() error = %v", err)
+		}
+	}
+	if len(a.requests) != 3 || len(b.requests) != 0 {
+		t.Errorf("requests = %d, %d, want writes pinned to one alpha", len(a.requests), len(b.requests))
+	}
+}
+
+func TestPoolHealth(t *testing.T) {
+	_, live := newFakeAlpha(t, ok)
+	_, failing := newFakeAlpha(t, func(r request) (int, string) { return http.StatusServiceUnavailable, "down" })
+	p := NewPool(live.URL, failing.URL)
+	if err := p.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth() error = %v", err)
+	}
+	if !p.healthy[0] || p.healthy[1] {
+		t.Errorf("healthy = %v, want [true false]", p.healthy)
+	}
+	if err := NewPool(failing.URL, deadURL()).CheckHealth(context.Background()); err == nil {
+		t.Error("CheckHealth() without a healthy alpha succeeded")
+	}
+	if _, err := NewPool().Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil); err == nil {
+		t.Error("Execute() on an empty pool succeeded")
+	}
+}
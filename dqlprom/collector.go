@@ -0,0 +1,258 @@
+// Package dqlprom exports the metrics of DQL executions in the Prometheus text format.
+//
+// A Collector implements dql.Metrics and serves the metrics it collected over HTTP, to be
+// scraped by Prometheus, without depending on the Prometheus client library.
+package dqlprom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dql/dql"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the buckets of the latency
+// histograms of a Collector.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are the upper bounds, in bytes, of the buckets of the mutation size
+// histogram of a Collector.
+var DefaultSizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+// Collector collects the metrics of DQL executions and serves them in the Prometheus text
+// format. It implements dql.Metrics and http.Handler, and is safe for concurrent use.
+//
+// The metrics are:
+//   - dql_queries_total{query, fingerprint, status}: the executed queries, by status ok or
+//     error.
+//   - dql_query_errors_total{query, fingerprint, kind}: the failed queries, by kind of error,
+//     see dql.ErrorKindName.
+//   - dql_query_duration_seconds{query, fingerprint}: the latency histogram of the queries.
+//   - dql_retries_total{kind}: the retries of failed requests, by kind of error.
+//   - dql_mutations_total{status}: the mutations, by status ok or error.
+//   - dql_mutation_size_bytes: the size histogram of the mutations.
+//   - dql_mutation_duration_seconds: the latency histogram of the mutations.
+type Collector struct {
+	// Namespace prefixes the names of the metrics, dql if empty.
+	Namespace string
+
+	mu                sync.Mutex
+	queries           map[labels]float64
+	queryErrors       map[labels]float64
+	queryDurations    map[labels]*histogram
+	retries           map[labels]float64
+	mutations         map[labels]float64
+	mutationSizes     *histogram
+	mutationDurations *histogram
+}
+
+// NewCollector creates a Collector with the default buckets.
+//
+// Returns:
+//   - A pointer to a Collector object.
+//
+// Example:
+//
+//	collector := dqlprom.NewCollector()
+//	http.Handle("/metrics", collector)
+//	client.Metrics = collector
+//	exec := dql.Chain(client, dql.MetricsMiddleware(collector))
+func NewCollector() *Collector {
+	return &Collector{
+		queries:           map[labels]float64{},
+		queryErrors:       map[labels]float64{},
+		queryDurations:    map[labels]*histogram{},
+		retries:           map[labels]float64{},
+		mutations:         map[labels]float64{},
+		mutationSizes:     newHistogram(DefaultSizeBuckets),
+		mutationDurations: newHistogram(DefaultDurationBuckets),
+	}
+}
+
+// ObserveQuery implements dql.Metrics.
+func (c *Collector) ObserveQuery(ctx context.Context, obs dql.QueryObservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	query := labelList{{"query", obs.Name}, {"fingerprint", obs.Fingerprint}}
+	c.queries[append(query, status(obs.Err)).key()]++
+	if obs.Err != nil {
+		c.queryErrors[append(query, label{"kind", dql.ErrorKindName(obs.Err)}).key()]++
+	}
+	h := c.queryDurations[query.key()]
+	if h == nil {
+		h = newHistogram(DefaultDurationBuckets)
+		c.queryDurations[query.key()] = h
+	}
+	h.observe(obs.Duration.Seconds())
+}
+
+// ObserveRetry implements dql.Metrics.
+func (c *Collector) ObserveRetry(attempt int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries[labelList{{"kind", dql.ErrorKindName(err)}}.key()]++
+}
+
+// ObserveMutation implements dql.Metrics.
+func (c *Collector) ObserveMutation(ctx context.Context, obs dql.MutationObservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mutations[labelList{status(obs.Err)}.key()]++
+	c.mutationSizes.observe(float64(obs.Size))
+	c.mutationDurations.observe(obs.Duration.Seconds())
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+//
+// Parameters:
+//   - w: The writer.
+//
+// Returns:
+//   - The number of bytes written.
+//   - An error if writing failed.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var b strings.Builder
+	c.counter(&b, "queries_total", "Executed DQL queries.", c.queries)
+	c.counter(&b, "query_errors_total", "Failed DQL queries, by kind of error.", c.queryErrors)
+	c.header(&b, "query_duration_seconds", "Latency of DQL queries.", "histogram")
+	for _, l := range sortedKeys(c.queryDurations) {
+		c.queryDurations[l].write(&b, c.name("query_duration_seconds"), l)
+	}
+	c.counter(&b, "retries_total", "Retries of failed requests, by kind of error.", c.retries)
+	c.counter(&b, "mutations_total", "Executed mutations.", c.mutations)
+	c.header(&b, "mutation_size_bytes", "Size of mutations.", "histogram")
+	c.mutationSizes.write(&b, c.name("mutation_size_bytes"), "")
+	c.header(&b, "mutation_duration_seconds", "Latency of mutations.", "histogram")
+	c.mutationDurations.write(&b, c.name("mutation_duration_seconds"), "")
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// name returns the full name of a metric.
+func (c *Collector) name(metric string) string {
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = "dql"
+	}
+	return namespace + "_" + metric
+}
+
+// header writes the HELP and TYPE lines of a metric.
+func (c *Collector) header(b *strings.Builder, metric string, help string, kind string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", c.name(metric), help, c.name(metric), kind)
+}
+
+// counter writes a counter metric.
+func (c *Collector) counter(b *strings.Builder, metric string, help string, values map[labels]float64) {
+	c.header(b, metric, help, "counter")
+	for _, l := range sortedKeys(values) {
+		fmt.Fprintf(b, "%s%s %s\n", c.name(metric), l.braced(), formatFloat(values[l]))
+	}
+}
+
+// label is a label of a metric.
+type label struct {
+	name  string
+	value string
+}
+
+// labelList is a list of labels, rendered by key as a map key.
+type labelList []label
+
+// labels is the rendering of a list of labels, e.g. query="me",status="ok", used as a map key.
+type labels string
+
+// labelValueReplacer escapes the characters that cannot appear as-is in a label value.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// key renders the labels.
+func (l labelList) key() labels {
+	parts := make([]string, len(l))
+	for i, lb := range l {
+		parts[i] = lb.name + `="` + labelValueReplacer.Replace(lb.value) + `"`
+	}
+	return labels(strings.Join(parts, ","))
+}
+
+// braced returns the labels in braces, or an empty string if there are none.
+func (l labels) braced() string {
+	if l == "" {
+		return ""
+	}
+	return "{" + string(l) + "}"
+}
+
+// status returns the status label of an error.
+func status(err error) label {
+	if err != nil {
+		return label{"status", "error"}
+	}
+	return label{"status", "ok"}
+}
+
+// histogram is a Prometheus histogram with cumulative buckets.
+type histogram struct {
+	bounds []float64
+	counts []float64
+	sum    float64
+	count  float64
+}
+
+// newHistogram creates a histogram with buckets bounded by bounds.
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]float64, len(bounds))}
+}
+
+// observe records a value.
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// write writes the samples of the histogram with labels l.
+func (h *histogram) write(b *strings.Builder, name string, l labels) {
+	prefix := ""
+	if l != "" {
+		prefix = string(l) + ","
+	}
+	for i, bound := range h.bounds {
+		fmt.Fprintf(b, "%s_bucket{%sle=%q} %s\n", name, prefix, formatFloat(bound), formatFloat(h.counts[i]))
+	}
+	fmt.Fprintf(b, "%s_bucket{%sle=\"+Inf\"} %s\n", name, prefix, formatFloat(h.count))
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, l.braced(), formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count%s %s\n", name, l.braced(), formatFloat(h.count))
+}
+
+// formatFloat formats a sample value.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a map of metrics in order, for a stable output.
+func sortedKeys[V any](m map[labels]V) []labels {
+	res := make([]labels, 0, len(m))
+	for l := range m {
+		res = append(res, l)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,74 @@
+package dqlprom
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"dql/dql"
+)
+
+func TestCollector(t *testing.T) {
+	errFailed := &dql.Error{Kind: dql.ErrExec, Err: errors.New("unavailable")}
+	c := NewCollector()
+	ctx := context.Background()
+	c.ObserveQuery(ctx, dql.QueryObservation{Name: "me", Fingerprint: "abc", Duration: 20 * time.Millisecond})
+	c.ObserveQuery(ctx, dql.QueryObservation{Name: "me", Fingerprint: "abc", Duration: 3 * time.Second, Err: errFailed})
+	c.ObserveQuery(ctx, dql.QueryObservation{Name: `say "hi"`, Fingerprint: "def"})
+	c.ObserveRetry(1, errFailed)
+	c.ObserveMutation(ctx, dql.MutationObservation{Size: 2000, Duration: time.Millisecond})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", got)
+	}
+	out := rec.Body.String()
+	tests := []string{
+		"# TYPE dql_queries_total counter",
+		`dql_queries_total{query="me",fingerprint="abc",status="ok"} 1`,
+		`dql_queries_total{query="me",fingerprint="abc",status="error"} 1`,
+		`dql_queries_total{query="say \"hi\"",fingerprint="def",status="ok"} 1`,
+		`dql_query_errors_total{query="me",fingerprint="abc",kind="exec"} 1`,
+		`dql_query_duration_seconds_bucket{query="me",fingerprint="abc",le="0.025"} 1`,
+		`dql_query_duration_seconds_bucket{query="me",fingerprint="abc",le="5"} 2`,
+		`dql_query_duration_seconds_bucket{query="me",fingerprint="abc",le="+Inf"} 2`,
+		`dql_query_duration_seconds_sum{query="me",fingerprint="abc"} 3.02`,
+		`dql_query_duration_seconds_count{query="me",fingerprint="abc"} 2`,
+		`dql_retries_total{kind="exec"} 1`,
+		`dql_mutations_total{status="ok"} 1`,
+		`dql_mutation_size_bytes_bucket{le="1024"} 0`,
+		`dql_mutation_size_bytes_bucket{le="4096"} 1`,
+		`dql_mutation_size_bytes_sum 2000`,
+		`dql_mutation_duration_seconds_count 1`,
+	}
+	for _, want := range tests {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics have no line %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestCollectorNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"", "dql_mutations_total"},
+		{"app", "app_mutations_total"},
+	}
+	for _, tt := range tests {
+		c := NewCollector()
+		c.Namespace = tt.namespace
+		var b strings.Builder
+		if _, err := c.WriteTo(&b); err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+		if !strings.Contains(b.String(), "# TYPE "+tt.want+" counter\n") {
+			t.Errorf("namespace %q: metrics have no %s:\n%s", tt.namespace, tt.want, b.String())
+		}
+	}
+}
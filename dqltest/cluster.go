@@ -0,0 +1,136 @@
+package dqltest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"dql/dql"
+	"dql/dqlhttp"
+)
+
+// ImageEnv is the environment variable overriding the Docker image started by StartCluster.
+const ImageEnv = "DQLTEST_IMAGE"
+
+// DefaultImage is the Docker image started by StartCluster, a single-node Dgraph.
+const DefaultImage = "dgraph/standalone:latest"
+
+// Cluster is a disposable Dgraph instance running in Docker, to verify generated queries
+// end-to-end. Cluster implements dql.Executor.
+type Cluster struct {
+	// URL is the URL of the HTTP endpoint of the Dgraph Alpha.
+	URL string
+
+	container string
+	client    *dqlhttp.Client
+}
+
+// StartCluster starts a Dgraph instance in Docker for the duration of a test.
+//
+// The test is skipped when Docker is not available. The container is removed when the test
+// and its subtests complete.
+//
+// Parameters:
+//   - t: The test.
+//
+// Returns:
+//   - A pointer to a Cluster object, ready to accept requests.
+//
+// Example:
+//
+//	func TestGetUser(t *testing.T) {
+//	    c := dqltest.StartCluster(t)
+//	    c.LoadSchema(t, schema)
+//	    c.Mutate(t, `{"set": [{"name": "Alice", "dgraph.type": "Person"}]}`)
+//	    resp, err := c.Execute(ctx, GetUserQuery("Alice"), nil)
+//	}
+func StartCluster(t testing.TB) *Cluster {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("dqltest: docker is not available")
+	}
+	image := os.Getenv(ImageEnv)
+	if image == "" {
+		image = DefaultImage
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm", "-p", "127.0.0.1::8080", image).Output()
+	if err != nil {
+		t.Fatalf("dqltest: start %s: %v", image, commandError(err))
+	}
+	c := &Cluster{container: strings.TrimSpace(string(out))}
+	t.Cleanup(func() {
+		exec.Command("docker", "rm", "-f", c.container).Run()
+	})
+
+	out, err = exec.Command("docker", "port", c.container, "8080/tcp").Output()
+	if err != nil {
+		t.Fatalf("dqltest: port of %s: %v", c.container, commandError(err))
+	}
+	addr, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	c.URL = "http://" + addr
+	c.client = dqlhttp.NewClient(c.URL)
+	c.client.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+	deadline := time.Now().Add(2 * time.Minute)
+	for {
+		resp, err := c.client.HTTPClient.Get(c.URL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return c
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dqltest: dgraph at %s did not become healthy", c.URL)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// LoadSchema alters the schema of the cluster, failing the test on error.
+//
+// Parameters:
+//   - t: The test.
+//   - schema: The schema to apply.
+func (c *Cluster) LoadSchema(t testing.TB, schema *dql.Schema) {
+	t.Helper()
+	if err := c.client.Alter(context.Background(), schema); err != nil {
+		t.Fatalf("dqltest: %v", err)
+	}
+}
+
+// Mutate runs a JSON mutation and commits it, failing the test on error.
+//
+// Parameters:
+//   - t: The test.
+//   - mutation: The JSON mutation, e.g. {"set": [{"name": "Alice"}]}.
+//
+// Returns:
+//   - The uids assigned to the blank nodes of the mutation, by blank node name.
+func (c *Cluster) Mutate(t testing.TB, mutation string) map[string]dql.UID {
+	t.Helper()
+	uids, err := c.client.Mutate(context.Background(), []byte(mutation))
+	if err != nil {
+		t.Fatalf("dqltest: %v", err)
+	}
+	return uids
+}
+
+// Execute runs a query against the cluster.
+func (c *Cluster) Execute(ctx context.Context, q *dql.Query, vars map[string]string) (*dql.Response, error) {
+	return c.client.Execute(ctx, q, vars)
+}
+
+// commandError adds the standard error of a failed command to its error.
+func commandError(err error) error {
+	if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) != 0 {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(exit.Stderr))
+	}
+	return err
+}
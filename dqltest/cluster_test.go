@@ -0,0 +1,98 @@
+package dqltest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dql/dql"
+	"dql/dqlhttp"
+)
+
+// newTestCluster returns a cluster talking to a fake Dgraph answering with handler.
+func newTestCluster(t *testing.T, handler http.HandlerFunc) *Cluster {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Cluster{URL: server.URL, client: dqlhttp.NewClient(server.URL)}
+}
+
+func TestClusterExecute(t *testing.T) {
+	c := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if r.URL.Path != "/query" || body.Query != "{ me (func: has(name)) { name } }" || body.Variables["$a"] != "1" {
+			t.Errorf("request %s %+v", r.URL.Path, body)
+		}
+		io.WriteString(w, `{"data": {"me": [{"name": "Alice"}]}}`)
+	})
+	q := dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name")).WithAttributes(dql.NewAttribute("name")))
+	resp, err := c.Execute(context.Background(), q, map[string]string{"$a": "1"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := string(resp.Json); got != `{"me": [{"name": "Alice"}]}` {
+		t.Errorf("Execute() = %s", got)
+	}
+}
+
+func TestClusterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"dgraph error", http.StatusOK, `{"errors": [{"message": "line 1: syntax error"}]}`, `dqlhttp: query "": dgraph: line 1: syntax error`},
+		{"not json", http.StatusBadGateway, "bad gateway", `dqlhttp: query "": 502 Bad Gateway: bad gateway`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			})
+			_, err := c.Execute(context.Background(), dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Execute() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterSetup(t *testing.T) {
+	requests := []string{}
+	c := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+		if strings.HasPrefix(r.URL.Path, "/mutate") {
+			io.WriteString(w, `{"data": {"uids": {"alice": "0x1"}}}`)
+			return
+		}
+		io.WriteString(w, `{"data": {"code": "Success"}}`)
+	})
+	schema, err := dql.ParseSchema("name: string @index(exact) .")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.LoadSchema(t, schema)
+	uids := c.Mutate(t, `{"set": [{"uid": "_:alice", "name": "Alice"}]}`)
+	if uids["alice"] != 0x1 {
+		t.Errorf("Mutate() = %v", uids)
+	}
+	want := []string{
+		"/alter application/rdf name: string @index(exact) .",
+		`/mutate?commitNow=true application/json {"set": [{"uid": "_:alice", "name": "Alice"}]}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
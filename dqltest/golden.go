@@ -0,0 +1,62 @@
+package dqltest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dql/dql"
+)
+
+// UpdateEnv is the environment variable that makes AssertQuery update golden files instead
+// of comparing against them, e.g. DQLTEST_UPDATE=1 go test ./...
+const UpdateEnv = "DQLTEST_UPDATE"
+
+// AssertQuery compares a query with the golden DQL file at path.
+//
+// Both sides are compared semantically with dql.Equal, so the golden file can be formatted
+// freely. On mismatch, the test fails with the differences and the formatted query. When the
+// UpdateEnv environment variable is set, the golden file is written with the formatted query
+// instead, which makes changes to the builders reviewable as DQL diffs.
+//
+// Parameters:
+//   - t: The test.
+//   - q: The query under test.
+//   - path: The path of the golden file, e.g. testdata/get_user.dql.
+//
+// Example:
+//
+//	func TestGetUser(t *testing.T) {
+//	    dqltest.AssertQuery(t, GetUserQuery("0x1"), "testdata/get_user.dql")
+//	}
+func AssertQuery(t testing.TB, q *dql.Query, path string) {
+	t.Helper()
+	formatted := dql.Format(q)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("dqltest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+			t.Fatalf("dqltest: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("dqltest: golden file %s does not exist, run with %s=1 to create it:\n%s", path, UpdateEnv, formatted)
+	}
+	if err != nil {
+		t.Fatalf("dqltest: %v", err)
+	}
+	golden, err := dql.Parse(string(data))
+	if err != nil {
+		t.Fatalf("dqltest: golden file %s: %v", path, err)
+	}
+	if !dql.Equal(golden, q) {
+		t.Errorf("dqltest: query differs from golden file %s:\n  %s\ngot:\n%s", path, strings.Join(dql.Diff(golden, q), "\n  "), formatted)
+	}
+}
@@ -0,0 +1,74 @@
+package dqltest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"dql/dql"
+)
+
+// recorder is a testing.TB recording failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs f in its own goroutine, as Fatalf requires, and returns its failures.
+func record(t *testing.T, f func(tb testing.TB)) []string {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	return r.failures
+}
+
+func TestAssertQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "me.dql")
+	q := dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("user")).WithAttributes(dql.NewAttribute("name")))
+
+	failures := record(t, func(tb testing.TB) { AssertQuery(tb, q, path) })
+	if len(failures) != 1 || !strings.Contains(failures[0], "does not exist") {
+		t.Fatalf("missing golden file failures = %q", failures)
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	AssertQuery(t, q, path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != dql.Format(q) {
+		t.Errorf("golden file = %q, want %q", data, dql.Format(q))
+	}
+	t.Setenv(UpdateEnv, "")
+
+	// The golden file is compared semantically, so reformatting it keeps the test passing.
+	if err := os.WriteFile(path, []byte("{ me(func: has(user)) { name } }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	AssertQuery(t, q, path)
+
+	other := dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("user")).WithAttributes(dql.NewAttribute("email")))
+	failures = record(t, func(tb testing.TB) { AssertQuery(tb, other, path) })
+	if len(failures) != 1 || !strings.Contains(failures[0], "query differs from golden file") {
+		t.Errorf("mismatch failures = %q", failures)
+	}
+}
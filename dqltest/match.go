@@ -0,0 +1,146 @@
+package dqltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	"dql/dql"
+)
+
+// HasBlock reports whether a query has a query or variable block with the given name.
+//
+// Parameters:
+//   - q: The query to inspect.
+//   - name: The name of the block.
+//
+// Returns:
+//   - true if the query has the block, false otherwise.
+func HasBlock(q *dql.Query, name string) bool {
+	_, _, ok := findBlock(q, name)
+	return ok
+}
+
+// HasFilter reports whether a block of a query filters its results with the given
+// expression.
+//
+// The expression matches if it is part of a @filter of the block, ignoring formatting, so
+// eq(name, "Alice") matches @filter(eq(name,"Alice") AND has(email)).
+//
+// Parameters:
+//   - q: The query to inspect.
+//   - block: The name of the query or variable block.
+//   - filter: The filter expression, either a Criteria such as Eq("name", "Alice") or a
+//     string.
+//
+// Returns:
+//   - true if the block has the filter, false otherwise.
+func HasFilter(q *dql.Query, block string, filter any) bool {
+	_, directives, ok := findBlock(q, block)
+	if !ok {
+		return false
+	}
+	want := compact(fmt.Sprint(filter))
+	for _, d := range directives {
+		s := compact(d.String())
+		if strings.HasPrefix(s, "@filter(") && strings.Contains(s, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAttribute reports whether a block of a query selects an attribute.
+//
+// Parameters:
+//   - q: The query to inspect.
+//   - block: The name of the query or variable block.
+//   - path: The dotted path of the attribute, e.g. friend.name. Aliased attributes are
+//     matched by alias or by name.
+//
+// Returns:
+//   - true if the block selects the attribute, false otherwise.
+func HasAttribute(q *dql.Query, block string, path string) bool {
+	attrs, _, ok := findBlock(q, block)
+	if !ok {
+		return false
+	}
+	for _, name := range strings.Split(path, ".") {
+		var found *dql.Attribute
+		for _, a := range attrs {
+			if a.Name == name || a.Alias == name {
+				found = a
+				break
+			}
+		}
+		if found == nil {
+			return false
+		}
+		attrs = found.Attributes
+	}
+	return true
+}
+
+// AssertHasBlock fails the test if the query has no block with the given name.
+func AssertHasBlock(t testing.TB, q *dql.Query, name string) {
+	t.Helper()
+	if !HasBlock(q, name) {
+		t.Errorf("dqltest: query has no block %q:\n%s", name, q.PrettyPrint())
+	}
+}
+
+// AssertHasFilter fails the test if the block does not filter its results with the given
+// expression, see HasFilter.
+func AssertHasFilter(t testing.TB, q *dql.Query, block string, filter any) {
+	t.Helper()
+	if !HasFilter(q, block, filter) {
+		t.Errorf("dqltest: block %q has no filter %s:\n%s", block, filter, q.PrettyPrint())
+	}
+}
+
+// AssertHasAttribute fails the test if the block does not select the attribute, see
+// HasAttribute.
+func AssertHasAttribute(t testing.TB, q *dql.Query, block string, path string) {
+	t.Helper()
+	if !HasAttribute(q, block, path) {
+		t.Errorf("dqltest: block %q does not select %q:\n%s", block, path, q.PrettyPrint())
+	}
+}
+
+// findBlock returns the attributes and directives of a query or variable block.
+func findBlock(q *dql.Query, name string) ([]*dql.Attribute, []dql.Criteria, bool) {
+	for _, qb := range q.QueryBlocks {
+		if qb.Name == name {
+			return qb.Attributes, qb.Directives, true
+		}
+	}
+	for _, vb := range q.VarBlocks {
+		if vb.Name == name {
+			return vb.Attributes, vb.Directives, true
+		}
+	}
+	return nil, nil, false
+}
+
+// compact removes the whitespace outside of string literals, so that differently formatted
+// expressions can be compared.
+func compact(s string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(s):
+			b.WriteByte(c)
+			i++
+			c = s[i]
+		case c == '"':
+			inString = !inString
+		case !inString && unicode.IsSpace(rune(c)):
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package dqltest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dql/dql"
+)
+
+func TestMatch(t *testing.T) {
+	q := dql.NewQuery("",
+		dql.NewQueryBlock("me", dql.Has("name")).
+			WithDirectives(dql.NewDirective("filter", `eq(name, "Alice") AND has(email)`)).
+			WithAttributes(dql.NewAttribute("friend").WithAlias("friends").WithAttributes(dql.NewAttribute("name"))),
+	).WithVarBlocks(dql.NewVarBlockOpt("F", dql.Func(dql.Has("friend"))))
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"query block", HasBlock(q, "me"), true},
+		{"var block", HasBlock(q, "F"), true},
+		{"missing block", HasBlock(q, "you"), false},
+		{"filter criteria", HasFilter(q, "me", dql.Eq("name", "Alice")), true},
+		{"filter string", HasFilter(q, "me", `eq(name,"Alice")`), true},
+		{"filter spaced string", HasFilter(q, "me", `has( email )`), true},
+		{"filter value with spaces", HasFilter(q, "me", `eq(name, "Ali ce")`), false},
+		{"missing filter", HasFilter(q, "me", dql.Eq("name", "Bob")), false},
+		{"filter of missing block", HasFilter(q, "you", dql.Has("email")), false},
+		{"attribute", HasAttribute(q, "me", "friend"), true},
+		{"attribute by alias", HasAttribute(q, "me", "friends.name"), true},
+		{"missing nested attribute", HasAttribute(q, "me", "friend.email"), false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestMock(t *testing.T) {
+	mock := NewMock().Respond(`{"other": []}`).RespondTo("me", `{"me": [{"name": "Alice"}]}`)
+	tests := []struct {
+		block string
+		want  string
+	}{
+		{"me", `{"me": [{"name": "Alice"}]}`},
+		{"you", `{"other": []}`},
+	}
+	for _, tt := range tests {
+		q := dql.NewQuery("", dql.NewQueryBlock(tt.block, dql.Has("name")))
+		resp, err := mock.Execute(context.Background(), q, map[string]string{"$a": "1"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if string(resp.Json) != tt.want {
+			t.Errorf("block %s: response %s, want %s", tt.block, resp.Json, tt.want)
+		}
+	}
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Rendered != "{ me (func: has(name)) { } }" || calls[1].Vars["$a"] != "1" {
+		t.Errorf("Calls() = %+v", calls)
+	}
+	last, err := mock.LastCall()
+	if err != nil || last.Query.QueryBlocks[0].Name != "you" {
+		t.Errorf("LastCall() = %+v, %v, want the you query", last, err)
+	}
+	if _, err := NewMock().LastCall(); err == nil {
+		t.Error("LastCall() without calls error = nil")
+	}
+}
+
+func TestMockErrors(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		mock    *Mock
+		timeout time.Duration
+		wantErr error
+	}{
+		{"fail", NewMock().Fail(errFailed), 0, errFailed},
+		{"delay within deadline", NewMock().Delay(time.Millisecond), time.Minute, nil},
+		{"delay past deadline", NewMock().Delay(time.Minute), 10 * time.Millisecond, ErrServerTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			_, err := tt.mock.Execute(ctx, dql.NewQuery("", dql.NewQueryBlock("me", dql.Has("name"))), nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if call, _ := tt.mock.LastCall(); call.Timeout > tt.timeout || tt.timeout > 0 && call.Timeout == 0 {
+				t.Errorf("Timeout = %v, want at most %v", call.Timeout, tt.timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Package dqltest provides helpers for testing code built on the dql package without a
+// running Dgraph.
+package dqltest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"dql/dql"
+)
+
+// Call is a query executed through a Mock.
+type Call struct {
+	// Query is the executed query.
+	Query *dql.Query
+
+	// Rendered is the DQL text of the query at the time it was executed.
+	Rendered string
+
+	// Vars is the variables the query was executed with.
+	Vars map[string]string
+
+	// Timeout is the timeout the query would have been sent to Dgraph with, from the deadline
+	// of its context, or zero if it had none, see dql.QueryTimeout.
+	Timeout time.Duration
+}
+
+// ErrServerTimeout is the error of a Mock whose simulated latency, see Mock.Delay, exceeds the
+// deadline of a query, as Dgraph reports a query running past its timeout.
+var ErrServerTimeout = &dql.Error{Kind: dql.ErrExec, Err: errors.New("dgraph: context deadline exceeded")}
+
+// Mock is an Executor recording the queries it executes and returning canned responses.
+//
+// Responses are matched by the name of a query block of the executed query, falling back to
+// the default response. A Mock is safe for concurrent use.
+//
+// Example:
+//
+//	mock := dqltest.NewMock().
+//	    RespondTo("me", `{"me": [{"name": "Alice"}]}`)
+//	svc := NewUserService(mock)
+//	user, err := svc.Get(ctx, "0x1")
+//	dqltest.AssertHasFilter(t, mock.Calls()[0].Query, "me", `eq(name, "Alice")`)
+type Mock struct {
+	mu        sync.Mutex
+	calls     []Call
+	fallback  string
+	responses map[string]string
+	err       error
+	delay     time.Duration
+}
+
+// NewMock creates a Mock responding with empty data.
+//
+// Returns:
+//   - A pointer to a Mock object.
+func NewMock() *Mock {
+	return &Mock{fallback: "{}", responses: map[string]string{}}
+}
+
+// Respond sets the default response, returned for queries no other response matches.
+//
+// Parameters:
+//   - json: The JSON data of the response.
+//
+// Returns:
+//   - The updated Mock object.
+func (m *Mock) Respond(json string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallback = json
+	return m
+}
+
+// RespondTo sets the response returned for queries with a query block of the given name.
+//
+// Parameters:
+//   - block: The name of the query block.
+//   - json: The JSON data of the response.
+//
+// Returns:
+//   - The updated Mock object.
+func (m *Mock) RespondTo(block string, json string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[block] = json
+	return m
+}
+
+// Fail makes every following query fail with err, e.g. to test error handling.
+//
+// Parameters:
+//   - err: The error returned by Execute, nil to stop failing.
+//
+// Returns:
+//   - The updated Mock object.
+func (m *Mock) Fail(err error) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+	return m
+}
+
+// Delay makes every following query take d to respond, simulating the latency of the server.
+// A query whose deadline falls within the delay fails with ErrServerTimeout once its timeout
+// elapses, e.g. to test the fallback paths of code with deadlines.
+//
+// Parameters:
+//   - d: The latency of the responses, zero to respond immediately.
+//
+// Returns:
+//   - The updated Mock object.
+//
+// Example:
+//
+//	mock := dqltest.NewMock().Delay(time.Second)
+//	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+//	defer cancel()
+//	_, err := mock.Execute(ctx, query, nil)
+//	errors.Is(err, dqltest.ErrServerTimeout) // true
+func (m *Mock) Delay(d time.Duration) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delay = d
+	return m
+}
+
+// Execute records the query and returns the matching response, after the delay set by Delay.
+func (m *Mock) Execute(ctx context.Context, q *dql.Query, vars map[string]string) (*dql.Response, error) {
+	call := Call{Query: q.Clone(), Rendered: q.String(), Vars: vars}
+	call.Timeout, _ = dql.QueryTimeout(ctx)
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	delay, err, json := m.delay, m.err, m.response(q)
+	m.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if delay > 0 {
+		timeout := false
+		if call.Timeout > 0 && call.Timeout < delay {
+			delay, timeout = call.Timeout, true
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+			return nil, ErrServerTimeout
+		case <-timer.C:
+		}
+		if timeout {
+			return nil, ErrServerTimeout
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dql.Response{Json: []byte(json)}, nil
+}
+
+// response returns the response matching a query. m.mu must be held.
+func (m *Mock) response(q *dql.Query) string {
+	for _, qb := range q.QueryBlocks {
+		if json, ok := m.responses[qb.Name]; ok {
+			return json
+		}
+	}
+	return m.fallback
+}
+
+// Calls returns the queries executed so far, in order.
+//
+// Returns:
+//   - The recorded calls.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// LastCall returns the last query executed.
+//
+// Returns:
+//   - The last recorded call.
+//   - An error if no query was executed.
+func (m *Mock) LastCall() (Call, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		return Call{}, fmt.Errorf("dqltest: no query was executed")
+	}
+	return m.calls[len(m.calls)-1], nil
+}
@@ -0,0 +1,173 @@
+package dqltest
+
+import (
+	"fmt"
+	"math/rand"
+
+	"dql/dql"
+)
+
+// randomPredicates is the pool of predicates random queries select and filter on.
+var randomPredicates = []string{"name", "name@en", "age", "email", "friend", "owns", "dgraph.type"}
+
+// RandomQuery generates a random valid query.
+//
+// The same seed always generates the same query. Queries combine parameters, variable blocks,
+// query blocks with root functions, pagination and filters, nested attributes with aliases
+// and variables, and fragments, and they pass dql.Validate. This makes them suitable for
+// fuzzing the formatter and parser round-trip, or services accepting serialized queries.
+//
+// Parameters:
+//   - seed: The seed of the generator.
+//
+// Returns:
+//   - A pointer to a random Query object.
+//
+// Example:
+//
+//	func FuzzRoundTrip(f *testing.F) {
+//	    f.Add(int64(1))
+//	    f.Fuzz(func(t *testing.T, seed int64) {
+//	        q := dqltest.RandomQuery(seed)
+//	        parsed, err := dql.Parse(q.String())
+//	        if err != nil || !dql.Equal(q, parsed) {
+//	            t.Fatalf("round-trip failed for %s: %v", q, err)
+//	        }
+//	    })
+//	}
+func RandomQuery(seed int64) *dql.Query {
+	g := &generator{rand: rand.New(rand.NewSource(seed))}
+	q := &dql.Query{}
+	if g.rand.Intn(2) == 0 {
+		q.Name = "Q" + fmt.Sprint(g.rand.Intn(100))
+	}
+	for i := 0; i < g.rand.Intn(3); i++ {
+		p := dql.NewParam(fmt.Sprintf("p%d", i), dql.ParamString)
+		if g.rand.Intn(2) == 0 {
+			p.WithDefault(g.word())
+		}
+		q.Params = append(q.Params, p)
+		g.params = append(g.params, p.Ref())
+	}
+	for i := 0; i < g.rand.Intn(3); i++ {
+		name := fmt.Sprintf("v%d", i)
+		vb := dql.NewVarBlock(g.root()).WithName(name).WithAttributes(g.attributes(2)...)
+		q.VarBlocks = append(q.VarBlocks, vb)
+		g.vars = append(g.vars, name)
+	}
+	if g.rand.Intn(3) == 0 {
+		f := dql.NewFragment("F" + fmt.Sprint(g.rand.Intn(100))).WithAttributes(g.attributes(1)...)
+		q.Fragments = append(q.Fragments, f)
+		g.fragments = append(g.fragments, f.Name)
+	}
+	for i := 0; i <= g.rand.Intn(3); i++ {
+		qb := dql.NewQueryBlock(fmt.Sprintf("b%d", i), g.root())
+		if g.rand.Intn(2) == 0 {
+			qb.WithFirst(1 + g.rand.Intn(20))
+		}
+		if g.rand.Intn(3) == 0 {
+			qb.WithOffset(g.rand.Intn(20))
+		}
+		if g.rand.Intn(3) == 0 {
+			qb.WithOrderAsc(g.scalar())
+		}
+		if g.rand.Intn(2) == 0 {
+			qb.WithDirectives(dql.NewDirective("filter", g.function()))
+		}
+		if g.rand.Intn(4) == 0 {
+			qb.WithDirectives(dql.NewDirective("cascade"))
+		}
+		attrs := g.attributes(3)
+		if len(g.fragments) != 0 && g.rand.Intn(2) == 0 {
+			attrs = append(attrs, dql.NewAttribute("..."+g.fragments[0]))
+		}
+		q.QueryBlocks = append(q.QueryBlocks, qb.WithAttributes(attrs...))
+	}
+	return q
+}
+
+// generator holds the state of RandomQuery: the declared parameters, variables and fragments
+// later parts of the query can refer to.
+type generator struct {
+	rand      *rand.Rand
+	params    []dql.ParamRef
+	vars      []string
+	fragments []string
+	counter   int
+}
+
+// word generates a random word.
+func (g *generator) word() string {
+	words := []string{"alice", "bob", "carol", "dave", "Hello, \"world\"", "42"}
+	return words[g.rand.Intn(len(words))]
+}
+
+// scalar picks a random scalar predicate.
+func (g *generator) scalar() string {
+	return randomPredicates[g.rand.Intn(4)]
+}
+
+// value generates a random function argument: a literal or a parameter reference.
+func (g *generator) value() any {
+	if len(g.params) != 0 && g.rand.Intn(2) == 0 {
+		return g.params[g.rand.Intn(len(g.params))]
+	}
+	if g.rand.Intn(2) == 0 {
+		return g.rand.Intn(100)
+	}
+	return g.word()
+}
+
+// function generates a random filter function.
+func (g *generator) function() dql.Criteria {
+	switch g.rand.Intn(4) {
+	case 0:
+		return dql.Has(randomPredicates[g.rand.Intn(len(randomPredicates))])
+	case 1:
+		return dql.Eq(g.scalar(), g.value())
+	case 2:
+		return dql.Ge("age", g.rand.Intn(100))
+	default:
+		return dql.AnyOfTerms("name", g.word())
+	}
+}
+
+// root generates a random root function, possibly starting from a declared variable.
+func (g *generator) root() dql.Criteria {
+	if len(g.vars) != 0 && g.rand.Intn(3) == 0 {
+		return dql.Uid(g.vars[g.rand.Intn(len(g.vars))])
+	}
+	return g.function()
+}
+
+// attributes generates a random selection set nested at most depth levels deep.
+func (g *generator) attributes(depth int) []*dql.Attribute {
+	attrs := []*dql.Attribute{dql.UIDAttribute()}
+	used := map[string]bool{"uid": true}
+	for i := 0; i < 1+g.rand.Intn(4); i++ {
+		name := randomPredicates[g.rand.Intn(len(randomPredicates))]
+		if used[name] {
+			continue
+		}
+		used[name] = true
+		attr := dql.NewAttribute(name)
+		if (name == "friend" || name == "owns") && depth > 1 {
+			attr.WithAttributes(g.attributes(depth - 1)...)
+			if g.rand.Intn(2) == 0 {
+				attr.WithFirst(1 + g.rand.Intn(10))
+			}
+		}
+		switch g.rand.Intn(4) {
+		case 0:
+			g.counter++
+			attr.WithAlias(fmt.Sprintf("a%d", g.counter))
+		case 1:
+			if len(attr.Attributes) == 0 {
+				g.counter++
+				attr.WithVar(fmt.Sprintf("x%d", g.counter))
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
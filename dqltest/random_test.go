@@ -0,0 +1,23 @@
+package dqltest
+
+import (
+	"testing"
+
+	"dql/dql"
+)
+
+func TestRandomQueryRoundTrip(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		q := RandomQuery(seed)
+		if err := q.Validate(); err != nil {
+			t.Fatalf("seed %d: Validate() error = %v for %s", seed, err, q)
+		}
+		if RandomQuery(seed).String() != q.String() {
+			t.Fatalf("seed %d: queries differ", seed)
+		}
+		parsed, err := dql.Parse(q.String())
+		if err != nil || !dql.Equal(q, parsed) {
+			t.Fatalf("seed %d: round trip of %s = %v, %v", seed, q, parsed, err)
+		}
+	}
+}
@@ -8,13 +8,14 @@ import (
 
 func Pagination() {
 	genreBlock := dql.NewAttribute("genre").
-		WithDirectives("(orderasc: name@en)", "(first: 3)").
+		WithOrderAsc("name@en").
+		WithFirst(3).
 		WithAttributes(
 			dql.NewAttribute("name@en"),
 		)
 
 	directorFilmBlock := dql.NewAttribute("director.film").
-		WithDirectives("(first: -2)").
+		WithFirst(-2).
 		WithAttributes(
 			dql.NewAttribute("name@en"),
 			dql.NewAttribute("initial_release_date"),
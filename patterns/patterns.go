@@ -0,0 +1,121 @@
+// Package patterns provides ready-made queries for common DQL patterns.
+//
+// Each builder returns a complete *dql.Query whose first block holds the results, so it can
+// be customized afterwards like any hand-built query, e.g. with more attributes or filters.
+package patterns
+
+import "dql/dql"
+
+// GetByUid creates a query fetching a node by its uid.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - uid: The uid of the node, e.g. 0x1a or a dql.UID, or a parameter reference such as $id.
+//   - attrs: The attributes to fetch.
+//
+// Returns:
+//   - A pointer to a dql.Query object.
+//
+// Example:
+//
+//	query := patterns.GetByUid("user", "0x1a", dql.NewAttribute("name"))
+//	fmt.Println(query.String()) // Output: { user (func: uid(0x1a)) { name } }
+func GetByUid(name string, uid any, attrs ...*dql.Attribute) *dql.Query {
+	return dql.NewQuery("", dql.NewQueryBlock(name, dql.Uid(uid)).WithAttributes(attrs...))
+}
+
+// GetByXid creates a query fetching a node by an external identifier, such as an email or a
+// slug held in an indexed predicate.
+//
+// At most one node is returned, since the identifier is expected to be unique.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - field: The predicate holding the identifier.
+//   - value: The identifier.
+//   - attrs: The attributes to fetch.
+//
+// Returns:
+//   - A pointer to a dql.Query object.
+//
+// Example:
+//
+//	query := patterns.GetByXid("user", "email", "alice@example.com", dql.UIDAttribute())
+//	fmt.Println(query.String()) // Output: { user (func: eq(email, "alice@example.com"), first: 1) { uid } }
+func GetByXid(name string, field string, value any, attrs ...*dql.Attribute) *dql.Query {
+	qb := dql.NewQueryBlock(name, dql.Eq(field, value)).WithFirst(1).WithAttributes(attrs...)
+	return dql.NewQuery("", qb)
+}
+
+// ExistsCheck creates a query checking whether any node matches a root function.
+//
+// The block returns the uid of at most one node, so the check stops at the first match: the
+// nodes exist if the block of the response is not empty.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - root: The root function, either a dql.Criteria or a string.
+//
+// Returns:
+//   - A pointer to a dql.Query object.
+//
+// Example:
+//
+//	query := patterns.ExistsCheck("taken", dql.Eq("username", "alice"))
+//	fmt.Println(query.String()) // Output: { taken (func: eq(username, "alice"), first: 1) { uid } }
+func ExistsCheck(name string, root any) *dql.Query {
+	qb := dql.NewQueryBlock(name, root).WithFirst(1).WithAttributes(dql.UIDAttribute())
+	return dql.NewQuery("", qb)
+}
+
+// CountByType creates a query counting the nodes of a type.
+//
+// The count is returned under the total key of the block.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - typeName: The name of the type.
+//
+// Returns:
+//   - A pointer to a dql.Query object.
+//
+// Example:
+//
+//	query := patterns.CountByType("users", "User")
+//	fmt.Println(query.String()) // Output: { users (func: type(User)) { total : count(uid) } }
+func CountByType(name string, typeName string) *dql.Query {
+	qb := dql.NewQueryBlock(name, dql.Type(typeName)).
+		WithAttributes(dql.NewAttribute("count(uid)").WithAlias("total"))
+	return dql.NewQuery("", qb)
+}
+
+// SearchWithPagination creates a query returning a page of the nodes matching any of some
+// terms, along with the total number of matches, see dql.NewPageQuery.
+//
+// The predicate requires a term index. Use dql.DecodePage to decode the response.
+//
+// Parameters:
+//   - name: The name of the block.
+//   - predicate: The predicate to search.
+//   - terms: The space-separated terms of which at least one must match.
+//   - first: The number of results of the page.
+//   - offset: The number of results to skip.
+//   - attrs: The attributes to fetch.
+//
+// Returns:
+//   - A pointer to a dql.Query object.
+//
+// Example:
+//
+//	query := patterns.SearchWithPagination("movies", "name", "star wars", 10, 20, dql.NewAttribute("name"))
+//	fmt.Println(query.String())
+//	// Output: { movies (func: anyofterms(name, "star wars"), first: 10, offset: 20) { name } movies_total (func: anyofterms(name, "star wars")) { total : count(uid) } }
+//
+// See: https://dgraph.io/docs/query-language/pagination/
+func SearchWithPagination(name string, predicate string, terms string, first int, offset int, attrs ...*dql.Attribute) *dql.Query {
+	qb := dql.NewQueryBlock(name, dql.AnyOfTerms(predicate, terms)).WithFirst(first)
+	if offset > 0 {
+		qb.WithOffset(offset)
+	}
+	return dql.NewPageQuery(qb.WithAttributes(attrs...))
+}
@@ -0,0 +1,42 @@
+package patterns
+
+import (
+	"testing"
+
+	"dql/dql"
+)
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *dql.Query
+		want string
+	}{
+		{"GetByUid", GetByUid("user", "0x1a", dql.NewAttribute("name")),
+			"{ user (func: uid(0x1a)) { name } }"},
+		{"GetByUid param", GetByUid("user", "$id", dql.NewAttribute("name")),
+			"{ user (func: uid($id)) { name } }"},
+		{"GetByXid", GetByXid("user", "email", "alice@example.com", dql.UIDAttribute()),
+			`{ user (func: eq(email, "alice@example.com"), first: 1) { uid } }`},
+		{"GetByXid escaped", GetByXid("user", "email", `a") { uid } }`, dql.UIDAttribute()),
+			`{ user (func: eq(email, "a\") { uid } }"), first: 1) { uid } }`},
+		{"ExistsCheck", ExistsCheck("taken", dql.Eq("username", "alice")),
+			`{ taken (func: eq(username, "alice"), first: 1) { uid } }`},
+		{"CountByType", CountByType("users", "User"),
+			"{ users (func: type(User)) { total : count(uid) } }"},
+		{"SearchWithPagination", SearchWithPagination("movies", "name", "star wars", 10, 20, dql.NewAttribute("name")),
+			`{ movies (func: anyofterms(name, "star wars"), first: 10, offset: 20) { name } movies_total (func: anyofterms(name, "star wars")) { total : count(uid) } }`},
+		{"SearchWithPagination first page", SearchWithPagination("movies", "name", "star wars", 10, 0, dql.NewAttribute("name")),
+			`{ movies (func: anyofterms(name, "star wars"), first: 10) { name } movies_total (func: anyofterms(name, "star wars")) { total : count(uid) } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			if err := tt.q.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}